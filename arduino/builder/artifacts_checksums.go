@@ -0,0 +1,128 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2023 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package builder
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// buildTimeProperties are the build properties containing the time of the build
+var buildTimeProperties = []string{"extra.time.utc", "extra.time.local", "extra.time.zone", "extra.time.dst"}
+
+// primaryArtifactsExtensions are the extensions of the files, named after the
+// project, that are considered the primary output of a build
+var primaryArtifactsExtensions = []string{".elf", ".hex", ".bin", ".eep", ".uf2", ".srec"}
+
+// SetReproducibleBuild makes the build time properties (extra.time.*) constant,
+// so that the same sketch built twice produces the same output. The time is
+// taken from the SOURCE_DATE_EPOCH environment variable if set, otherwise the
+// Unix epoch is used.
+func (b *Builder) SetReproducibleBuild(enable bool) {
+	if enable && b.savedBuildTimeProperties == nil {
+		b.savedBuildTimeProperties = map[string]string{}
+		for _, key := range buildTimeProperties {
+			b.savedBuildTimeProperties[key] = b.buildProperties.Get(key)
+		}
+		epoch := "0"
+		if sourceDateEpoch, err := strconv.ParseInt(os.Getenv("SOURCE_DATE_EPOCH"), 10, 64); err == nil {
+			epoch = strconv.FormatInt(sourceDateEpoch, 10)
+		}
+		b.buildProperties.Set("extra.time.utc", epoch)
+		b.buildProperties.Set("extra.time.local", epoch)
+		b.buildProperties.Set("extra.time.zone", "0")
+		b.buildProperties.Set("extra.time.dst", "0")
+	} else if !enable && b.savedBuildTimeProperties != nil {
+		for key, value := range b.savedBuildTimeProperties {
+			b.buildProperties.Set(key, value)
+		}
+		b.savedBuildTimeProperties = nil
+	}
+	if b.buildOptions != nil {
+		if enable {
+			b.buildOptions.currentOptions.Set("reproducibleBuild", "true")
+		} else {
+			b.buildOptions.currentOptions.Remove("reproducibleBuild")
+		}
+	}
+}
+
+// ArtifactChecksum is the checksum of an output file of the build
+type ArtifactChecksum struct {
+	// File is the path of the artifact relative to the build path
+	File string
+	// SHA256 is the hex encoded SHA-256 of the artifact contents
+	SHA256 string
+}
+
+// ArtifactsChecksums returns the checksums of the primary artifacts produced by
+// the last build: the files in the build path named after the project with one
+// of the extensions .elf, .hex, .bin, .eep, .uf2 or .srec. If normalize is true
+// the absolute paths of the build and sketch directories embedded in the
+// artifacts (for example in the debug sections of the .elf) are replaced with a
+// placeholder before hashing, so that the checksums don't depend on where the
+// sketch has been built.
+func (b *Builder) ArtifactsChecksums(normalize bool) ([]*ArtifactChecksum, error) {
+	projectName := b.buildProperties.Get("build.project_name")
+	files, err := b.buildPath.ReadDir()
+	if err != nil {
+		return nil, err
+	}
+	files.FilterOutDirs()
+	files.Sort()
+
+	replacements := map[string]string{}
+	if normalize {
+		replacements[b.buildPath.String()] = "{build.path}"
+		if b.sketch != nil {
+			replacements[b.sketch.FullPath.String()] = "{sketch.path}"
+		}
+	}
+
+	res := []*ArtifactChecksum{}
+	for _, file := range files {
+		if !strings.HasPrefix(file.Base(), projectName+".") || !file.HasSuffix(primaryArtifactsExtensions...) {
+			continue
+		}
+		data, err := file.ReadFile()
+		if err != nil {
+			return nil, err
+		}
+		data = normalizeArtifact(data, replacements)
+		sum := sha256.Sum256(data)
+		res = append(res, &ArtifactChecksum{File: file.Base(), SHA256: hex.EncodeToString(sum[:])})
+	}
+	return res, nil
+}
+
+// normalizeArtifact replaces the given strings in the data, longest first to
+// correctly handle nested paths
+func normalizeArtifact(data []byte, replacements map[string]string) []byte {
+	olds := make([]string, 0, len(replacements))
+	for old := range replacements {
+		olds = append(olds, old)
+	}
+	sort.Slice(olds, func(i, j int) bool { return len(olds[i]) > len(olds[j]) })
+	for _, old := range olds {
+		data = bytes.ReplaceAll(data, []byte(old), []byte(replacements[old]))
+	}
+	return data
+}
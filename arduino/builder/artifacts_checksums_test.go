@@ -0,0 +1,91 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2023 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package builder
+
+import (
+	"io"
+	"os/exec"
+	"testing"
+
+	"github.com/arduino/arduino-cli/arduino/builder/internal/logger"
+	"github.com/arduino/arduino-cli/arduino/builder/internal/progress"
+	"github.com/arduino/go-paths-helper"
+	"github.com/arduino/go-properties-orderedmap"
+	"github.com/stretchr/testify/require"
+)
+
+func TestArtifactsChecksums(t *testing.T) {
+	gpp, err := exec.LookPath("g++")
+	if err != nil {
+		t.Skip("g++ not available")
+	}
+
+	build := func(buildTime string, reproducible bool) []*ArtifactChecksum {
+		buildPath := paths.New(t.TempDir())
+		source := buildPath.Join("sketch", "sketch.ino.cpp")
+		require.NoError(t, source.Parent().MkdirAll())
+		require.NoError(t, source.WriteFile([]byte("const char *file = __FILE__;\nlong buildTime = BUILD_TIME;\n")))
+		require.NoError(t, buildPath.Join("sketch.ino.map").WriteFile([]byte(buildTime)))
+
+		buildProperties := properties.NewMap()
+		buildProperties.SetPath("build.path", buildPath)
+		buildProperties.Set("build.project_name", "sketch.ino")
+		buildProperties.Set("extra.time.utc", buildTime)
+		buildProperties.Set("recipe.c.combine.pattern", `"`+gpp+`" -c -DBUILD_TIME={extra.time.utc} "{build.path}/sketch/sketch.ino.cpp" -o "{build.path}/{build.project_name}.elf"`)
+		b := &Builder{
+			buildProperties: buildProperties,
+			buildPath:       buildPath,
+			buildArtifacts:  &buildArtifacts{},
+			logger:          logger.New(io.Discard, io.Discard, false, ""),
+			Progress:        progress.New(nil),
+		}
+		b.SetReproducibleBuild(reproducible)
+		require.NoError(t, b.RunRecipe("recipe.c.combine", ".pattern", false))
+
+		checksums, err := b.ArtifactsChecksums(true)
+		require.NoError(t, err)
+		require.Len(t, checksums, 1)
+		require.Equal(t, "sketch.ino.elf", checksums[0].File)
+		require.Len(t, checksums[0].SHA256, 64)
+
+		raw, err := b.ArtifactsChecksums(false)
+		require.NoError(t, err)
+		require.NotEqual(t, raw, checksums, "the build path is embedded in the artifact")
+		return checksums
+	}
+
+	require.Equal(t, build("1700000000", true), build("1800000000", true))
+	require.NotEqual(t, build("1700000000", false), build("1800000000", false))
+}
+
+func TestSetReproducibleBuild(t *testing.T) {
+	t.Setenv("SOURCE_DATE_EPOCH", "1600000000")
+	b := &Builder{buildProperties: properties.NewFromHashmap(map[string]string{
+		"extra.time.utc":   "1700000000",
+		"extra.time.local": "1700003600",
+		"extra.time.zone":  "3600",
+		"extra.time.dst":   "0",
+	})}
+	b.SetReproducibleBuild(true)
+	require.Equal(t, "1600000000", b.buildProperties.Get("extra.time.utc"))
+	require.Equal(t, "1600000000", b.buildProperties.Get("extra.time.local"))
+	require.Equal(t, "0", b.buildProperties.Get("extra.time.zone"))
+
+	b.SetReproducibleBuild(false)
+	require.Equal(t, "1700000000", b.buildProperties.Get("extra.time.utc"))
+	require.Equal(t, "1700003600", b.buildProperties.Get("extra.time.local"))
+	require.Equal(t, "3600", b.buildProperties.Get("extra.time.zone"))
+}
@@ -0,0 +1,134 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2023 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package builder
+
+import (
+	"bytes"
+	"slices"
+	"strings"
+
+	"github.com/arduino/arduino-cli/arduino/builder/internal/utils"
+	"github.com/arduino/arduino-cli/executils"
+	f "github.com/arduino/arduino-cli/internal/algorithms"
+	"github.com/arduino/go-paths-helper"
+	"github.com/arduino/go-properties-orderedmap"
+	"github.com/pkg/errors"
+)
+
+// SetAssemblyListings enables the generation of the assembly listing (.s file)
+// of each compiled C/C++ source file. The listings are saved in the given
+// directory, mirroring the layout of the build path, or next to the object
+// files if dir is nil.
+func (b *Builder) SetAssemblyListings(enabled bool, dir *paths.Path) {
+	b.assemblyListings = enabled
+	b.assemblyListingsDir = dir
+}
+
+// AssemblyListings returns the paths of the assembly listings produced by the
+// last build.
+func (b *Builder) AssemblyListings() paths.PathList {
+	b.assemblyListingsMux.Lock()
+	defer b.assemblyListingsMux.Unlock()
+	res := b.assemblyListingsFiles.Clone()
+	res.Sort()
+	return res
+}
+
+// assemblyListingPath returns the path of the assembly listing of the given object file
+func (b *Builder) assemblyListingPath(objectFile *paths.Path) (*paths.Path, error) {
+	listing := paths.New(strings.TrimSuffix(objectFile.String(), ".o") + ".s")
+	if b.assemblyListingsDir == nil {
+		return listing, nil
+	}
+	rel, err := b.buildPath.RelTo(listing)
+	if err != nil {
+		return nil, err
+	}
+	return b.assemblyListingsDir.JoinPath(rel), nil
+}
+
+// compileAssemblyListing runs the compile recipe of the given source file again
+// producing the assembly listing instead of the object file. The dependency
+// file and the object file of the normal build are left untouched.
+func (b *Builder) compileAssemblyListing(properties *properties.Map, recipe string, source, objectFile *paths.Path) error {
+	if ext := source.Ext(); ext == ".S" || ext == ".s" {
+		// Already an assembly source
+		return nil
+	}
+	listing, err := b.assemblyListingPath(objectFile)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	if err := listing.Parent().MkdirAll(); err != nil {
+		return errors.WithStack(err)
+	}
+
+	if listingIsUpToDate(listing, objectFile) {
+		b.addAssemblyListing(listing)
+		return nil
+	}
+
+	listingProperties := properties.Clone()
+	listingProperties.SetPath("object_file", listing)
+	command, err := b.prepareCommandForRecipe(listingProperties, recipe, false)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	args := f.Filter(command.GetArgs(), func(arg string) bool {
+		return arg != "-c" && arg != "-MMD" && arg != "-MD"
+	})
+	args = append(args, "-S")
+	if slices.Contains(args, "-flto") {
+		// with LTO the output would be the intermediate representation
+		args = append(args, "-fno-lto")
+	}
+	listingCommand, err := executils.NewProcess(nil, args...)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	listingCommand.SetDir(command.GetDir())
+
+	if b.logger.Verbose() {
+		b.logger.Info(utils.PrintableCommand(listingCommand.GetArgs()))
+	}
+	commandStderr := &bytes.Buffer{}
+	listingCommand.RedirectStderrTo(commandStderr)
+	if err := b.runCommand(listingCommand); err != nil {
+		b.logger.WriteStderr(commandStderr.Bytes())
+		return errors.WithStack(err)
+	}
+	b.addAssemblyListing(listing)
+	return nil
+}
+
+// listingIsUpToDate returns true if the listing is newer than the object file
+func listingIsUpToDate(listing, objectFile *paths.Path) bool {
+	listingStat, err := listing.Stat()
+	if err != nil {
+		return false
+	}
+	objectFileStat, err := objectFile.Stat()
+	if err != nil {
+		return false
+	}
+	return !objectFileStat.ModTime().After(listingStat.ModTime())
+}
+
+func (b *Builder) addAssemblyListing(listing *paths.Path) {
+	b.assemblyListingsMux.Lock()
+	b.assemblyListingsFiles.Add(listing)
+	b.assemblyListingsMux.Unlock()
+}
@@ -0,0 +1,86 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2023 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package builder
+
+import (
+	"io"
+	"os/exec"
+	"testing"
+
+	"github.com/arduino/arduino-cli/arduino/builder/internal/logger"
+	"github.com/arduino/go-paths-helper"
+	"github.com/arduino/go-properties-orderedmap"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAssemblyListings(t *testing.T) {
+	gpp, err := exec.LookPath("g++")
+	if err != nil {
+		t.Skip("g++ not available")
+	}
+	sketchDir := paths.New(t.TempDir())
+	source := sketchDir.Join("sketch.ino.cpp")
+	require.NoError(t, source.WriteFile([]byte("int twice(int a) { return a * 2; }\n")))
+	buildPath := paths.New(t.TempDir())
+	sketchBuildPath := buildPath.Join("sketch")
+
+	buildProperties := properties.NewMap()
+	buildProperties.Set("recipe.cpp.o.pattern", `"`+gpp+`" -c -MMD {includes} "{source_file}" -o "{object_file}"`)
+	newBuilder := func() *Builder {
+		return &Builder{
+			buildProperties: buildProperties,
+			buildPath:       buildPath,
+			logger:          logger.New(io.Discard, io.Discard, false, ""),
+		}
+	}
+
+	t.Run("NextToObjectFiles", func(t *testing.T) {
+		b := newBuilder()
+		b.SetAssemblyListings(true, nil)
+		objectFile, err := b.compileFileWithRecipe(sketchDir, source, sketchBuildPath, nil, "recipe.cpp.o.pattern")
+		require.NoError(t, err)
+		require.Equal(t, sketchBuildPath.Join("sketch.ino.cpp.o"), objectFile)
+		require.FileExists(t, objectFile.String())
+
+		listing := sketchBuildPath.Join("sketch.ino.cpp.s")
+		require.Equal(t, paths.PathList{listing}, b.AssemblyListings())
+		asm, err := listing.ReadFile()
+		require.NoError(t, err)
+		require.Contains(t, string(asm), "twice")
+
+		// The dependency file still refers to the object file
+		deps, err := sketchBuildPath.Join("sketch.ino.cpp.d").ReadFile()
+		require.NoError(t, err)
+		require.Contains(t, string(deps), "sketch.ino.cpp.o:")
+	})
+
+	t.Run("CustomDirectory", func(t *testing.T) {
+		listingsDir := paths.New(t.TempDir())
+		b := newBuilder()
+		b.SetAssemblyListings(true, listingsDir)
+		_, err := b.compileFileWithRecipe(sketchDir, source, sketchBuildPath, nil, "recipe.cpp.o.pattern")
+		require.NoError(t, err)
+		require.Equal(t, paths.PathList{listingsDir.Join("sketch", "sketch.ino.cpp.s")}, b.AssemblyListings())
+		require.FileExists(t, listingsDir.Join("sketch", "sketch.ino.cpp.s").String())
+	})
+
+	t.Run("Disabled", func(t *testing.T) {
+		b := newBuilder()
+		_, err := b.compileFileWithRecipe(sketchDir, source, sketchBuildPath, nil, "recipe.cpp.o.pattern")
+		require.NoError(t, err)
+		require.Empty(t, b.AssemblyListings())
+	})
+}
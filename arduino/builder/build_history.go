@@ -0,0 +1,155 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2023 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package builder
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"sync"
+	"time"
+
+	"github.com/arduino/arduino-cli/arduino/sketch"
+	"github.com/arduino/go-paths-helper"
+)
+
+// maxBuildHistoryRecords is the number of builds kept in the history for each FQBN and sketch
+const maxBuildHistoryRecords = 5
+
+// maxBuildHistoryKeys is the number of FQBN and sketch pairs kept in the
+// history, the least recently built ones are evicted first
+const maxBuildHistoryKeys = 100
+
+// BuildRecord contains the duration of the stages of a completed build
+type BuildRecord struct {
+	Date   time.Time                `json:"date"`
+	Stages map[string]time.Duration `json:"stages"`
+	Total  time.Duration            `json:"total"`
+}
+
+// BuildHistory keeps the durations of the past builds on disk, to estimate
+// the duration of the next builds. The history is keyed by FQBN and by the
+// hash of the sketch sources and is never shared outside the local machine.
+type BuildHistory struct {
+	file    *paths.Path
+	lock    sync.Mutex
+	Records map[string][]*BuildRecord `json:"records"`
+}
+
+// LoadBuildHistory loads the build history from the given file. If the file
+// doesn't exist an empty history is returned.
+func LoadBuildHistory(file *paths.Path) (*BuildHistory, error) {
+	history := &BuildHistory{file: file, Records: map[string][]*BuildRecord{}}
+	if file.NotExist() {
+		return history, nil
+	}
+	data, err := file.ReadFile()
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, history); err != nil {
+		return nil, err
+	}
+	if history.Records == nil {
+		history.Records = map[string][]*BuildRecord{}
+	}
+	return history, nil
+}
+
+// buildHistoryKey returns the key used to store the builds of the given sketch for the given FQBN
+func buildHistoryKey(fqbn string, sk *sketch.Sketch) string {
+	hash := sha256.New()
+	files := paths.PathList{sk.MainFile}
+	files.AddAll(sk.OtherSketchFiles)
+	files.AddAll(sk.AdditionalFiles)
+	for _, file := range files {
+		hash.Write([]byte(file.String()))
+		if data, err := file.ReadFile(); err == nil {
+			hash.Write(data)
+		}
+	}
+	return fqbn + "|" + hex.EncodeToString(hash.Sum(nil))
+}
+
+// Record adds a completed build of the given sketch to the history and
+// saves the history to disk.
+func (h *BuildHistory) Record(fqbn string, sk *sketch.Sketch, stages map[string]time.Duration) error {
+	record := &BuildRecord{Date: time.Now(), Stages: stages}
+	for _, duration := range stages {
+		record.Total += duration
+	}
+
+	h.lock.Lock()
+	defer h.lock.Unlock()
+	key := buildHistoryKey(fqbn, sk)
+	records := append(h.Records[key], record)
+	if len(records) > maxBuildHistoryRecords {
+		records = records[len(records)-maxBuildHistoryRecords:]
+	}
+	h.Records[key] = records
+	h.evictOldestKeys()
+
+	data, err := json.MarshalIndent(h, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := h.file.Parent().MkdirAll(); err != nil {
+		return err
+	}
+	return h.file.WriteFile(data)
+}
+
+// evictOldestKeys removes the least recently recorded FQBN and sketch pairs
+// until at most maxBuildHistoryKeys are left. The caller must hold the lock.
+func (h *BuildHistory) evictOldestKeys() {
+	for len(h.Records) > maxBuildHistoryKeys {
+		oldestKey := ""
+		var oldestDate time.Time
+		for key, records := range h.Records {
+			var lastDate time.Time
+			if len(records) > 0 {
+				lastDate = records[len(records)-1].Date
+			}
+			if oldestKey == "" || lastDate.Before(oldestDate) {
+				oldestKey, oldestDate = key, lastDate
+			}
+		}
+		delete(h.Records, oldestKey)
+	}
+}
+
+// EstimateBuildTime returns the expected duration of the build of the given
+// sketch for the given FQBN, computed as the average of the recorded builds.
+// Zero is returned if there are no builds in the history.
+func (h *BuildHistory) EstimateBuildTime(fqbn string, sk *sketch.Sketch) time.Duration {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+	records := h.Records[buildHistoryKey(fqbn, sk)]
+	if len(records) == 0 {
+		return 0
+	}
+	var total time.Duration
+	for _, record := range records {
+		total += record.Total
+	}
+	return total / time.Duration(len(records))
+}
+
+// SetBuildHistory enables the recording of the durations of the build stages
+// in the given history.
+func (b *Builder) SetBuildHistory(history *BuildHistory) {
+	b.buildHistory = history
+}
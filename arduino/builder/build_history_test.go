@@ -0,0 +1,91 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2023 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package builder
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/arduino/arduino-cli/arduino/sketch"
+	"github.com/arduino/go-paths-helper"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuildHistory(t *testing.T) {
+	sketchDir := paths.New(t.TempDir(), "Blink")
+	require.NoError(t, sketchDir.MkdirAll())
+	require.NoError(t, sketchDir.Join("Blink.ino").WriteFile([]byte("void setup() {}\nvoid loop() {}\n")))
+	sk, err := sketch.New(sketchDir)
+	require.NoError(t, err)
+
+	historyFile := paths.New(t.TempDir(), "build_history.json")
+	history, err := LoadBuildHistory(historyFile)
+	require.NoError(t, err)
+	require.Zero(t, history.EstimateBuildTime("arduino:avr:uno", sk))
+
+	require.NoError(t, history.Record("arduino:avr:uno", sk, map[string]time.Duration{
+		"preprocess": 2 * time.Second,
+		"build":      8 * time.Second,
+	}))
+	require.NoError(t, history.Record("arduino:avr:uno", sk, map[string]time.Duration{
+		"preprocess": 3 * time.Second,
+		"build":      11 * time.Second,
+	}))
+	estimate := history.EstimateBuildTime("arduino:avr:uno", sk)
+	require.GreaterOrEqual(t, estimate, 10*time.Second)
+	require.LessOrEqual(t, estimate, 14*time.Second)
+	require.Zero(t, history.EstimateBuildTime("arduino:avr:mega", sk))
+
+	// The history is persisted
+	history, err = LoadBuildHistory(historyFile)
+	require.NoError(t, err)
+	require.Equal(t, estimate, history.EstimateBuildTime("arduino:avr:uno", sk))
+
+	// Only the latest builds are kept
+	for i := 0; i < maxBuildHistoryRecords; i++ {
+		require.NoError(t, history.Record("arduino:avr:uno", sk, map[string]time.Duration{"build": time.Second}))
+	}
+	require.Equal(t, time.Second, history.EstimateBuildTime("arduino:avr:uno", sk))
+
+	// Changing the sources invalidates the history
+	require.NoError(t, sketchDir.Join("Blink.ino").WriteFile([]byte("void setup() {}\nvoid loop() { delay(1); }\n")))
+	require.Zero(t, history.EstimateBuildTime("arduino:avr:uno", sk))
+}
+
+func TestBuildHistoryEvictsOldestKeys(t *testing.T) {
+	sketchDir := paths.New(t.TempDir(), "Blink")
+	require.NoError(t, sketchDir.MkdirAll())
+	require.NoError(t, sketchDir.Join("Blink.ino").WriteFile([]byte("void setup() {}\nvoid loop() {}\n")))
+	sk, err := sketch.New(sketchDir)
+	require.NoError(t, err)
+
+	history, err := LoadBuildHistory(paths.New(t.TempDir(), "build_history.json"))
+	require.NoError(t, err)
+	for i := 0; i < maxBuildHistoryKeys; i++ {
+		require.NoError(t, history.Record(fmt.Sprintf("test:arch:board%d", i), sk, map[string]time.Duration{"build": time.Second}))
+	}
+	require.Len(t, history.Records, maxBuildHistoryKeys)
+
+	// Building again the first board makes the second one the least recent
+	require.NoError(t, history.Record("test:arch:board0", sk, map[string]time.Duration{"build": time.Second}))
+	require.NoError(t, history.Record("test:arch:new", sk, map[string]time.Duration{"build": time.Second}))
+	require.Len(t, history.Records, maxBuildHistoryKeys)
+	require.NotZero(t, history.EstimateBuildTime("test:arch:board0", sk))
+	require.Zero(t, history.EstimateBuildTime("test:arch:board1", sk))
+	require.NotZero(t, history.EstimateBuildTime("test:arch:board2", sk))
+	require.NotZero(t, history.EstimateBuildTime("test:arch:new", sk))
+}
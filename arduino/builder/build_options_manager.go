@@ -169,5 +169,34 @@ func (b *Builder) wipeBuildPathIfBuildOptionsChanged() error {
 		}
 	}
 
+	// If only the custom build properties are changed the core may not be
+	// affected: keep the core build folder, it's rebuilt only if the properties
+	// used by the core are changed (see coreBuildFingerprint).
+	if !currentOptions.Equals(prevOpts) && b.coreBuildPath != nil {
+		currentOptions.Remove("customBuildProperties")
+		prevOpts.Remove("customBuildProperties")
+		if currentOptions.Equals(prevOpts) {
+			return b.wipeBuildPathExcept(b.coreBuildPath)
+		}
+	}
+
 	return b.wipeBuildPath()
 }
+
+// wipeBuildPathExcept removes all the contents of the build path except the
+// given folder.
+func (b *Builder) wipeBuildPathExcept(keep *paths.Path) error {
+	files, err := b.buildOptions.buildPath.ReadDir()
+	if err != nil {
+		return errors.WithMessage(err, tr("cleaning build path"))
+	}
+	for _, file := range files {
+		if file.EquivalentTo(keep) {
+			continue
+		}
+		if err := file.RemoveAll(); err != nil {
+			return errors.WithMessage(err, tr("cleaning build path"))
+		}
+	}
+	return nil
+}
@@ -0,0 +1,97 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2023 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package builder
+
+// Names of the build steps that are not recipes, the other steps are named
+// after the recipes they run (for example "recipe.hooks.core.prebuild")
+const (
+	BuildStepSketch    = "sketch"
+	BuildStepLibraries = "libraries"
+	BuildStepCore      = "core"
+	BuildStepLink      = "link"
+)
+
+// Reasons why a build step has been skipped
+const (
+	StepSkippedNoRecipe                = "no recipe defined"
+	StepSkippedUpToDate                = "up to date"
+	StepSkippedCachedCore              = "precompiled core used"
+	StepSkippedCompilationDatabaseOnly = "only updating the compilation database"
+	StepFailed                         = "failed"
+)
+
+// BuildStepOutcome reports if a step of the build has run some command and, if
+// not, why it has been skipped
+type BuildStepOutcome struct {
+	Step     string
+	Executed bool
+	// Reason is one of the StepSkipped constants, or StepFailed, if the step
+	// has not been executed
+	Reason string
+}
+
+// BuildSteps returns the outcome of the steps of the last build, in the order
+// they have been run. The steps following a failed step are not reported.
+func (b *Builder) BuildSteps() []*BuildStepOutcome {
+	return append([]*BuildStepOutcome{}, b.buildSteps...)
+}
+
+// runBuildStep runs the given build step and records its outcome: the step is
+// considered executed if it runs at least one command, otherwise skipReason is
+// used to tell why it has been skipped.
+func (b *Builder) runBuildStep(step string, run func() error, skipReason func() string) error {
+	before := b.commandsCount.Load()
+	err := run()
+	outcome := &BuildStepOutcome{Step: step, Executed: b.commandsCount.Load() > before}
+	if err != nil {
+		outcome.Reason = StepFailed
+	} else if !outcome.Executed {
+		outcome.Reason = skipReason()
+	}
+	b.buildSteps = append(b.buildSteps, outcome)
+	return err
+}
+
+// runRecipeStep runs the recipes matching prefix and suffix as a build step
+func (b *Builder) runRecipeStep(prefix, suffix string, skipIfOnlyUpdatingCompilationDatabase bool) error {
+	return b.runBuildStep(prefix, func() error {
+		return b.RunRecipe(prefix, suffix, skipIfOnlyUpdatingCompilationDatabase)
+	}, func() string {
+		if len(findRecipes(b.buildProperties, prefix, suffix)) == 0 {
+			return StepSkippedNoRecipe
+		}
+		return StepSkippedCompilationDatabaseOnly
+	})
+}
+
+func (b *Builder) compileStepSkipReason() string {
+	if b.onlyUpdateCompilationDatabase {
+		return StepSkippedCompilationDatabaseOnly
+	}
+	return StepSkippedUpToDate
+}
+
+func (b *Builder) coreStepSkipReason() string {
+	if b.onlyUpdateCompilationDatabase {
+		return StepSkippedCompilationDatabaseOnly
+	}
+	if archive := b.buildArtifacts.coreArchiveFilePath; archive != nil {
+		if !archive.Parent().EquivalentTo(b.coreBuildPath) {
+			return StepSkippedCachedCore
+		}
+	}
+	return StepSkippedUpToDate
+}
@@ -0,0 +1,95 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2023 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package builder
+
+import (
+	"io"
+	"os/exec"
+	"testing"
+
+	"github.com/arduino/arduino-cli/arduino/builder/internal/logger"
+	"github.com/arduino/arduino-cli/arduino/builder/internal/progress"
+	"github.com/arduino/go-paths-helper"
+	"github.com/arduino/go-properties-orderedmap"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuildStepsOutcome(t *testing.T) {
+	gpp, err := exec.LookPath("g++")
+	if err != nil {
+		t.Skip("g++ not available")
+	}
+	ar, err := exec.LookPath("ar")
+	if err != nil {
+		t.Skip("ar not available")
+	}
+
+	tmp := paths.New(t.TempDir())
+	platformDir := tmp.Join("hardware", "test", "avr")
+	coreDir := platformDir.Join("cores", "arduino")
+	require.NoError(t, coreDir.MkdirAll())
+	require.NoError(t, coreDir.Join("core.cpp").WriteFile([]byte("int core() { return 0; }\n")))
+	coreBuildCachePath := tmp.Join("cache")
+
+	newBuilder := func() *Builder {
+		buildPath := paths.New(t.TempDir())
+		buildProperties := properties.NewMap()
+		buildProperties.Set("build.fqbn", "test:avr:board")
+		buildProperties.SetPath("build.core.path", coreDir)
+		buildProperties.SetPath("runtime.platform.path", platformDir)
+		buildProperties.Set("recipe.cpp.o.pattern", `"`+gpp+`" -c -MMD {includes} "{source_file}" -o "{object_file}"`)
+		buildProperties.Set("recipe.ar.pattern", `"`+ar+`" rcs "{archive_file_path}" "{object_file}"`)
+		buildProperties.Set("recipe.hooks.core.prebuild.1.pattern", `"`+gpp+`" --version`)
+		return &Builder{
+			buildProperties:    buildProperties,
+			buildPath:          buildPath,
+			coreBuildPath:      buildPath.Join("core"),
+			coreBuildCachePath: coreBuildCachePath,
+			buildArtifacts:     &buildArtifacts{},
+			logger:             logger.New(io.Discard, io.Discard, false, ""),
+			Progress:           progress.New(nil),
+		}
+	}
+	runSteps := func(b *Builder) []*BuildStepOutcome {
+		require.NoError(t, b.runRecipeStep("recipe.hooks.core.prebuild", ".pattern", false))
+		require.NoError(t, b.runBuildStep(BuildStepCore, b.buildCore, b.coreStepSkipReason))
+		require.NoError(t, b.runRecipeStep("recipe.hooks.core.postbuild", ".pattern", true))
+		return b.BuildSteps()
+	}
+
+	// The core is compiled and cached
+	b := newBuilder()
+	require.Equal(t, []*BuildStepOutcome{
+		{Step: "recipe.hooks.core.prebuild", Executed: true},
+		{Step: BuildStepCore, Executed: true},
+		{Step: "recipe.hooks.core.postbuild", Reason: StepSkippedNoRecipe},
+	}, runSteps(b))
+
+	// The core is already compiled in the build path
+	b.buildSteps = nil
+	b.coreBuildCachePath = nil
+	require.Equal(t, &BuildStepOutcome{Step: BuildStepCore, Reason: StepSkippedUpToDate}, runSteps(b)[1])
+
+	// A new build uses the cached core
+	b = newBuilder()
+	require.Equal(t, &BuildStepOutcome{Step: BuildStepCore, Reason: StepSkippedCachedCore}, runSteps(b)[1])
+
+	// A failing step is reported as failed
+	b = newBuilder()
+	b.buildProperties.Set("recipe.hooks.core.postbuild.1.pattern", `"`+gpp+`" --invalid-option`)
+	require.Error(t, b.runRecipeStep("recipe.hooks.core.postbuild", ".pattern", true))
+	require.Equal(t, []*BuildStepOutcome{{Step: "recipe.hooks.core.postbuild", Executed: true, Reason: StepFailed}}, b.BuildSteps())
+}
@@ -0,0 +1,147 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2023 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package builder
+
+import (
+	"github.com/arduino/arduino-cli/arduino/libraries"
+)
+
+// BuildSummary is a machine readable summary of a completed build. The sizes
+// that could not be determined are set to -1.
+type BuildSummary struct {
+	// ProgramSize is the size of the program (flash) in bytes
+	ProgramSize int `json:"program_size"`
+	// MaxProgramSize is the maximum program size allowed by the board
+	MaxProgramSize int `json:"max_program_size"`
+	// FreeProgramSize is the flash space left free by the sketch
+	FreeProgramSize int `json:"free_program_size"`
+	// DataSize is the size of the global variables (RAM) in bytes
+	DataSize int `json:"data_size"`
+	// MaxDataSize is the maximum data size allowed by the board
+	MaxDataSize int `json:"max_data_size"`
+	// FreeDataSize is the RAM left free for local variables
+	FreeDataSize int `json:"free_data_size"`
+	// EepromSize is the size of the EEPROM section in bytes
+	EepromSize int `json:"eeprom_size"`
+	// Sections are the sizes of the executable sections
+	Sections ExecutablesFileSections `json:"sections"`
+	// Libraries are the libraries used in the build
+	Libraries []*BuildSummaryLibrary `json:"libraries"`
+	// CoreArchiveFile is the path of the compiled core archive (core.a)
+	CoreArchiveFile string `json:"core_archive_file"`
+	// Toolchains are the compilers used in the build
+	Toolchains []*BuildSummaryToolchain `json:"toolchains"`
+	// MapFile is the parsed map file produced by the linker, nil if the map
+	// file parsing is disabled (see SetMapFileParsing)
+	MapFile *MapFile `json:"map_file,omitempty"`
+}
+
+// BuildSummaryToolchain is a compiler used in a build
+type BuildSummaryToolchain struct {
+	Recipe  string `json:"recipe"`
+	Path    string `json:"path"`
+	Version string `json:"version"`
+	Banner  string `json:"banner"`
+}
+
+// BuildSummaryLibrary is a library used in a build
+type BuildSummaryLibrary struct {
+	Name       string `json:"name"`
+	Version    string `json:"version"`
+	InstallDir string `json:"install_dir"`
+}
+
+func newBuildSummary() *BuildSummary {
+	return &BuildSummary{
+		ProgramSize:     -1,
+		MaxProgramSize:  -1,
+		FreeProgramSize: -1,
+		DataSize:        -1,
+		MaxDataSize:     -1,
+		FreeDataSize:    -1,
+		EepromSize:      -1,
+		Sections:        ExecutablesFileSections{},
+		Libraries:       []*BuildSummaryLibrary{},
+		Toolchains:      []*BuildSummaryToolchain{},
+	}
+}
+
+// setSizes fills the sizes of the summary, the negative values are unknown sizes.
+func (s *BuildSummary) setSizes(programSize, maxProgramSize, dataSize, maxDataSize, eepromSize int) {
+	s.ProgramSize, s.MaxProgramSize = programSize, maxProgramSize
+	s.DataSize, s.MaxDataSize = dataSize, maxDataSize
+	s.EepromSize = eepromSize
+	if programSize >= 0 && maxProgramSize > 0 {
+		s.FreeProgramSize = maxProgramSize - programSize
+	}
+	if dataSize >= 0 && maxDataSize > 0 {
+		s.FreeDataSize = maxDataSize - dataSize
+	}
+}
+
+// setSections fills the sections of the summary. If the program and data sizes
+// are not already known they are taken from the "text" and "data" sections.
+func (s *BuildSummary) setSections(sections ExecutablesFileSections) {
+	if sections == nil {
+		sections = ExecutablesFileSections{}
+	}
+	s.Sections = sections
+	if s.ProgramSize != -1 || s.DataSize != -1 {
+		return
+	}
+	programSize, maxProgramSize, dataSize, maxDataSize := -1, -1, -1, -1
+	for _, section := range sections {
+		switch section.Name {
+		case "text":
+			programSize, maxProgramSize = section.Size, section.MaxSize
+		case "data":
+			dataSize, maxDataSize = section.Size, section.MaxSize
+		}
+	}
+	s.setSizes(programSize, maxProgramSize, dataSize, maxDataSize, -1)
+}
+
+func (s *BuildSummary) setLibraries(libs libraries.List) {
+	s.Libraries = []*BuildSummaryLibrary{}
+	for _, lib := range libs {
+		summaryLib := &BuildSummaryLibrary{Name: lib.Name}
+		if lib.Version != nil {
+			summaryLib.Version = lib.Version.String()
+		}
+		if lib.InstallDir != nil {
+			summaryLib.InstallDir = lib.InstallDir.String()
+		}
+		s.Libraries = append(s.Libraries, summaryLib)
+	}
+}
+
+func (s *BuildSummary) setToolchains(versions []*ToolchainVersion) {
+	s.Toolchains = []*BuildSummaryToolchain{}
+	for _, toolchain := range versions {
+		s.Toolchains = append(s.Toolchains, &BuildSummaryToolchain{
+			Recipe:  toolchain.Recipe,
+			Path:    toolchain.Path.String(),
+			Version: toolchain.Version,
+			Banner:  toolchain.Banner,
+		})
+	}
+}
+
+// BuildSummary returns the summary of the last build, or nil if the build
+// has not been completed yet.
+func (b *Builder) BuildSummary() *BuildSummary {
+	return b.buildSummary
+}
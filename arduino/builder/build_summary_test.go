@@ -0,0 +1,100 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2023 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package builder
+
+import (
+	"io"
+	"runtime"
+	"testing"
+
+	"github.com/arduino/arduino-cli/arduino/builder/internal/logger"
+	"github.com/arduino/arduino-cli/arduino/libraries"
+	"github.com/arduino/go-paths-helper"
+	"github.com/arduino/go-properties-orderedmap"
+	"github.com/stretchr/testify/require"
+	semver "go.bug.st/relaxed-semver"
+)
+
+func TestBuildSummary(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("the size recipe uses cat")
+	}
+	buildPath := paths.New(t.TempDir())
+	require.NoError(t, buildPath.Join("size.txt").WriteFile([]byte(`sketch.ino.elf  :
+section           size      addr
+.data               36   8388864
+.text             3966         0
+.bss               112   8388900
+.comment            17         0
+Total             4131
+`)))
+
+	buildProperties := properties.NewMap()
+	buildProperties.SetPath("build.path", buildPath)
+	buildProperties.Set("upload.maximum_size", "32256")
+	buildProperties.Set("upload.maximum_data_size", "2048")
+	buildProperties.Set("recipe.size.pattern", `cat "{build.path}/size.txt"`)
+	buildProperties.Set("recipe.size.regex", `^(?:\.text|\.data|\.bootloader)\s+([0-9]+).*`)
+	buildProperties.Set("recipe.size.regex.data", `^(?:\.data|\.bss|\.noinit)\s+([0-9]+).*`)
+	b := &Builder{
+		buildProperties: buildProperties,
+		logger:          logger.New(io.Discard, io.Discard, false, ""),
+	}
+	require.Nil(t, b.BuildSummary())
+	require.NoError(t, b.size())
+
+	summary := b.BuildSummary()
+	require.NotNil(t, summary)
+	require.Equal(t, 4002, summary.ProgramSize)
+	require.Equal(t, 32256, summary.MaxProgramSize)
+	require.Equal(t, 32256-4002, summary.FreeProgramSize)
+	require.Equal(t, 148, summary.DataSize)
+	require.Equal(t, 2048, summary.MaxDataSize)
+	require.Equal(t, 2048-148, summary.FreeDataSize)
+	require.Equal(t, -1, summary.EepromSize)
+	require.Equal(t, ExecutablesFileSections{
+		{Name: "text", Size: 4002, MaxSize: 32256},
+		{Name: "data", Size: 148, MaxSize: 2048},
+	}, summary.Sections)
+
+	summary.setLibraries(libraries.List{
+		{Name: "Servo", Version: semver.MustParse("1.2.0"), InstallDir: paths.New("/libraries/Servo")},
+		{Name: "Unversioned"},
+	})
+	require.Equal(t, []*BuildSummaryLibrary{
+		{Name: "Servo", Version: "1.2.0", InstallDir: paths.New("/libraries/Servo").String()},
+		{Name: "Unversioned"},
+	}, summary.Libraries)
+
+	summary.setToolchains([]*ToolchainVersion{
+		{Recipe: "compiler.cpp.cmd", Path: paths.New("/tools/avr-g++"), Version: "7.3.0", Banner: "avr-g++ (GCC) 7.3.0"},
+	})
+	require.Equal(t, []*BuildSummaryToolchain{
+		{Recipe: "compiler.cpp.cmd", Path: paths.New("/tools/avr-g++").String(), Version: "7.3.0", Banner: "avr-g++ (GCC) 7.3.0"},
+	}, summary.Toolchains)
+}
+
+func TestBuildSummaryFromAdvancedSizerSections(t *testing.T) {
+	summary := newBuildSummary()
+	summary.setSections(ExecutablesFileSections{
+		{Name: "text", Size: 1000, MaxSize: 4000},
+		{Name: "data", Size: 100, MaxSize: 400},
+	})
+	require.Equal(t, 1000, summary.ProgramSize)
+	require.Equal(t, 3000, summary.FreeProgramSize)
+	require.Equal(t, 100, summary.DataSize)
+	require.Equal(t, 300, summary.FreeDataSize)
+}
@@ -22,16 +22,21 @@ import (
 	"os"
 	"path/filepath"
 	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
 
 	"github.com/arduino/arduino-cli/arduino/builder/internal/compilation"
 	"github.com/arduino/arduino-cli/arduino/builder/internal/detector"
 	"github.com/arduino/arduino-cli/arduino/builder/internal/logger"
+	"github.com/arduino/arduino-cli/arduino/builder/internal/preprocessor"
 	"github.com/arduino/arduino-cli/arduino/builder/internal/progress"
 	"github.com/arduino/arduino-cli/arduino/builder/internal/utils"
 	"github.com/arduino/arduino-cli/arduino/cores"
 	"github.com/arduino/arduino-cli/arduino/libraries"
 	"github.com/arduino/arduino-cli/arduino/libraries/librariesmanager"
 	"github.com/arduino/arduino-cli/arduino/sketch"
+	arduinoutils "github.com/arduino/arduino-cli/arduino/utils"
 	"github.com/arduino/arduino-cli/executils"
 	rpc "github.com/arduino/arduino-cli/rpc/cc/arduino/cli/commands/v1"
 	"github.com/arduino/go-paths-helper"
@@ -79,6 +84,75 @@ type Builder struct {
 	// Sizer results
 	executableSectionsSize ExecutablesFileSections
 
+	// Assembly listings generation
+	assemblyListings      bool
+	assemblyListingsDir   *paths.Path
+	assemblyListingsFiles paths.PathList
+	assemblyListingsMux   sync.Mutex
+
+	// Set to true to only print a warning if the sketch doesn't fit the board memory
+	warnOnSizeExceeded bool
+
+	// Linker script that replaces the one specified by the platform, if not nil
+	linkerScript *paths.Path
+
+	// Flags added to the compile and link commands
+	extraFlags []string
+
+	// Set to true to compile the sketch without adding the function prototypes
+	skipPrototypesGeneration bool
+
+	// C++ standard that overrides the one of the platform, and the original
+	// compiler.cpp.flags saved while the override is active
+	cppStandard   string
+	savedCppFlags *string
+
+	// Original build time properties, saved while the build is reproducible
+	savedBuildTimeProperties map[string]string
+
+	// Libraries changed since the previous build
+	librariesChanges *LibrariesChanges
+
+	// Glob patterns of the sketch files that must not be compiled
+	sketchExcludePatterns []string
+
+	// Architecture families used to check the compatibility of the imported libraries
+	architectureFamilies libraries.ArchitectureFamilies
+
+	// Which symlinks are followed while scanning the imported libraries for changes
+	symlinkPolicy arduinoutils.SymlinkPolicy
+
+	// Outcome of the steps of the last build
+	buildSteps    []*BuildStepOutcome
+	commandsCount atomic.Int64
+
+	// Set to true to parse the map file produced by the linker
+	parseMapFile bool
+	mapFile      *MapFile
+
+	// History of the durations of the builds, if nil the durations are not recorded
+	buildHistory *BuildHistory
+
+	// Machine readable summary of the build
+	buildSummary *BuildSummary
+
+	// File where the aggregated dependencies of the build are written, if nil
+	// the dependencies are not written
+	dependenciesFile *paths.Path
+
+	// Versions of the compilers used in the build
+	toolchainVersions []*ToolchainVersion
+
+	// Diagnostics reported by the compiler, and the filter applied before returning them
+	diagnostics       []*Diagnostic
+	diagnosticsMux    sync.Mutex
+	diagnosticsFilter DiagnosticsFilter
+
+	// Commands executed during the build, recorded only if recordCommands is true
+	recordCommands      bool
+	executedCommands    []*ExecutedCommand
+	executedCommandsMux sync.Mutex
+
 	// C++ Parsing
 	lineOffset int
 
@@ -100,6 +174,9 @@ type buildArtifacts struct {
 
 	// populated by BuildLibraries
 	librariesObjectFiles paths.PathList
+	// linker flags declared by the libraries that are not linked as
+	// precompiled libraries, in the order the libraries are compiled
+	librariesLDFlags []string
 
 	// populated by BuildSketch
 	sketchObjectFiles paths.PathList
@@ -174,7 +251,8 @@ func NewBuilder(
 		return nil, ErrSketchCannotBeLocatedInBuildPath
 	}
 
-	logger := logger.New(stdout, stderr, verbose, warningsLevel)
+	logger := logger.New(stdout, stderr, verbose, "")
+	setWarningsLevelOrDefault(logger, warningsLevel)
 	libsManager, libsResolver, verboseOut, err := detector.LibrariesLoader(
 		useCachedLibrariesResolution, librariesManager,
 		builtInLibrariesDirs, libraryDirs, otherLibrariesDirs,
@@ -242,17 +320,119 @@ func (b *Builder) ExecutableSectionsSize() ExecutablesFileSections {
 	return b.executableSectionsSize
 }
 
+// CoreArchiveFile returns the path of the compiled core archive (core.a) that
+// is linked with the sketch. After the core has been built it may point to the
+// global core cache, before that the expected location in the build path is
+// returned.
+func (b *Builder) CoreArchiveFile() *paths.Path {
+	if b.buildArtifacts.coreArchiveFilePath != nil {
+		return b.buildArtifacts.coreArchiveFilePath
+	}
+	return b.coreBuildPath.Join("core.a")
+}
+
+// SetSeparateToolStreams enables the collection of the stdout and stderr of the
+// tools run during the build (compilers, hooks, size, etc.) in two separate
+// buffers, returned by ToolStdout and ToolStderr, and as a sequence of chunks
+// tagged with their stream, returned by ToolOutput. Both streams are always
+// collected, even if the stdout of the tools is displayed only in verbose mode.
+// The output is still displayed as usual.
+func (b *Builder) SetSeparateToolStreams(enable bool) {
+	b.logger.SetSeparateToolStreams(enable)
+}
+
+// ToolStdout returns the stdout of the tools collected during the build
+func (b *Builder) ToolStdout() []byte {
+	return b.logger.ToolStdout()
+}
+
+// ToolStderr returns the stderr of the tools collected during the build
+func (b *Builder) ToolStderr() []byte {
+	return b.logger.ToolStderr()
+}
+
+// ToolOutputChunk is a piece of the output of the tools, tagged with the
+// stream (ToolStdoutStream or ToolStderrStream) it has been written to
+type ToolOutputChunk = logger.ToolOutputChunk
+
+// ToolStream is the output stream of a tool
+type ToolStream = logger.ToolStream
+
+// The output streams of the tools
+const (
+	ToolStdoutStream = logger.ToolStdoutStream
+	ToolStderrStream = logger.ToolStderrStream
+)
+
+// ToolOutput returns the output of the tools collected during the build, in
+// the order it has been written
+func (b *Builder) ToolOutput() []*ToolOutputChunk {
+	return b.logger.ToolOutput()
+}
+
+// LibrariesSearchOrder returns the directories where the libraries are searched
+// for a build with the given parameters (the same passed to NewBuilder), sorted
+// by location priority: when more libraries provide the same header the one in
+// the first directory wins, unless another one has a better matching name or
+// architecture (see the "Dependency resolution" section of the sketch build
+// process documentation).
+func LibrariesSearchOrder(
+	builtInLibrariesDirs *paths.Path, libraryDirs, otherLibrariesDirs paths.PathList,
+	actualPlatform, targetPlatform *cores.PlatformRelease,
+) paths.PathList {
+	return detector.LibrariesSearchOrder(builtInLibrariesDirs, libraryDirs, otherLibrariesDirs, actualPlatform, targetPlatform)
+}
+
 // ImportedLibraries fixdoc
 func (b *Builder) ImportedLibraries() libraries.List {
 	return b.libsDetector.ImportedLibraries()
 }
 
-// Preprocess fixdoc
-func (b *Builder) Preprocess() ([]byte, error) {
+// LibrariesDetectionProgress reports the progress of the libraries detection:
+// the number of source files scanned and the file and library being scanned.
+type LibrariesDetectionProgress = detector.Progress
+
+// SetLibrariesDetectionProgressCallback sets a callback that is called each time
+// a source file is scanned during the libraries detection, and once more at the
+// end of the detection.
+func (b *Builder) SetLibrariesDetectionProgressCallback(cb func(*LibrariesDetectionProgress)) {
+	b.libsDetector.SetProgressCallback(cb)
+}
+
+// MissingLibraryError is returned, if SetFailOnMissingLibrary is enabled, when
+// an included header is not provided by any installed library.
+type MissingLibraryError = detector.MissingLibraryError
+
+// SetFailOnMissingLibrary sets the builder to stop at the first included header
+// not provided by any installed library, returning a MissingLibraryError that
+// suggests the libraries of the Library Manager index providing it, instead of
+// the compiler errors.
+func (b *Builder) SetFailOnMissingLibrary(fail bool) {
+	b.libsDetector.SetFailOnMissingLibrary(fail)
+}
+
+// SetArchitectureFamilies sets the architecture families used to warn about
+// the imported libraries not compatible with the board architecture.
+func (b *Builder) SetArchitectureFamilies(families libraries.ArchitectureFamilies) {
+	b.architectureFamilies = families
+}
+
+// SetSymlinkPolicy sets which symlinks are followed while scanning the
+// imported libraries to detect their changes.
+func (b *Builder) SetSymlinkPolicy(policy arduinoutils.SymlinkPolicy) {
+	b.symlinkPolicy = policy
+}
+
+// Preprocess runs the preprocessing of the sketch and returns the preprocessed
+// source. The optional extraDefines (in the form "NAME" or "NAME=VALUE") are
+// passed only to the preprocessor run on the sketch to generate the function
+// prototypes: the libraries detection, whose result is cached and reused by the
+// following builds, and the compilation are not affected.
+func (b *Builder) Preprocess(extraDefines ...string) ([]byte, error) {
 	b.Progress.AddSubSteps(6)
 	defer b.Progress.RemoveSubSteps()
 
-	if err := b.preprocess(); err != nil {
+	if err := b.preprocess(extraDefines); err != nil {
 		return nil, err
 	}
 
@@ -261,7 +441,7 @@ func (b *Builder) Preprocess() ([]byte, error) {
 	return preprocessedSketch, err
 }
 
-func (b *Builder) preprocess() error {
+func (b *Builder) preprocess(extraDefines []string) error {
 	if err := b.buildPath.MkdirAll(); err != nil {
 		return err
 	}
@@ -303,8 +483,13 @@ func (b *Builder) preprocess() error {
 	b.warnAboutArchIncompatibleLibraries(b.libsDetector.ImportedLibraries())
 	b.Progress.CompleteStep()
 
-	b.logIfVerbose(false, tr("Generating function prototypes..."))
-	if err := b.preprocessSketch(b.libsDetector.IncludeFolders()); err != nil {
+	if b.skipPrototypesGeneration {
+		b.logIfVerbose(false, tr("Skipping generation of function prototypes..."))
+	} else {
+		b.logIfVerbose(false, tr("Generating function prototypes..."))
+	}
+	preprocessBuildProperties := preprocessor.WithExtraDefines(b.buildProperties, extraDefines)
+	if err := b.preprocessSketch(b.libsDetector.IncludeFolders(), preprocessBuildProperties); err != nil {
 		return err
 	}
 	b.Progress.CompleteStep()
@@ -328,11 +513,25 @@ func (b *Builder) Build() error {
 	b.Progress.AddSubSteps(6 /** preprocess **/ + 21 /** build **/)
 	defer b.Progress.RemoveSubSteps()
 
-	if err := b.preprocess(); err != nil {
+	stages := map[string]time.Duration{}
+	stageStart := time.Now()
+	endStage := func(stage string) {
+		now := time.Now()
+		stages[stage] = now.Sub(stageStart)
+		stageStart = now
+	}
+
+	if err := b.preprocess(nil); err != nil {
 		return err
 	}
+	endStage("preprocess")
+
+	b.toolchainVersions = detectToolchainVersions(b.buildProperties)
+	b.warnAboutUnsupportedCppStandard()
 
+	b.buildSteps = nil
 	buildErr := b.build()
+	endStage("build")
 
 	b.libsDetector.PrintUsedAndNotUsedLibraries(buildErr != nil)
 	b.Progress.CompleteStep()
@@ -343,15 +542,41 @@ func (b *Builder) Build() error {
 	if buildErr != nil {
 		return buildErr
 	}
+	if !b.onlyUpdateCompilationDatabase {
+		if err := b.updateLibrariesState(b.libsDetector.ImportedLibraries()); err != nil {
+			b.logIfVerbose(true, tr("Could not save the state of the libraries: %s", err))
+		}
+	}
 	if err := b.exportProjectCMake(b.libsDetector.ImportedLibraries(), b.libsDetector.IncludeFolders()); err != nil {
 		return err
 	}
 	b.Progress.CompleteStep()
+	endStage("export")
 
-	if err := b.size(); err != nil {
-		return err
+	sizeErr := b.size()
+	if b.buildSummary != nil {
+		b.buildSummary.setLibraries(b.libsDetector.ImportedLibraries())
+		b.buildSummary.CoreArchiveFile = b.CoreArchiveFile().String()
+		b.buildSummary.setToolchains(b.toolchainVersions)
+		b.buildSummary.MapFile = b.mapFile
+	}
+	if sizeErr != nil {
+		return sizeErr
 	}
 	b.Progress.CompleteStep()
+	endStage("size")
+
+	if b.dependenciesFile != nil {
+		if err := b.writeDependenciesFile(); err != nil {
+			return err
+		}
+	}
+
+	if b.buildHistory != nil && !b.onlyUpdateCompilationDatabase {
+		if err := b.buildHistory.Record(b.buildProperties.Get("build.fqbn"), b.sketch, stages); err != nil {
+			b.logIfVerbose(true, tr("Could not save build history: %s", err))
+		}
+	}
 
 	return nil
 }
@@ -359,23 +584,25 @@ func (b *Builder) Build() error {
 // Build fixdoc
 func (b *Builder) build() error {
 	b.logIfVerbose(false, tr("Compiling sketch..."))
-	if err := b.RunRecipe("recipe.hooks.sketch.prebuild", ".pattern", false); err != nil {
+	if err := b.runRecipeStep("recipe.hooks.sketch.prebuild", ".pattern", false); err != nil {
 		return err
 	}
 	b.Progress.CompleteStep()
 
-	if err := b.buildSketch(b.libsDetector.IncludeFolders()); err != nil {
+	if err := b.runBuildStep(BuildStepSketch, func() error {
+		return b.buildSketch(b.libsDetector.IncludeFolders())
+	}, b.compileStepSkipReason); err != nil {
 		return err
 	}
 	b.Progress.CompleteStep()
 
-	if err := b.RunRecipe("recipe.hooks.sketch.postbuild", ".pattern", true); err != nil {
+	if err := b.runRecipeStep("recipe.hooks.sketch.postbuild", ".pattern", true); err != nil {
 		return err
 	}
 	b.Progress.CompleteStep()
 
 	b.logIfVerbose(false, tr("Compiling libraries..."))
-	if err := b.RunRecipe("recipe.hooks.libraries.prebuild", ".pattern", false); err != nil {
+	if err := b.runRecipeStep("recipe.hooks.libraries.prebuild", ".pattern", false); err != nil {
 		return err
 	}
 	b.Progress.CompleteStep()
@@ -385,59 +612,61 @@ func (b *Builder) build() error {
 	}
 	b.Progress.CompleteStep()
 
-	if err := b.buildLibraries(b.libsDetector.IncludeFolders(), b.libsDetector.ImportedLibraries()); err != nil {
+	if err := b.runBuildStep(BuildStepLibraries, func() error {
+		return b.buildLibraries(b.libsDetector.IncludeFolders(), b.libsDetector.ImportedLibraries())
+	}, b.compileStepSkipReason); err != nil {
 		return err
 	}
 	b.Progress.CompleteStep()
 
-	if err := b.RunRecipe("recipe.hooks.libraries.postbuild", ".pattern", true); err != nil {
+	if err := b.runRecipeStep("recipe.hooks.libraries.postbuild", ".pattern", true); err != nil {
 		return err
 	}
 	b.Progress.CompleteStep()
 
 	b.logIfVerbose(false, tr("Compiling core..."))
-	if err := b.RunRecipe("recipe.hooks.core.prebuild", ".pattern", false); err != nil {
+	if err := b.runRecipeStep("recipe.hooks.core.prebuild", ".pattern", false); err != nil {
 		return err
 	}
 	b.Progress.CompleteStep()
 
-	if err := b.buildCore(); err != nil {
+	if err := b.runBuildStep(BuildStepCore, b.buildCore, b.coreStepSkipReason); err != nil {
 		return err
 	}
 	b.Progress.CompleteStep()
 
-	if err := b.RunRecipe("recipe.hooks.core.postbuild", ".pattern", true); err != nil {
+	if err := b.runRecipeStep("recipe.hooks.core.postbuild", ".pattern", true); err != nil {
 		return err
 	}
 	b.Progress.CompleteStep()
 
 	b.logIfVerbose(false, tr("Linking everything together..."))
-	if err := b.RunRecipe("recipe.hooks.linking.prelink", ".pattern", false); err != nil {
+	if err := b.runRecipeStep("recipe.hooks.linking.prelink", ".pattern", false); err != nil {
 		return err
 	}
 	b.Progress.CompleteStep()
 
-	if err := b.link(); err != nil {
+	if err := b.runBuildStep(BuildStepLink, b.link, b.compileStepSkipReason); err != nil {
 		return err
 	}
 	b.Progress.CompleteStep()
 
-	if err := b.RunRecipe("recipe.hooks.linking.postlink", ".pattern", true); err != nil {
+	if err := b.runRecipeStep("recipe.hooks.linking.postlink", ".pattern", true); err != nil {
 		return err
 	}
 	b.Progress.CompleteStep()
 
-	if err := b.RunRecipe("recipe.hooks.objcopy.preobjcopy", ".pattern", false); err != nil {
+	if err := b.runRecipeStep("recipe.hooks.objcopy.preobjcopy", ".pattern", false); err != nil {
 		return err
 	}
 	b.Progress.CompleteStep()
 
-	if err := b.RunRecipe("recipe.objcopy.", ".pattern", true); err != nil {
+	if err := b.runRecipeStep("recipe.objcopy.", ".pattern", true); err != nil {
 		return err
 	}
 	b.Progress.CompleteStep()
 
-	if err := b.RunRecipe("recipe.hooks.objcopy.postobjcopy", ".pattern", true); err != nil {
+	if err := b.runRecipeStep("recipe.hooks.objcopy.postobjcopy", ".pattern", true); err != nil {
 		return err
 	}
 	b.Progress.CompleteStep()
@@ -447,7 +676,7 @@ func (b *Builder) build() error {
 	}
 	b.Progress.CompleteStep()
 
-	if err := b.RunRecipe("recipe.hooks.postbuild", ".pattern", true); err != nil {
+	if err := b.runRecipeStep("recipe.hooks.postbuild", ".pattern", true); err != nil {
 		return err
 	}
 	b.Progress.CompleteStep()
@@ -506,12 +735,10 @@ func (b *Builder) execCommand(command *executils.Process) error {
 	if b.logger.Verbose() {
 		b.logger.Info(utils.PrintableCommand(command.GetArgs()))
 		command.RedirectStdoutTo(b.logger.Stdout())
+	} else {
+		command.RedirectStdoutTo(b.logger.ToolStdoutRecorder())
 	}
 	command.RedirectStderrTo(b.logger.Stderr())
 
-	if err := command.Start(); err != nil {
-		return err
-	}
-
-	return command.Wait()
+	return b.runCommand(command)
 }
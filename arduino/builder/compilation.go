@@ -19,6 +19,7 @@ import (
 	"bytes"
 	"fmt"
 	"runtime"
+	"sort"
 	"strings"
 	"sync"
 
@@ -48,15 +49,24 @@ func (b *Builder) compileFiles(
 	defer b.Progress.RemoveSubSteps()
 
 	objectFiles := paths.NewPathList()
-	var objectFilesMux sync.Mutex
 	if len(sources) == 0 {
 		return objectFiles, nil
 	}
-	var errorsList []error
+
+	// The results are stored by source index, so that the reported error and
+	// the compilation database don't depend on the order of completion of the jobs
+	results := make([]*paths.Path, len(sources))
+	errorsList := make([]error, len(sources))
+	gotError := false
 	var errorsMux sync.Mutex
+	dbStart := 0
+	if b.compilationDatabase != nil {
+		dbStart = len(b.compilationDatabase.Contents)
+	}
 
-	queue := make(chan *paths.Path)
-	job := func(source *paths.Path) {
+	queue := make(chan int)
+	job := func(i int) {
+		source := sources[i]
 		recipe := fmt.Sprintf("recipe%s.o.pattern", source.Ext())
 		if !b.buildProperties.ContainsKey(recipe) {
 			recipe = fmt.Sprintf("recipe%s.o.pattern", globals.SourceFilesValidExtensions[source.Ext()])
@@ -64,12 +74,11 @@ func (b *Builder) compileFiles(
 		objectFile, err := b.compileFileWithRecipe(sourceDir, source, buildPath, includes, recipe)
 		if err != nil {
 			errorsMux.Lock()
-			errorsList = append(errorsList, err)
+			errorsList[i] = err
+			gotError = true
 			errorsMux.Unlock()
 		} else {
-			objectFilesMux.Lock()
-			objectFiles.Add(objectFile)
-			objectFilesMux.Unlock()
+			results[i] = objectFile
 		}
 	}
 
@@ -81,35 +90,60 @@ func (b *Builder) compileFiles(
 	for i := 0; i < b.jobs; i++ {
 		wg.Add(1)
 		go func() {
-			for source := range queue {
-				job(source)
+			for i := range queue {
+				job(i)
 			}
 			wg.Done()
 		}()
 	}
 
 	// Feed jobs until error or done
-	for _, source := range sources {
+	for i := range sources {
 		errorsMux.Lock()
-		gotError := len(errorsList) > 0
+		stop := gotError
 		errorsMux.Unlock()
-		if gotError {
+		if stop {
 			break
 		}
-		queue <- source
+		queue <- i
 
 		b.Progress.CompleteStep()
 	}
 	close(queue)
 	wg.Wait()
-	if len(errorsList) > 0 {
-		// output the first error
-		return nil, errors.WithStack(errorsList[0])
+
+	if b.compilationDatabase != nil {
+		b.sortCompilationDatabaseEntries(dbStart, sources)
+	}
+	for _, err := range errorsList {
+		if err != nil {
+			// output the error of the first failed source
+			return nil, errors.WithStack(err)
+		}
+	}
+	for _, objectFile := range results {
+		if objectFile != nil {
+			objectFiles.Add(objectFile)
+		}
 	}
 	objectFiles.Sort()
 	return objectFiles, nil
 }
 
+// sortCompilationDatabaseEntries sorts the compilation database entries added
+// since the given index following the order of the sources, because parallel
+// jobs may add them in any order.
+func (b *Builder) sortCompilationDatabaseEntries(start int, sources paths.PathList) {
+	order := map[string]int{}
+	for i, source := range sources {
+		order[source.String()] = i
+	}
+	entries := b.compilationDatabase.Contents[start:]
+	sort.SliceStable(entries, func(i, j int) bool {
+		return order[entries[i].File] < order[entries[j].File]
+	})
+}
+
 // CompileFilesRecursive fixdoc
 func (b *Builder) compileFileWithRecipe(
 	sourcePath *paths.Path,
@@ -144,6 +178,10 @@ func (b *Builder) compileFileWithRecipe(
 	if err != nil {
 		return nil, errors.WithStack(err)
 	}
+	command, err = b.addExtraFlags(command)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
 	if b.compilationDatabase != nil {
 		b.compilationDatabase.Add(source, command)
 	}
@@ -156,15 +194,15 @@ func (b *Builder) compileFileWithRecipe(
 			b.logger.Info(utils.PrintableCommand(command.GetArgs()))
 		}
 		// Since this compile could be multithreaded, we first capture the command output
-		if err := command.Start(); err != nil {
-			return nil, err
-		}
-		err := command.Wait()
+		err := b.runCommand(command)
 		// and transfer all at once at the end...
 		if b.logger.Verbose() {
 			b.logger.WriteStdout(commandStdout.Bytes())
+		} else {
+			b.logger.RecordToolStdout(commandStdout.Bytes())
 		}
 		b.logger.WriteStderr(commandStderr.Bytes())
+		b.addDiagnostics(commandStderr.Bytes())
 
 		// ...and then return the error
 		if err != nil {
@@ -178,5 +216,11 @@ func (b *Builder) compileFileWithRecipe(
 		}
 	}
 
+	if b.assemblyListings && !b.onlyUpdateCompilationDatabase {
+		if err := b.compileAssemblyListing(properties, recipe, source, objectFile); err != nil {
+			return nil, err
+		}
+	}
+
 	return objectFile, nil
 }
@@ -0,0 +1,114 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2023 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package builder
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os/exec"
+	"testing"
+
+	"github.com/arduino/arduino-cli/arduino/builder/internal/compilation"
+	"github.com/arduino/arduino-cli/arduino/builder/internal/logger"
+	"github.com/arduino/arduino-cli/arduino/builder/internal/progress"
+	"github.com/arduino/go-paths-helper"
+	"github.com/arduino/go-properties-orderedmap"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParallelCompilation(t *testing.T) {
+	gpp, err := exec.LookPath("g++")
+	if err != nil {
+		t.Skip("g++ not available")
+	}
+	sketchDir := paths.New(t.TempDir(), "sketch")
+	require.NoError(t, sketchDir.MkdirAll())
+	for i := 0; i < 8; i++ {
+		source := fmt.Sprintf("int func%d(int a) { return a * %d; }\n", i, i)
+		require.NoError(t, sketchDir.Join(fmt.Sprintf("file%d.cpp", i)).WriteFile([]byte(source)))
+	}
+
+	compile := func(jobs int) (*paths.Path, paths.PathList, *compilation.Database) {
+		buildPath := paths.New(t.TempDir())
+		buildProperties := properties.NewMap()
+		buildProperties.Set("recipe.cpp.o.pattern", `"`+gpp+`" -c {includes} "{source_file}" -o "{object_file}"`)
+		b := &Builder{
+			buildProperties:     buildProperties,
+			buildPath:           buildPath,
+			jobs:                jobs,
+			compilationDatabase: compilation.NewDatabase(buildPath.Join("compile_commands.json")),
+			Progress:            progress.New(nil),
+			logger:              logger.New(io.Discard, io.Discard, false, ""),
+		}
+		objectFiles, err := b.compileFiles(sketchDir, buildPath, false, nil)
+		require.NoError(t, err)
+		return buildPath, objectFiles, b.compilationDatabase
+	}
+
+	serialBuildPath, serialObjects, serialDB := compile(1)
+	parallelBuildPath, parallelObjects, parallelDB := compile(4)
+	require.Len(t, serialObjects, 8)
+	require.Len(t, parallelObjects, 8)
+	for i := range serialObjects {
+		serialRel, err := serialObjects[i].RelFrom(serialBuildPath)
+		require.NoError(t, err)
+		parallelRel, err := parallelObjects[i].RelFrom(parallelBuildPath)
+		require.NoError(t, err)
+		require.Equal(t, serialRel, parallelRel)
+
+		serialData, err := serialObjects[i].ReadFile()
+		require.NoError(t, err)
+		parallelData, err := parallelObjects[i].ReadFile()
+		require.NoError(t, err)
+		require.Equal(t, serialData, parallelData)
+	}
+
+	// The compilation database lists the sources in the same order
+	require.Len(t, parallelDB.Contents, len(serialDB.Contents))
+	for i := range serialDB.Contents {
+		require.Equal(t, serialDB.Contents[i].File, parallelDB.Contents[i].File)
+	}
+
+	// The error of the first failing source is reported, whatever the completion order
+	sh, err := exec.LookPath("sh")
+	if err != nil {
+		t.Skip("sh not available")
+	}
+	for i := 0; i < 8; i++ {
+		exitCode := "0"
+		if i == 2 || i == 5 {
+			exitCode = fmt.Sprint(i)
+		}
+		require.NoError(t, sketchDir.Join(fmt.Sprintf("file%d.cpp", i)).WriteFile([]byte(exitCode)))
+	}
+	for _, jobs := range []int{1, 4} {
+		buildPath := paths.New(t.TempDir())
+		buildProperties := properties.NewMap()
+		buildProperties.Set("recipe.cpp.o.pattern", `"`+sh+`" -c 'exit $(cat "$0")' "{source_file}"`)
+		b := &Builder{
+			buildProperties: buildProperties,
+			buildPath:       buildPath,
+			jobs:            jobs,
+			Progress:        progress.New(nil),
+			logger:          logger.New(io.Discard, io.Discard, false, ""),
+		}
+		_, err := b.compileFiles(sketchDir, buildPath, false, nil)
+		var exitErr *exec.ExitError
+		require.True(t, errors.As(err, &exitErr))
+		require.Equal(t, 2, exitErr.ExitCode())
+	}
+}
@@ -0,0 +1,235 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2023 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package builder
+
+import (
+	"os"
+	"runtime"
+	"sort"
+	"strings"
+
+	"github.com/arduino/arduino-cli/arduino/cores"
+	"github.com/arduino/arduino-cli/version"
+)
+
+// CompileReport collects the information useful to diagnose a build problem:
+// the environment, the platforms and tools used, the compilers versions, the
+// commands executed and the diagnostics reported by the compiler. It can be
+// serialized to JSON and attached to a bug report.
+type CompileReport struct {
+	// CLIVersion is the version of the arduino-cli that made the build
+	CLIVersion string `json:"cli_version"`
+	// OS and Arch are the operating system and architecture of the host
+	OS   string `json:"os"`
+	Arch string `json:"arch"`
+	// FQBN is the fully qualified board name used for the build
+	FQBN string `json:"fqbn"`
+	// CustomBuildProperties are the build properties set by the user
+	CustomBuildProperties []string `json:"custom_build_properties"`
+	// BoardPlatform is the platform that provides the board
+	BoardPlatform *CompileReportPlatform `json:"board_platform,omitempty"`
+	// BuildPlatform is the platform used for the build (it differs from the
+	// BoardPlatform if the board references the core of another platform)
+	BuildPlatform *CompileReportPlatform `json:"build_platform,omitempty"`
+	// Toolchains are the compilers used in the build
+	Toolchains []*CompileReportToolchain `json:"toolchains"`
+	// Libraries are the libraries used in the build
+	Libraries []*BuildSummaryLibrary `json:"libraries"`
+	// Commands are the commands executed during the build, they are available
+	// only if RecordExecutedCommands has been enabled
+	Commands []*CompileReportCommand `json:"commands"`
+	// Diagnostics are the errors and warnings reported by the compiler
+	Diagnostics []*CompileReportDiagnostic `json:"diagnostics"`
+	// Summary is the summary of the build, nil if the build has not been completed
+	Summary *BuildSummary `json:"summary,omitempty"`
+}
+
+// CompileReportPlatform is a platform used in a build
+type CompileReportPlatform struct {
+	ID         string   `json:"id"`
+	Version    string   `json:"version"`
+	InstallDir string   `json:"install_dir"`
+	Tools      []string `json:"tools"`
+}
+
+// CompileReportToolchain is a compiler used in a build
+type CompileReportToolchain = BuildSummaryToolchain
+
+// CompileReportCommand is a command executed during a build
+type CompileReportCommand struct {
+	Args       []string `json:"args"`
+	Dir        string   `json:"dir,omitempty"`
+	ExitCode   int      `json:"exit_code"`
+	DurationMs int64    `json:"duration_ms"`
+}
+
+// CompileReportDiagnostic is a message reported by the compiler
+type CompileReportDiagnostic struct {
+	File     string `json:"file"`
+	Line     int    `json:"line"`
+	Column   int    `json:"column"`
+	Severity string `json:"severity"`
+	Message  string `json:"message"`
+}
+
+// CompileReport returns a report of the build made so far, it's usually called
+// after Build, but it may be called at any time to get a partial report.
+// If redactPaths is true the build path, the sketch path, the platform path
+// and the user home directory are replaced by placeholders in all the paths
+// contained in the report.
+func (b *Builder) CompileReport(redactPaths bool) *CompileReport {
+	report := &CompileReport{
+		OS:                    runtime.GOOS,
+		Arch:                  runtime.GOARCH,
+		FQBN:                  b.buildProperties.Get("build.fqbn"),
+		CustomBuildProperties: append([]string{}, b.customBuildProperties...),
+		Toolchains:            []*CompileReportToolchain{},
+		Libraries:             []*BuildSummaryLibrary{},
+		Commands:              []*CompileReportCommand{},
+		Diagnostics:           []*CompileReportDiagnostic{},
+	}
+	if version.VersionInfo != nil {
+		report.CLIVersion = version.VersionInfo.VersionString
+	}
+	report.BoardPlatform = newCompileReportPlatform(b.targetPlatform)
+	report.BuildPlatform = newCompileReportPlatform(b.actualPlatform)
+	partialSummary := newBuildSummary()
+	partialSummary.setToolchains(b.toolchainVersions)
+	report.Toolchains = partialSummary.Toolchains
+	if b.libsDetector != nil {
+		partialSummary.setLibraries(b.libsDetector.ImportedLibraries())
+		report.Libraries = partialSummary.Libraries
+	}
+	for _, command := range b.ExecutedCommands() {
+		report.Commands = append(report.Commands, &CompileReportCommand{
+			Args:       append([]string{}, command.Args...),
+			Dir:        command.Dir,
+			ExitCode:   command.ExitCode,
+			DurationMs: command.Duration.Milliseconds(),
+		})
+	}
+	for _, diagnostic := range b.Diagnostics() {
+		report.Diagnostics = append(report.Diagnostics, &CompileReportDiagnostic{
+			File:     diagnostic.File,
+			Line:     diagnostic.Line,
+			Column:   diagnostic.Column,
+			Severity: diagnostic.Severity,
+			Message:  diagnostic.Message,
+		})
+	}
+	if b.buildSummary != nil {
+		summary := *b.buildSummary
+		summary.Libraries = report.Libraries
+		summary.Toolchains = report.Toolchains
+		report.Summary = &summary
+	}
+
+	if redactPaths {
+		report.redact(b.pathsRedactor())
+	}
+	return report
+}
+
+func newCompileReportPlatform(platform *cores.PlatformRelease) *CompileReportPlatform {
+	if platform == nil {
+		return nil
+	}
+	res := &CompileReportPlatform{Tools: []string{}}
+	if platform.Platform != nil && platform.Platform.Package != nil {
+		res.ID = platform.Platform.String()
+	}
+	if platform.Version != nil {
+		res.Version = platform.Version.String()
+	}
+	if platform.InstallDir != nil {
+		res.InstallDir = platform.InstallDir.String()
+	}
+	for _, tool := range platform.ToolDependencies {
+		if tool.ToolVersion == nil {
+			res.Tools = append(res.Tools, tool.ToolPackager+":"+tool.ToolName)
+			continue
+		}
+		res.Tools = append(res.Tools, tool.String())
+	}
+	return res
+}
+
+// pathsRedactor returns a function that replaces the paths that may contain
+// personal information with placeholders
+func (b *Builder) pathsRedactor() func(string) string {
+	placeholders := map[string]string{}
+	addPlaceholder := func(path, placeholder string) {
+		if path != "" {
+			placeholders[path] = placeholder
+		}
+	}
+	if b.buildPath != nil {
+		addPlaceholder(b.buildPath.String(), "{build.path}")
+	}
+	if b.sketch != nil {
+		addPlaceholder(b.sketch.FullPath.String(), "{sketch.path}")
+	}
+	addPlaceholder(b.buildProperties.Get("runtime.platform.path"), "{runtime.platform.path}")
+	if b.targetPlatform != nil && b.targetPlatform.InstallDir != nil {
+		addPlaceholder(b.targetPlatform.InstallDir.String(), "{runtime.platform.path}")
+	}
+	if home, err := os.UserHomeDir(); err == nil {
+		addPlaceholder(home, "~")
+	}
+
+	// Replace the longest paths first, so the build path is not partially
+	// replaced by the home directory placeholder.
+	oldPaths := []string{}
+	for path := range placeholders {
+		oldPaths = append(oldPaths, path)
+	}
+	sort.Slice(oldPaths, func(i, j int) bool { return len(oldPaths[i]) > len(oldPaths[j]) })
+	replacements := []string{}
+	for _, path := range oldPaths {
+		replacements = append(replacements, path, placeholders[path])
+	}
+	return strings.NewReplacer(replacements...).Replace
+}
+
+func (r *CompileReport) redact(redactor func(string) string) {
+	for i, prop := range r.CustomBuildProperties {
+		r.CustomBuildProperties[i] = redactor(prop)
+	}
+	for _, platform := range []*CompileReportPlatform{r.BoardPlatform, r.BuildPlatform} {
+		if platform != nil {
+			platform.InstallDir = redactor(platform.InstallDir)
+		}
+	}
+	for _, toolchain := range r.Toolchains {
+		toolchain.Path = redactor(toolchain.Path)
+	}
+	for _, lib := range r.Libraries {
+		lib.InstallDir = redactor(lib.InstallDir)
+	}
+	for _, command := range r.Commands {
+		for i, arg := range command.Args {
+			command.Args[i] = redactor(arg)
+		}
+		command.Dir = redactor(command.Dir)
+	}
+	for _, diagnostic := range r.Diagnostics {
+		diagnostic.File = redactor(diagnostic.File)
+		diagnostic.Message = redactor(diagnostic.Message)
+	}
+	if r.Summary != nil {
+		r.Summary.CoreArchiveFile = redactor(r.Summary.CoreArchiveFile)
+	}
+}
@@ -0,0 +1,109 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2023 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package builder
+
+import (
+	"encoding/json"
+	"io"
+	"os/exec"
+	"strings"
+	"testing"
+
+	"github.com/arduino/arduino-cli/arduino/builder/internal/logger"
+	"github.com/arduino/arduino-cli/arduino/builder/internal/progress"
+	"github.com/arduino/arduino-cli/arduino/cores"
+	"github.com/arduino/arduino-cli/arduino/sketch"
+	"github.com/arduino/go-paths-helper"
+	"github.com/arduino/go-properties-orderedmap"
+	"github.com/stretchr/testify/require"
+	semver "go.bug.st/relaxed-semver"
+)
+
+func TestCompileReport(t *testing.T) {
+	gpp, err := exec.LookPath("g++")
+	if err != nil {
+		t.Skip("g++ not available")
+	}
+	sketchDir := paths.New(t.TempDir(), "sketch")
+	require.NoError(t, sketchDir.MkdirAll())
+	source := sketchDir.Join("sketch.ino.cpp")
+	require.NoError(t, source.WriteFile([]byte("void setup() {}\nvoid loop() { int unused; }\n")))
+	broken := sketchDir.Join("broken.cpp")
+	require.NoError(t, broken.WriteFile([]byte("void broken() { undefined(); }\n")))
+	buildPath := paths.New(t.TempDir())
+
+	pm := cores.NewPackages()
+	platform := pm.GetOrCreatePackage("arduino").GetOrCreatePlatform("avr")
+	release := platform.GetOrCreateRelease(semver.MustParse("1.8.6"))
+	release.InstallDir = paths.New(t.TempDir())
+	release.ToolDependencies = cores.ToolDependencies{
+		{ToolPackager: "arduino", ToolName: "avr-gcc", ToolVersion: semver.ParseRelaxed("7.3.0-atmel3.6.1-arduino7")},
+	}
+
+	buildProperties := properties.NewMap()
+	buildProperties.Set("build.fqbn", "arduino:avr:uno")
+	buildProperties.SetPath("runtime.platform.path", release.InstallDir)
+	buildProperties.Set("compiler.path", paths.New(gpp).Parent().String()+"/")
+	buildProperties.Set("compiler.cpp.cmd", paths.New(gpp).Base())
+	buildProperties.Set("recipe.cpp.o.pattern", `"{compiler.path}{compiler.cpp.cmd}" -Wall -c "{source_file}" -o "{object_file}"`)
+	b := &Builder{
+		sketch:                &sketch.Sketch{FullPath: sketchDir},
+		buildProperties:       buildProperties,
+		buildPath:             buildPath,
+		sketchBuildPath:       buildPath.Join("sketch"),
+		customBuildProperties: []string{"build.extra_flags=-DPATH=" + sketchDir.String()},
+		targetPlatform:        release,
+		actualPlatform:        release,
+		logger:                logger.New(io.Discard, io.Discard, false, ""),
+		Progress:              progress.New(nil),
+	}
+	b.RecordExecutedCommands(true)
+	b.toolchainVersions = detectToolchainVersions(buildProperties)
+	_, err = b.compileFileWithRecipe(sketchDir, source, b.sketchBuildPath, nil, "recipe.cpp.o.pattern")
+	require.NoError(t, err)
+	_, err = b.compileFileWithRecipe(sketchDir, broken, b.sketchBuildPath, nil, "recipe.cpp.o.pattern")
+	require.Error(t, err)
+
+	report := b.CompileReport(false)
+	require.NotEmpty(t, report.OS)
+	require.NotEmpty(t, report.Arch)
+	require.Equal(t, "arduino:avr:uno", report.FQBN)
+	require.Equal(t, "arduino:avr", report.BoardPlatform.ID)
+	require.Equal(t, "1.8.6", report.BoardPlatform.Version)
+	require.Equal(t, []string{"arduino:avr-gcc@7.3.0-atmel3.6.1-arduino7"}, report.BoardPlatform.Tools)
+	require.Equal(t, report.BoardPlatform, report.BuildPlatform)
+	require.Len(t, report.Toolchains, 1)
+	require.Equal(t, "compiler.cpp.cmd", report.Toolchains[0].Recipe)
+	require.Equal(t, gpp, report.Toolchains[0].Path)
+	require.Len(t, report.Commands, 2)
+	require.Equal(t, 0, report.Commands[0].ExitCode)
+	require.NotEqual(t, 0, report.Commands[1].ExitCode)
+	require.NotEmpty(t, report.Diagnostics)
+	require.Equal(t, broken.String(), report.Diagnostics[len(report.Diagnostics)-1].File)
+	require.Nil(t, report.Summary)
+
+	// Redacted report
+	report = b.CompileReport(true)
+	require.Equal(t, "{runtime.platform.path}", report.BoardPlatform.InstallDir)
+	require.Equal(t, "{sketch.path}/broken.cpp", report.Diagnostics[len(report.Diagnostics)-1].File)
+	require.Equal(t, []string{"build.extra_flags=-DPATH={sketch.path}"}, report.CustomBuildProperties)
+	require.Contains(t, report.Commands[0].Args, "{build.path}/sketch/sketch.ino.cpp.o")
+	data, err := json.Marshal(report)
+	require.NoError(t, err)
+	require.False(t, strings.Contains(string(data), sketchDir.String()))
+	require.False(t, strings.Contains(string(data), buildPath.String()))
+	require.Contains(t, string(data), `"toolchains":[{"recipe":"compiler.cpp.cmd"`)
+}
@@ -68,6 +68,27 @@ func (b *Builder) compileCore() (*paths.Path, paths.PathList, error) {
 	}
 	includes = f.Map(includes, cpp.WrapWithHyphenI)
 
+	// The core build folder may be kept when only the custom build properties
+	// are changed (see wipeBuildPathIfBuildOptionsChanged): recompile the core
+	// if the properties it uses are changed. A missing or unreadable fingerprint
+	// is considered changed, since the existing objects can't be trusted.
+	fingerprint := b.coreBuildFingerprint(includes)
+	fingerprintFile := b.coreBuildPath.Join("core.fingerprint")
+	if previous, err := fingerprintFile.ReadFile(); err != nil || string(previous) != fingerprint {
+		if err == nil {
+			b.logger.Info(tr("The build properties used by the core are changed, rebuilding the core"))
+		}
+		if err := b.coreBuildPath.RemoveAll(); err != nil {
+			return nil, nil, errors.WithStack(err)
+		}
+		if err := b.coreBuildPath.MkdirAll(); err != nil {
+			return nil, nil, errors.WithStack(err)
+		}
+	}
+	if err := fingerprintFile.WriteFile([]byte(fingerprint)); err != nil {
+		return nil, nil, errors.WithStack(err)
+	}
+
 	var err error
 	variantObjectFiles := paths.NewPathList()
 	if variantFolder != nil && variantFolder.IsDir() {
@@ -86,7 +107,7 @@ func (b *Builder) compileCore() (*paths.Path, paths.PathList, error) {
 		realCoreFolder := coreFolder.Parent().Parent()
 		archivedCoreName := getCachedCoreArchiveDirName(
 			b.buildProperties.Get("build.fqbn"),
-			b.buildProperties.Get("compiler.optimization_flags"),
+			b.buildProperties.Get("compiler.optimization_flags")+strings.Join(b.extraFlags, " "),
 			realCoreFolder,
 		)
 		targetArchivedCore = b.coreBuildCachePath.Join(archivedCoreName, "core.a")
@@ -152,6 +173,27 @@ func (b *Builder) compileCore() (*paths.Path, paths.PathList, error) {
 	return archiveFile, variantObjectFiles, nil
 }
 
+// coreBuildRecipes are the recipes used to build the core
+var coreBuildRecipes = []string{"recipe.c.o.pattern", "recipe.cpp.o.pattern", "recipe.S.o.pattern", "recipe.ar.pattern"}
+
+// coreBuildFingerprint returns a hash of the subset of the build properties
+// consumed by the core build: the core recipes expanded with the given includes
+// (the per-file properties and the build time are left out).
+func (b *Builder) coreBuildFingerprint(includes []string) string {
+	props := b.buildProperties.Clone()
+	props.Set("compiler.warning_flags", props.Get("compiler.warning_flags."+b.logger.WarningsLevel()))
+	props.Set("includes", strings.Join(includes, " "))
+	for _, key := range buildTimeProperties {
+		props.Remove(key)
+	}
+	hash := md5.New()
+	for _, recipe := range coreBuildRecipes {
+		hash.Write([]byte(recipe + "=" + props.ExpandPropsInString(props.Get(recipe)) + "\n"))
+	}
+	hash.Write([]byte(strings.Join(b.extraFlags, " ")))
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
 // getCachedCoreArchiveDirName returns the directory name to be used to store
 // the global cached core.a.
 func getCachedCoreArchiveDirName(fqbn string, optimizationFlags string, coreFolder *paths.Path) string {
@@ -0,0 +1,121 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2023 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package builder
+
+import (
+	"io"
+	"os/exec"
+	"strings"
+	"testing"
+
+	"github.com/arduino/arduino-cli/arduino/builder/internal/logger"
+	"github.com/arduino/arduino-cli/arduino/builder/internal/progress"
+	"github.com/arduino/arduino-cli/arduino/cores"
+	"github.com/arduino/arduino-cli/arduino/sketch"
+	"github.com/arduino/go-paths-helper"
+	"github.com/arduino/go-properties-orderedmap"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCoreKeptWhenOnlySketchPropertiesChange(t *testing.T) {
+	gpp, err := exec.LookPath("g++")
+	if err != nil {
+		t.Skip("g++ not available")
+	}
+	ar, err := exec.LookPath("ar")
+	if err != nil {
+		t.Skip("ar not available")
+	}
+
+	tmp := paths.New(t.TempDir())
+	platformDir := tmp.Join("hardware", "test", "avr")
+	coreDir := platformDir.Join("cores", "arduino")
+	require.NoError(t, coreDir.MkdirAll())
+	require.NoError(t, platformDir.Join("platform.txt").WriteFile([]byte{}))
+	require.NoError(t, coreDir.Join("core.cpp").WriteFile([]byte("int core() { return 0; }\n")))
+	sketchDir := tmp.Join("sketch")
+	require.NoError(t, sketchDir.MkdirAll())
+	require.NoError(t, sketchDir.Join("sketch.ino").WriteFile([]byte{}))
+	sk, err := sketch.New(sketchDir)
+	require.NoError(t, err)
+	fqbn, err := cores.ParseFQBN("test:avr:board")
+	require.NoError(t, err)
+	buildPath := tmp.Join("build")
+	coreBuildPath := buildPath.Join("core")
+	coreObject := coreBuildPath.Join("core.cpp.o")
+
+	build := func(customBuildProperties ...string) *Builder {
+		buildProperties := properties.NewMap()
+		buildProperties.SetPath("build.core.path", coreDir)
+		buildProperties.SetPath("runtime.platform.path", platformDir)
+		buildProperties.Set("compiler.cpp.flags", "-O0")
+		buildProperties.Set("build.sketch_flags", "")
+		buildProperties.Set("recipe.cpp.o.pattern", `"`+gpp+`" -c -MMD {compiler.cpp.flags} {includes} "{source_file}" -o "{object_file}"`)
+		buildProperties.Set("recipe.ar.pattern", `"`+ar+`" rcs "{archive_file_path}" "{object_file}"`)
+		for _, prop := range customBuildProperties {
+			key, value, _ := strings.Cut(prop, "=")
+			buildProperties.Set(key, value)
+		}
+		b := &Builder{
+			buildProperties: buildProperties,
+			buildPath:       buildPath,
+			sketchBuildPath: buildPath.Join("sketch"),
+			coreBuildPath:   coreBuildPath,
+			buildArtifacts:  &buildArtifacts{},
+			logger:          logger.New(io.Discard, io.Discard, false, ""),
+			Progress:        progress.New(nil),
+			buildOptions: newBuildOptions(
+				nil, nil, nil, nil, buildPath, sk, customBuildProperties, fqbn,
+				false, "", platformDir, coreDir),
+		}
+		require.NoError(t, buildPath.MkdirAll())
+		require.NoError(t, b.wipeBuildPathIfBuildOptionsChanged())
+		require.NoError(t, b.createBuildOptionsJSON())
+		require.NoError(t, b.sketchBuildPath.MkdirAll())
+		require.NoError(t, b.sketchBuildPath.Join("sketch.ino.cpp.o").WriteFile([]byte{}))
+		require.NoError(t, b.buildCore())
+		return b
+	}
+
+	b := build("build.sketch_flags=-DFOO")
+	require.Equal(t, coreBuildPath.Join("core.a").String(), b.CoreArchiveFile().String())
+	require.FileExists(t, b.CoreArchiveFile().String())
+	require.FileExists(t, coreObject.String())
+	firstBuild, err := coreObject.Stat()
+	require.NoError(t, err)
+
+	// Changing a sketch-only define keeps the core but wipes the sketch
+	require.NoError(t, buildPath.Join("sketch", "marker").WriteFile([]byte{}))
+	build("build.sketch_flags=-DBAR")
+	require.NoFileExists(t, buildPath.Join("sketch", "marker").String())
+	secondBuild, err := coreObject.Stat()
+	require.NoError(t, err)
+	require.Equal(t, firstBuild.ModTime(), secondBuild.ModTime())
+
+	// Changing a property used by the core recompiles it
+	require.NoError(t, coreBuildPath.Join("marker").WriteFile([]byte{}))
+	build("build.sketch_flags=-DBAR", "compiler.cpp.flags=-O1")
+	require.NoFileExists(t, coreBuildPath.Join("marker").String())
+	require.FileExists(t, coreObject.String())
+
+	// A missing fingerprint recompiles the core
+	require.NoError(t, coreBuildPath.Join("marker").WriteFile([]byte{}))
+	require.NoError(t, coreBuildPath.Join("core.fingerprint").Remove())
+	build("build.sketch_flags=-DBAR", "compiler.cpp.flags=-O1")
+	require.NoFileExists(t, coreBuildPath.Join("marker").String())
+	require.FileExists(t, coreObject.String())
+	require.FileExists(t, coreBuildPath.Join("core.fingerprint").String())
+}
@@ -0,0 +1,119 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2023 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package builder
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+
+	semver "go.bug.st/relaxed-semver"
+)
+
+// CppStandards are the C++ standards that can be used to override the one
+// selected by the platform
+var CppStandards = []string{
+	"c++98", "gnu++98", "c++03", "gnu++03",
+	"c++11", "gnu++11", "c++14", "gnu++14",
+	"c++17", "gnu++17", "c++20", "gnu++20",
+	"c++23", "gnu++23",
+}
+
+// ErrInvalidCppStandard is returned when an unknown C++ standard is requested
+var ErrInvalidCppStandard = errors.New("invalid C++ standard")
+
+// cppStandardMinGCCVersion is the first GCC release accepting each standard
+var cppStandardMinGCCVersion = map[string]string{
+	"11": "4.8.1",
+	"14": "5.0.0",
+	"17": "7.0.0",
+	"20": "10.0.0",
+	"23": "11.0.0",
+}
+
+var cppStandardFlagRegexp = regexp.MustCompile(`(^|\s)-std=\S+`)
+
+// SetCppStandard overrides the C++ standard used to compile the C++ sources
+// (for example "gnu++17"), an empty standard restores the one selected by the
+// platform. The -std flag is replaced in compiler.cpp.flags, so the C and
+// assembly sources are not affected. Changing the standard triggers a full
+// rebuild.
+func (b *Builder) SetCppStandard(std string) error {
+	if std != "" && !slices.Contains(CppStandards, std) {
+		return fmt.Errorf("%w '%s', %s", ErrInvalidCppStandard, std, tr("must be one of: %s", CppStandards))
+	}
+	if b.savedCppFlags == nil {
+		flags := b.buildProperties.Get("compiler.cpp.flags")
+		b.savedCppFlags = &flags
+	}
+	if std == "" {
+		b.buildProperties.Set("compiler.cpp.flags", *b.savedCppFlags)
+		b.savedCppFlags = nil
+	} else {
+		flags := strings.TrimSpace(cppStandardFlagRegexp.ReplaceAllString(*b.savedCppFlags, ""))
+		b.buildProperties.Set("compiler.cpp.flags", strings.TrimSpace(flags+" -std="+std))
+	}
+	b.cppStandard = std
+	if b.buildOptions != nil {
+		if std != "" {
+			b.buildOptions.currentOptions.Set("cppStandard", std)
+		} else {
+			b.buildOptions.currentOptions.Remove("cppStandard")
+		}
+	}
+	return nil
+}
+
+// CppStandard returns the C++ standard override, or an empty string if the
+// standard selected by the platform is used
+func (b *Builder) CppStandard() string {
+	return b.cppStandard
+}
+
+// warnAboutUnsupportedCppStandard warns if the C++ compiler used in the build
+// is known to predate the requested C++ standard
+func (b *Builder) warnAboutUnsupportedCppStandard() {
+	if b.cppStandard == "" {
+		return
+	}
+	for _, toolchain := range b.toolchainVersions {
+		if toolchain.Recipe != "compiler.cpp.cmd" {
+			continue
+		}
+		if msg := unsupportedCppStandardMessage(toolchain, b.cppStandard); msg != "" {
+			b.logger.Warn(msg)
+		}
+	}
+}
+
+func unsupportedCppStandardMessage(toolchain *ToolchainVersion, std string) string {
+	// Only the versions of GCC are known
+	banner := strings.ToLower(toolchain.Banner)
+	if toolchain.Version == "" || strings.Contains(banner, "clang") {
+		return ""
+	}
+	minVersion, ok := cppStandardMinGCCVersion[strings.TrimLeft(std, "cgnu+")]
+	if !ok {
+		return ""
+	}
+	if !semver.ParseRelaxed(toolchain.Version).LessThan(semver.ParseRelaxed(minVersion)) {
+		return ""
+	}
+	return tr("The C++ compiler %[1]s (version %[2]s) may not support the %[3]s standard, version %[4]s or later is required",
+		toolchain.Path.Base(), toolchain.Version, std, minVersion)
+}
@@ -0,0 +1,71 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2023 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package builder
+
+import (
+	"testing"
+
+	"github.com/arduino/go-paths-helper"
+	"github.com/arduino/go-properties-orderedmap"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSetCppStandard(t *testing.T) {
+	buildProperties := properties.NewMap()
+	buildProperties.Set("compiler.c.flags", "-c -g -Os -std=gnu11")
+	buildProperties.Set("compiler.cpp.flags", "-c -g -Os -std=gnu++11 -fno-exceptions")
+	buildProperties.Set("recipe.c.o.pattern", `gcc {compiler.c.flags} "{source_file}"`)
+	buildProperties.Set("recipe.cpp.o.pattern", `g++ {compiler.cpp.flags} "{source_file}"`)
+	b := &Builder{buildProperties: buildProperties}
+
+	require.NoError(t, b.SetCppStandard("gnu++17"))
+	require.Equal(t, "gnu++17", b.CppStandard())
+	require.Equal(t, `g++ -c -g -Os -fno-exceptions -std=gnu++17 "{source_file}"`,
+		buildProperties.ExpandPropsInString(buildProperties.Get("recipe.cpp.o.pattern")))
+	require.Equal(t, `gcc -c -g -Os -std=gnu11 "{source_file}"`,
+		buildProperties.ExpandPropsInString(buildProperties.Get("recipe.c.o.pattern")))
+
+	// Changing the standard again replaces the override
+	require.NoError(t, b.SetCppStandard("c++20"))
+	require.Equal(t, "-c -g -Os -fno-exceptions -std=c++20", buildProperties.Get("compiler.cpp.flags"))
+
+	// An invalid standard is rejected and the current one is kept
+	require.ErrorIs(t, b.SetCppStandard("c++17 -O0"), ErrInvalidCppStandard)
+	require.ErrorIs(t, b.SetCppStandard("gnu11"), ErrInvalidCppStandard)
+	require.Equal(t, "c++20", b.CppStandard())
+
+	// An empty standard restores the platform flags
+	require.NoError(t, b.SetCppStandard(""))
+	require.Equal(t, "-c -g -Os -std=gnu++11 -fno-exceptions", buildProperties.Get("compiler.cpp.flags"))
+}
+
+func TestUnsupportedCppStandardMessage(t *testing.T) {
+	avrGpp := &ToolchainVersion{
+		Recipe:  "compiler.cpp.cmd",
+		Path:    paths.New("/tools/avr-gcc/bin/avr-g++"),
+		Version: "7.3.0",
+		Banner:  "avr-g++ (GCC) 7.3.0",
+	}
+	require.Empty(t, unsupportedCppStandardMessage(avrGpp, "gnu++11"))
+	require.Empty(t, unsupportedCppStandardMessage(avrGpp, "gnu++17"))
+	require.Contains(t, unsupportedCppStandardMessage(avrGpp, "gnu++20"), "10.0.0")
+	require.Contains(t, unsupportedCppStandardMessage(avrGpp, "c++23"), "avr-g++")
+
+	clang := &ToolchainVersion{Version: "7.0.0", Banner: "clang version 7.0.0"}
+	require.Empty(t, unsupportedCppStandardMessage(clang, "c++20"))
+	unknown := &ToolchainVersion{Banner: "unknown compiler"}
+	require.Empty(t, unsupportedCppStandardMessage(unknown, "c++20"))
+}
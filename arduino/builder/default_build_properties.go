@@ -0,0 +1,51 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2023 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package builder
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/arduino/go-paths-helper"
+	"github.com/arduino/go-properties-orderedmap"
+	"github.com/pkg/errors"
+)
+
+// LoadDefaultBuildProperties loads a properties file with default values for
+// the build properties: the properties are used only if they are not already
+// defined by the board, the platform or the custom build properties (that
+// always override the platform ones), in other words they have the lowest
+// precedence. Changing the defaults triggers a full rebuild.
+func (b *Builder) LoadDefaultBuildProperties(file *paths.Path) error {
+	defaults, err := properties.LoadFromPath(file)
+	if err != nil {
+		return errors.WithMessage(err, tr("loading default build properties"))
+	}
+	applied := []string{}
+	for _, key := range defaults.Keys() {
+		if b.buildProperties.ContainsKey(key) {
+			continue
+		}
+		value := defaults.Get(key)
+		b.buildProperties.Set(key, value)
+		applied = append(applied, key+"="+value)
+	}
+	if b.buildOptions != nil && len(applied) > 0 {
+		sort.Strings(applied)
+		b.buildOptions.currentOptions.Set("defaultBuildProperties", strings.Join(applied, ","))
+	}
+	return nil
+}
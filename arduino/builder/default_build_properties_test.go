@@ -0,0 +1,40 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2023 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package builder
+
+import (
+	"testing"
+
+	"github.com/arduino/go-paths-helper"
+	"github.com/arduino/go-properties-orderedmap"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadDefaultBuildProperties(t *testing.T) {
+	defaults := paths.New(t.TempDir()).Join("defaults.txt")
+	require.NoError(t, defaults.WriteFile([]byte("compiler.cpp.flags=-O0\nbuild.extra_flags=-DFALLBACK\n")))
+
+	buildProperties := properties.NewMap()
+	buildProperties.Set("compiler.cpp.flags", "-Os -g")
+	b := &Builder{buildProperties: buildProperties}
+	require.NoError(t, b.LoadDefaultBuildProperties(defaults))
+	// The property defined by the platform is not changed
+	require.Equal(t, "-Os -g", b.buildProperties.Get("compiler.cpp.flags"))
+	// The missing property is added
+	require.Equal(t, "-DFALLBACK", b.buildProperties.Get("build.extra_flags"))
+
+	require.Error(t, b.LoadDefaultBuildProperties(defaults.Parent().Join("missing.txt")))
+}
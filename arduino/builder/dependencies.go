@@ -0,0 +1,76 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2023 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package builder
+
+import (
+	"strings"
+
+	"github.com/arduino/arduino-cli/arduino/builder/internal/utils"
+	"github.com/arduino/go-paths-helper"
+)
+
+// SetDependenciesFile sets the file where the aggregated list of the dependencies
+// of the build is written at the end of a successful build, one path per line.
+// If nil the list is not written.
+func (b *Builder) SetDependenciesFile(file *paths.Path) {
+	b.dependenciesFile = file
+}
+
+// Dependencies returns the deduplicated and sorted list of the files (sources
+// and headers) the compiled objects depend on, as reported by the dependency
+// files (.d) generated by the compiler in the build path.
+func (b *Builder) Dependencies() (paths.PathList, error) {
+	seen := map[string]bool{}
+	res := paths.PathList{}
+	for _, dir := range []*paths.Path{b.sketchBuildPath, b.librariesBuildPath, b.coreBuildPath} {
+		if dir == nil || !dir.IsDir() {
+			continue
+		}
+		depFiles, err := dir.ReadDirRecursiveFiltered(nil, paths.FilterSuffixes(".d"), paths.FilterOutDirectories())
+		if err != nil {
+			return nil, err
+		}
+		for _, depFile := range depFiles {
+			_, deps, err := utils.ParseDependencyFile(depFile)
+			if err != nil {
+				return nil, err
+			}
+			for _, dep := range deps {
+				if !seen[dep] {
+					seen[dep] = true
+					res.Add(paths.New(dep))
+				}
+			}
+		}
+	}
+	res.Sort()
+	return res, nil
+}
+
+func (b *Builder) writeDependenciesFile() error {
+	deps, err := b.Dependencies()
+	if err != nil {
+		return err
+	}
+	lines := strings.Builder{}
+	for _, dep := range deps {
+		lines.WriteString(dep.String() + "\n")
+	}
+	if err := b.dependenciesFile.Parent().MkdirAll(); err != nil {
+		return err
+	}
+	return b.dependenciesFile.WriteFile([]byte(lines.String()))
+}
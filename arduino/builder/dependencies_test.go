@@ -0,0 +1,67 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2023 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package builder
+
+import (
+	"io"
+	"os/exec"
+	"testing"
+
+	"github.com/arduino/arduino-cli/arduino/builder/internal/logger"
+	"github.com/arduino/go-paths-helper"
+	"github.com/arduino/go-properties-orderedmap"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDependencies(t *testing.T) {
+	gpp, err := exec.LookPath("g++")
+	if err != nil {
+		t.Skip("g++ not available")
+	}
+	sketchDir := paths.New(t.TempDir(), "my sketch")
+	require.NoError(t, sketchDir.MkdirAll())
+	header := sketchDir.Join("config.h")
+	require.NoError(t, header.WriteFile([]byte("#define VALUE 2\n")))
+	source := sketchDir.Join("sketch.ino.cpp")
+	require.NoError(t, source.WriteFile([]byte("#include \"config.h\"\nint twice(int a) { return a * VALUE; }\n")))
+	other := sketchDir.Join("other.cpp")
+	require.NoError(t, other.WriteFile([]byte("#include \"config.h\"\nint thrice(int a) { return a * VALUE + a; }\n")))
+	buildPath := paths.New(t.TempDir())
+
+	buildProperties := properties.NewMap()
+	buildProperties.Set("recipe.cpp.o.pattern", `"`+gpp+`" -c -MMD {includes} "{source_file}" -o "{object_file}"`)
+	b := &Builder{
+		buildProperties: buildProperties,
+		buildPath:       buildPath,
+		sketchBuildPath: buildPath.Join("sketch"),
+		logger:          logger.New(io.Discard, io.Discard, false, ""),
+	}
+	for _, src := range []*paths.Path{source, other} {
+		_, err := b.compileFileWithRecipe(sketchDir, src, b.sketchBuildPath, nil, "recipe.cpp.o.pattern")
+		require.NoError(t, err)
+	}
+
+	deps, err := b.Dependencies()
+	require.NoError(t, err)
+	require.Equal(t, paths.PathList{header, other, source}, deps)
+
+	depsFile := buildPath.Join("deps", "dependencies.txt")
+	b.SetDependenciesFile(depsFile)
+	require.NoError(t, b.writeDependenciesFile())
+	data, err := depsFile.ReadFile()
+	require.NoError(t, err)
+	require.Equal(t, header.String()+"\n"+other.String()+"\n"+source.String()+"\n", string(data))
+}
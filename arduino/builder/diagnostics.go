@@ -0,0 +1,96 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2023 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package builder
+
+import (
+	"bufio"
+	"bytes"
+	"regexp"
+	"strconv"
+)
+
+// Diagnostic is a message (error, warning or note) reported by the compiler
+type Diagnostic struct {
+	File     string
+	Line     int
+	Column   int
+	Severity string
+	Message  string
+}
+
+// DiagnosticsFilter receives the diagnostics reported by the compiler and
+// returns the diagnostics that should be returned by the Builder. It may drop
+// diagnostics or rewrite them, for example to map the files in the build
+// path back to the sketch sources.
+type DiagnosticsFilter func([]*Diagnostic) []*Diagnostic
+
+// diagnosticRegexp matches the GCC/Clang diagnostics in the form
+// "file:line:column: severity: message" (the column is optional)
+var diagnosticRegexp = regexp.MustCompile(`^(.+?):(\d+):(?:(\d+):)? (fatal error|error|warning|note): (.*)$`)
+
+// parseDiagnostics extracts the diagnostics from the output of the compiler,
+// the lines that are not diagnostics (source excerpts, "In function..." context
+// lines, etc.) are ignored.
+func parseDiagnostics(output []byte) []*Diagnostic {
+	res := []*Diagnostic{}
+	scanner := bufio.NewScanner(bytes.NewReader(output))
+	for scanner.Scan() {
+		match := diagnosticRegexp.FindStringSubmatch(scanner.Text())
+		if match == nil {
+			continue
+		}
+		line, _ := strconv.Atoi(match[2])
+		column, _ := strconv.Atoi(match[3])
+		res = append(res, &Diagnostic{
+			File:     match[1],
+			Line:     line,
+			Column:   column,
+			Severity: match[4],
+			Message:  match[5],
+		})
+	}
+	return res
+}
+
+// SetDiagnosticsFilter sets a filter that post-processes the diagnostics
+// reported by the compiler before they are returned by Diagnostics. By default
+// the diagnostics are returned as they are.
+func (b *Builder) SetDiagnosticsFilter(filter DiagnosticsFilter) {
+	b.diagnosticsFilter = filter
+}
+
+// Diagnostics returns the diagnostics reported by the compiler during the
+// build, in the order they have been collected, processed by the filter set
+// with SetDiagnosticsFilter.
+func (b *Builder) Diagnostics() []*Diagnostic {
+	b.diagnosticsMux.Lock()
+	diagnostics := append([]*Diagnostic{}, b.diagnostics...)
+	b.diagnosticsMux.Unlock()
+	if b.diagnosticsFilter == nil {
+		return diagnostics
+	}
+	return b.diagnosticsFilter(diagnostics)
+}
+
+func (b *Builder) addDiagnostics(compilerOutput []byte) {
+	diagnostics := parseDiagnostics(compilerOutput)
+	if len(diagnostics) == 0 {
+		return
+	}
+	b.diagnosticsMux.Lock()
+	b.diagnostics = append(b.diagnostics, diagnostics...)
+	b.diagnosticsMux.Unlock()
+}
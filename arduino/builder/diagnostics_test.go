@@ -0,0 +1,100 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2023 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package builder
+
+import (
+	"io"
+	"os/exec"
+	"strings"
+	"testing"
+
+	"github.com/arduino/arduino-cli/arduino/builder/internal/logger"
+	"github.com/arduino/go-paths-helper"
+	"github.com/arduino/go-properties-orderedmap"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseDiagnostics(t *testing.T) {
+	output := "/tmp/sketch/sketch.ino.cpp: In function 'void setup()':\n" +
+		"/tmp/sketch/sketch.ino.cpp:5:7: warning: unused variable 'a' [-Wunused-variable]\n" +
+		"    5 |   int a;\n" +
+		"      |       ^\n" +
+		"/tmp/sketch/other.cpp:12: error: 'b' was not declared in this scope\n"
+	diagnostics := parseDiagnostics([]byte(output))
+	require.Equal(t, []*Diagnostic{
+		{File: "/tmp/sketch/sketch.ino.cpp", Line: 5, Column: 7, Severity: "warning", Message: "unused variable 'a' [-Wunused-variable]"},
+		{File: "/tmp/sketch/other.cpp", Line: 12, Severity: "error", Message: "'b' was not declared in this scope"},
+	}, diagnostics)
+}
+
+func TestDiagnosticsFilter(t *testing.T) {
+	gpp, err := exec.LookPath("g++")
+	if err != nil {
+		t.Skip("g++ not available")
+	}
+	sketchDir := paths.New(t.TempDir(), "sketch")
+	require.NoError(t, sketchDir.MkdirAll())
+	source := sketchDir.Join("sketch.ino.cpp")
+	require.NoError(t, source.WriteFile([]byte("#warning \"check this\"\nvoid setup() {\n  int unused;\n}\n")))
+	buildPath := paths.New(t.TempDir())
+
+	compile := func(filter DiagnosticsFilter) []*Diagnostic {
+		buildProperties := properties.NewMap()
+		buildProperties.Set("recipe.cpp.o.pattern", `"`+gpp+`" -c -Wall "{source_file}" -o "{object_file}"`)
+		b := &Builder{
+			buildProperties: buildProperties,
+			buildPath:       buildPath,
+			sketchBuildPath: buildPath.Join("sketch"),
+			logger:          logger.New(io.Discard, io.Discard, false, ""),
+		}
+		b.SetDiagnosticsFilter(filter)
+		require.NoError(t, buildPath.Join("sketch").RemoveAll())
+		_, err := b.compileFileWithRecipe(sketchDir, source, b.sketchBuildPath, nil, "recipe.cpp.o.pattern")
+		require.NoError(t, err)
+		return b.Diagnostics()
+	}
+
+	messages := func(diagnostics []*Diagnostic) []string {
+		res := []string{}
+		for _, d := range diagnostics {
+			res = append(res, d.Severity+": "+d.Message)
+		}
+		return res
+	}
+
+	// Identity by default
+	all := messages(compile(nil))
+	require.Len(t, all, 2)
+	require.Contains(t, all[0], "check this")
+	require.Contains(t, all[1], "unused variable")
+
+	// Drop the unused variable warnings and rewrite the path of the others
+	filtered := compile(func(diagnostics []*Diagnostic) []*Diagnostic {
+		res := []*Diagnostic{}
+		for _, d := range diagnostics {
+			if strings.Contains(d.Message, "[-Wunused-variable]") {
+				continue
+			}
+			d.File = "sketch.ino"
+			res = append(res, d)
+		}
+		return res
+	})
+	require.Len(t, filtered, 1)
+	require.Contains(t, filtered[0].Message, "check this")
+	require.Equal(t, "sketch.ino", filtered[0].File)
+	require.Equal(t, 1, filtered[0].Line)
+}
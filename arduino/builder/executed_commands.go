@@ -0,0 +1,86 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2023 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package builder
+
+import (
+	"errors"
+	"os/exec"
+	"time"
+
+	"github.com/arduino/arduino-cli/executils"
+)
+
+// ExecutedCommand is a subprocess executed during the build
+type ExecutedCommand struct {
+	// Args is the command line, the first element is the executable
+	Args []string
+	// Dir is the working directory of the command, empty if the command
+	// has been run in the current working directory
+	Dir string
+	// ExitCode is the exit code of the command, -1 if the command could not
+	// be started or has been terminated by a signal
+	ExitCode int
+	// Duration is the time elapsed from the start to the end of the command
+	Duration time.Duration
+}
+
+// RecordExecutedCommands enables or disables the recording of the commands
+// (compiler, linker, hooks, etc.) executed during the build, independently of
+// the verbosity. The recorded commands are returned by ExecutedCommands.
+func (b *Builder) RecordExecutedCommands(enable bool) {
+	b.recordCommands = enable
+}
+
+// ExecutedCommands returns the commands executed during the build, in the
+// order they have been completed. RecordExecutedCommands must be called
+// before the build to enable the recording.
+func (b *Builder) ExecutedCommands() []*ExecutedCommand {
+	b.executedCommandsMux.Lock()
+	defer b.executedCommandsMux.Unlock()
+	return append([]*ExecutedCommand{}, b.executedCommands...)
+}
+
+// runCommand runs the command and waits for its termination, recording it if
+// requested
+func (b *Builder) runCommand(command *executils.Process) error {
+	start := time.Now()
+	b.commandsCount.Add(1)
+	err := command.Start()
+	if err == nil {
+		err = command.Wait()
+	}
+	if !b.recordCommands {
+		return err
+	}
+
+	exitCode := 0
+	if err != nil {
+		exitCode = -1
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) {
+			exitCode = exitErr.ExitCode()
+		}
+	}
+	b.executedCommandsMux.Lock()
+	b.executedCommands = append(b.executedCommands, &ExecutedCommand{
+		Args:     command.GetArgs(),
+		Dir:      command.GetDir(),
+		ExitCode: exitCode,
+		Duration: time.Since(start),
+	})
+	b.executedCommandsMux.Unlock()
+	return err
+}
@@ -0,0 +1,80 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2023 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package builder
+
+import (
+	"io"
+	"os/exec"
+	"testing"
+
+	"github.com/arduino/arduino-cli/arduino/builder/internal/logger"
+	"github.com/arduino/arduino-cli/arduino/builder/internal/progress"
+	"github.com/arduino/go-paths-helper"
+	"github.com/arduino/go-properties-orderedmap"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExecutedCommands(t *testing.T) {
+	gpp, err := exec.LookPath("g++")
+	if err != nil {
+		t.Skip("g++ not available")
+	}
+	sh, err := exec.LookPath("sh")
+	if err != nil {
+		t.Skip("sh not available")
+	}
+	sketchDir := paths.New(t.TempDir(), "sketch")
+	require.NoError(t, sketchDir.MkdirAll())
+	source := sketchDir.Join("sketch.ino.cpp")
+	require.NoError(t, source.WriteFile([]byte("void setup() {}\n")))
+	broken := sketchDir.Join("broken.cpp")
+	require.NoError(t, broken.WriteFile([]byte("void broken() {\n")))
+	buildPath := paths.New(t.TempDir())
+
+	buildProperties := properties.NewMap()
+	buildProperties.Set("recipe.cpp.o.pattern", `"`+gpp+`" -c "{source_file}" -o "{object_file}"`)
+	buildProperties.Set("recipe.hooks.prebuild.1.pattern", `"`+sh+`" -c 'exit 3'`)
+	b := &Builder{
+		buildProperties: buildProperties,
+		buildPath:       buildPath,
+		sketchBuildPath: buildPath.Join("sketch"),
+		logger:          logger.New(io.Discard, io.Discard, false, ""),
+		Progress:        progress.New(nil),
+	}
+
+	// Commands are not recorded by default
+	_, err = b.compileFileWithRecipe(sketchDir, source, b.sketchBuildPath, nil, "recipe.cpp.o.pattern")
+	require.NoError(t, err)
+	require.Empty(t, b.ExecutedCommands())
+
+	b.RecordExecutedCommands(true)
+	require.NoError(t, b.sketchBuildPath.RemoveAll())
+	_, err = b.compileFileWithRecipe(sketchDir, source, b.sketchBuildPath, nil, "recipe.cpp.o.pattern")
+	require.NoError(t, err)
+	_, err = b.compileFileWithRecipe(sketchDir, broken, b.sketchBuildPath, nil, "recipe.cpp.o.pattern")
+	require.Error(t, err)
+	require.Error(t, b.RunRecipe("recipe.hooks.prebuild", ".pattern", false))
+
+	commands := b.ExecutedCommands()
+	require.Len(t, commands, 3)
+	require.Equal(t, []string{gpp, "-c", source.String(), "-o", b.sketchBuildPath.Join("sketch.ino.cpp.o").String()}, commands[0].Args)
+	require.Equal(t, 0, commands[0].ExitCode)
+	require.Equal(t, gpp, commands[1].Args[0])
+	require.Contains(t, commands[1].Args, broken.String())
+	require.NotEqual(t, 0, commands[1].ExitCode)
+	require.Equal(t, []string{sh, "-c", "exit 3"}, commands[2].Args)
+	require.Equal(t, 3, commands[2].ExitCode)
+}
@@ -236,7 +236,7 @@ func (b *Builder) exportProjectCMake(importedLibraries libraries.List, includeFo
 		fmt.Println(err)
 	}
 
-	if err := b.preprocessSketch(includeFolders); err != nil {
+	if err := b.preprocessSketch(includeFolders, b.buildProperties); err != nil {
 		return err
 	}
 
@@ -0,0 +1,81 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2023 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package builder
+
+import (
+	"strings"
+
+	"github.com/arduino/arduino-cli/executils"
+	"github.com/pkg/errors"
+)
+
+// extraFlagsForbidden are the flags that would change the kind of output
+// produced by the recipes, they can't be used as extra flags
+var extraFlagsForbidden = []string{"-c", "-E", "-S", "-o"}
+
+// SetExtraFlags sets a list of flags that are added at the end of all the
+// compile and link commands of the build (for example "-fsanitize=address"),
+// without the need to override the single build properties of the platform.
+// Each flag must be a single argument starting with "-", the duplicated flags
+// are added once. Changing the extra flags triggers a full rebuild.
+func (b *Builder) SetExtraFlags(flags []string) error {
+	res := []string{}
+	seen := map[string]bool{}
+	for _, flag := range flags {
+		if !strings.HasPrefix(flag, "-") || strings.TrimSpace(flag) != flag || flag == "-" {
+			return errors.New(tr("invalid extra flag: %s", flag))
+		}
+		for _, forbidden := range extraFlagsForbidden {
+			if flag == forbidden || (forbidden == "-o" && strings.HasPrefix(flag, "-o")) {
+				return errors.New(tr("extra flag not allowed: %s", flag))
+			}
+		}
+		if seen[flag] {
+			continue
+		}
+		seen[flag] = true
+		res = append(res, flag)
+	}
+	b.extraFlags = res
+	if b.buildOptions != nil {
+		if len(res) > 0 {
+			b.buildOptions.currentOptions.Set("extraFlags", strings.Join(res, " "))
+		} else {
+			b.buildOptions.currentOptions.Remove("extraFlags")
+		}
+	}
+	return nil
+}
+
+// ExtraFlags returns the flags added to the compile and link commands
+func (b *Builder) ExtraFlags() []string {
+	return append([]string{}, b.extraFlags...)
+}
+
+// addExtraFlags returns the given command with the extra flags appended
+func (b *Builder) addExtraFlags(command *executils.Process) (*executils.Process, error) {
+	if len(b.extraFlags) == 0 {
+		return command, nil
+	}
+	dir := command.GetDir()
+	args := append(append([]string{}, command.GetArgs()...), b.extraFlags...)
+	res, err := executils.NewProcess(nil, args...)
+	if err != nil {
+		return nil, err
+	}
+	res.SetDir(dir)
+	return res, nil
+}
@@ -0,0 +1,70 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2023 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package builder
+
+import (
+	"io"
+	"os/exec"
+	"testing"
+
+	"github.com/arduino/arduino-cli/arduino/builder/internal/logger"
+	"github.com/arduino/arduino-cli/arduino/builder/internal/progress"
+	"github.com/arduino/go-paths-helper"
+	"github.com/arduino/go-properties-orderedmap"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExtraFlags(t *testing.T) {
+	sh, err := exec.LookPath("sh")
+	if err != nil {
+		t.Skip("sh not available")
+	}
+	sketchDir := paths.New(t.TempDir())
+	source := sketchDir.Join("sketch.ino.cpp")
+	require.NoError(t, source.WriteFile([]byte{}))
+	buildPath := paths.New(t.TempDir())
+
+	buildProperties := properties.NewMap()
+	buildProperties.Set("recipe.cpp.o.pattern", `"`+sh+`" -c "touch {object_file}" compile "{source_file}"`)
+	buildProperties.Set("recipe.c.combine.pattern", `"`+sh+`" -c "exit 0" link {object_files}`)
+	b := &Builder{
+		buildProperties: buildProperties,
+		buildPath:       buildPath,
+		sketchBuildPath: buildPath.Join("sketch"),
+		buildArtifacts:  &buildArtifacts{coreArchiveFilePath: buildPath.Join("core", "core.a")},
+		logger:          logger.New(io.Discard, io.Discard, false, ""),
+		Progress:        progress.New(nil),
+	}
+
+	require.Error(t, b.SetExtraFlags([]string{"fsanitize=address"}))
+	require.Error(t, b.SetExtraFlags([]string{"-fsanitize=address", "-o"}))
+	require.Error(t, b.SetExtraFlags([]string{"-ofile"}))
+	require.Error(t, b.SetExtraFlags([]string{" -g"}))
+	require.Error(t, b.SetExtraFlags([]string{"-c"}))
+	require.NoError(t, b.SetExtraFlags([]string{"-fsanitize=address", "-g", "-fsanitize=address"}))
+	require.Equal(t, []string{"-fsanitize=address", "-g"}, b.ExtraFlags())
+
+	b.RecordExecutedCommands(true)
+	_, err = b.compileFileWithRecipe(sketchDir, source, b.sketchBuildPath, nil, "recipe.cpp.o.pattern")
+	require.NoError(t, err)
+	require.NoError(t, b.link())
+
+	commands := b.ExecutedCommands()
+	require.Len(t, commands, 2)
+	require.Equal(t, []string{"compile", source.String(), "-fsanitize=address", "-g"}, commands[0].Args[3:])
+	require.Equal(t, []string{"-fsanitize=address", "-g"}, commands[1].Args[len(commands[1].Args)-2:])
+	require.Equal(t, "link", commands[1].Args[3])
+}
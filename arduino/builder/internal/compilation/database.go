@@ -19,6 +19,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"os"
+	"sync"
 
 	"github.com/arduino/arduino-cli/executils"
 	"github.com/arduino/arduino-cli/i18n"
@@ -31,6 +32,7 @@ var tr = i18n.Tr
 type Database struct {
 	Contents []Command
 	File     *paths.Path
+	mux      sync.Mutex
 }
 
 // Command keeps track of a single run of a compile command
@@ -89,5 +91,7 @@ func (db *Database) Add(target *paths.Path, command *executils.Process) {
 		File:      target.String(),
 	}
 
+	db.mux.Lock()
 	db.Contents = append(db.Contents, entry)
+	db.mux.Unlock()
 }
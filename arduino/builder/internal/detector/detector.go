@@ -57,6 +57,100 @@ type SketchLibrariesDetector struct {
 	librariesResolutionResults    map[string]libraryResolutionResult
 	includeFolders                paths.PathList
 	logger                        *logger.BuilderLogger
+	progressCB                    ProgressCB
+	filesScanned                  int
+	failOnMissingLibrary          bool
+}
+
+// MissingLibraryError is returned, when the detector is set to fail on a missing
+// library, if no library providing an included header is found
+type MissingLibraryError struct {
+	// Header is the included header file
+	Header string
+	// SearchedDirs are the libraries directories searched for the header
+	SearchedDirs paths.PathList
+	// Suggestions are the names of the libraries available in the Library
+	// Manager index that provide the header
+	Suggestions []string
+}
+
+func (e *MissingLibraryError) Error() string {
+	msg := tr("library providing %[1]s not found; searched %[2]s", e.Header, strings.Join(e.SearchedDirs.AsStrings(), ", "))
+	if len(e.Suggestions) > 0 {
+		msg += "; " + tr("try installing %s", strings.Join(e.Suggestions, ", "))
+	}
+	return msg
+}
+
+// SetFailOnMissingLibrary sets the detector to stop, with a MissingLibraryError,
+// at the first included header that is not provided by any installed library,
+// instead of reporting the preprocessor error.
+func (l *SketchLibrariesDetector) SetFailOnMissingLibrary(fail bool) {
+	l.failOnMissingLibrary = fail
+}
+
+func (l *SketchLibrariesDetector) missingLibraryError(header string) *MissingLibraryError {
+	res := &MissingLibraryError{
+		Header:       header,
+		SearchedDirs: paths.PathList{},
+		Suggestions:  []string{},
+	}
+	if l.librariesManager == nil {
+		return res
+	}
+	for _, dir := range l.librariesManager.LibrariesDir {
+		res.SearchedDirs.Add(dir.Path)
+	}
+	if index := l.librariesManager.Index; index != nil {
+		for _, indexLib := range index.FindLibrariesProvidingInclude(header) {
+			res.Suggestions = append(res.Suggestions, indexLib.Name)
+		}
+	}
+	return res
+}
+
+// Progress reports the progress of the libraries detection
+type Progress struct {
+	// FilesScanned is the number of source files already scanned for includes
+	FilesScanned int
+	// FilesTotal is the number of source files to scan found so far, it grows
+	// each time a new library is detected and its source files are queued
+	FilesTotal int
+	// CurrentFile is the source file being scanned
+	CurrentFile *paths.Path
+	// CurrentLibrary is the library containing the source file being scanned,
+	// nil if the file belongs to the sketch
+	CurrentLibrary *libraries.Library
+}
+
+// ProgressCB is called to report the progress of the libraries detection
+type ProgressCB func(*Progress)
+
+// SetProgressCallback sets the callback used to report the progress of the
+// libraries detection
+func (l *SketchLibrariesDetector) SetProgressCallback(cb ProgressCB) {
+	l.progressCB = cb
+}
+
+func (l *SketchLibrariesDetector) reportProgress(current *sourceFile, queueSize int) {
+	if l.progressCB == nil {
+		return
+	}
+	progress := &Progress{
+		FilesScanned: l.filesScanned,
+		FilesTotal:   l.filesScanned + queueSize,
+	}
+	if current != nil {
+		progress.FilesTotal++
+		progress.CurrentFile = current.SourcePath()
+		for _, library := range l.importedLibraries {
+			if library.SourceDir.EqualsTo(current.sourceRoot) {
+				progress.CurrentLibrary = library
+				break
+			}
+		}
+	}
+	l.progressCB(progress)
 }
 
 // NewSketchLibrariesDetector todo
@@ -265,13 +359,16 @@ func (l *SketchLibrariesDetector) findIncludes(
 			l.queueSourceFilesFromFolder(sourceFileQueue, srcSubfolderPath, true /* recurse */, sketchBuildPath, sketchBuildPath)
 		}
 
+		l.filesScanned = 0
 		for !sourceFileQueue.empty() {
 			err := l.findIncludesUntilDone(cache, sourceFileQueue, buildProperties, sketchBuildPath, librariesBuildPath, platformArch)
 			if err != nil {
 				cachePath.Remove()
 				return errors.WithStack(err)
 			}
+			l.filesScanned++
 		}
+		l.reportProgress(nil, 0)
 
 		// Finalize the cache
 		cache.ExpectEnd()
@@ -303,6 +400,7 @@ func (l *SketchLibrariesDetector) findIncludesUntilDone(
 ) error {
 	sourceFile := sourceFileQueue.pop()
 	sourcePath := sourceFile.SourcePath()
+	l.reportProgress(sourceFile, len(*sourceFileQueue))
 	targetFilePath := paths.NullPath()
 	depPath := sourceFile.DepfilePath()
 	objPath := sourceFile.ObjectPath()
@@ -351,6 +449,8 @@ func (l *SketchLibrariesDetector) findIncludesUntilDone(
 			preprocStdout, preprocStderr, preprocErr = preprocessor.GCC(sourcePath, targetFilePath, includeFolders, buildProperties)
 			if l.logger.Verbose() {
 				l.logger.WriteStdout(preprocStdout)
+			} else {
+				l.logger.RecordToolStdout(preprocStdout)
 			}
 			// Unwrap error and see if it is an ExitError.
 			if preprocErr == nil {
@@ -374,6 +474,9 @@ func (l *SketchLibrariesDetector) findIncludesUntilDone(
 		}
 
 		library := l.resolveLibrary(missingIncludeH, platformArch)
+		if library == nil && l.failOnMissingLibrary && len(l.librariesResolver.AlternativesFor(missingIncludeH)) == 0 {
+			return l.missingLibraryError(missingIncludeH)
+		}
 		if library == nil {
 			// Library could not be resolved, show error
 			if preprocErr == nil || preprocStderr == nil {
@@ -382,6 +485,8 @@ func (l *SketchLibrariesDetector) findIncludesUntilDone(
 				preprocStdout, preprocStderr, preprocErr = preprocessor.GCC(sourcePath, targetFilePath, includeFolders, buildProperties)
 				if l.logger.Verbose() {
 					l.logger.WriteStdout(preprocStdout)
+				} else {
+					l.logger.RecordToolStdout(preprocStdout)
 				}
 				if preprocErr == nil {
 					// If there is a missing #include in the cache, but running
@@ -660,6 +765,47 @@ func LibrariesLoader(
 	return lm, resolver, verboseOut.Bytes(), nil
 }
 
+// LibrariesSearchOrder returns the directories searched for libraries by
+// LibrariesLoader, from the highest to the lowest location priority: the
+// libraryDirs (each one a single library), the otherLibrariesDirs in the given
+// order, the libraries bundled with the target platform and with the actual
+// (referenced) platform, and the builtInLibrariesDirs. The directories that
+// don't exist are omitted.
+func LibrariesSearchOrder(
+	builtInLibrariesDirs *paths.Path, libraryDirs, otherLibrariesDirs paths.PathList,
+	actualPlatform, targetPlatform *cores.PlatformRelease,
+) paths.PathList {
+	res := paths.NewPathList()
+	add := func(dir *paths.Path) {
+		if dir == nil || !dir.IsDir() {
+			return
+		}
+		if abs, err := dir.Abs(); err == nil {
+			dir = abs
+		}
+		for _, d := range res {
+			if d.EquivalentTo(dir) {
+				return
+			}
+		}
+		res.Add(dir)
+	}
+	for _, dir := range libraryDirs {
+		add(dir)
+	}
+	for _, dir := range otherLibrariesDirs {
+		add(dir)
+	}
+	if targetPlatform != nil {
+		add(targetPlatform.GetLibrariesDir())
+	}
+	if actualPlatform != nil && actualPlatform != targetPlatform {
+		add(actualPlatform.GetLibrariesDir())
+	}
+	add(builtInLibrariesDirs)
+	return res
+}
+
 type includeCacheEntry struct {
 	Sourcefile  *paths.Path
 	Include     string
@@ -16,10 +16,20 @@
 package detector_test
 
 import (
+	"bytes"
+	"io"
+	"os/exec"
 	"testing"
 
 	"github.com/arduino/arduino-cli/arduino/builder/internal/detector"
+	"github.com/arduino/arduino-cli/arduino/builder/internal/logger"
+	"github.com/arduino/arduino-cli/arduino/cores"
+	"github.com/arduino/arduino-cli/arduino/libraries/librariesindex"
+	"github.com/arduino/arduino-cli/arduino/sketch"
+	"github.com/arduino/go-paths-helper"
+	"github.com/arduino/go-properties-orderedmap"
 	"github.com/stretchr/testify/require"
+	semver "go.bug.st/relaxed-semver"
 )
 
 func TestIncludesFinderWithRegExp(t *testing.T) {
@@ -75,3 +85,152 @@ func TestIncludesFinderWithRegExpPaddedIncludes4(t *testing.T) {
 
 	require.Equal(t, "register.h", include)
 }
+
+func TestFindIncludesProgress(t *testing.T) {
+	gpp, err := exec.LookPath("g++")
+	if err != nil {
+		t.Skip("g++ not available")
+	}
+
+	// LibA.h includes LibB.h, the sketch includes LibA.h only
+	librariesDir := paths.New(t.TempDir())
+	createLibrary := func(name, source string) {
+		libDir := librariesDir.Join(name)
+		require.NoError(t, libDir.MkdirAll())
+		require.NoError(t, libDir.Join(name+".h").WriteFile([]byte(source)))
+		require.NoError(t, libDir.Join(name+".cpp").WriteFile([]byte("#include \""+name+".h\"\n")))
+	}
+	createLibrary("LibA", "#include <LibB.h>\n")
+	createLibrary("LibB", "\n")
+
+	buildPath := paths.New(t.TempDir())
+	sketchBuildPath := buildPath.Join("sketch")
+	require.NoError(t, sketchBuildPath.MkdirAll())
+	require.NoError(t, sketchBuildPath.Join("sketch.ino.cpp").WriteFile([]byte("#include <LibA.h>\n")))
+	coreDir := paths.New(t.TempDir())
+
+	buildProperties := properties.NewMap()
+	buildProperties.Set("recipe.preproc.macros", `"`+gpp+`" {preproc.macros.flags} {includes} "{source_file}" -o "{preprocessed_file_path}"`)
+
+	platform := &cores.PlatformRelease{}
+	lm, resolver, _, err := detector.LibrariesLoader(false, nil, nil, nil, paths.PathList{librariesDir}, platform, platform)
+	require.NoError(t, err)
+	libsDetector := detector.NewSketchLibrariesDetector(lm, resolver, false, false, logger.New(io.Discard, io.Discard, false, ""))
+
+	progress := []*detector.Progress{}
+	libsDetector.SetProgressCallback(func(p *detector.Progress) {
+		progress = append(progress, p)
+	})
+	sk := &sketch.Sketch{MainFile: paths.New("sketch.ino")}
+	err = libsDetector.FindIncludes(buildPath, coreDir, nil, sketchBuildPath, sk, buildPath.Join("libraries"), buildProperties, "avr")
+	require.NoError(t, err)
+	require.Len(t, libsDetector.ImportedLibraries(), 2)
+
+	// The sketch, LibA.cpp and LibB.cpp are scanned, then the end of the detection is reported
+	require.Len(t, progress, 4)
+	require.Equal(t, sketchBuildPath.Join("sketch.ino.cpp"), progress[0].CurrentFile)
+	require.Nil(t, progress[0].CurrentLibrary)
+	require.Equal(t, 0, progress[0].FilesScanned)
+	require.Equal(t, 1, progress[0].FilesTotal)
+	for i, lib := range []string{"LibA", "LibB"} {
+		p := progress[i+1]
+		require.Equal(t, librariesDir.Join(lib, lib+".cpp"), p.CurrentFile)
+		require.Equal(t, lib, p.CurrentLibrary.Name)
+		require.Equal(t, i+1, p.FilesScanned)
+	}
+	require.Equal(t, 3, progress[1].FilesTotal)
+	end := progress[3]
+	require.Nil(t, end.CurrentFile)
+	require.Equal(t, 3, end.FilesScanned)
+	require.Equal(t, 3, end.FilesTotal)
+}
+
+func TestFindIncludesFailOnMissingLibrary(t *testing.T) {
+	gpp, err := exec.LookPath("g++")
+	if err != nil {
+		t.Skip("g++ not available")
+	}
+
+	librariesDir := paths.New(t.TempDir())
+	buildPath := paths.New(t.TempDir())
+	sketchBuildPath := buildPath.Join("sketch")
+	require.NoError(t, sketchBuildPath.MkdirAll())
+	require.NoError(t, sketchBuildPath.Join("sketch.ino.cpp").WriteFile([]byte("#include <Servo.h>\n")))
+	coreDir := paths.New(t.TempDir())
+
+	buildProperties := properties.NewMap()
+	buildProperties.Set("recipe.preproc.macros", `"`+gpp+`" {preproc.macros.flags} {includes} "{source_file}" -o "{preprocessed_file_path}"`)
+
+	platform := &cores.PlatformRelease{}
+	lm, resolver, _, err := detector.LibrariesLoader(false, nil, nil, nil, paths.PathList{librariesDir}, platform, platform)
+	require.NoError(t, err)
+	index := &librariesindex.Index{Libraries: map[string]*librariesindex.Library{}}
+	for name, includes := range map[string][]string{
+		"Servo":           {"Servo.h"},
+		"SlowMotionServo": {"SlowMotionServo.h", "Servo.h"},
+		"Wire":            {"Wire.h"},
+	} {
+		release := &librariesindex.Release{Version: semver.MustParse("1.0.0"), ProvidesIncludes: includes}
+		index.Libraries[name] = &librariesindex.Library{Name: name, Latest: release}
+	}
+	lm.Index = index
+
+	stderr := &bytes.Buffer{}
+	libsDetector := detector.NewSketchLibrariesDetector(lm, resolver, false, false, logger.New(io.Discard, stderr, false, ""))
+	libsDetector.SetFailOnMissingLibrary(true)
+	sk := &sketch.Sketch{MainFile: paths.New("sketch.ino")}
+	err = libsDetector.FindIncludes(buildPath, coreDir, nil, sketchBuildPath, sk, buildPath.Join("libraries"), buildProperties, "avr")
+	var missingErr *detector.MissingLibraryError
+	require.ErrorAs(t, err, &missingErr)
+	require.Equal(t, "Servo.h", missingErr.Header)
+	require.Equal(t, paths.PathList{librariesDir}, missingErr.SearchedDirs)
+	require.Equal(t, []string{"Servo", "SlowMotionServo"}, missingErr.Suggestions)
+	require.EqualError(t, err, "library providing Servo.h not found; searched "+librariesDir.String()+"; try installing Servo, SlowMotionServo")
+	// The preprocessor errors are not printed
+	require.Empty(t, stderr.String())
+}
+
+func TestLibrariesSearchOrder(t *testing.T) {
+	tmp := paths.New(t.TempDir())
+	mkdir := func(elems ...string) *paths.Path {
+		dir := tmp.Join(elems...)
+		require.NoError(t, dir.MkdirAll())
+		return dir
+	}
+	installLib := func(dir *paths.Path, name string) {
+		libDir := dir.Join(name)
+		require.NoError(t, libDir.MkdirAll())
+		require.NoError(t, libDir.Join("library.properties").WriteFile([]byte("name="+name+"\nversion=1.0.0\n")))
+		require.NoError(t, libDir.Join(name+".h").WriteFile([]byte{}))
+	}
+	singleLibrary := mkdir("single", "MyLib")
+	customLibraries := mkdir("custom")
+	sketchbookLibraries := mkdir("sketchbook", "libraries")
+	targetPlatform := &cores.PlatformRelease{InstallDir: mkdir("packages", "target")}
+	targetPlatformLibraries := mkdir("packages", "target", "libraries")
+	actualPlatform := &cores.PlatformRelease{InstallDir: mkdir("packages", "actual")}
+	actualPlatformLibraries := mkdir("packages", "actual", "libraries")
+	builtinLibraries := mkdir("builtin")
+
+	order := detector.LibrariesSearchOrder(
+		builtinLibraries,
+		paths.NewPathList(singleLibrary.String()),
+		paths.NewPathList(customLibraries.String(), tmp.Join("missing").String(), sketchbookLibraries.String()),
+		actualPlatform, targetPlatform)
+	require.Equal(t, paths.PathList{
+		singleLibrary, customLibraries, sketchbookLibraries,
+		targetPlatformLibraries, actualPlatformLibraries, builtinLibraries,
+	}, order)
+
+	// The referenced platform is not repeated if it's the target platform
+	order = detector.LibrariesSearchOrder(builtinLibraries, nil, nil, targetPlatform, targetPlatform)
+	require.Equal(t, paths.PathList{targetPlatformLibraries, builtinLibraries}, order)
+
+	// A library in a directory that comes first wins over a library with the same name
+	installLib(sketchbookLibraries, "Servo")
+	installLib(builtinLibraries, "Servo")
+	_, resolver, _, err := detector.LibrariesLoader(false, nil, builtinLibraries, nil,
+		paths.NewPathList(sketchbookLibraries.String()), targetPlatform, targetPlatform)
+	require.NoError(t, err)
+	require.True(t, resolver.ResolveFor("Servo.h", "avr").InstallDir.EquivalentTo(sketchbookLibraries.Join("Servo")))
+}
@@ -16,6 +16,7 @@
 package logger
 
 import (
+	"bytes"
 	"fmt"
 	"io"
 	"os"
@@ -30,6 +31,31 @@ type BuilderLogger struct {
 
 	verbose       bool
 	warningsLevel string
+
+	// If separateToolStreams is true the output of the tools is also
+	// collected in two distinct buffers, one for each stream, and as a
+	// sequence of chunks tagged with the stream they come from
+	separateToolStreams bool
+	toolStdout          bytes.Buffer
+	toolStderr          bytes.Buffer
+	toolOutput          []*ToolOutputChunk
+}
+
+// ToolStream is the output stream of a tool
+type ToolStream string
+
+const (
+	// ToolStdoutStream is the standard output of a tool
+	ToolStdoutStream ToolStream = "stdout"
+	// ToolStderrStream is the standard error of a tool
+	ToolStderrStream ToolStream = "stderr"
+)
+
+// ToolOutputChunk is a piece of the output of the tools, tagged with the
+// stream it has been written to
+type ToolOutputChunk struct {
+	Stream ToolStream
+	Data   []byte
 }
 
 // New fixdoc
@@ -69,6 +95,7 @@ func (l *BuilderLogger) Warn(msg string) {
 func (l *BuilderLogger) WriteStdout(data []byte) (int, error) {
 	l.stdLock.Lock()
 	defer l.stdLock.Unlock()
+	l.recordToolOutput(ToolStdoutStream, data)
 	return l.stdout.Write(data)
 }
 
@@ -76,9 +103,74 @@ func (l *BuilderLogger) WriteStdout(data []byte) (int, error) {
 func (l *BuilderLogger) WriteStderr(data []byte) (int, error) {
 	l.stdLock.Lock()
 	defer l.stdLock.Unlock()
+	l.recordToolOutput(ToolStderrStream, data)
 	return l.stderr.Write(data)
 }
 
+// RecordToolStdout collects the given tool output together with the tool stdout
+// written with WriteStdout, without displaying it. It does nothing if the tool
+// streams are not separated.
+func (l *BuilderLogger) RecordToolStdout(data []byte) (int, error) {
+	l.stdLock.Lock()
+	defer l.stdLock.Unlock()
+	l.recordToolOutput(ToolStdoutStream, data)
+	return len(data), nil
+}
+
+// recordToolOutput collects the given tool output, if the tool streams are
+// separated. The caller must hold stdLock.
+func (l *BuilderLogger) recordToolOutput(stream ToolStream, data []byte) {
+	if !l.separateToolStreams || len(data) == 0 {
+		return
+	}
+	if stream == ToolStdoutStream {
+		l.toolStdout.Write(data)
+	} else {
+		l.toolStderr.Write(data)
+	}
+	if n := len(l.toolOutput); n > 0 && l.toolOutput[n-1].Stream == stream {
+		l.toolOutput[n-1].Data = append(l.toolOutput[n-1].Data, data...)
+		return
+	}
+	l.toolOutput = append(l.toolOutput, &ToolOutputChunk{Stream: stream, Data: bytes.Clone(data)})
+}
+
+// SetSeparateToolStreams enables or disables the collection of the tools
+// output: if enabled the data written to stdout and stderr by the tools is
+// still displayed, but it's also kept in two separate buffers returned by
+// ToolStdout and ToolStderr.
+func (l *BuilderLogger) SetSeparateToolStreams(enable bool) {
+	l.stdLock.Lock()
+	defer l.stdLock.Unlock()
+	l.separateToolStreams = enable
+}
+
+// ToolStdout returns the stdout of the tools collected so far
+func (l *BuilderLogger) ToolStdout() []byte {
+	l.stdLock.Lock()
+	defer l.stdLock.Unlock()
+	return bytes.Clone(l.toolStdout.Bytes())
+}
+
+// ToolStderr returns the stderr of the tools collected so far
+func (l *BuilderLogger) ToolStderr() []byte {
+	l.stdLock.Lock()
+	defer l.stdLock.Unlock()
+	return bytes.Clone(l.toolStderr.Bytes())
+}
+
+// ToolOutput returns the output of the tools collected so far, in the order
+// it has been written, split in chunks tagged with their stream
+func (l *BuilderLogger) ToolOutput() []*ToolOutputChunk {
+	l.stdLock.Lock()
+	defer l.stdLock.Unlock()
+	res := make([]*ToolOutputChunk, len(l.toolOutput))
+	for i, chunk := range l.toolOutput {
+		res[i] = &ToolOutputChunk{Stream: chunk.Stream, Data: bytes.Clone(chunk.Data)}
+	}
+	return res
+}
+
 // Verbose fixdoc
 func (l *BuilderLogger) Verbose() bool {
 	return l.verbose
@@ -86,15 +178,40 @@ func (l *BuilderLogger) Verbose() bool {
 
 // WarningsLevel fixdoc
 func (l *BuilderLogger) WarningsLevel() string {
+	l.stdLock.Lock()
+	defer l.stdLock.Unlock()
 	return l.warningsLevel
 }
 
-// Stdout fixdoc
+// SetWarningsLevel changes the warnings level, an empty level is the same as "none"
+func (l *BuilderLogger) SetWarningsLevel(warningsLevel string) {
+	if warningsLevel == "" {
+		warningsLevel = "none"
+	}
+	l.stdLock.Lock()
+	defer l.stdLock.Unlock()
+	l.warningsLevel = warningsLevel
+}
+
+// Stdout returns a writer for the tools stdout, the data is written with WriteStdout
 func (l *BuilderLogger) Stdout() io.Writer {
-	return l.stdout
+	return writerFunc(l.WriteStdout)
 }
 
-// Stderr fixdoc
+// Stderr returns a writer for the tools stderr, the data is written with WriteStderr
 func (l *BuilderLogger) Stderr() io.Writer {
-	return l.stderr
+	return writerFunc(l.WriteStderr)
+}
+
+// ToolStdoutRecorder returns a writer for the tools stdout that must not be
+// displayed, the data is written with RecordToolStdout
+func (l *BuilderLogger) ToolStdoutRecorder() io.Writer {
+	return writerFunc(l.RecordToolStdout)
+}
+
+// writerFunc adapts a function to the io.Writer interface
+type writerFunc func([]byte) (int, error)
+
+func (f writerFunc) Write(data []byte) (int, error) {
+	return f(data)
 }
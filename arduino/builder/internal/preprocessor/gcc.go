@@ -28,11 +28,35 @@ import (
 	"github.com/pkg/errors"
 )
 
+// defaultPreprocMacrosFlags are the flags used to run the gcc preprocessor if
+// the platform doesn't define its own preproc.macros.flags.
+const defaultPreprocMacrosFlags = "-w -x c++ -E -CC"
+
+// WithExtraDefines returns a copy of the given build properties where the
+// preprocessor flags are extended with the given definitions (in the form
+// "NAME" or "NAME=VALUE"). The build properties used by the rest of the build
+// are not modified.
+func WithExtraDefines(buildProperties *properties.Map, defines []string) *properties.Map {
+	if len(defines) == 0 {
+		return buildProperties
+	}
+	res := buildProperties.Clone()
+	flags, ok := res.GetOk("preproc.macros.flags")
+	if !ok {
+		flags = defaultPreprocMacrosFlags
+	}
+	for _, define := range defines {
+		flags += " -D" + strings.TrimPrefix(define, "-D")
+	}
+	res.Set("preproc.macros.flags", flags)
+	return res
+}
+
 // GCC performs a run of the gcc preprocess (macro/includes expansion). The function outputs the result
 // to targetFilePath. Returns the stdout/stderr of gcc if any.
 func GCC(sourceFilePath *paths.Path, targetFilePath *paths.Path, includes paths.PathList, buildProperties *properties.Map) ([]byte, []byte, error) {
 	gccBuildProperties := properties.NewMap()
-	gccBuildProperties.Set("preproc.macros.flags", "-w -x c++ -E -CC")
+	gccBuildProperties.Set("preproc.macros.flags", defaultPreprocMacrosFlags)
 	gccBuildProperties.Merge(buildProperties)
 	gccBuildProperties.Set("build.library_discovery_phase", "1")
 	gccBuildProperties.SetPath("source_file", sourceFilePath)
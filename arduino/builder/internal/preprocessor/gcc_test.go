@@ -0,0 +1,61 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2023 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package preprocessor
+
+import (
+	"os/exec"
+	"testing"
+
+	"github.com/arduino/go-paths-helper"
+	"github.com/arduino/go-properties-orderedmap"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGCCWithExtraDefines(t *testing.T) {
+	gpp, err := exec.LookPath("g++")
+	if err != nil {
+		t.Skip("g++ not available")
+	}
+	tmp := paths.New(t.TempDir())
+	source := tmp.Join("sketch.ino.cpp")
+	require.NoError(t, source.WriteFile([]byte(
+		"#ifdef DEBUG\nvoid debugEnabled() {}\n#endif\n"+
+			"#if LEVEL == 3\nvoid levelThree() {}\n#endif\n")))
+	target := tmp.Join("sketch_merged.cpp")
+
+	buildProperties := properties.NewMap()
+	buildProperties.Set("recipe.preproc.macros", `"`+gpp+`" {preproc.macros.flags} {includes} "{source_file}" -o "{preprocessed_file_path}"`)
+
+	_, _, err = GCC(source, target, nil, buildProperties)
+	require.NoError(t, err)
+	preprocessed, err := target.ReadFile()
+	require.NoError(t, err)
+	require.NotContains(t, string(preprocessed), "debugEnabled")
+	require.NotContains(t, string(preprocessed), "levelThree")
+
+	preprocBuildProperties := WithExtraDefines(buildProperties, []string{"-DDEBUG", "LEVEL=3"})
+	require.Equal(t, "-w -x c++ -E -CC -DDEBUG -DLEVEL=3", preprocBuildProperties.Get("preproc.macros.flags"))
+	_, _, err = GCC(source, target, nil, preprocBuildProperties)
+	require.NoError(t, err)
+	preprocessed, err = target.ReadFile()
+	require.NoError(t, err)
+	require.Contains(t, string(preprocessed), "void debugEnabled() {}")
+	require.Contains(t, string(preprocessed), "void levelThree() {}")
+
+	// The original build properties are not modified
+	require.False(t, buildProperties.ContainsKey("preproc.macros.flags"))
+	require.Same(t, buildProperties, WithExtraDefines(buildProperties, nil))
+}
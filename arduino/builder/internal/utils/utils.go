@@ -20,6 +20,7 @@ import (
 	"strings"
 	"unicode"
 
+	arduinoutils "github.com/arduino/arduino-cli/arduino/utils"
 	"github.com/arduino/arduino-cli/i18n"
 	f "github.com/arduino/arduino-cli/internal/algorithms"
 	"github.com/arduino/go-paths-helper"
@@ -145,6 +146,68 @@ func unescapeDep(s string) string {
 	return s
 }
 
+// ParseDependencyFile returns the target and the dependencies listed in a
+// make-style dependency file, as the ones generated by gcc with the -MMD flag.
+// The phony targets added by the -MP flag are ignored.
+func ParseDependencyFile(dependencyFile *paths.Path) (string, []string, error) {
+	data, err := dependencyFile.ReadFile()
+	if err != nil {
+		return "", nil, errors.WithStack(err)
+	}
+	content := strings.ReplaceAll(string(data), "\r\n", "\n")
+	content = strings.ReplaceAll(content, "\\\n", " ")
+
+	target := ""
+	deps := []string{}
+	for _, rule := range strings.Split(content, "\n") {
+		tokens := splitDepTokens(rule)
+		for i, token := range tokens {
+			if !strings.HasSuffix(token, ":") {
+				continue
+			}
+			if target == "" {
+				target = unescapeDep(strings.TrimSuffix(token, ":"))
+			}
+			for _, dep := range tokens[i+1:] {
+				deps = append(deps, unescapeDep(dep))
+			}
+			break
+		}
+	}
+	return target, deps, nil
+}
+
+// splitDepTokens splits a dependency file rule on the unescaped whitespaces
+func splitDepTokens(rule string) []string {
+	tokens := []string{}
+	current := strings.Builder{}
+	escaped := false
+	for _, c := range rule {
+		if escaped {
+			current.WriteRune(c)
+			escaped = false
+			continue
+		}
+		if c == '\\' {
+			current.WriteRune(c)
+			escaped = true
+			continue
+		}
+		if c == ' ' || c == '\t' {
+			if current.Len() > 0 {
+				tokens = append(tokens, current.String())
+				current.Reset()
+			}
+			continue
+		}
+		current.WriteRune(c)
+	}
+	if current.Len() > 0 {
+		tokens = append(tokens, current.String())
+	}
+	return tokens
+}
+
 // NormalizeUTF8 byte slice
 // TODO: use it more often troughout all the project (maybe on logger interface?)
 func NormalizeUTF8(buf []byte) []byte {
@@ -193,7 +256,7 @@ func FindFilesInFolder(dir *paths.Path, recurse bool, extensions ...string) (pat
 			filterOutHiddenFiles,
 			filterOutSCCS,
 		)
-		return dir.ReadDirRecursiveFiltered(dirFilter, fileFilter)
+		return arduinoutils.FollowAllSymlinks.ReadDirRecursiveFiltered(dir, dir, dirFilter, fileFilter)
 	}
 	return dir.ReadDir(fileFilter)
 }
@@ -59,17 +59,45 @@ func directoryContainsFile(folder *paths.Path) bool {
 	return false
 }
 
+// strictPrecompiledLibrariesProperty is the build property that, when true,
+// makes the build fail if a library declared as precompiled=true doesn't
+// provide the binaries for the target, instead of compiling its sources.
+const strictPrecompiledLibrariesProperty = "build.precompiled_libraries.strict"
+
+// PrecompiledLibraryNotFoundError is returned when a library declared as
+// precompiled=true doesn't provide the binaries for the target being built and
+// the build property build.precompiled_libraries.strict is true.
+type PrecompiledLibraryNotFoundError struct {
+	// Library is the name of the precompiled library
+	Library string
+	// MCU is the target architecture (build.mcu) of the build
+	MCU string
+	// SearchedDirs are the folders where the binaries have been looked for
+	SearchedDirs paths.PathList
+}
+
+func (e *PrecompiledLibraryNotFoundError) Error() string {
+	return tr("precompiled library %[1]s doesn't provide binaries for %[2]s; searched %[3]s",
+		e.Library, e.MCU, strings.Join(e.SearchedDirs.AsStrings(), ", "))
+}
+
+// findExpectedPrecompiledLibFolder returns the folder containing the binaries
+// of the precompiled library that match the target, or nil if not found.
+// The folders that have been searched are returned too.
 func (b *Builder) findExpectedPrecompiledLibFolder(
 	library *libraries.Library,
 	buildProperties *properties.Map,
-) *paths.Path {
+) (*paths.Path, paths.PathList) {
 	mcu := buildProperties.Get("build.mcu")
 	// Add fpu specifications if they exist
 	// To do so, resolve recipe.cpp.o.pattern,
 	// search for -mfpu=xxx -mfloat-abi=yyy and add to a subfolder
-	command, _ := b.prepareCommandForRecipe(buildProperties, "recipe.cpp.o.pattern", true)
+	var args []string
+	if command, err := b.prepareCommandForRecipe(buildProperties, "recipe.cpp.o.pattern", true); err == nil {
+		args = command.GetArgs()
+	}
 	fpuSpecs := ""
-	for _, el := range command.GetArgs() {
+	for _, el := range args {
 		if strings.Contains(el, FpuCflag) {
 			toAdd := strings.Split(el, "=")
 			if len(toAdd) > 1 {
@@ -78,7 +106,7 @@ func (b *Builder) findExpectedPrecompiledLibFolder(
 			}
 		}
 	}
-	for _, el := range command.GetArgs() {
+	for _, el := range args {
 		if strings.Contains(el, FloatAbiCflag) {
 			toAdd := strings.Split(el, "=")
 			if len(toAdd) > 1 {
@@ -90,24 +118,28 @@ func (b *Builder) findExpectedPrecompiledLibFolder(
 
 	b.logger.Info(tr("Library %[1]s has been declared precompiled:", library.Name))
 
+	searched := paths.NewPathList()
+
 	// Try directory with full fpuSpecs first, if available
 	if len(fpuSpecs) > 0 {
 		fpuSpecs = strings.TrimRight(fpuSpecs, "-")
 		fullPrecompDir := library.SourceDir.Join(mcu).Join(fpuSpecs)
 		if fullPrecompDir.Exist() && directoryContainsFile(fullPrecompDir) {
 			b.logger.Info(tr("Using precompiled library in %[1]s", fullPrecompDir))
-			return fullPrecompDir
+			return fullPrecompDir, searched
 		}
 		b.logger.Info(tr(`Precompiled library in "%[1]s" not found`, fullPrecompDir))
+		searched.Add(fullPrecompDir)
 	}
 
 	precompDir := library.SourceDir.Join(mcu)
 	if precompDir.Exist() && directoryContainsFile(precompDir) {
 		b.logger.Info(tr("Using precompiled library in %[1]s", precompDir))
-		return precompDir
+		return precompDir, searched
 	}
 	b.logger.Info(tr(`Precompiled library in "%[1]s" not found`, precompDir))
-	return nil
+	searched.Add(precompDir)
+	return nil, searched
 }
 
 func (b *Builder) compileLibraries(libraries libraries.List, includes []string) (paths.PathList, error) {
@@ -139,16 +171,29 @@ func (b *Builder) compileLibrary(library *libraries.Library, includes []string)
 	}
 
 	objectFiles := paths.NewPathList()
+	ldflagsAdded := false
 
 	if library.Precompiled {
 		coreSupportPrecompiled := b.buildProperties.ContainsKey("compiler.libraries.ldflags")
-		precompiledPath := b.findExpectedPrecompiledLibFolder(
+		precompiledPath, searchedDirs := b.findExpectedPrecompiledLibFolder(
 			library,
 			b.buildProperties,
 		)
 
 		if !coreSupportPrecompiled {
 			b.logger.Info(tr("The platform does not support '%[1]s' for precompiled libraries.", "compiler.libraries.ldflags"))
+		} else if precompiledPath == nil && !library.PrecompiledWithSources {
+			// The sources are compiled as a fallback, unless the build explicitly
+			// requires the binaries of all the precompiled libraries
+			notFound := &PrecompiledLibraryNotFoundError{
+				Library:      library.Name,
+				MCU:          b.buildProperties.Get("build.mcu"),
+				SearchedDirs: searchedDirs,
+			}
+			if b.buildProperties.GetBoolean(strictPrecompiledLibrariesProperty) {
+				return nil, notFound
+			}
+			b.logger.Warn(tr("Warning: %[1]s, compiling the sources instead", notFound))
 		} else if precompiledPath != nil {
 			// Find all libraries in precompiledPath
 			libs, err := precompiledPath.ReadDir()
@@ -169,6 +214,7 @@ func (b *Builder) compileLibrary(library *libraries.Library, includes []string)
 
 			currLDFlags := b.buildProperties.Get("compiler.libraries.ldflags")
 			b.buildProperties.Set("compiler.libraries.ldflags", currLDFlags+" \"-L"+precompiledPath.String()+"\" "+libsCmd+" ")
+			ldflagsAdded = true
 
 			// TODO: This codepath is just taken for .a with unusual names that would
 			// be ignored by -L / -l methods.
@@ -187,6 +233,10 @@ func (b *Builder) compileLibrary(library *libraries.Library, includes []string)
 		}
 	}
 
+	if library.LDflags != "" && !ldflagsAdded {
+		b.buildArtifacts.librariesLDFlags = append(b.buildArtifacts.librariesLDFlags, library.LDflags)
+	}
+
 	if library.Layout == libraries.RecursiveLayout {
 		libObjectFiles, err := b.compileFiles(
 			library.SourceDir, libraryBuildPath,
@@ -278,7 +328,7 @@ func (b *Builder) warnAboutArchIncompatibleLibraries(importedLibraries libraries
 	}
 
 	for _, importedLibrary := range importedLibraries {
-		if !importedLibrary.SupportsAnyArchitectureIn(archs...) {
+		if !importedLibrary.SupportsAnyArchitectureIn(b.architectureFamilies, archs...) {
 			b.logger.Info(
 				tr("WARNING: library %[1]s claims to run on %[2]s architecture(s) and may be incompatible with your current board which runs on %[3]s architecture(s).",
 					importedLibrary.Name,
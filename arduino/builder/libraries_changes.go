@@ -0,0 +1,155 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2023 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package builder
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"sort"
+
+	"github.com/arduino/arduino-cli/arduino/libraries"
+	arduinoutils "github.com/arduino/arduino-cli/arduino/utils"
+	"github.com/arduino/go-paths-helper"
+)
+
+// librariesStateFileName is the file, in the build path, where the state of the
+// libraries used by the last build is saved
+const librariesStateFileName = "libraries.state.json"
+
+// LibrariesChanges lists the names of the libraries that changed since the
+// previous build of the sketch in the same build path
+type LibrariesChanges struct {
+	// Added are the libraries used now that were not used by the previous build
+	Added []string
+	// Removed are the libraries used by the previous build that are not used anymore
+	Removed []string
+	// Changed are the libraries whose version, location or content changed
+	Changed []string
+}
+
+// IsEmpty returns true if no library changed
+func (c *LibrariesChanges) IsEmpty() bool {
+	return len(c.Added) == 0 && len(c.Removed) == 0 && len(c.Changed) == 0
+}
+
+// libraryState is the state of a library, used to detect its changes
+type libraryState struct {
+	Version    string `json:"version"`
+	InstallDir string `json:"install_dir"`
+	Hash       string `json:"hash"`
+}
+
+// LibrariesChanges returns the libraries added, removed or changed by the last
+// build compared to the previous build of the sketch in the same build path.
+// If there was no previous build (or the build path has been cleaned) all the
+// libraries are reported as added. It returns nil if the sketch has not been
+// built yet.
+func (b *Builder) LibrariesChanges() *LibrariesChanges {
+	return b.librariesChanges
+}
+
+// updateLibrariesState compares the state of the given libraries with the one
+// saved by the previous build, and saves the new state.
+func (b *Builder) updateLibrariesState(importedLibraries libraries.List) error {
+	stateFile := b.buildPath.Join(librariesStateFileName)
+	current, err := computeLibrariesState(importedLibraries, b.symlinkPolicy)
+	if err != nil {
+		return err
+	}
+	b.librariesChanges = diffLibrariesState(loadLibrariesState(stateFile), current)
+	data, err := json.MarshalIndent(current, "", "  ")
+	if err != nil {
+		return err
+	}
+	return stateFile.WriteFile(data)
+}
+
+func loadLibrariesState(stateFile *paths.Path) map[string]*libraryState {
+	res := map[string]*libraryState{}
+	data, err := stateFile.ReadFile()
+	if err != nil {
+		return res
+	}
+	if err := json.Unmarshal(data, &res); err != nil {
+		return map[string]*libraryState{}
+	}
+	return res
+}
+
+func computeLibrariesState(importedLibraries libraries.List, symlinkPolicy arduinoutils.SymlinkPolicy) (map[string]*libraryState, error) {
+	res := map[string]*libraryState{}
+	for _, library := range importedLibraries {
+		hash, err := hashLibraryFiles(library.InstallDir, symlinkPolicy)
+		if err != nil {
+			return nil, err
+		}
+		state := &libraryState{InstallDir: library.InstallDir.String(), Hash: hash}
+		if library.Version != nil {
+			state.Version = library.Version.String()
+		}
+		res[library.Name] = state
+	}
+	return res, nil
+}
+
+func diffLibrariesState(previous, current map[string]*libraryState) *LibrariesChanges {
+	res := &LibrariesChanges{Added: []string{}, Removed: []string{}, Changed: []string{}}
+	for name, state := range current {
+		if prev, ok := previous[name]; !ok {
+			res.Added = append(res.Added, name)
+		} else if *prev != *state {
+			res.Changed = append(res.Changed, name)
+		}
+	}
+	for name := range previous {
+		if _, ok := current[name]; !ok {
+			res.Removed = append(res.Removed, name)
+		}
+	}
+	sort.Strings(res.Added)
+	sort.Strings(res.Removed)
+	sort.Strings(res.Changed)
+	return res
+}
+
+// hashLibraryFiles returns a hash of the names, the sizes and the modification
+// times of all the files of the library, the hidden files and folders (like
+// .git) are ignored. The content of the files is not read, to keep the check
+// cheap even for the builds that don't change anything. The symlinks are
+// followed according to the given policy.
+func hashLibraryFiles(dir *paths.Path, symlinkPolicy arduinoutils.SymlinkPolicy) (string, error) {
+	files, err := symlinkPolicy.ReadDirRecursiveFiltered(dir, dir, paths.FilterOutPrefixes("."), paths.FilterOutDirectories(), paths.FilterOutPrefixes("."))
+	if err != nil {
+		return "", err
+	}
+	files.Sort()
+	hash := sha256.New()
+	for _, file := range files {
+		rel, err := file.RelFrom(dir)
+		if err != nil {
+			return "", err
+		}
+		info, err := file.Stat()
+		if err != nil {
+			return "", err
+		}
+		fmt.Fprintf(hash, "%s\x00%d\x00%d\x00", filepath.ToSlash(rel.String()), info.Size(), info.ModTime().UnixNano())
+	}
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
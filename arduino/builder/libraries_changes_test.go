@@ -0,0 +1,97 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2023 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package builder
+
+import (
+	"os"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/arduino/arduino-cli/arduino/libraries"
+	"github.com/arduino/arduino-cli/arduino/utils"
+	"github.com/arduino/go-paths-helper"
+	"github.com/stretchr/testify/require"
+	semver "go.bug.st/relaxed-semver"
+)
+
+func TestLibrariesChanges(t *testing.T) {
+	librariesDir := paths.New(t.TempDir())
+	newLibrary := func(name, version string) *libraries.Library {
+		dir := librariesDir.Join(name)
+		require.NoError(t, dir.Join("src").MkdirAll())
+		require.NoError(t, dir.Join("src", name+".h").WriteFile([]byte("// "+name)))
+		require.NoError(t, dir.Join(".git").MkdirAll())
+		return &libraries.Library{Name: name, InstallDir: dir, Version: semver.MustParse(version)}
+	}
+	servo := newLibrary("Servo", "1.0.0")
+	wire := newLibrary("Wire", "1.0.0")
+	spi := newLibrary("SPI", "1.0.0")
+
+	b := &Builder{buildPath: paths.New(t.TempDir())}
+	require.Nil(t, b.LibrariesChanges())
+
+	// First build: all the libraries are new
+	require.NoError(t, b.updateLibrariesState(libraries.List{servo, wire, spi}))
+	require.Equal(t, &LibrariesChanges{Added: []string{"SPI", "Servo", "Wire"}, Removed: []string{}, Changed: []string{}}, b.LibrariesChanges())
+
+	// Nothing changed, hidden files are ignored
+	require.NoError(t, wire.InstallDir.Join(".git", "HEAD").WriteFile([]byte("ref")))
+	require.NoError(t, b.updateLibrariesState(libraries.List{servo, wire, spi}))
+	require.True(t, b.LibrariesChanges().IsEmpty())
+
+	// Only the modified library is reported
+	require.NoError(t, wire.InstallDir.Join("src", "Wire.cpp").WriteFile([]byte("int wire;")))
+	require.NoError(t, b.updateLibrariesState(libraries.List{servo, wire, spi}))
+	require.Equal(t, &LibrariesChanges{Added: []string{}, Removed: []string{}, Changed: []string{"Wire"}}, b.LibrariesChanges())
+
+	// A file rewritten with the same size is detected by its modification time
+	header := spi.InstallDir.Join("src", "SPI.h")
+	require.NoError(t, header.WriteFile([]byte("// XXX")))
+	later := time.Now().Add(time.Hour)
+	require.NoError(t, os.Chtimes(header.String(), later, later))
+	require.NoError(t, b.updateLibrariesState(libraries.List{servo, wire, spi}))
+	require.Equal(t, &LibrariesChanges{Added: []string{}, Removed: []string{}, Changed: []string{"SPI"}}, b.LibrariesChanges())
+
+	// A version change and a removed library
+	servo.Version = semver.MustParse("1.1.0")
+	require.NoError(t, b.updateLibrariesState(libraries.List{servo, wire}))
+	require.Equal(t, &LibrariesChanges{Added: []string{}, Removed: []string{"SPI"}, Changed: []string{"Servo"}}, b.LibrariesChanges())
+}
+
+func TestLibrariesChangesWithSymlinkLoop(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("symlinks are not supported on windows")
+	}
+	dir := paths.New(t.TempDir(), "Loop")
+	require.NoError(t, dir.Join("src").MkdirAll())
+	require.NoError(t, dir.Join("src", "Loop.h").WriteFile([]byte("// Loop")))
+	require.NoError(t, os.Symlink("..", dir.Join("src", "back").String()))
+	loop := &libraries.Library{Name: "Loop", InstallDir: dir}
+
+	for _, policy := range []utils.SymlinkPolicy{utils.FollowSymlinksWithinRoot, utils.FollowAllSymlinks, utils.NeverFollowSymlinks} {
+		b := &Builder{buildPath: paths.New(t.TempDir()), symlinkPolicy: policy}
+		done := make(chan error)
+		go func() { done <- b.updateLibrariesState(libraries.List{loop}) }()
+		select {
+		case err := <-done:
+			require.NoError(t, err)
+		case <-time.After(10 * time.Second):
+			require.FailNow(t, "the scan of the library doesn't terminate", "policy %s", policy)
+		}
+		require.Equal(t, []string{"Loop"}, b.LibrariesChanges().Added)
+	}
+}
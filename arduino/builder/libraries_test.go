@@ -0,0 +1,164 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2023 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package builder
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"os/exec"
+	"testing"
+
+	"github.com/arduino/arduino-cli/arduino/builder/internal/logger"
+	"github.com/arduino/arduino-cli/arduino/builder/internal/progress"
+	"github.com/arduino/arduino-cli/arduino/libraries"
+	"github.com/arduino/go-paths-helper"
+	"github.com/arduino/go-properties-orderedmap"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCompilePrecompiledLibrary(t *testing.T) {
+	libDir := paths.New(t.TempDir(), "PrecompiledLib")
+	srcDir := libDir.Join("src")
+	require.NoError(t, srcDir.Join("cortex-m0plus").MkdirAll())
+	require.NoError(t, srcDir.Join("PrecompiledLib.h").WriteFile([]byte("int foo();\n")))
+	require.NoError(t, srcDir.Join("PrecompiledLib.cpp").WriteFile([]byte("this does not compile\n")))
+	require.NoError(t, srcDir.Join("cortex-m0plus", "libPrecompiledLib.a").WriteFile([]byte{}))
+	require.NoError(t, srcDir.Join("cortex-m0plus", "extra.a").WriteFile([]byte{}))
+
+	newBuilder := func(mcu string) *Builder {
+		buildPath := paths.New(t.TempDir())
+		buildProperties := properties.NewMap()
+		buildProperties.Set("build.mcu", mcu)
+		buildProperties.Set("compiler.libraries.ldflags", "")
+		buildProperties.Set("recipe.cpp.o.pattern", `false -c "{source_file}" -o "{object_file}"`)
+		return &Builder{
+			buildProperties:    buildProperties,
+			buildPath:          buildPath,
+			librariesBuildPath: buildPath.Join("libraries"),
+			logger:             logger.New(io.Discard, io.Discard, false, ""),
+		}
+	}
+	newLibrary := func(precompiled string) *libraries.Library {
+		return &libraries.Library{
+			Name:                   "PrecompiledLib",
+			DirName:                "PrecompiledLib",
+			InstallDir:             libDir,
+			SourceDir:              srcDir,
+			Layout:                 libraries.RecursiveLayout,
+			Precompiled:            true,
+			PrecompiledWithSources: precompiled == "full",
+		}
+	}
+
+	t.Run("ArchiveLinkedAndSourcesSkipped", func(t *testing.T) {
+		b := newBuilder("cortex-m0plus")
+		objectFiles, err := b.compileLibrary(newLibrary("full"), nil)
+		require.NoError(t, err)
+		require.Equal(t, paths.PathList{srcDir.Join("cortex-m0plus", "extra.a")}, objectFiles)
+		ldflags := b.buildProperties.Get("compiler.libraries.ldflags")
+		require.Contains(t, ldflags, `"-L`+srcDir.Join("cortex-m0plus").String()+`"`)
+		require.Contains(t, ldflags, "-lPrecompiledLib")
+		require.False(t, b.librariesBuildPath.Join("PrecompiledLib", "PrecompiledLib.cpp.o").Exist())
+	})
+
+	t.Run("MissingBinariesFallbackToSources", func(t *testing.T) {
+		b := newBuilder("cortex-m4")
+		stderr := &bytes.Buffer{}
+		b.logger = logger.New(io.Discard, stderr, false, "")
+		b.Progress = progress.New(nil)
+		_, err := b.compileLibrary(newLibrary("true"), nil)
+		// The sources are compiled (and fail with the dummy recipe)
+		var notFound *PrecompiledLibraryNotFoundError
+		require.False(t, errors.As(err, &notFound))
+		require.Error(t, err)
+		require.Contains(t, stderr.String(), "Warning: precompiled library PrecompiledLib doesn't provide binaries for cortex-m4")
+		require.Contains(t, stderr.String(), "compiling the sources instead")
+	})
+
+	t.Run("MissingBinariesStrict", func(t *testing.T) {
+		b := newBuilder("cortex-m4")
+		b.buildProperties.Set("build.precompiled_libraries.strict", "true")
+		_, err := b.compileLibrary(newLibrary("true"), nil)
+		var notFound *PrecompiledLibraryNotFoundError
+		require.True(t, errors.As(err, &notFound))
+		require.Equal(t, "PrecompiledLib", notFound.Library)
+		require.Equal(t, "cortex-m4", notFound.MCU)
+		require.Equal(t, paths.PathList{srcDir.Join("cortex-m4")}, notFound.SearchedDirs)
+	})
+}
+
+func TestLibrariesLDFlags(t *testing.T) {
+	gpp, err := exec.LookPath("g++")
+	if err != nil {
+		t.Skip("g++ not available")
+	}
+	sh, err := exec.LookPath("sh")
+	if err != nil {
+		t.Skip("sh not available")
+	}
+	newLibrary := func(name, ldflags string) *libraries.Library {
+		libDir := paths.New(t.TempDir(), name)
+		require.NoError(t, libDir.Join("src").MkdirAll())
+		require.NoError(t, libDir.Join("src", name+".cpp").WriteFile([]byte("int "+name+"() { return 0; }\n")))
+		return &libraries.Library{
+			Name:       name,
+			DirName:    name,
+			InstallDir: libDir,
+			SourceDir:  libDir.Join("src"),
+			Layout:     libraries.RecursiveLayout,
+			LDflags:    ldflags,
+		}
+	}
+	libs := libraries.List{
+		newLibrary("First", "-lfirst -lm"),
+		newLibrary("NoFlags", ""),
+		newLibrary("Second", "-lsecond"),
+	}
+
+	link := func(recipe string) []string {
+		buildPath := paths.New(t.TempDir())
+		buildProperties := properties.NewMap()
+		buildProperties.Set("compiler.libraries.ldflags", "")
+		buildProperties.Set("recipe.cpp.o.pattern", `"`+gpp+`" -c "{source_file}" -o "{object_file}"`)
+		buildProperties.Set("recipe.c.combine.pattern", `"`+sh+`" -c "exit 0" `+recipe)
+		b := &Builder{
+			buildProperties:    buildProperties,
+			buildPath:          buildPath,
+			librariesBuildPath: buildPath.Join("libraries"),
+			buildArtifacts:     &buildArtifacts{coreArchiveFilePath: buildPath.Join("core", "core.a")},
+			logger:             logger.New(io.Discard, io.Discard, false, ""),
+			Progress:           progress.New(nil),
+		}
+		b.RecordExecutedCommands(true)
+		require.NoError(t, b.buildLibraries(nil, libs))
+		require.NoError(t, b.link())
+		commands := b.ExecutedCommands()
+		require.NotEmpty(t, commands)
+		args := commands[len(commands)-1].Args
+		require.Equal(t, "link", args[3])
+		return args[4:]
+	}
+
+	// The flags are added to compiler.libraries.ldflags if the recipe uses it...
+	args := link(`link {compiler.libraries.ldflags} {object_files} END`)
+	require.Equal(t, []string{"-lfirst", "-lm", "-lsecond"}, args[:3])
+	require.Equal(t, "END", args[len(args)-1])
+
+	// ...otherwise after the object files
+	args = link(`link {object_files} END`)
+	require.Equal(t, []string{"-lfirst", "-lm", "-lsecond", "END"}, args[len(args)-4:])
+}
@@ -18,11 +18,77 @@ package builder
 import (
 	"strings"
 
+	"github.com/arduino/arduino-cli/executils"
 	f "github.com/arduino/arduino-cli/internal/algorithms"
 	"github.com/arduino/go-paths-helper"
 	"github.com/pkg/errors"
 )
 
+// SetLinkerScript sets a linker script that replaces the one specified by the
+// platform in the link recipe: the -T options of the recipe are removed and
+// the given script is passed to the linker with -T. A nil script restores the
+// default (the linker script of the platform).
+func (b *Builder) SetLinkerScript(script *paths.Path) error {
+	if script == nil {
+		b.linkerScript = nil
+		return nil
+	}
+	script, err := script.Abs()
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	if !script.IsNotDir() {
+		return errors.New(tr("linker script %s not found", script))
+	}
+	b.linkerScript = script
+	return nil
+}
+
+// overrideLinkerScript replaces the linker scripts in the arguments of the link
+// command (-T <script>, -T<script>, --script=<script> and their -Wl, forms) with
+// the given one. The script takes the place of the first script removed, or is
+// added at the end if the command doesn't specify any.
+func overrideLinkerScript(args []string, script *paths.Path) []string {
+	res := []string{}
+	replaced := false
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		if arg == "-T" && i > 0 {
+			// the script is the next argument
+			i++
+		} else if i == 0 || !isLinkerScriptArg(arg) {
+			res = append(res, arg)
+			continue
+		}
+		if !replaced {
+			res = append(res, "-T"+script.String())
+			replaced = true
+		}
+	}
+	if !replaced {
+		res = append(res, "-T"+script.String())
+	}
+	return res
+}
+
+func isLinkerScriptArg(arg string) bool {
+	opt := strings.TrimPrefix(arg, "-Wl,")
+	if strings.HasPrefix(opt, "--script=") || strings.HasPrefix(opt, "-T,") {
+		return true
+	}
+	if !strings.HasPrefix(opt, "-T") {
+		return false
+	}
+	// -Ttext, -Tdata, -Tbss (and -Ttext-segment, etc.) set the address of the sections
+	for _, section := range []string{"text", "data", "bss", "ldata", "rodata"} {
+		rest, found := strings.CutPrefix(opt, "-T"+section)
+		if found && (rest == "" || strings.HasPrefix(rest, "=") || strings.HasPrefix(rest, "-segment")) {
+			return false
+		}
+	}
+	return true
+}
+
 // link fixdoc
 func (b *Builder) link() error {
 	if b.onlyUpdateCompilationDatabase {
@@ -92,10 +158,47 @@ func (b *Builder) link() error {
 	properties.Set("archive_file_path", b.buildArtifacts.coreArchiveFilePath.String())
 	properties.Set("object_files", objectFileList)
 
+	// Add the linker flags declared by the libraries: they go in the
+	// compiler.libraries.ldflags property if the recipe uses it, otherwise
+	// after the object files
+	if len(b.buildArtifacts.librariesLDFlags) > 0 {
+		librariesLDFlags := strings.Join(b.buildArtifacts.librariesLDFlags, " ")
+		if strings.Contains(properties.Get("recipe.c.combine.pattern"), "{compiler.libraries.ldflags}") {
+			properties.Set("compiler.libraries.ldflags", properties.Get("compiler.libraries.ldflags")+" "+librariesLDFlags)
+		} else {
+			properties.Set("object_files", objectFileList+" "+librariesLDFlags)
+		}
+	}
+
 	command, err := b.prepareCommandForRecipe(properties, "recipe.c.combine.pattern", false)
 	if err != nil {
 		return err
 	}
+	if b.linkerScript != nil {
+		dir := command.GetDir()
+		command, err = executils.NewProcess(nil, overrideLinkerScript(command.GetArgs(), b.linkerScript)...)
+		if err != nil {
+			return err
+		}
+		command.SetDir(dir)
+	}
+	command, err = b.addExtraFlags(command)
+	if err != nil {
+		return err
+	}
+	var mapFile *paths.Path
+	if b.parseMapFile {
+		command, mapFile, err = b.addMapFileArgs(command)
+		if err != nil {
+			return err
+		}
+	}
 
-	return b.execCommand(command)
+	if err := b.execCommand(command); err != nil {
+		return err
+	}
+	if mapFile != nil {
+		b.loadMapFile(mapFile)
+	}
+	return nil
 }
@@ -0,0 +1,73 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2023 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package builder
+
+import (
+	"io"
+	"os/exec"
+	"testing"
+
+	"github.com/arduino/arduino-cli/arduino/builder/internal/logger"
+	"github.com/arduino/go-paths-helper"
+	"github.com/arduino/go-properties-orderedmap"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOverrideLinkerScript(t *testing.T) {
+	script := paths.New("/custom/boot.ld")
+	args := []string{"gcc", "-Os", "-T/variant/flash.ld", "-T", "/variant/other.ld", "-Wl,-T/variant/wl.ld",
+		"-Wl,--script=/variant/script.ld", "-Wl,-Ttext=0x7000", "-Tdata", "0x800100", "-o", "sketch.elf", "a.o"}
+	require.Equal(t, []string{"gcc", "-Os", "-T/custom/boot.ld", "-Wl,-Ttext=0x7000", "-Tdata", "0x800100", "-o", "sketch.elf", "a.o"},
+		overrideLinkerScript(args, script))
+	require.Equal(t, []string{"gcc", "-o", "sketch.elf", "-T/custom/boot.ld"},
+		overrideLinkerScript([]string{"gcc", "-o", "sketch.elf"}, script))
+}
+
+func TestLinkWithCustomLinkerScript(t *testing.T) {
+	sh, err := exec.LookPath("sh")
+	if err != nil {
+		t.Skip("sh not available")
+	}
+	buildPath := paths.New(t.TempDir())
+	script := buildPath.Join("custom.ld")
+
+	link := func(setScript bool) []string {
+		buildProperties := properties.NewMap()
+		buildProperties.Set("build.ldscript", "flash.ld")
+		buildProperties.Set("recipe.c.combine.pattern", `"`+sh+`" -c "exit 0" link "-T{build.ldscript}" {object_files}`)
+		b := &Builder{
+			buildProperties: buildProperties,
+			buildPath:       buildPath,
+			buildArtifacts:  &buildArtifacts{coreArchiveFilePath: buildPath.Join("core", "core.a")},
+			logger:          logger.New(io.Discard, io.Discard, false, ""),
+		}
+		if setScript {
+			require.Error(t, b.SetLinkerScript(buildPath.Join("missing.ld")))
+			require.NoError(t, b.SetLinkerScript(script))
+		}
+		b.RecordExecutedCommands(true)
+		require.NoError(t, b.link())
+		commands := b.ExecutedCommands()
+		require.Len(t, commands, 1)
+		return commands[0].Args
+	}
+
+	// The platform script is used by default
+	require.Equal(t, []string{sh, "-c", "exit 0", "link", "-Tflash.ld"}, link(false))
+
+	require.NoError(t, script.WriteFile([]byte{}))
+	require.Equal(t, []string{sh, "-c", "exit 0", "link", "-T" + script.String()}, link(true))
+}
@@ -0,0 +1,351 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2023 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package builder
+
+import (
+	"bufio"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/arduino/arduino-cli/executils"
+	rpc "github.com/arduino/arduino-cli/rpc/cc/arduino/cli/commands/v1"
+	"github.com/arduino/go-paths-helper"
+	"github.com/pkg/errors"
+)
+
+// MapFile is the content of the map file produced by the linker
+type MapFile struct {
+	// Sections are the output sections of the executable
+	Sections []*MapFileSection `json:"sections"`
+	// Symbols are the symbols placed in the output sections
+	Symbols []*MapFileSymbol `json:"symbols"`
+}
+
+// MapFileSection is an output section of the executable
+type MapFileSection struct {
+	Name    string `json:"name"`
+	Address uint64 `json:"address"`
+	Size    uint64 `json:"size"`
+}
+
+// MapFileSymbol is a symbol listed in the map file. The map file doesn't
+// contain the size of the symbols, so it is computed as the distance from the
+// next symbol of the same input section (or from the end of the input section).
+type MapFileSymbol struct {
+	// Name is the name of the symbol, demangled if the linker has been
+	// asked to demangle the names
+	Name string `json:"name"`
+	// Section is the output section containing the symbol (ex. ".text")
+	Section string `json:"section"`
+	// InputSection is the input section containing the symbol (ex. ".text._Z4loopv")
+	InputSection string `json:"input_section"`
+	// Object is the object file or archive member that defines the symbol
+	Object  string `json:"object"`
+	Address uint64 `json:"address"`
+	Size    uint64 `json:"size"`
+}
+
+// ToRPC converts the map file into a *rpc.MapFile, a nil map file is
+// converted to nil
+func (m *MapFile) ToRPC() *rpc.MapFile {
+	if m == nil {
+		return nil
+	}
+	res := &rpc.MapFile{
+		Sections: []*rpc.MapFileSection{},
+		Symbols:  []*rpc.MapFileSymbol{},
+	}
+	for _, section := range m.Sections {
+		res.Sections = append(res.Sections, &rpc.MapFileSection{
+			Name:    section.Name,
+			Address: section.Address,
+			Size:    section.Size,
+		})
+	}
+	for _, symbol := range m.Symbols {
+		res.Symbols = append(res.Symbols, &rpc.MapFileSymbol{
+			Name:         symbol.Name,
+			Section:      symbol.Section,
+			InputSection: symbol.InputSection,
+			Object:       symbol.Object,
+			Address:      symbol.Address,
+			Size:         symbol.Size,
+		})
+	}
+	return res
+}
+
+// SetMapFileParsing enables the parsing of the map file produced by the
+// linker, the result is returned by MapFile and in the BuildSummary. If the link recipe doesn't
+// produce a map file, the linker is asked to create one in the build path.
+// Only the GNU ld map file format is supported.
+func (b *Builder) SetMapFileParsing(enable bool) {
+	b.parseMapFile = enable
+}
+
+// MapFile returns the parsed map file of the last link, or nil if map file
+// parsing is disabled or the map file could not be parsed.
+func (b *Builder) MapFile() *MapFile {
+	return b.mapFile
+}
+
+// addMapFileArgs returns the given link command with the option to produce a
+// map file added, if not already present, and the path of the map file.
+func (b *Builder) addMapFileArgs(command *executils.Process) (*executils.Process, *paths.Path, error) {
+	args := command.GetArgs()
+	if mapFile := mapFileFromArgs(args); mapFile != "" {
+		path := paths.New(mapFile)
+		if !path.IsAbs() && command.GetDir() != "" {
+			path = paths.New(command.GetDir()).Join(mapFile)
+		}
+		return command, path, nil
+	}
+
+	mapFile := b.buildPath.Join(b.buildProperties.Get("build.project_name") + ".map")
+	dir := command.GetDir()
+	res, err := executils.NewProcess(nil, append(append([]string{}, args...), "-Wl,-Map,"+mapFile.String())...)
+	if err != nil {
+		return nil, nil, err
+	}
+	res.SetDir(dir)
+	return res, mapFile, nil
+}
+
+// mapFileFromArgs returns the map file passed to the linker in the given
+// arguments (-Wl,-Map,<file>, -Wl,-Map=<file>, -Map <file> or -Map=<file>),
+// or an empty string if the arguments don't ask for a map file.
+func mapFileFromArgs(args []string) string {
+	for i, arg := range args {
+		opt := strings.TrimPrefix(arg, "-Wl,")
+		opt = strings.TrimPrefix(opt, "-")
+		opt = strings.TrimPrefix(opt, "-")
+		mapFile, ok := strings.CutPrefix(opt, "Map")
+		if !ok || opt == arg {
+			continue
+		}
+		if mapFile == "" && i+1 < len(args) {
+			return strings.TrimPrefix(args[i+1], "-Wl,")
+		}
+		if strings.HasPrefix(mapFile, ",") || strings.HasPrefix(mapFile, "=") {
+			return mapFile[1:]
+		}
+	}
+	return ""
+}
+
+// loadMapFile parses the given map file, an error is reported only in verbose mode
+// because the build is completed anyway.
+func (b *Builder) loadMapFile(mapFile *paths.Path) {
+	b.mapFile = nil
+	file, err := mapFile.Open()
+	if err != nil {
+		b.logIfVerbose(true, tr("Could not read map file: %s", err))
+		return
+	}
+	defer file.Close()
+	parsed, err := parseMapFile(file)
+	if err != nil {
+		b.logIfVerbose(true, tr("Could not parse map file %[1]s: %[2]s", mapFile, err))
+		return
+	}
+	b.mapFile = parsed
+}
+
+// parseMapFile parses the "Linker script and memory map" part of a map file
+// in the GNU ld format:
+//
+//	.text           0x0000000000000000      0x2c4
+//	 .text._Z4loopv
+//	                0x0000000000000110       0x12 sketch.ino.cpp.o
+//	                0x0000000000000110                loop()
+//
+// Output sections start at the first column, input sections are indented by
+// one space and symbols by more spaces. Names too long to be aligned are
+// followed by the address and size in the next line.
+func parseMapFile(r io.Reader) (*MapFile, error) {
+	res := &MapFile{Sections: []*MapFileSection{}, Symbols: []*MapFileSymbol{}}
+
+	var section *MapFileSection
+	var inputSection *MapFileSymbol // the input section is represented as a symbol without name
+	var inputSectionSymbols []*MapFileSymbol
+	pendingName, pendingIsInput := "", false
+
+	closeInputSection := func() {
+		if inputSection == nil {
+			return
+		}
+		sort.SliceStable(inputSectionSymbols, func(i, j int) bool {
+			return inputSectionSymbols[i].Address < inputSectionSymbols[j].Address
+		})
+		end := inputSection.Address + inputSection.Size
+		for i, symbol := range inputSectionSymbols {
+			next := end
+			for _, other := range inputSectionSymbols[i+1:] {
+				if other.Address > symbol.Address {
+					next = other.Address
+					break
+				}
+			}
+			if next > symbol.Address {
+				symbol.Size = next - symbol.Address
+			}
+			res.Symbols = append(res.Symbols, symbol)
+		}
+		inputSection, inputSectionSymbols = nil, nil
+	}
+	openSection := func(name string, address, size uint64) {
+		closeInputSection()
+		section = &MapFileSection{Name: name, Address: address, Size: size}
+		res.Sections = append(res.Sections, section)
+	}
+	openInputSection := func(name string, address, size uint64, object string) {
+		closeInputSection()
+		if section == nil {
+			return
+		}
+		inputSection = &MapFileSymbol{InputSection: name, Section: section.Name, Object: object, Address: address, Size: size}
+	}
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	memoryMapFound := false
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), " \t\r")
+		if !memoryMapFound {
+			memoryMapFound = strings.HasPrefix(line, "Linker script and memory map")
+			continue
+		}
+		if line == "" {
+			continue
+		}
+		if strings.HasPrefix(line, "OUTPUT(") {
+			break
+		}
+		fields := strings.Fields(line)
+
+		if line[0] != ' ' {
+			// Output section
+			pendingName = ""
+			name := fields[0]
+			if !strings.HasPrefix(name, ".") {
+				// LOAD, START GROUP, assignments...
+				closeInputSection()
+				section = nil
+				continue
+			}
+			if len(fields) == 1 {
+				pendingName, pendingIsInput = name, false
+				continue
+			}
+			address, size, ok := parseMapFileAddressAndSize(fields[1:])
+			if ok {
+				openSection(name, address, size)
+			}
+			continue
+		}
+
+		if line[1] != ' ' {
+			// Input section
+			pendingName = ""
+			name := fields[0]
+			if !strings.HasPrefix(name, ".") && name != "COMMON" {
+				// *fill*, *(.text*), etc.
+				closeInputSection()
+				continue
+			}
+			if len(fields) == 1 {
+				pendingName, pendingIsInput = name, true
+				continue
+			}
+			if address, size, ok := parseMapFileAddressAndSize(fields[1:]); ok {
+				openInputSection(name, address, size, strings.Join(fields[3:], " "))
+			}
+			continue
+		}
+
+		if pendingName != "" {
+			// Address and size of a section with a long name
+			name := pendingName
+			pendingName = ""
+			if address, size, ok := parseMapFileAddressAndSize(fields); ok {
+				if pendingIsInput {
+					openInputSection(name, address, size, strings.Join(fields[2:], " "))
+				} else {
+					openSection(name, address, size)
+				}
+				continue
+			}
+		}
+
+		// Symbol
+		if inputSection == nil || len(fields) < 2 {
+			continue
+		}
+		address, ok := parseMapFileNumber(fields[0])
+		if !ok {
+			continue
+		}
+		name := strings.TrimSpace(strings.TrimSpace(line)[len(fields[0]):])
+		if strings.Contains(name, " = ") || strings.HasPrefix(name, ".") || strings.HasPrefix(name, "PROVIDE") || strings.HasPrefix(name, "[") {
+			// Linker script assignments
+			continue
+		}
+		if _, isNumber := parseMapFileNumber(fields[1]); isNumber {
+			continue
+		}
+		inputSectionSymbols = append(inputSectionSymbols, &MapFileSymbol{
+			Name:         name,
+			Section:      inputSection.Section,
+			InputSection: inputSection.InputSection,
+			Object:       inputSection.Object,
+			Address:      address,
+		})
+	}
+	closeInputSection()
+	if err := scanner.Err(); err != nil {
+		return nil, errors.WithStack(err)
+	}
+	if !memoryMapFound {
+		return nil, errors.New(tr("memory map not found"))
+	}
+	return res, nil
+}
+
+func parseMapFileAddressAndSize(fields []string) (uint64, uint64, bool) {
+	if len(fields) < 2 {
+		return 0, 0, false
+	}
+	address, ok := parseMapFileNumber(fields[0])
+	if !ok {
+		return 0, 0, false
+	}
+	size, ok := parseMapFileNumber(fields[1])
+	if !ok {
+		return 0, 0, false
+	}
+	return address, size, true
+}
+
+func parseMapFileNumber(field string) (uint64, bool) {
+	hex, ok := strings.CutPrefix(field, "0x")
+	if !ok {
+		return 0, false
+	}
+	n, err := strconv.ParseUint(hex, 16, 64)
+	return n, err == nil
+}
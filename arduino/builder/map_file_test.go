@@ -0,0 +1,159 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2023 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package builder
+
+import (
+	"io"
+	"os/exec"
+	"strings"
+	"testing"
+
+	"github.com/arduino/arduino-cli/arduino/builder/internal/logger"
+	"github.com/arduino/arduino-cli/arduino/builder/internal/progress"
+	"github.com/arduino/go-paths-helper"
+	"github.com/arduino/go-properties-orderedmap"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMapFileFromArgs(t *testing.T) {
+	require.Equal(t, "out.map", mapFileFromArgs([]string{"gcc", "-Wl,-Map,out.map"}))
+	require.Equal(t, "out.map", mapFileFromArgs([]string{"gcc", "-Wl,-Map=out.map"}))
+	require.Equal(t, "out.map", mapFileFromArgs([]string{"gcc", "-Wl,--Map=out.map"}))
+	require.Equal(t, "out.map", mapFileFromArgs([]string{"ld", "-Map", "out.map"}))
+	require.Equal(t, "out.map", mapFileFromArgs([]string{"gcc", "-Wl,-Map", "-Wl,out.map"}))
+	require.Equal(t, "", mapFileFromArgs([]string{"gcc", "-Wl,--gc-sections", "Map"}))
+}
+
+func TestParseMapFile(t *testing.T) {
+	mapFile := `Archive member included to satisfy reference by file (symbol)
+
+Memory Configuration
+
+Name             Origin             Length             Attributes
+text             0x0000000000000000 0x0000000000020000 xr
+
+Linker script and memory map
+
+LOAD sketch.ino.cpp.o
+                [!provide]                        PROVIDE (__data_start = .)
+
+.text           0x0000000000000000      0x144
+ *(.vectors)
+ .vectors       0x0000000000000000       0x68 crtatmega328p.o
+                0x0000000000000000                __vectors
+                0x0000000000000000                __vector_default
+ .text._Z25a_very_long_function_namev
+                0x0000000000000068       0x24 sketch.ino.cpp.o
+                0x0000000000000068                a_very_long_function_name()
+                0x0000000000000070                an_alias
+ *fill*         0x000000000000008c        0x4 
+ .text.loop     0x0000000000000090       0xb4 core.a(main.cpp.o)
+                0x0000000000000090                loop
+                0x0000000000000144                . = ALIGN (0x2)
+
+.bss            0x0000000000800100        0x9
+ .bss.counter   0x0000000000800100        0x9 sketch.ino.cpp.o
+                0x0000000000800100                counter
+OUTPUT(sketch.ino.elf elf32-avr)
+`
+	parsed, err := parseMapFile(strings.NewReader(mapFile))
+	require.NoError(t, err)
+	require.Equal(t, []*MapFileSection{
+		{Name: ".text", Address: 0, Size: 0x144},
+		{Name: ".bss", Address: 0x800100, Size: 9},
+	}, parsed.Sections)
+	require.Equal(t, []*MapFileSymbol{
+		{Name: "__vectors", Section: ".text", InputSection: ".vectors", Object: "crtatmega328p.o", Address: 0, Size: 0x68},
+		{Name: "__vector_default", Section: ".text", InputSection: ".vectors", Object: "crtatmega328p.o", Address: 0, Size: 0x68},
+		{Name: "a_very_long_function_name()", Section: ".text", InputSection: ".text._Z25a_very_long_function_namev", Object: "sketch.ino.cpp.o", Address: 0x68, Size: 8},
+		{Name: "an_alias", Section: ".text", InputSection: ".text._Z25a_very_long_function_namev", Object: "sketch.ino.cpp.o", Address: 0x70, Size: 0x1c},
+		{Name: "loop", Section: ".text", InputSection: ".text.loop", Object: "core.a(main.cpp.o)", Address: 0x90, Size: 0xb4},
+		{Name: "counter", Section: ".bss", InputSection: ".bss.counter", Object: "sketch.ino.cpp.o", Address: 0x800100, Size: 9},
+	}, parsed.Symbols)
+
+	_, err = parseMapFile(strings.NewReader("not a map file"))
+	require.Error(t, err)
+}
+
+func TestLinkWithMapFileParsing(t *testing.T) {
+	gpp, err := exec.LookPath("g++")
+	if err != nil {
+		t.Skip("g++ not available")
+	}
+	sketchDir := paths.New(t.TempDir())
+	source := sketchDir.Join("sketch.ino.cpp")
+	require.NoError(t, source.WriteFile([]byte(`
+int counter = 3;
+int buffer[100];
+int multiply(int a) { return a * counter; }
+int main() { return multiply(2) + buffer[1]; }
+`)))
+	buildPath := paths.New(t.TempDir())
+
+	buildProperties := properties.NewMap()
+	buildProperties.SetPath("build.path", buildPath)
+	buildProperties.Set("build.project_name", "sketch.ino")
+	buildProperties.Set("recipe.cpp.o.pattern", `"`+gpp+`" -ffunction-sections -fdata-sections -c "{source_file}" -o "{object_file}"`)
+	buildProperties.Set("recipe.c.combine.pattern", `"`+gpp+`" {object_files} -o "{build.path}/{build.project_name}.elf"`)
+	b := &Builder{
+		buildProperties: buildProperties,
+		buildPath:       buildPath,
+		sketchBuildPath: buildPath.Join("sketch"),
+		buildArtifacts:  &buildArtifacts{coreArchiveFilePath: buildPath.Join("core", "core.a")},
+		logger:          logger.New(io.Discard, io.Discard, false, ""),
+		Progress:        progress.New(nil),
+	}
+	objectFile, err := b.compileFileWithRecipe(sketchDir, source, b.sketchBuildPath, nil, "recipe.cpp.o.pattern")
+	require.NoError(t, err)
+	b.buildArtifacts.sketchObjectFiles = paths.NewPathList(objectFile.String())
+
+	// Map file parsing is disabled by default
+	require.NoError(t, b.link())
+	require.Nil(t, b.MapFile())
+	require.False(t, buildPath.Join("sketch.ino.map").Exist())
+
+	b.SetMapFileParsing(true)
+	require.NoError(t, b.link())
+	require.True(t, buildPath.Join("sketch.ino.map").Exist())
+	mapFile := b.MapFile()
+	require.NotNil(t, mapFile)
+
+	symbols := map[string]*MapFileSymbol{}
+	for _, symbol := range mapFile.Symbols {
+		symbols[symbol.Name] = symbol
+	}
+	require.Contains(t, symbols, "counter")
+	require.Equal(t, ".data", symbols["counter"].Section)
+	require.Equal(t, uint64(4), symbols["counter"].Size)
+	require.Contains(t, symbols, "buffer")
+	require.Equal(t, ".bss", symbols["buffer"].Section)
+	require.Equal(t, uint64(400), symbols["buffer"].Size)
+	require.Contains(t, symbols, "main")
+	require.Equal(t, ".text", symbols["main"].Section)
+	require.NotZero(t, symbols["main"].Size)
+	require.Equal(t, objectFile.String(), symbols["main"].Object)
+
+	rpcMapFile := mapFile.ToRPC()
+	require.Len(t, rpcMapFile.GetSections(), len(mapFile.Sections))
+	require.Len(t, rpcMapFile.GetSymbols(), len(mapFile.Symbols))
+	for _, symbol := range rpcMapFile.GetSymbols() {
+		if symbol.GetName() == "buffer" {
+			require.Equal(t, ".bss", symbol.GetSection())
+			require.Equal(t, uint64(400), symbol.GetSize())
+		}
+	}
+	require.Nil(t, (*MapFile)(nil).ToRPC())
+}
@@ -18,14 +18,34 @@ package builder
 import (
 	"github.com/arduino/arduino-cli/arduino/builder/internal/preprocessor"
 	"github.com/arduino/go-paths-helper"
+	"github.com/arduino/go-properties-orderedmap"
 )
 
+// SetPrototypesGeneration enables or disables the automatic generation of the
+// prototypes of the functions defined in the sketch. When disabled the merged
+// sketch is compiled as plain C++, so all the functions must be declared before
+// being used. Prototypes are generated by default.
+func (b *Builder) SetPrototypesGeneration(enable bool) {
+	b.skipPrototypesGeneration = !enable
+	if b.buildOptions != nil {
+		if enable {
+			b.buildOptions.currentOptions.Remove("skipPrototypesGeneration")
+		} else {
+			b.buildOptions.currentOptions.Set("skipPrototypesGeneration", "true")
+		}
+	}
+}
+
 // preprocessSketch fixdoc
-func (b *Builder) preprocessSketch(includes paths.PathList) error {
+func (b *Builder) preprocessSketch(includes paths.PathList, buildProperties *properties.Map) error {
+	if b.skipPrototypesGeneration {
+		return nil
+	}
+
 	// In the future we might change the preprocessor
 	normalOutput, verboseOutput, err := preprocessor.PreprocessSketchWithCtags(
 		b.sketch, b.buildPath, includes, b.lineOffset,
-		b.buildProperties, b.onlyUpdateCompilationDatabase,
+		buildProperties, b.onlyUpdateCompilationDatabase,
 	)
 	if b.logger.Verbose() {
 		b.logger.WriteStdout(verboseOutput)
@@ -0,0 +1,61 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2023 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package builder
+
+import (
+	"io"
+	"testing"
+
+	"github.com/arduino/arduino-cli/arduino/builder/internal/logger"
+	"github.com/arduino/arduino-cli/arduino/sketch"
+	"github.com/arduino/go-paths-helper"
+	"github.com/arduino/go-properties-orderedmap"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPreprocessSketchWithoutPrototypes(t *testing.T) {
+	sketchPath := paths.New(t.TempDir()).Join("NoPrototypes")
+	require.NoError(t, sketchPath.MkdirAll())
+	source := "void setup() { helper(); }\nvoid loop() {}\nvoid helper() {}\n"
+	require.NoError(t, sketchPath.Join("NoPrototypes.ino").WriteFile([]byte(source)))
+	sk, err := sketch.New(sketchPath)
+	require.NoError(t, err)
+
+	buildPath := paths.New(t.TempDir())
+	require.NoError(t, buildPath.Join("sketch").MkdirAll())
+	b := &Builder{
+		sketch:          sk,
+		buildPath:       buildPath,
+		buildProperties: properties.NewMap(),
+		logger:          logger.New(io.Discard, io.Discard, false, ""),
+	}
+	offset, merged, err := b.sketchMergeSources(nil)
+	require.NoError(t, err)
+	b.lineOffset = offset
+	mergedFile := buildPath.Join("sketch", "NoPrototypes.ino.cpp")
+	require.NoError(t, mergedFile.WriteFile([]byte(merged)))
+
+	// With the default settings the prototypes generator runs and fails
+	// because the platform has no recipes
+	require.Error(t, b.preprocessSketch(nil, b.buildProperties))
+
+	b.SetPrototypesGeneration(false)
+	require.NoError(t, b.preprocessSketch(nil, b.buildProperties))
+	preprocessed, err := mergedFile.ReadFile()
+	require.NoError(t, err)
+	require.Equal(t, merged, string(preprocessed))
+	require.NotContains(t, string(preprocessed), "void helper();")
+}
@@ -51,12 +51,35 @@ func (s ExecutablesFileSections) ToRPCExecutableSectionSizeArray() []*rpc.Execut
 	return res
 }
 
+// SizeExceededError is returned when a section of the compiled sketch exceeds
+// the maximum size allowed by the board.
+type SizeExceededError struct {
+	// Section is the section that doesn't fit, "text" (program storage) or "data" (dynamic memory)
+	Section string
+	// Size is the actual size of the section in bytes
+	Size int
+	// MaxSize is the maximum size allowed by the board in bytes
+	MaxSize int
+}
+
+func (e *SizeExceededError) Error() string {
+	return tr("%[1]s section exceeds available space in board", e.Section)
+}
+
+// SetWarnOnSizeExceeded sets the policy used when the sketch doesn't fit in the
+// board memory: if warnOnly is true the build only prints a warning, otherwise
+// (the default) the build fails with a SizeExceededError.
+func (b *Builder) SetWarnOnSizeExceeded(warnOnly bool) {
+	b.warnOnSizeExceeded = warnOnly
+}
+
 // size fixdoc
 func (b *Builder) size() error {
 	if b.onlyUpdateCompilationDatabase {
 		return nil
 	}
 
+	b.buildSummary = newBuildSummary()
 	check := b.checkSize
 	if b.buildProperties.ContainsKey("recipe.advanced_size.pattern") {
 		check = b.checkSizeAdvanced
@@ -64,10 +87,12 @@ func (b *Builder) size() error {
 
 	result, err := check()
 	if err != nil {
+		b.buildSummary.setSections(result)
 		return err
 	}
 
 	b.executableSectionsSize = result
+	b.buildSummary.setSections(result)
 
 	return nil
 }
@@ -89,6 +114,7 @@ func (b *Builder) checkSizeAdvanced() (ExecutablesFileSections, error) {
 	if err := command.Wait(); err != nil {
 		return nil, errors.New(tr("Error while determining sketch size: %s", err))
 	}
+	b.logger.RecordToolStdout(out.Bytes())
 
 	type AdvancedSizerResponse struct {
 		// Output are the messages displayed in console to the user
@@ -148,11 +174,12 @@ func (b *Builder) checkSize() (ExecutablesFileSections, error) {
 		}
 	}
 
-	textSize, dataSize, _, err := b.execSizeRecipe(properties)
+	textSize, dataSize, eepromSize, err := b.execSizeRecipe(properties)
 	if err != nil {
 		b.logger.Warn(tr("Couldn't determine program size"))
 		return nil, nil
 	}
+	b.buildSummary.setSizes(textSize, maxTextSize, dataSize, maxDataSize, eepromSize)
 
 	b.logger.Info(tr("Sketch uses %[1]s bytes (%[3]s%%) of program storage space. Maximum is %[2]s bytes.",
 		strconv.Itoa(textSize),
@@ -187,12 +214,16 @@ func (b *Builder) checkSize() (ExecutablesFileSections, error) {
 
 	if textSize > maxTextSize {
 		b.logger.Warn(tr("Sketch too big; see %[1]s for tips on reducing it.", "https://support.arduino.cc/hc/en-us/articles/360013825179"))
-		return executableSectionsSize, errors.New(tr("text section exceeds available space in board"))
+		if !b.warnOnSizeExceeded {
+			return executableSectionsSize, &SizeExceededError{Section: "text", Size: textSize, MaxSize: maxTextSize}
+		}
 	}
 
 	if maxDataSize > 0 && dataSize > maxDataSize {
 		b.logger.Warn(tr("Not enough memory; see %[1]s for tips on reducing your footprint.", "https://support.arduino.cc/hc/en-us/articles/360013825179"))
-		return executableSectionsSize, errors.New(tr("data section exceeds available space in board"))
+		if !b.warnOnSizeExceeded {
+			return executableSectionsSize, &SizeExceededError{Section: "data", Size: dataSize, MaxSize: maxDataSize}
+		}
 	}
 
 	if w := properties.Get("build.warn_data_percentage"); w != "" {
@@ -230,6 +261,7 @@ func (b *Builder) execSizeRecipe(properties *properties.Map) (textSize int, data
 	}
 
 	out := commandStdout.Bytes()
+	b.logger.RecordToolStdout(out)
 
 	// force multiline match prepending "(?m)" to the actual regexp
 	// return an error if RECIPE_SIZE_REGEXP doesn't exist
@@ -16,8 +16,13 @@
 package builder
 
 import (
+	"io"
+	"runtime"
 	"testing"
 
+	"github.com/arduino/arduino-cli/arduino/builder/internal/logger"
+	"github.com/arduino/go-paths-helper"
+	"github.com/arduino/go-properties-orderedmap"
 	"github.com/stretchr/testify/require"
 )
 
@@ -86,3 +91,52 @@ func TestSizerWithInvalidRegexp(t *testing.T) {
 	_, err := computeSize(`[xx`, []byte(`xxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxx`))
 	require.Error(t, err)
 }
+
+func TestSizeExceeded(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("the size recipe uses cat")
+	}
+	buildPath := paths.New(t.TempDir())
+	require.NoError(t, buildPath.Join("size.txt").WriteFile([]byte(`sketch.ino.elf  :
+section           size      addr
+.data               36   8388864
+.text             3966         0
+.bss               112   8388900
+`)))
+
+	newBuilder := func(maxSize, maxDataSize string) *Builder {
+		buildProperties := properties.NewMap()
+		buildProperties.SetPath("build.path", buildPath)
+		buildProperties.Set("upload.maximum_size", maxSize)
+		buildProperties.Set("upload.maximum_data_size", maxDataSize)
+		buildProperties.Set("recipe.size.pattern", `cat "{build.path}/size.txt"`)
+		buildProperties.Set("recipe.size.regex", `^(?:\.text|\.data|\.bootloader)\s+([0-9]+).*`)
+		buildProperties.Set("recipe.size.regex.data", `^(?:\.data|\.bss|\.noinit)\s+([0-9]+).*`)
+		return &Builder{
+			buildProperties: buildProperties,
+			logger:          logger.New(io.Discard, io.Discard, false, ""),
+		}
+	}
+
+	// By default the build fails
+	var sizeErr *SizeExceededError
+	err := newBuilder("4000", "2048").size()
+	require.ErrorAs(t, err, &sizeErr)
+	require.Equal(t, &SizeExceededError{Section: "text", Size: 4002, MaxSize: 4000}, sizeErr)
+	require.EqualError(t, err, "text section exceeds available space in board")
+
+	err = newBuilder("32256", "100").size()
+	require.ErrorAs(t, err, &sizeErr)
+	require.Equal(t, &SizeExceededError{Section: "data", Size: 148, MaxSize: 100}, sizeErr)
+
+	// In warn-only mode the build succeeds and the sizes are still reported
+	b := newBuilder("4000", "100")
+	b.SetWarnOnSizeExceeded(true)
+	require.NoError(t, b.size())
+	require.Equal(t, ExecutablesFileSections{
+		{Name: "text", Size: 4002, MaxSize: 4000},
+		{Name: "data", Size: 148, MaxSize: 100},
+	}, b.ExecutableSectionsSize())
+
+	require.NoError(t, newBuilder("32256", "2048").size())
+}
@@ -59,11 +59,58 @@ func (b *Builder) prepareSketchBuildPath() error {
 		return err
 	}
 
+	excluded, compiled := paths.PathList{}, paths.PathList{b.sketch.MainFile}
+	for _, file := range append(b.sketch.OtherSketchFiles.Clone(), b.sketch.AdditionalFiles...) {
+		if b.isSketchFileExcluded(file) {
+			excluded.Add(file)
+		} else {
+			compiled.Add(file)
+		}
+	}
+	if err := b.checkExcludedFilesNotIncluded(excluded, compiled); err != nil {
+		return err
+	}
+
 	b.lineOffset = offset
 
 	return nil
 }
 
+// SetSketchMainFile overrides the main file of the sketch detected by convention,
+// the given file is used as the primary translation unit and gives the name to
+// the build outputs (unless build.project_name is set as a custom build property).
+// See sketch.Sketch.SetMainFile for the accepted files.
+func (b *Builder) SetSketchMainFile(file *paths.Path) error {
+	// Don't change the sketch of the caller
+	sk := *b.sketch
+	sk.OtherSketchFiles = b.sketch.OtherSketchFiles.Clone()
+	if err := sk.SetMainFile(file); err != nil {
+		return err
+	}
+	if b.buildProperties.Get("build.project_name") == b.sketch.MainFile.Base() {
+		b.buildProperties.Set("build.project_name", sk.MainFile.Base())
+	}
+	b.sketch = &sk
+	if b.buildOptions != nil {
+		// A different main file produces different sources in the build path
+		b.buildOptions.sketch = &sk
+		b.buildOptions.currentOptions.Set("sketchMainFile", sk.MainFile.Base())
+	}
+	return nil
+}
+
+// SketchFilesMergeOrder returns the .ino files of the sketch in the order they are
+// merged together: the main file first, followed by the other sketch files.
+func (b *Builder) SketchFilesMergeOrder() paths.PathList {
+	res := paths.PathList{b.sketch.MainFile}
+	for _, file := range b.sketch.OtherSketchFiles {
+		if !b.isSketchFileExcluded(file) {
+			res.Add(file)
+		}
+	}
+	return res
+}
+
 // sketchMergeSources merges all the .ino source files included in a sketch to produce
 // a single .cpp file.
 func (b *Builder) sketchMergeSources(overrides map[string]string) (int, string, error) {
@@ -100,6 +147,9 @@ func (b *Builder) sketchMergeSources(overrides map[string]string) (int, string,
 	lineOffset++
 
 	for _, file := range b.sketch.OtherSketchFiles {
+		if b.isSketchFileExcluded(file) {
+			continue
+		}
 		src, err := getSource(file)
 		if err != nil {
 			return 0, "", err
@@ -121,6 +171,16 @@ func (b *Builder) sketchCopyAdditionalFiles(buildPath *paths.Path, overrides map
 		}
 
 		targetPath := buildPath.JoinPath(relpath)
+		if b.isSketchFileExcluded(file) {
+			// remove the copy made by a previous build, if any
+			if targetPath.Exist() {
+				if err := targetPath.Remove(); err != nil {
+					return errors.Wrap(err, tr("unable to remove excluded file"))
+				}
+			}
+			continue
+		}
+
 		// create the directory containing the target
 		if err = targetPath.Parent().MkdirAll(); err != nil {
 			return errors.Wrap(err, tr("unable to create the folder containing the item"))
@@ -0,0 +1,109 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2023 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package builder
+
+import (
+	"fmt"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/arduino/go-paths-helper"
+	"github.com/pkg/errors"
+)
+
+var quotedInclude = regexp.MustCompile(`(?m)^\s*#\s*include\s*"([^"]+)"`)
+
+// SetSketchExcludePatterns sets the glob patterns of the sketch files that must
+// not be compiled. The patterns are matched against the path of the files
+// relative to the sketch folder, using forward slashes: a pattern without
+// slashes is matched against the name of the files and of the folders at any
+// depth (for example "*_alt.cpp" or "scratch"), otherwise against the whole
+// relative path (for example "src/impl/*.cpp"). A matching folder excludes
+// all its content. The main sketch file can't be excluded.
+func (b *Builder) SetSketchExcludePatterns(patterns []string) error {
+	for _, pattern := range patterns {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return fmt.Errorf("%s '%s': %w", tr("invalid exclude pattern"), pattern, err)
+		}
+	}
+	b.sketchExcludePatterns = append([]string{}, patterns...)
+	if b.buildOptions != nil {
+		if len(patterns) > 0 {
+			b.buildOptions.currentOptions.Set("sketchExcludePatterns", strings.Join(patterns, ","))
+		} else {
+			b.buildOptions.currentOptions.Remove("sketchExcludePatterns")
+		}
+	}
+	return nil
+}
+
+// isSketchFileExcluded returns true if the given sketch file matches one of
+// the exclude patterns
+func (b *Builder) isSketchFileExcluded(file *paths.Path) bool {
+	if len(b.sketchExcludePatterns) == 0 {
+		return false
+	}
+	rel, err := b.sketch.FullPath.RelTo(file)
+	if err != nil {
+		return false
+	}
+	return matchExcludePatterns(b.sketchExcludePatterns, filepath.ToSlash(rel.String()))
+}
+
+// matchExcludePatterns returns true if the given slash separated relative path,
+// or one of its parent folders, matches one of the patterns
+func matchExcludePatterns(patterns []string, rel string) bool {
+	parts := strings.Split(rel, "/")
+	for i := range parts {
+		prefix := strings.Join(parts[:i+1], "/")
+		for _, pattern := range patterns {
+			if matched, _ := path.Match(pattern, prefix); matched {
+				return true
+			}
+			if !strings.Contains(pattern, "/") {
+				if matched, _ := path.Match(pattern, parts[i]); matched {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+// checkExcludedFilesNotIncluded returns an error if one of the excluded sketch
+// files is included by a file that is compiled
+func (b *Builder) checkExcludedFilesNotIncluded(excluded, included []*paths.Path) error {
+	if len(excluded) == 0 {
+		return nil
+	}
+	for _, file := range included {
+		data, err := file.ReadFile()
+		if err != nil {
+			return errors.WithStack(err)
+		}
+		for _, match := range quotedInclude.FindAllStringSubmatch(string(data), -1) {
+			target := file.Parent().Join(match[1])
+			for _, excludedFile := range excluded {
+				if target.EquivalentTo(excludedFile) {
+					return errors.New(tr("%[1]s is excluded from the build but it's included by %[2]s", excludedFile, file))
+				}
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,93 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2023 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package builder
+
+import (
+	"io"
+	"os/exec"
+	"testing"
+
+	"github.com/arduino/arduino-cli/arduino/builder/internal/logger"
+	"github.com/arduino/arduino-cli/arduino/builder/internal/progress"
+	"github.com/arduino/arduino-cli/arduino/sketch"
+	"github.com/arduino/go-paths-helper"
+	"github.com/arduino/go-properties-orderedmap"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMatchExcludePatterns(t *testing.T) {
+	require.True(t, matchExcludePatterns([]string{"*_alt.cpp"}, "impl_alt.cpp"))
+	require.True(t, matchExcludePatterns([]string{"*_alt.cpp"}, "src/impl_alt.cpp"))
+	require.True(t, matchExcludePatterns([]string{"scratch"}, "src/scratch/test.cpp"))
+	require.True(t, matchExcludePatterns([]string{"src/impl/*.cpp"}, "src/impl/a.cpp"))
+	require.False(t, matchExcludePatterns([]string{"src/impl/*.cpp"}, "impl/a.cpp"))
+	require.False(t, matchExcludePatterns([]string{"*_alt.cpp"}, "impl.cpp"))
+	require.False(t, matchExcludePatterns(nil, "impl.cpp"))
+}
+
+func TestSketchExcludePatterns(t *testing.T) {
+	gpp, err := exec.LookPath("g++")
+	if err != nil {
+		t.Skip("g++ not available")
+	}
+
+	sketchPath := paths.New(t.TempDir()).Join("Exclude")
+	require.NoError(t, sketchPath.Join("scratch").MkdirAll())
+	require.NoError(t, sketchPath.Join("Exclude.ino").WriteFile([]byte("#include \"impl.h\"\nvoid setup() { impl(); }\nvoid loop() {}\n")))
+	require.NoError(t, sketchPath.Join("impl.h").WriteFile([]byte("int impl();\n")))
+	require.NoError(t, sketchPath.Join("impl.cpp").WriteFile([]byte("int impl() { return 1; }\n")))
+	require.NoError(t, sketchPath.Join("impl_alt.cpp").WriteFile([]byte("this does not compile\n")))
+	require.NoError(t, sketchPath.Join("scratch", "test.cpp").WriteFile([]byte("neither this\n")))
+	sk, err := sketch.New(sketchPath)
+	require.NoError(t, err)
+
+	includesDir := paths.New(t.TempDir())
+	require.NoError(t, includesDir.Join("Arduino.h").WriteFile([]byte("")))
+
+	newBuilder := func() *Builder {
+		buildPath := paths.New(t.TempDir())
+		buildProperties := properties.NewMap()
+		buildProperties.Set("recipe.cpp.o.pattern", `"`+gpp+`" -c {includes} "{source_file}" -o "{object_file}"`)
+		return &Builder{
+			sketch:          sk,
+			buildProperties: buildProperties,
+			buildPath:       buildPath,
+			sketchBuildPath: buildPath.Join("sketch"),
+			buildArtifacts:  &buildArtifacts{},
+			logger:          logger.New(io.Discard, io.Discard, false, ""),
+			Progress:        progress.New(nil),
+		}
+	}
+
+	// Without exclusions the build fails
+	b := newBuilder()
+	require.NoError(t, b.prepareSketchBuildPath())
+	require.Error(t, b.buildSketch(paths.NewPathList(includesDir.String())))
+
+	// The excluded files are skipped, the ones copied by the previous build are removed
+	require.NoError(t, b.SetSketchExcludePatterns([]string{"*_alt.cpp", "scratch"}))
+	require.NoError(t, b.prepareSketchBuildPath())
+	require.False(t, b.sketchBuildPath.Join("impl_alt.cpp").Exist())
+	require.NoError(t, b.buildSketch(paths.NewPathList(includesDir.String())))
+	require.Len(t, b.buildArtifacts.sketchObjectFiles, 2)
+
+	// An excluded file can't be included
+	b = newBuilder()
+	require.NoError(t, b.SetSketchExcludePatterns([]string{"impl.*"}))
+	require.ErrorContains(t, b.prepareSketchBuildPath(), "is excluded from the build but it's included by")
+
+	require.Error(t, b.SetSketchExcludePatterns([]string{"[invalid"}))
+}
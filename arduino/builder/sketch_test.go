@@ -23,6 +23,7 @@ import (
 
 	"github.com/arduino/arduino-cli/arduino/sketch"
 	"github.com/arduino/go-paths-helper"
+	"github.com/arduino/go-properties-orderedmap"
 	"github.com/stretchr/testify/require"
 )
 
@@ -69,6 +70,32 @@ func TestMergeSketchSourcesArduinoIncluded(t *testing.T) {
 	require.Equal(t, 1, strings.Count(source, "<Arduino.h>"))
 }
 
+func TestMergeSketchSourcesOrder(t *testing.T) {
+	sketchPath := paths.New(t.TempDir()).Join("SketchOrder")
+	require.NoError(t, sketchPath.MkdirAll())
+	for _, name := range []string{"SketchOrder.ino", "a.ino", "b.ino", "c.ino"} {
+		require.NoError(t, sketchPath.Join(name).WriteFile([]byte("// "+name)))
+	}
+	require.NoError(t, sketchPath.Join("sketch.yaml").WriteFile([]byte("sketch_files_order:\n  - c.ino\n")))
+	sk, err := sketch.New(sketchPath)
+	require.NoError(t, err)
+
+	b := Builder{sketch: sk}
+	order := []string{}
+	for _, file := range b.SketchFilesMergeOrder() {
+		order = append(order, file.Base())
+	}
+	require.Equal(t, []string{"SketchOrder.ino", "c.ino", "a.ino", "b.ino"}, order)
+
+	_, source, err := b.sketchMergeSources(nil)
+	require.NoError(t, err)
+	positions := []int{}
+	for _, name := range order {
+		positions = append(positions, strings.Index(source, "// "+name))
+	}
+	require.IsIncreasing(t, positions)
+}
+
 func TestCopyAdditionalFiles(t *testing.T) {
 	tmp, err := paths.MkTempDir("", "")
 	require.NoError(t, err)
@@ -106,3 +133,38 @@ func TestCopyAdditionalFiles(t *testing.T) {
 	require.NoError(t, err)
 	require.Equal(t, info1.ModTime(), info2.ModTime())
 }
+
+func TestSetSketchMainFile(t *testing.T) {
+	sketchPath := paths.New(t.TempDir()).Join("MySketch")
+	require.NoError(t, sketchPath.MkdirAll())
+	require.NoError(t, sketchPath.Join("MySketch.ino").WriteFile([]byte("void helper() {}\n")))
+	require.NoError(t, sketchPath.Join("generated.ino").WriteFile([]byte("void setup() {}\nvoid loop() {}\n")))
+	sk, err := sketch.New(sketchPath)
+	require.NoError(t, err)
+	buildPath := paths.New(t.TempDir())
+
+	buildProperties := properties.NewMap()
+	buildProperties.Set("build.project_name", "MySketch.ino")
+	b := &Builder{
+		sketch:          sk,
+		buildProperties: buildProperties,
+		buildPath:       buildPath,
+		sketchBuildPath: buildPath.Join("sketch"),
+	}
+	require.Error(t, b.SetSketchMainFile(paths.New("missing.ino")))
+	require.NoError(t, b.SetSketchMainFile(paths.New("generated.ino")))
+	require.Equal(t, "generated.ino", buildProperties.Get("build.project_name"))
+	require.Equal(t, paths.PathList{sketchPath.Join("generated.ino"), sketchPath.Join("MySketch.ino")}, b.SketchFilesMergeOrder())
+	// The sketch of the caller is not changed
+	require.Equal(t, sketchPath.Join("MySketch.ino"), sk.MainFile)
+
+	require.NoError(t, b.prepareSketchBuildPath())
+	require.False(t, b.sketchBuildPath.Join("MySketch.ino.cpp").Exist())
+	merged, err := b.sketchBuildPath.Join("generated.ino.cpp").ReadFile()
+	require.NoError(t, err)
+	setupIdx := strings.Index(string(merged), "void setup()")
+	helperIdx := strings.Index(string(merged), "void helper()")
+	require.NotEqual(t, -1, setupIdx)
+	require.NotEqual(t, -1, helperIdx)
+	require.Less(t, setupIdx, helperIdx)
+}
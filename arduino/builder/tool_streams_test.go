@@ -0,0 +1,77 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2023 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package builder
+
+import (
+	"bytes"
+	"os/exec"
+	"testing"
+
+	"github.com/arduino/arduino-cli/arduino/builder/internal/logger"
+	"github.com/arduino/go-properties-orderedmap"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSeparateToolStreams(t *testing.T) {
+	sh, err := exec.LookPath("sh")
+	if err != nil {
+		t.Skip("sh not available")
+	}
+	buildProperties := properties.NewMap()
+	buildProperties.Set("recipe.hooks.prebuild.1.pattern", `"`+sh+`" -c 'echo out1; echo err1 >&2; echo out2; echo err2 >&2'`)
+	stdout, stderr := &bytes.Buffer{}, &bytes.Buffer{}
+	b := &Builder{
+		buildProperties: buildProperties,
+		logger:          logger.New(stdout, stderr, true, ""),
+	}
+
+	// Not collected by default
+	require.NoError(t, b.RunRecipe("recipe.hooks.prebuild", ".pattern", false))
+	require.Empty(t, b.ToolStdout())
+	require.Empty(t, b.ToolStderr())
+
+	b.SetSeparateToolStreams(true)
+	stdout.Reset()
+	stderr.Reset()
+	require.NoError(t, b.RunRecipe("recipe.hooks.prebuild", ".pattern", false))
+	require.Equal(t, "out1\nout2\n", string(b.ToolStdout()))
+	require.Equal(t, "err1\nerr2\n", string(b.ToolStderr()))
+	// The output is still displayed
+	require.Contains(t, stdout.String(), "out1\nout2\n")
+	require.Equal(t, "err1\nerr2\n", stderr.String())
+	// Each chunk is tagged with its stream
+	streams := map[ToolStream]string{}
+	for _, chunk := range b.ToolOutput() {
+		streams[chunk.Stream] += string(chunk.Data)
+	}
+	require.Equal(t, map[ToolStream]string{
+		ToolStdoutStream: "out1\nout2\n",
+		ToolStderrStream: "err1\nerr2\n",
+	}, streams)
+
+	// The stdout of the tools is collected even if it's not displayed
+	nonVerbose := &Builder{
+		buildProperties: buildProperties,
+		logger:          logger.New(stdout, stderr, false, ""),
+	}
+	nonVerbose.SetSeparateToolStreams(true)
+	stdout.Reset()
+	stderr.Reset()
+	require.NoError(t, nonVerbose.RunRecipe("recipe.hooks.prebuild", ".pattern", false))
+	require.Equal(t, "out1\nout2\n", string(nonVerbose.ToolStdout()))
+	require.Equal(t, "err1\nerr2\n", string(nonVerbose.ToolStderr()))
+	require.Empty(t, stdout.String())
+}
@@ -0,0 +1,138 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2023 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package builder
+
+import (
+	"context"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/arduino/arduino-cli/executils"
+	"github.com/arduino/go-paths-helper"
+	"github.com/arduino/go-properties-orderedmap"
+	"github.com/sirupsen/logrus"
+)
+
+// ToolchainVersion contains the version reported by one of the compilers
+// used during the build.
+type ToolchainVersion struct {
+	// Recipe is the build property used to determine the compiler command (ex. "compiler.cpp.cmd")
+	Recipe string
+	// Path is the full path to the compiler executable
+	Path *paths.Path
+	// Version is the version number parsed from the compiler output (ex. "7.3.0"),
+	// it's empty if the version could not be determined.
+	Version string
+	// Banner is the first line printed by the compiler when invoked with --version
+	Banner string
+}
+
+// toolchainVersionCommands is the list of compilers queried for their version
+var toolchainVersionCommands = []string{"compiler.c.cmd", "compiler.cpp.cmd", "compiler.S.cmd"}
+
+// toolchainVersionTimeout is the maximum time allowed to a compiler to print its version
+var toolchainVersionTimeout = 10 * time.Second
+
+var toolchainVersionRegexp = regexp.MustCompile(`\d+\.\d+(\.\d+)*`)
+
+// toolchainVersionsCache caches the output of "--version" for each compiler
+// executable (map of *toolchainVersionsCacheEntry): the executable path already
+// identifies the tool release that provides it, so the version is computed only
+// once.
+var toolchainVersionsCache sync.Map
+
+type toolchainVersionsCacheEntry struct {
+	once    sync.Once
+	version *ToolchainVersion
+}
+
+// ToolchainVersions returns the versions of the compilers used in the build
+func (b *Builder) ToolchainVersions() []*ToolchainVersion {
+	return b.toolchainVersions
+}
+
+// detectToolchainVersions runs each compiler used by the build with --version and
+// collects the reported versions. Compilers that do not support --version are
+// reported with an empty Version.
+func detectToolchainVersions(buildProperties *properties.Map) []*ToolchainVersion {
+	res := []*ToolchainVersion{}
+	seen := map[string]bool{}
+	for _, recipe := range toolchainVersionCommands {
+		cmd := buildProperties.ExpandPropsInString(buildProperties.Get(recipe))
+		if cmd == "" {
+			continue
+		}
+		compiler := paths.New(buildProperties.ExpandPropsInString(buildProperties.Get("compiler.path")) + cmd)
+		if seen[compiler.String()] {
+			continue
+		}
+		seen[compiler.String()] = true
+
+		version := getToolchainVersion(compiler)
+		res = append(res, &ToolchainVersion{
+			Recipe:  recipe,
+			Path:    compiler,
+			Version: version.Version,
+			Banner:  version.Banner,
+		})
+	}
+	return res
+}
+
+func getToolchainVersion(compiler *paths.Path) *ToolchainVersion {
+	cached, _ := toolchainVersionsCache.LoadOrStore(compiler.String(), &toolchainVersionsCacheEntry{})
+	entry := cached.(*toolchainVersionsCacheEntry)
+	entry.once.Do(func() {
+		entry.version = runToolchainVersion(compiler)
+	})
+	return entry.version
+}
+
+// runToolchainVersion runs the given compiler with --version and parses its output
+func runToolchainVersion(compiler *paths.Path) *ToolchainVersion {
+	version := &ToolchainVersion{Path: compiler}
+	proc, err := executils.NewProcessFromPath(nil, compiler, "--version")
+	if err != nil {
+		logrus.WithError(err).Debugf("Could not determine version of %s", compiler)
+		return version
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), toolchainVersionTimeout)
+	defer cancel()
+	stdout, _, err := proc.RunAndCaptureOutput(ctx)
+	if err != nil {
+		logrus.WithError(err).Debugf("Could not determine version of %s", compiler)
+		return version
+	}
+	version.Banner, version.Version = parseToolchainVersion(string(stdout))
+	return version
+}
+
+// parseToolchainVersion extracts the first line and the version number
+// from the output of "compiler --version"
+func parseToolchainVersion(output string) (banner string, version string) {
+	banner = strings.TrimSpace(strings.SplitN(strings.TrimSpace(output), "\n", 2)[0])
+	// The version is usually the last version-looking token of the first line:
+	//   avr-gcc (GCC) 7.3.0
+	//   arm-none-eabi-gcc (GNU Arm Embedded Toolchain 10.3-2021.10) 10.3.1 20210824 (release)
+	for _, field := range strings.Fields(banner) {
+		if match := toolchainVersionRegexp.FindString(field); match != "" && match == strings.Trim(field, "()") {
+			version = match
+		}
+	}
+	return banner, version
+}
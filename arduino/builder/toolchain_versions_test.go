@@ -0,0 +1,76 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2023 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package builder
+
+import (
+	"os"
+	"runtime"
+	"testing"
+
+	"github.com/arduino/go-paths-helper"
+	"github.com/arduino/go-properties-orderedmap"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseToolchainVersion(t *testing.T) {
+	banner, version := parseToolchainVersion("avr-gcc (GCC) 7.3.0\nCopyright (C) 2017 Free Software Foundation, Inc.\n")
+	require.Equal(t, "avr-gcc (GCC) 7.3.0", banner)
+	require.Equal(t, "7.3.0", version)
+
+	banner, version = parseToolchainVersion("arm-none-eabi-gcc (GNU Arm Embedded Toolchain 10.3-2021.10) 10.3.1 20210824 (release)\n")
+	require.Equal(t, "arm-none-eabi-gcc (GNU Arm Embedded Toolchain 10.3-2021.10) 10.3.1 20210824 (release)", banner)
+	require.Equal(t, "10.3.1", version)
+
+	banner, version = parseToolchainVersion("unknown option --version\n")
+	require.Equal(t, "unknown option --version", banner)
+	require.Equal(t, "", version)
+}
+
+func TestDetectToolchainVersions(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("fake compilers are shell scripts")
+	}
+	tmp, err := paths.MkTempDir("", "toolchain_versions")
+	require.NoError(t, err)
+	defer tmp.RemoveAll()
+
+	fakeGcc := tmp.Join("fake-gcc")
+	require.NoError(t, fakeGcc.WriteFile([]byte("#!/bin/sh\necho 'fake-gcc (GCC) 7.3.0'\n")))
+	require.NoError(t, os.Chmod(fakeGcc.String(), 0755))
+	fakeGpp := tmp.Join("fake-g++")
+	require.NoError(t, fakeGpp.WriteFile([]byte("#!/bin/sh\necho 'unrecognized option' >&2\nexit 1\n")))
+	require.NoError(t, os.Chmod(fakeGpp.String(), 0755))
+
+	buildProperties := properties.NewMap()
+	buildProperties.Set("compiler.path", tmp.String()+"/")
+	buildProperties.Set("compiler.c.cmd", "fake-gcc")
+	buildProperties.Set("compiler.cpp.cmd", "fake-g++")
+	buildProperties.Set("compiler.S.cmd", "fake-gcc")
+
+	versions := detectToolchainVersions(buildProperties)
+	require.Len(t, versions, 2)
+	require.Equal(t, "compiler.c.cmd", versions[0].Recipe)
+	require.Equal(t, fakeGcc.String(), versions[0].Path.String())
+	require.Equal(t, "7.3.0", versions[0].Version)
+	require.Equal(t, "fake-gcc (GCC) 7.3.0", versions[0].Banner)
+	require.Equal(t, "compiler.cpp.cmd", versions[1].Recipe)
+	require.Equal(t, "", versions[1].Version)
+
+	// The result is cached per executable
+	require.NoError(t, fakeGcc.WriteFile([]byte("#!/bin/sh\necho 'fake-gcc (GCC) 9.9.9'\n")))
+	versions = detectToolchainVersions(buildProperties)
+	require.Equal(t, "7.3.0", versions[0].Version)
+}
@@ -0,0 +1,119 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2023 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package builder
+
+import (
+	"debug/elf"
+
+	"github.com/arduino/arduino-cli/arduino/builder/internal/utils"
+	"github.com/arduino/arduino-cli/arduino/libraries"
+	"github.com/arduino/go-paths-helper"
+)
+
+// UnusedLibraries returns the libraries that have been included by the sketch
+// but whose compiled code is not referenced by the sketch or by the other
+// libraries. It must be called after the build.
+//
+// The result is a best-effort heuristic, based on the symbols of the compiled
+// object files: libraries whose compiled code is all inlined in the sketch are
+// reported as unused even if they are actually needed, while the libraries
+// without compiled object files (for example the header-only or the
+// precompiled ones) and the ones that can't be analyzed (for example because
+// they are built with a toolchain that doesn't produce ELF objects) are never
+// reported.
+func (b *Builder) UnusedLibraries() libraries.List {
+	return findUnusedLibraries(b.libsDetector.ImportedLibraries(), b.librariesBuildPath, b.buildArtifacts.sketchObjectFiles)
+}
+
+func findUnusedLibraries(importedLibraries libraries.List, librariesBuildPath *paths.Path, sketchObjectFiles paths.PathList) libraries.List {
+	type librarySymbols struct {
+		defined   map[string]bool
+		undefined map[string]bool
+	}
+	symbolsOf := func(objectFiles paths.PathList) (*librarySymbols, bool) {
+		res := &librarySymbols{defined: map[string]bool{}, undefined: map[string]bool{}}
+		for _, objectFile := range objectFiles {
+			if !readObjectFileSymbols(objectFile, res.defined, res.undefined) {
+				return nil, false
+			}
+		}
+		return res, true
+	}
+
+	librariesSymbols := map[*libraries.Library]*librarySymbols{}
+	for _, library := range importedLibraries {
+		objectFiles, err := utils.FindFilesInFolder(librariesBuildPath.Join(library.DirName), true, ".o")
+		if err != nil || len(objectFiles) == 0 {
+			continue
+		}
+		if symbols, ok := symbolsOf(objectFiles); ok {
+			librariesSymbols[library] = symbols
+		}
+	}
+	sketchSymbols, ok := symbolsOf(sketchObjectFiles)
+	if !ok {
+		return libraries.List{}
+	}
+
+	isReferenced := func(library *libraries.Library) bool {
+		for symbol := range librariesSymbols[library].defined {
+			if sketchSymbols.undefined[symbol] {
+				return true
+			}
+			for other, otherSymbols := range librariesSymbols {
+				if other != library && otherSymbols.undefined[symbol] {
+					return true
+				}
+			}
+		}
+		return false
+	}
+
+	unused := libraries.List{}
+	for _, library := range importedLibraries {
+		if _, analyzed := librariesSymbols[library]; analyzed && !isReferenced(library) {
+			unused.Add(library)
+		}
+	}
+	return unused
+}
+
+// readObjectFileSymbols adds the global symbols defined and referenced by the
+// given ELF object file to the given sets. It returns false if the file can't
+// be parsed.
+func readObjectFileSymbols(objectFile *paths.Path, defined, undefined map[string]bool) bool {
+	f, err := elf.Open(objectFile.String())
+	if err != nil {
+		return false
+	}
+	defer f.Close()
+	symbols, err := f.Symbols()
+	if err != nil && err != elf.ErrNoSymbols {
+		return false
+	}
+	for _, symbol := range symbols {
+		binding := elf.ST_BIND(symbol.Info)
+		if symbol.Name == "" || (binding != elf.STB_GLOBAL && binding != elf.STB_WEAK) {
+			continue
+		}
+		if symbol.Section == elf.SHN_UNDEF {
+			undefined[symbol.Name] = true
+		} else {
+			defined[symbol.Name] = true
+		}
+	}
+	return true
+}
@@ -0,0 +1,58 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2023 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package builder
+
+import (
+	"os/exec"
+	"testing"
+
+	"github.com/arduino/arduino-cli/arduino/libraries"
+	"github.com/arduino/go-paths-helper"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFindUnusedLibraries(t *testing.T) {
+	gpp, err := exec.LookPath("g++")
+	if err != nil {
+		t.Skip("g++ not available")
+	}
+	buildPath := paths.New(t.TempDir())
+	compile := func(objectFile *paths.Path, source string) {
+		require.NoError(t, objectFile.Parent().MkdirAll())
+		sourceFile := objectFile.Parent().Join("source.cpp")
+		require.NoError(t, sourceFile.WriteFile([]byte(source)))
+		require.NoError(t, exec.Command(gpp, "-c", sourceFile.String(), "-o", objectFile.String()).Run())
+		require.NoError(t, sourceFile.Remove())
+	}
+
+	sketchObject := buildPath.Join("sketch", "sketch.ino.cpp.o")
+	compile(sketchObject, "int used();\nvoid setup() { used(); }\n")
+	librariesBuildPath := buildPath.Join("libraries")
+	compile(librariesBuildPath.Join("Used", "Used.cpp.o"), "int helper();\nint used() { return helper(); }\n")
+	compile(librariesBuildPath.Join("Helper", "Helper.cpp.o"), "int helper() { return 1; }\n")
+	compile(librariesBuildPath.Join("Unused", "Unused.cpp.o"), "int unused() { return 2; }\n")
+
+	used := &libraries.Library{Name: "Used", DirName: "Used"}
+	helper := &libraries.Library{Name: "Helper", DirName: "Helper"}
+	unused := &libraries.Library{Name: "Unused", DirName: "Unused"}
+	precompiled := &libraries.Library{Name: "Precompiled", DirName: "Precompiled"}
+	require.NoError(t, librariesBuildPath.Join("HeaderOnly").MkdirAll())
+	headerOnly := &libraries.Library{Name: "HeaderOnly", DirName: "HeaderOnly"}
+	imported := libraries.List{used, helper, unused, precompiled, headerOnly}
+
+	res := findUnusedLibraries(imported, librariesBuildPath, paths.PathList{sketchObject})
+	require.Equal(t, libraries.List{unused}, res)
+}
@@ -0,0 +1,64 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2023 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package builder
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/arduino/arduino-cli/arduino/builder/internal/logger"
+)
+
+// WarningsLevels are the known compiler warnings levels. Each level selects the
+// compiler.warning_flags.<level> build property of the platform as the
+// compiler.warning_flags used in the compile recipes.
+var WarningsLevels = []string{"none", "default", "more", "all"}
+
+// ErrInvalidWarningsLevel is returned when an unknown warnings level is requested
+var ErrInvalidWarningsLevel = errors.New("invalid warnings level")
+
+func validateWarningsLevel(level string) error {
+	if level != "" && !slices.Contains(WarningsLevels, level) {
+		return fmt.Errorf("%w '%s', %s", ErrInvalidWarningsLevel, level, tr("must be one of: %s", WarningsLevels))
+	}
+	return nil
+}
+
+// setWarningsLevelOrDefault sets the given warnings level in the logger or, if
+// the level is unknown, prints a warning and keeps the default level.
+func setWarningsLevelOrDefault(l *logger.BuilderLogger, level string) {
+	if err := validateWarningsLevel(level); err != nil {
+		l.Warn(tr("Warning: %[1]s, using warnings level %[2]s", err, l.WarningsLevel()))
+		return
+	}
+	l.SetWarningsLevel(level)
+}
+
+// SetWarningsLevel overrides the compiler warnings level used for the build,
+// an empty level is the same as "none".
+func (b *Builder) SetWarningsLevel(level string) error {
+	if err := validateWarningsLevel(level); err != nil {
+		return err
+	}
+	b.logger.SetWarningsLevel(level)
+	return nil
+}
+
+// WarningsLevel returns the compiler warnings level used for the build
+func (b *Builder) WarningsLevel() string {
+	return b.logger.WarningsLevel()
+}
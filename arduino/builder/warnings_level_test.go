@@ -0,0 +1,78 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2023 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package builder
+
+import (
+	"bytes"
+	"io"
+	"testing"
+
+	"github.com/arduino/arduino-cli/arduino/builder/internal/compilation"
+	"github.com/arduino/arduino-cli/arduino/builder/internal/logger"
+	"github.com/arduino/go-paths-helper"
+	"github.com/arduino/go-properties-orderedmap"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWarningsLevel(t *testing.T) {
+	sketchDir := paths.New(t.TempDir())
+	source := sketchDir.Join("sketch.ino.cpp")
+	require.NoError(t, source.WriteFile([]byte("void setup() {}\n")))
+	buildPath := paths.New(t.TempDir())
+
+	buildProperties := properties.NewMap()
+	buildProperties.Set("compiler.warning_flags", "-w")
+	buildProperties.Set("compiler.warning_flags.none", "-w")
+	buildProperties.Set("compiler.warning_flags.default", "")
+	buildProperties.Set("compiler.warning_flags.more", "-Wall")
+	buildProperties.Set("compiler.warning_flags.all", "-Wall -Wextra")
+	buildProperties.Set("recipe.cpp.o.pattern", `g++ -c {compiler.warning_flags} "{source_file}" -o "{object_file}"`)
+
+	compileArgs := func(level string) []string {
+		b := &Builder{
+			buildProperties:               buildProperties,
+			buildPath:                     buildPath,
+			logger:                        logger.New(io.Discard, io.Discard, false, ""),
+			onlyUpdateCompilationDatabase: true,
+			compilationDatabase:           compilation.NewDatabase(buildPath.Join("compile_commands.json")),
+		}
+		require.NoError(t, b.SetWarningsLevel(level))
+		_, err := b.compileFileWithRecipe(sketchDir, source, buildPath.Join("sketch"), nil, "recipe.cpp.o.pattern")
+		require.NoError(t, err)
+		require.Len(t, b.compilationDatabase.Contents, 1)
+		args := b.compilationDatabase.Contents[0].Arguments
+		return args[2 : len(args)-3]
+	}
+
+	require.Equal(t, []string{"-w"}, compileArgs(""))
+	require.Equal(t, []string{"-w"}, compileArgs("none"))
+	require.Equal(t, []string{}, compileArgs("default"))
+	require.Equal(t, []string{"-Wall"}, compileArgs("more"))
+	require.Equal(t, []string{"-Wall", "-Wextra"}, compileArgs("all"))
+
+	b := &Builder{logger: logger.New(io.Discard, io.Discard, false, "")}
+	require.ErrorIs(t, b.SetWarningsLevel("pedantic"), ErrInvalidWarningsLevel)
+	require.Equal(t, "none", b.WarningsLevel())
+
+	// Unknown levels passed to the builder fall back to the default one
+	stderr := &bytes.Buffer{}
+	l := logger.New(io.Discard, stderr, false, "")
+	setWarningsLevelOrDefault(l, "pedantic")
+	require.Equal(t, "none", l.WarningsLevel())
+	require.Contains(t, stderr.String(), "pedantic")
+	setWarningsLevelOrDefault(l, "all")
+	require.Equal(t, "all", l.WarningsLevel())
+}
@@ -17,6 +17,7 @@ package cores
 
 import (
 	"fmt"
+	"path"
 	"strings"
 	"sync"
 
@@ -50,6 +51,68 @@ func (b *Board) HasUsbID(reqVid, reqPid string) bool {
 	return false
 }
 
+// UsbIDMatch is how well a board matches a USB VID/PID, see Board.MatchUsbID
+type UsbIDMatch int
+
+const (
+	// UsbIDNoMatch means that the board doesn't declare the VID
+	UsbIDNoMatch UsbIDMatch = iota
+	// UsbIDVendorMatch means that the board declares the VID with other PIDs
+	UsbIDVendorMatch
+	// UsbIDWildcardMatch means that the PID matches by a wildcard pattern
+	UsbIDWildcardMatch
+	// UsbIDExactMatch means that the board declares exactly the VID/PID
+	UsbIDExactMatch
+)
+
+var usbIDMatchNames = map[UsbIDMatch]string{
+	UsbIDNoMatch:       "none",
+	UsbIDVendorMatch:   "vendor",
+	UsbIDWildcardMatch: "wildcard",
+	UsbIDExactMatch:    "exact",
+}
+
+func (m UsbIDMatch) String() string {
+	return usbIDMatchNames[m]
+}
+
+// MatchUsbID returns how well the board matches the given USB VID and PID. The
+// requested PID and the PIDs declared by the board (as vid.N/pid.N or as
+// upload_port.N.vid/pid) may be shell patterns, for example "0x80*" or "*".
+// The VID is compared ignoring case and must always be equal.
+func (b *Board) MatchUsbID(reqVid, reqPid string) UsbIDMatch {
+	res := UsbIDNoMatch
+	check := func(vid, pid string) {
+		if !strings.EqualFold(vid, reqVid) {
+			return
+		}
+		match := UsbIDVendorMatch
+		if strings.EqualFold(pid, reqPid) {
+			match = UsbIDExactMatch
+		} else if matchIdentificationValue(pid, reqPid) || matchIdentificationValue(reqPid, pid) {
+			match = UsbIDWildcardMatch
+		}
+		if match > res {
+			res = match
+		}
+	}
+	vids := b.Properties.SubTree("vid")
+	pids := b.Properties.SubTree("pid")
+	for id, vid := range vids.AsMap() {
+		if pid, ok := pids.GetOk(id); ok {
+			check(vid, pid)
+		}
+	}
+	for _, idProps := range b.GetIdentificationProperties() {
+		vid, hasVid := idProps.GetOk("vid")
+		pid, hasPid := idProps.GetOk("pid")
+		if hasVid && hasPid {
+			check(vid, pid)
+		}
+	}
+	return res
+}
+
 // Name returns the board name as defined in boards.txt properties
 func (b *Board) Name() string {
 	return b.Properties.Get("name")
@@ -180,23 +243,46 @@ func (b *Board) GetIdentificationProperties() []*properties.Map {
 // IsBoardMatchingIDProperties returns true if the board match the given
 // upload port identification properties
 func (b *Board) IsBoardMatchingIDProperties(query *properties.Map) bool {
-	// check checks if the given set of properties p match the "query"
-	check := func(p *properties.Map) bool {
-		for k, v := range p.AsMap() {
-			if !strings.EqualFold(query.Get(k), v) {
-				return false
-			}
+	return b.IdentificationSpecificity(query) > 0
+}
+
+// IdentificationSpecificity returns the number of properties of the most
+// specific set of identification properties (upload_port.N.xxx) of the board
+// matching the given upload port properties, or 0 if none matches. A set
+// matches if all its properties are found in the query. The values may be
+// shell patterns (for example upload_port.0.serialNumber=ABC*), matched
+// ignoring case.
+func (b *Board) IdentificationSpecificity(query *properties.Map) int {
+	res := 0
+	for _, idProps := range b.GetIdentificationProperties() {
+		if matchIdentificationProperties(idProps, query) && idProps.Size() > res {
+			res = idProps.Size()
 		}
-		return true
 	}
+	return res
+}
 
-	// First check the identification properties with sub index "upload_port.N.xxx"
-	for _, idProps := range b.GetIdentificationProperties() {
-		if check(idProps) {
-			return true
+// matchIdentificationProperties checks if the given set of properties p match the "query"
+func matchIdentificationProperties(p, query *properties.Map) bool {
+	for k, v := range p.AsMap() {
+		if !matchIdentificationValue(v, query.Get(k)) {
+			return false
 		}
 	}
-	return false
+	return true
+}
+
+// matchIdentificationValue returns true if value is equal to the expected one,
+// or matches it if the expected value is a pattern, ignoring case
+func matchIdentificationValue(expected, value string) bool {
+	if strings.EqualFold(expected, value) {
+		return true
+	}
+	if !strings.ContainsAny(expected, "*?[") {
+		return false
+	}
+	matched, err := path.Match(strings.ToLower(expected), strings.ToLower(value))
+	return err == nil && matched
 }
 
 // GetMonitorSettings returns the settings for the pluggable monitor of the given protocol
@@ -208,18 +294,9 @@ func GetMonitorSettings(protocol string, boardProperties *properties.Map) *prope
 // IdentifyBoardConfiguration returns the configuration of the board that can be
 // deduced from the given upload port identification properties
 func (b *Board) IdentifyBoardConfiguration(query *properties.Map) *properties.Map {
-	// check checks if the given set of properties p match the "query"
-	check := func(p *properties.Map) bool {
-		for k, v := range p.AsMap() {
-			if !strings.EqualFold(query.Get(k), v) {
-				return false
-			}
-		}
-		return true
-	}
 	checkAll := func(allP []*properties.Map) bool {
 		for _, p := range allP {
-			if check(p) {
+			if matchIdentificationProperties(p, query) {
 				return true
 			}
 		}
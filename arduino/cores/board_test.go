@@ -208,6 +208,15 @@ func TestBoard(t *testing.T) {
 	require.False(t, boardMega.HasUsbID("0x2A03", "0x0043"), "has usb 2A03:0043")
 }
 
+func TestBoardMatchUsbID(t *testing.T) {
+	require.Equal(t, UsbIDExactMatch, boardUno.MatchUsbID("0x2341", "0x0043"))
+	require.Equal(t, UsbIDExactMatch, boardUno.MatchUsbID("0x2a03", "0x0043"))
+	require.Equal(t, UsbIDWildcardMatch, boardUno.MatchUsbID("0x2341", "0x02*"))
+	require.Equal(t, UsbIDWildcardMatch, boardUno.MatchUsbID("0x2341", "*"))
+	require.Equal(t, UsbIDVendorMatch, boardUno.MatchUsbID("0x2341", "0x0010"))
+	require.Equal(t, UsbIDNoMatch, boardUno.MatchUsbID("0x1A03", "0x0043"))
+}
+
 func TestBoardOptions(t *testing.T) {
 	expConf2560 := properties.NewMap()
 	expConf2560.Set("bootloader.extended_fuses", "0xFD")
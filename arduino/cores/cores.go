@@ -24,6 +24,7 @@ import (
 	"path/filepath"
 	"sort"
 	"strings"
+	"time"
 
 	"github.com/arduino/arduino-cli/arduino/globals"
 	"github.com/arduino/arduino-cli/arduino/resources"
@@ -37,19 +38,23 @@ import (
 
 // Platform represents a platform package.
 type Platform struct {
-	Architecture      string // The name of the architecture of this package.
-	Name              string
-	Category          string
-	Releases          map[semver.NormalizedString]*PlatformRelease // The Releases of this platform, labeled by version.
-	Package           *Package                                     `json:"-"`
-	ManuallyInstalled bool                                         // true if the Platform has been installed without the CLI
-	Deprecated        bool                                         // true if the Platform has been deprecated
-	Indexed           bool                                         // true if the Platform has been indexed from additional-urls
-}
-
-// PlatformReleaseHelp represents the help URL for this Platform release
+	Architecture       string // The name of the architecture of this package.
+	Name               string
+	Category           string
+	Releases           map[semver.NormalizedString]*PlatformRelease // The Releases of this platform, labeled by version.
+	Package            *Package                                     `json:"-"`
+	ManuallyInstalled  bool                                         // true if the Platform has been installed without the CLI
+	Deprecated         bool                                         // true if the Platform has been deprecated
+	DeprecationMessage string                                       // Why the Platform has been deprecated and how to migrate
+	ReplacedBy         string                                       // The platform (PACKAGER:ARCH) replacing the deprecated one
+	EndOfLife          time.Time                                    // When the Platform stops being supported, zero if not planned
+	Indexed            bool                                         // true if the Platform has been indexed from additional-urls
+}
+
+// PlatformReleaseHelp represents the help and changelog URLs for this Platform release
 type PlatformReleaseHelp struct {
-	Online string `json:"-"`
+	Online    string `json:"-"`
+	Changelog string `json:"-"`
 }
 
 // PlatformRelease represents a release of a plaform package.
@@ -61,6 +66,7 @@ type PlatformRelease struct {
 	DiscoveryDependencies   DiscoveryDependencies
 	MonitorDependencies     MonitorDependencies
 	Help                    PlatformReleaseHelp           `json:"-"`
+	ReleaseNotes            string                        `json:"-"` // The release notes embedded in the package index, if any
 	Platform                *Platform                     `json:"-"`
 	Properties              *properties.Map               `json:"-"`
 	Boards                  map[string]*Board             `json:"-"`
@@ -124,6 +130,25 @@ type ToolDependency struct {
 	ToolName     string
 	ToolVersion  *semver.RelaxedVersion
 	ToolPackager string
+	// ToolVersionConstraint is an optional version range, if set it's used in
+	// place of ToolVersion to determine the releases satisfying the dependency.
+	ToolVersionConstraint semver.Constraint
+}
+
+// IsSatisfiedBy returns true if the given tool version satisfies the dependency.
+// A nil version never satisfies the dependency.
+func (dep *ToolDependency) IsSatisfiedBy(version *semver.RelaxedVersion) bool {
+	if version == nil {
+		return false
+	}
+	if dep.ToolVersionConstraint == nil {
+		return dep.ToolVersion != nil && dep.ToolVersion.Equal(version)
+	}
+	v, err := semver.Parse(version.String())
+	if err != nil {
+		return false
+	}
+	return dep.ToolVersionConstraint.Match(v)
 }
 
 func (dep *ToolDependency) String() string {
@@ -278,6 +303,21 @@ func (platform *Platform) GetAllInstalled() []*PlatformRelease {
 	return res
 }
 
+// EndOfLifeReached returns true if the Platform has an end of life date and
+// the date has already passed
+func (platform *Platform) EndOfLifeReached() bool {
+	return !platform.EndOfLife.IsZero() && !time.Now().Before(platform.EndOfLife)
+}
+
+// EndOfLifeDate returns the end of life date of the Platform formatted as
+// YYYY-MM-DD, or an empty string if no end of life is planned
+func (platform *Platform) EndOfLifeDate() string {
+	if platform.EndOfLife.IsZero() {
+		return ""
+	}
+	return platform.EndOfLife.Format("2006-01-02")
+}
+
 func (platform *Platform) String() string {
 	return platform.Package.Name + ":" + platform.Architecture
 }
@@ -304,13 +344,60 @@ func (release *PlatformRelease) GetBoards() []*Board {
 	return release.orderedBoards
 }
 
+// DuplicateBoards returns the groups of boards of the platform release having
+// identical build properties with the default configuration (the name and the
+// FQBN of the board are not compared): they are likely copy-paste errors in
+// boards.txt. The groups, and the boards in each group, are sorted by board ID.
+func (release *PlatformRelease) DuplicateBoards() [][]*Board {
+	groups := map[string][]*Board{}
+	for _, board := range release.Boards {
+		fqbn, err := ParseFQBN(board.String())
+		if err != nil {
+			continue
+		}
+		buildProperties, err := board.GetBuildProperties(fqbn)
+		if err != nil {
+			continue
+		}
+		buildProperties.Remove("name")
+		buildProperties.Remove("build.fqbn")
+		keys := buildProperties.Keys()
+		sort.Strings(keys)
+		fingerprint := sha256.New()
+		for _, key := range keys {
+			fingerprint.Write([]byte(key + "=" + buildProperties.Get(key) + "\n"))
+		}
+		id := hex.EncodeToString(fingerprint.Sum(nil))
+		groups[id] = append(groups[id], board)
+	}
+
+	res := [][]*Board{}
+	for _, group := range groups {
+		if len(group) < 2 {
+			continue
+		}
+		sort.Slice(group, func(i, j int) bool { return group[i].BoardID < group[j].BoardID })
+		res = append(res, group)
+	}
+	sort.Slice(res, func(i, j int) bool { return res[i][0].BoardID < res[j][0].BoardID })
+	return res
+}
+
 // RequiresToolRelease returns true if the PlatformRelease requires the
-// toolReleased passed as parameter
+// toolReleased passed as parameter. A dependency declared as a version range
+// requires only the release it resolves to, not every release in the range,
+// so the other ones can be uninstalled.
 func (release *PlatformRelease) RequiresToolRelease(toolRelease *ToolRelease) bool {
 	for _, toolDep := range release.ToolDependencies {
-		if toolDep.ToolName == toolRelease.Tool.Name &&
-			toolDep.ToolPackager == toolRelease.Tool.Package.Name &&
-			toolDep.ToolVersion.Equal(toolRelease.Version) {
+		if toolDep.ToolName != toolRelease.Tool.Name ||
+			toolDep.ToolPackager != toolRelease.Tool.Package.Name {
+			continue
+		}
+		if toolDep.ToolVersionConstraint == nil {
+			if toolDep.ToolVersion.Equal(toolRelease.Version) {
+				return true
+			}
+		} else if toolRelease.Tool.FindReleaseForDependency(toolDep) == toolRelease {
 			return true
 		}
 	}
@@ -344,6 +431,26 @@ func (release *PlatformRelease) RuntimeProperties() *properties.Map {
 	return res
 }
 
+// MenuDefinition is a custom board menu declared by a platform in the
+// boards.txt file (for example "menu.cpu=Processor").
+type MenuDefinition struct {
+	ID    string
+	Label string
+}
+
+// PlatformMenus returns all the custom board menus declared by the given
+// PlatformRelease, in the same order as they are defined in the boards.txt.
+func PlatformMenus(release *PlatformRelease) []MenuDefinition {
+	res := []MenuDefinition{}
+	if release.Menus == nil {
+		return res
+	}
+	for _, id := range release.Menus.FirstLevelKeys() {
+		res = append(res, MenuDefinition{ID: id, Label: release.Menus.Get(id)})
+	}
+	return res
+}
+
 // GetLibrariesDir returns the path to the core libraries or nil if not
 // present
 func (release *PlatformRelease) GetLibrariesDir() *paths.Path {
@@ -382,10 +489,14 @@ func (release *PlatformRelease) ToRPCPlatformReference() *rpc.InstalledPlatformR
 		url = ""
 	}
 	return &rpc.InstalledPlatformReference{
-		Id:         release.Platform.String(),
-		Version:    release.Version.String(),
-		InstallDir: release.InstallDir.String(),
-		PackageUrl: url,
+		Id:               release.Platform.String(),
+		Version:          release.Version.String(),
+		InstallDir:       release.InstallDir.String(),
+		PackageUrl:       url,
+		Deprecated:       release.Platform.Deprecated,
+		EndOfLife:        release.Platform.EndOfLifeDate(),
+		EndOfLifeReached: release.Platform.EndOfLifeReached(),
+		ReplacedBy:       release.Platform.ReplacedBy,
 	}
 }
 
@@ -18,6 +18,8 @@ package cores
 import (
 	"testing"
 
+	"github.com/arduino/go-paths-helper"
+	properties "github.com/arduino/go-properties-orderedmap"
 	"github.com/stretchr/testify/require"
 	semver "go.bug.st/relaxed-semver"
 )
@@ -56,6 +58,39 @@ func TestRequiresToolRelease(t *testing.T) {
 	require.True(t, release.RequiresToolRelease(toolRelease))
 }
 
+func TestRequiresToolReleaseWithVersionRange(t *testing.T) {
+	tool := &Tool{Name: "avr-gcc", Package: &Package{Name: "arduino"}}
+	tool.Releases = map[semver.NormalizedString]*ToolRelease{}
+	for _, version := range []semver.NormalizedString{"7.3.0", "7.4.0", "8.0.0"} {
+		tool.Releases[version] = &ToolRelease{
+			Version:    semver.ParseRelaxed(string(version)),
+			Tool:       tool,
+			InstallDir: paths.New("tools", string(version)),
+		}
+	}
+	constraint, err := ParseToolVersionConstraint("^7.3.0")
+	require.NoError(t, err)
+	release := PlatformRelease{
+		ToolDependencies: ToolDependencies{
+			{
+				ToolName:              "avr-gcc",
+				ToolVersion:           semver.ParseRelaxed("^7.3.0"),
+				ToolPackager:          "arduino",
+				ToolVersionConstraint: constraint,
+			},
+		},
+	}
+
+	// Only the release the range resolves to is required
+	require.False(t, release.RequiresToolRelease(tool.Releases["7.3.0"]))
+	require.True(t, release.RequiresToolRelease(tool.Releases["7.4.0"]))
+	require.False(t, release.RequiresToolRelease(tool.Releases["8.0.0"]))
+
+	// ...that is the newest installed one satisfying the range
+	tool.Releases["7.4.0"].InstallDir = nil
+	require.True(t, release.RequiresToolRelease(tool.Releases["7.3.0"]))
+}
+
 func TestRequiresToolReleaseDiscovery(t *testing.T) {
 	toolDependencyName := "ble-discovery"
 	toolDependencyPackager := "arduino"
@@ -92,3 +127,39 @@ func TestRequiresToolReleaseDiscovery(t *testing.T) {
 	toolRelease.Version = semver.ParseRelaxed("1.0.0")
 	require.True(t, release.RequiresToolRelease(toolRelease))
 }
+
+func TestDuplicateBoards(t *testing.T) {
+	release := NewPackages().GetOrCreatePackage("test").GetOrCreatePlatform("avr").GetOrCreateRelease(semver.MustParse("1.0.0"))
+	release.Menus = properties.NewMap()
+	release.Menus.Set("speed", "Speed")
+	setBoard := func(boardID, name, mcu, fcpu string) {
+		board := release.GetOrCreateBoard(boardID)
+		board.Properties.Set("name", name)
+		board.Properties.Set("build.mcu", mcu)
+		board.Properties.Set("build.board", "AVR_BOARD")
+		board.Properties.Set("menu.speed.fast", "Fast")
+		board.Properties.Set("menu.speed.fast.build.f_cpu", fcpu)
+		board.Properties.Set("menu.speed.slow", "Slow")
+		board.Properties.Set("menu.speed.slow.build.f_cpu", "1000000L")
+	}
+	require.Empty(t, release.DuplicateBoards())
+
+	setBoard("uno", "Uno", "atmega328p", "16000000L")
+	setBoard("uno_copy", "Uno (copy)", "atmega328p", "16000000L")
+	setBoard("mega", "Mega", "atmega2560", "16000000L")
+	setBoard("nano", "Nano", "atmega328p", "16000000L")
+	setBoard("mega_copy", "Mega (copy)", "atmega2560", "16000000L")
+	setBoard("mini", "Mini", "atmega168", "16000000L")
+	// A different value in the default menu option makes the boards different
+	setBoard("uno_8mhz", "Uno 8MHz", "atmega328p", "8000000L")
+
+	boardIDs := [][]string{}
+	for _, group := range release.DuplicateBoards() {
+		ids := []string{}
+		for _, board := range group {
+			ids = append(ids, board.BoardID)
+		}
+		boardIDs = append(boardIDs, ids)
+	}
+	require.Equal(t, [][]string{{"mega", "mega_copy"}, {"nano", "uno", "uno_copy"}}, boardIDs)
+}
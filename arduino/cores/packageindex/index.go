@@ -16,12 +16,16 @@
 package packageindex
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
 	"fmt"
+	"io"
+	"time"
 
+	"github.com/arduino/arduino-cli/arduino"
 	"github.com/arduino/arduino-cli/arduino/cores"
 	"github.com/arduino/arduino-cli/arduino/resources"
-	"github.com/arduino/arduino-cli/arduino/security"
 	"github.com/arduino/arduino-cli/i18n"
 	"github.com/arduino/go-paths-helper"
 	easyjson "github.com/mailru/easyjson"
@@ -33,11 +37,19 @@ import (
 //
 //easyjson:json
 type Index struct {
-	Packages        []*indexPackage `json:"packages"`
-	IsTrusted       bool
-	isInstalledJSON bool
+	Packages []*indexPackage `json:"packages"`
+	// TrustedDownloadHosts are the hosts where the downloads of the platforms
+	// and tools of this index may be redirected to (in addition to the host of
+	// the download URL). If empty the redirects are not restricted.
+	TrustedDownloadHosts []string `json:"trustedDownloadHosts,omitempty"`
+	IsTrusted            bool
+	isInstalledJSON      bool
+	checksum             string
 }
 
+// endOfLifeDateFormat is the format of the endOfLife date of the platforms
+const endOfLifeDateFormat = "2006-01-02"
+
 // indexPackage represents a single entry from package_index.json file.
 //
 //easyjson:json
@@ -60,13 +72,18 @@ type indexPlatformRelease struct {
 	Architecture          string                     `json:"architecture"`
 	Version               *semver.Version            `json:"version"`
 	Deprecated            bool                       `json:"deprecated"`
+	DeprecationMessage    string                     `json:"deprecationMessage,omitempty"`
+	ReplacedBy            string                     `json:"replacedBy,omitempty"`
+	EndOfLife             string                     `json:"endOfLife,omitempty"`
 	Category              string                     `json:"category"`
 	URL                   string                     `json:"url"`
 	ArchiveFileName       string                     `json:"archiveFileName"`
 	Checksum              string                     `json:"checksum"`
+	Mirrors               []string                   `json:"mirrors,omitempty"`
 	Size                  json.Number                `json:"size"`
 	Boards                []indexBoard               `json:"boards"`
 	Help                  indexHelp                  `json:"help,omitempty"`
+	ReleaseNotes          string                     `json:"releaseNotes,omitempty"`
 	ToolDependencies      []indexToolDependency      `json:"toolsDependencies"`
 	DiscoveryDependencies []indexDiscoveryDependency `json:"discoveryDependencies"`
 	MonitorDependencies   []indexMonitorDependency   `json:"monitorDependencies"`
@@ -115,6 +132,7 @@ type indexToolReleaseFlavour struct {
 	ArchiveFileName string      `json:"archiveFileName"`
 	Size            json.Number `json:"size"`
 	Checksum        string      `json:"checksum"`
+	Mirrors         []string    `json:"mirrors,omitempty"`
 }
 
 // indexBoard represents a single Board as written in package_index.json file.
@@ -132,11 +150,12 @@ type indexBoardID struct {
 	USB string `json:"usb"`
 }
 
-// indexHelp represents the help URL
+// indexHelp represents the help URL and, for platforms, the URL of the changelog
 //
 //easyjson:json
 type indexHelp struct {
-	Online string `json:"online,omitempty"`
+	Online    string `json:"online,omitempty"`
+	Changelog string `json:"changelog,omitempty"`
 }
 
 var tr = i18n.Tr
@@ -145,7 +164,7 @@ var tr = i18n.Tr
 // with the existing contents of the cores.Packages passed as parameter.
 func (index Index) MergeIntoPackages(outPackages cores.Packages) {
 	for _, inPackage := range index.Packages {
-		inPackage.extractPackageIn(outPackages, index.IsTrusted, index.isInstalledJSON)
+		inPackage.extractPackageIn(outPackages, index.IsTrusted, index.isInstalledJSON, index.TrustedDownloadHosts)
 	}
 }
 
@@ -202,6 +221,7 @@ func IndexFromPlatformRelease(pr *cores.PlatformRelease) Index {
 					ArchiveFileName: flavour.Resource.ArchiveFileName,
 					Size:            json.Number(fmt.Sprintf("%d", flavour.Resource.Size)),
 					Checksum:        flavour.Resource.Checksum,
+					Mirrors:         flavour.Resource.Mirrors,
 				})
 			}
 			packageTools = append(packageTools, &indexToolRelease{
@@ -226,13 +246,18 @@ func IndexFromPlatformRelease(pr *cores.PlatformRelease) Index {
 					Architecture:          pr.Platform.Architecture,
 					Version:               pr.Version,
 					Deprecated:            pr.Platform.Deprecated,
+					DeprecationMessage:    pr.Platform.DeprecationMessage,
+					ReplacedBy:            pr.Platform.ReplacedBy,
+					EndOfLife:             pr.Platform.EndOfLifeDate(),
 					Category:              pr.Platform.Category,
 					URL:                   pr.Resource.URL,
 					ArchiveFileName:       pr.Resource.ArchiveFileName,
 					Checksum:              pr.Resource.Checksum,
+					Mirrors:               pr.Resource.Mirrors,
 					Size:                  json.Number(fmt.Sprintf("%d", pr.Resource.Size)),
 					Boards:                boards,
-					Help:                  indexHelp{Online: pr.Help.Online},
+					Help:                  indexHelp{Online: pr.Help.Online, Changelog: pr.Help.Changelog},
+					ReleaseNotes:          pr.ReleaseNotes,
 					ToolDependencies:      tools,
 					DiscoveryDependencies: discoveries,
 					MonitorDependencies:   monitors,
@@ -244,7 +269,7 @@ func IndexFromPlatformRelease(pr *cores.PlatformRelease) Index {
 	}
 }
 
-func (inPackage indexPackage) extractPackageIn(outPackages cores.Packages, trusted bool, isInstallJSON bool) {
+func (inPackage indexPackage) extractPackageIn(outPackages cores.Packages, trusted bool, isInstallJSON bool, trustedDownloadHosts []string) {
 	outPackage := outPackages.GetOrCreatePackage(inPackage.Name)
 	outPackage.Maintainer = inPackage.Maintainer
 	outPackage.WebsiteURL = inPackage.WebsiteURL
@@ -253,15 +278,15 @@ func (inPackage indexPackage) extractPackageIn(outPackages cores.Packages, trust
 	outPackage.Help = cores.PackageHelp{Online: inPackage.Help.Online}
 
 	for _, inTool := range inPackage.Tools {
-		inTool.extractToolIn(outPackage)
+		inTool.extractToolIn(outPackage, trustedDownloadHosts)
 	}
 
 	for _, inPlatform := range inPackage.Platforms {
-		inPlatform.extractPlatformIn(outPackage, trusted, isInstallJSON)
+		inPlatform.extractPlatformIn(outPackage, trusted, isInstallJSON, trustedDownloadHosts)
 	}
 }
 
-func (inPlatformRelease indexPlatformRelease) extractPlatformIn(outPackage *cores.Package, trusted bool, isInstallJSON bool) error {
+func (inPlatformRelease indexPlatformRelease) extractPlatformIn(outPackage *cores.Package, trusted bool, isInstallJSON bool, trustedDownloadHosts []string) error {
 	outPlatform := outPackage.GetOrCreatePlatform(inPlatformRelease.Architecture)
 	// FIXME: shall we use the Name and Category of the latest release? or maybe move Name and Category in PlatformRelease?
 	outPlatform.Name = inPlatformRelease.Name
@@ -277,6 +302,19 @@ func (inPlatformRelease indexPlatformRelease) extractPlatformIn(outPackage *core
 	if !outPlatform.Deprecated {
 		outPlatform.Deprecated = inPlatformRelease.Deprecated
 	}
+	// The same applies to the other deprecation info
+	if inPlatformRelease.DeprecationMessage != "" {
+		outPlatform.DeprecationMessage = inPlatformRelease.DeprecationMessage
+	}
+	if inPlatformRelease.ReplacedBy != "" {
+		outPlatform.ReplacedBy = inPlatformRelease.ReplacedBy
+	}
+	if inPlatformRelease.EndOfLife != "" {
+		// An invalid date is ignored, it must not prevent the use of the platform
+		if endOfLife, err := time.Parse(endOfLifeDateFormat, inPlatformRelease.EndOfLife); err == nil {
+			outPlatform.EndOfLife = endOfLife
+		}
+	}
 
 	size, err := inPlatformRelease.Size.Int64()
 	if err != nil {
@@ -285,13 +323,19 @@ func (inPlatformRelease indexPlatformRelease) extractPlatformIn(outPackage *core
 	outPlatformRelease := outPlatform.GetOrCreateRelease(inPlatformRelease.Version)
 	outPlatformRelease.IsTrusted = trusted
 	outPlatformRelease.Resource = &resources.DownloadResource{
-		ArchiveFileName: inPlatformRelease.ArchiveFileName,
-		Checksum:        inPlatformRelease.Checksum,
-		Size:            size,
-		URL:             inPlatformRelease.URL,
-		CachePath:       "packages",
+		ArchiveFileName:      inPlatformRelease.ArchiveFileName,
+		Checksum:             inPlatformRelease.Checksum,
+		Size:                 size,
+		URL:                  inPlatformRelease.URL,
+		Mirrors:              inPlatformRelease.Mirrors,
+		CachePath:            "packages",
+		TrustedRedirectHosts: trustedDownloadHosts,
+	}
+	outPlatformRelease.Help = cores.PlatformReleaseHelp{
+		Online:    inPlatformRelease.Help.Online,
+		Changelog: inPlatformRelease.Help.Changelog,
 	}
-	outPlatformRelease.Help = cores.PlatformReleaseHelp{Online: inPlatformRelease.Help.Online}
+	outPlatformRelease.ReleaseNotes = inPlatformRelease.ReleaseNotes
 	outPlatformRelease.BoardsManifest = inPlatformRelease.extractBoardsManifest()
 	outPlatformRelease.ToolDependencies = inPlatformRelease.extractToolDependencies()
 	outPlatformRelease.DiscoveryDependencies = inPlatformRelease.extractDiscoveryDependencies()
@@ -307,6 +351,13 @@ func (inPlatformRelease indexPlatformRelease) extractToolDependencies() cores.To
 			ToolVersion:  tool.Version,
 			ToolPackager: tool.Packager,
 		}
+		if tool.Version != nil && cores.IsToolVersionConstraint(tool.Version.String()) {
+			if constraint, err := cores.ParseToolVersionConstraint(tool.Version.String()); err != nil {
+				logrus.WithError(err).Warnf("Invalid version constraint for tool %s:%s", tool.Packager, tool.Name)
+			} else {
+				res[i].ToolVersionConstraint = constraint
+			}
+		}
 	}
 	return res
 }
@@ -347,26 +398,28 @@ func (inPlatformRelease indexPlatformRelease) extractBoardsManifest() []*cores.B
 	return boards
 }
 
-func (inToolRelease indexToolRelease) extractToolIn(outPackage *cores.Package) {
+func (inToolRelease indexToolRelease) extractToolIn(outPackage *cores.Package, trustedDownloadHosts []string) {
 	outTool := outPackage.GetOrCreateTool(inToolRelease.Name)
 
 	outToolRelease := outTool.GetOrCreateRelease(inToolRelease.Version)
-	outToolRelease.Flavors = inToolRelease.extractFlavours()
+	outToolRelease.Flavors = inToolRelease.extractFlavours(trustedDownloadHosts)
 }
 
 // extractFlavours extracts a map[OS]Flavor object from an indexToolRelease entry.
-func (inToolRelease indexToolRelease) extractFlavours() []*cores.Flavor {
+func (inToolRelease indexToolRelease) extractFlavours(trustedDownloadHosts []string) []*cores.Flavor {
 	ret := make([]*cores.Flavor, len(inToolRelease.Systems))
 	for i, flavour := range inToolRelease.Systems {
 		size, _ := flavour.Size.Int64()
 		ret[i] = &cores.Flavor{
 			OS: flavour.OS,
 			Resource: &resources.DownloadResource{
-				ArchiveFileName: flavour.ArchiveFileName,
-				Checksum:        flavour.Checksum,
-				Size:            size,
-				URL:             flavour.URL,
-				CachePath:       "packages",
+				ArchiveFileName:      flavour.ArchiveFileName,
+				Checksum:             flavour.Checksum,
+				Size:                 size,
+				URL:                  flavour.URL,
+				Mirrors:              flavour.Mirrors,
+				CachePath:            "packages",
+				TrustedRedirectHosts: trustedDownloadHosts,
 			},
 		}
 	}
@@ -374,8 +427,11 @@ func (inToolRelease indexToolRelease) extractFlavours() []*cores.Flavor {
 }
 
 // LoadIndex reads a package_index.json from a file and returns the corresponding Index structure.
-func LoadIndex(jsonIndexFile *paths.Path) (*Index, error) {
-	buff, err := jsonIndexFile.ReadFile()
+// The detached signature of the index is checked with the given settings. Files bigger than
+// maxSize bytes (or the DefaultMaxIndexSize if 0 or less) fail to load with an
+// arduino.LimitExceededError.
+func LoadIndex(jsonIndexFile *paths.Path, signatureVerification SignatureVerification, maxSize int64) (*Index, error) {
+	buff, err := readIndexFile(jsonIndexFile, maxSize)
 	if err != nil {
 		return nil, err
 	}
@@ -384,36 +440,63 @@ func LoadIndex(jsonIndexFile *paths.Path) (*Index, error) {
 	if err != nil {
 		return nil, err
 	}
-
-	jsonSignatureFile := jsonIndexFile.Parent().Join(jsonIndexFile.Base() + ".sig")
-	if jsonSignatureFile.Exist() {
-		trusted, _, err := security.VerifyArduinoDetachedSignature(jsonIndexFile, jsonSignatureFile)
-		if err != nil {
-			logrus.
-				WithField("index", jsonIndexFile).
-				WithField("signatureFile", jsonSignatureFile).
-				WithError(err).Warnf("Checking signature")
-		} else {
-			logrus.
-				WithField("index", jsonIndexFile).
-				WithField("signatureFile", jsonSignatureFile).
-				WithField("trusted", trusted).Infof("Checking signature")
-			index.IsTrusted = trusted
-		}
-	} else {
-		logrus.WithField("index", jsonIndexFile).Infof("Missing signature file")
-	}
+	index.checksum = indexChecksum(buff)
 
 	if jsonIndexFile.Base() == "installed.json" {
 		index.isInstalledJSON = true
+		// The installed.json is a copy of an index already verified
+		signatureVerification.RequireSignature = false
+	}
+
+	index.IsTrusted, err = verifyIndexSignature(jsonIndexFile, signatureVerification)
+	if err != nil {
+		return nil, err
 	}
 
 	return &index, nil
 }
 
+// Checksum returns the SHA-256 checksum of the file the index has been loaded
+// from, in the "SHA-256:<hex digest>" format.
+func (index *Index) Checksum() string {
+	return index.checksum
+}
+
+// indexChecksum returns the checksum of the content of an index file
+func indexChecksum(data []byte) string {
+	sum := sha256.Sum256(data)
+	return "SHA-256:" + hex.EncodeToString(sum[:])
+}
+
+// DefaultMaxIndexSize is the default maximum size of a package index file (256 MiB)
+const DefaultMaxIndexSize int64 = 256 * 1024 * 1024
+
+// readIndexFile reads the given file, failing as soon as the given maximum
+// size (or the DefaultMaxIndexSize if 0 or less) is exceeded, without loading
+// the whole file in memory.
+func readIndexFile(jsonIndexFile *paths.Path, limit int64) ([]byte, error) {
+	if limit <= 0 {
+		limit = DefaultMaxIndexSize
+	}
+	f, err := jsonIndexFile.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	buff, err := io.ReadAll(io.LimitReader(f, limit+1))
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(buff)) > limit {
+		return nil, &arduino.LimitExceededError{Message: tr("package index %s is too big", jsonIndexFile), Limit: limit}
+	}
+	return buff, nil
+}
+
 // LoadIndexNoSign reads a package_index.json from a file and returns the corresponding Index structure.
-func LoadIndexNoSign(jsonIndexFile *paths.Path) (*Index, error) {
-	buff, err := jsonIndexFile.ReadFile()
+// Files bigger than maxSize bytes (or the DefaultMaxIndexSize if 0 or less) fail to load.
+func LoadIndexNoSign(jsonIndexFile *paths.Path, maxSize int64) (*Index, error) {
+	buff, err := readIndexFile(jsonIndexFile, maxSize)
 	if err != nil {
 		return nil, err
 	}
@@ -422,6 +505,7 @@ func LoadIndexNoSign(jsonIndexFile *paths.Path) (*Index, error) {
 	if err != nil {
 		return nil, err
 	}
+	index.checksum = indexChecksum(buff)
 
 	index.IsTrusted = true
 
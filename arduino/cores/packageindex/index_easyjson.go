@@ -48,6 +48,29 @@ func easyjsonE2a549a6DecodeGithubComArduinoArduinoCliArduinoCoresPackageindex(in
 			out.Size = in.JsonNumber()
 		case "checksum":
 			out.Checksum = string(in.String())
+		case "mirrors":
+			if in.IsNull() {
+				in.Skip()
+				out.Mirrors = nil
+			} else {
+				in.Delim('[')
+				if out.Mirrors == nil {
+					if !in.IsDelim(']') {
+						out.Mirrors = make([]string, 0, 4)
+					} else {
+						out.Mirrors = []string{}
+					}
+				} else {
+					out.Mirrors = (out.Mirrors)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v41 string
+					v41 = string(in.String())
+					out.Mirrors = append(out.Mirrors, v41)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
 		default:
 			switch strings.ToLower(key) {
 			case "host":
@@ -60,6 +83,29 @@ func easyjsonE2a549a6DecodeGithubComArduinoArduinoCliArduinoCoresPackageindex(in
 				out.Size = in.JsonNumber()
 			case "checksum":
 				out.Checksum = string(in.String())
+			case "mirrors":
+				if in.IsNull() {
+					in.Skip()
+					out.Mirrors = nil
+				} else {
+					in.Delim('[')
+					if out.Mirrors == nil {
+						if !in.IsDelim(']') {
+							out.Mirrors = make([]string, 0, 4)
+						} else {
+							out.Mirrors = []string{}
+						}
+					} else {
+						out.Mirrors = (out.Mirrors)[:0]
+					}
+					for !in.IsDelim(']') {
+						var v42 string
+						v42 = string(in.String())
+						out.Mirrors = append(out.Mirrors, v42)
+						in.WantComma()
+					}
+					in.Delim(']')
+				}
 			default:
 				in.SkipRecursive()
 			}
@@ -100,6 +146,20 @@ func easyjsonE2a549a6EncodeGithubComArduinoArduinoCliArduinoCoresPackageindex(ou
 		out.RawString(prefix)
 		out.String(string(in.Checksum))
 	}
+	if len(in.Mirrors) != 0 {
+		const prefix string = ",\"mirrors\":"
+		out.RawString(prefix)
+		{
+			out.RawByte('[')
+			for v43, v44 := range in.Mirrors {
+				if v43 > 0 {
+					out.RawByte(',')
+				}
+				out.String(string(v44))
+			}
+			out.RawByte(']')
+		}
+	}
 	out.RawByte('}')
 }
 
@@ -442,6 +502,12 @@ func easyjsonE2a549a6DecodeGithubComArduinoArduinoCliArduinoCoresPackageindex3(i
 			}
 		case "deprecated":
 			out.Deprecated = bool(in.Bool())
+		case "deprecationMessage":
+			out.DeprecationMessage = string(in.String())
+		case "replacedBy":
+			out.ReplacedBy = string(in.String())
+		case "endOfLife":
+			out.EndOfLife = string(in.String())
 		case "category":
 			out.Category = string(in.String())
 		case "url":
@@ -450,6 +516,29 @@ func easyjsonE2a549a6DecodeGithubComArduinoArduinoCliArduinoCoresPackageindex3(i
 			out.ArchiveFileName = string(in.String())
 		case "checksum":
 			out.Checksum = string(in.String())
+		case "mirrors":
+			if in.IsNull() {
+				in.Skip()
+				out.Mirrors = nil
+			} else {
+				in.Delim('[')
+				if out.Mirrors == nil {
+					if !in.IsDelim(']') {
+						out.Mirrors = make([]string, 0, 4)
+					} else {
+						out.Mirrors = []string{}
+					}
+				} else {
+					out.Mirrors = (out.Mirrors)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v45 string
+					v45 = string(in.String())
+					out.Mirrors = append(out.Mirrors, v45)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
 		case "size":
 			out.Size = in.JsonNumber()
 		case "boards":
@@ -477,6 +566,8 @@ func easyjsonE2a549a6DecodeGithubComArduinoArduinoCliArduinoCoresPackageindex3(i
 			}
 		case "help":
 			(out.Help).UnmarshalEasyJSON(in)
+		case "releaseNotes":
+			out.ReleaseNotes = string(in.String())
 		case "toolsDependencies":
 			if in.IsNull() {
 				in.Skip()
@@ -566,6 +657,12 @@ func easyjsonE2a549a6DecodeGithubComArduinoArduinoCliArduinoCoresPackageindex3(i
 				}
 			case "deprecated":
 				out.Deprecated = bool(in.Bool())
+			case "deprecationmessage":
+				out.DeprecationMessage = string(in.String())
+			case "replacedby":
+				out.ReplacedBy = string(in.String())
+			case "endoflife":
+				out.EndOfLife = string(in.String())
 			case "category":
 				out.Category = string(in.String())
 			case "url":
@@ -574,6 +671,29 @@ func easyjsonE2a549a6DecodeGithubComArduinoArduinoCliArduinoCoresPackageindex3(i
 				out.ArchiveFileName = string(in.String())
 			case "checksum":
 				out.Checksum = string(in.String())
+			case "mirrors":
+				if in.IsNull() {
+					in.Skip()
+					out.Mirrors = nil
+				} else {
+					in.Delim('[')
+					if out.Mirrors == nil {
+						if !in.IsDelim(']') {
+							out.Mirrors = make([]string, 0, 4)
+						} else {
+							out.Mirrors = []string{}
+						}
+					} else {
+						out.Mirrors = (out.Mirrors)[:0]
+					}
+					for !in.IsDelim(']') {
+						var v46 string
+						v46 = string(in.String())
+						out.Mirrors = append(out.Mirrors, v46)
+						in.WantComma()
+					}
+					in.Delim(']')
+				}
 			case "size":
 				out.Size = in.JsonNumber()
 			case "boards":
@@ -601,6 +721,8 @@ func easyjsonE2a549a6DecodeGithubComArduinoArduinoCliArduinoCoresPackageindex3(i
 				}
 			case "help":
 				(out.Help).UnmarshalEasyJSON(in)
+			case "releasenotes":
+				out.ReleaseNotes = string(in.String())
 			case "toolsdependencies":
 				if in.IsNull() {
 					in.Skip()
@@ -709,6 +831,21 @@ func easyjsonE2a549a6EncodeGithubComArduinoArduinoCliArduinoCoresPackageindex3(o
 		out.RawString(prefix)
 		out.Bool(bool(in.Deprecated))
 	}
+	if in.DeprecationMessage != "" {
+		const prefix string = ",\"deprecationMessage\":"
+		out.RawString(prefix)
+		out.String(string(in.DeprecationMessage))
+	}
+	if in.ReplacedBy != "" {
+		const prefix string = ",\"replacedBy\":"
+		out.RawString(prefix)
+		out.String(string(in.ReplacedBy))
+	}
+	if in.EndOfLife != "" {
+		const prefix string = ",\"endOfLife\":"
+		out.RawString(prefix)
+		out.String(string(in.EndOfLife))
+	}
 	{
 		const prefix string = ",\"category\":"
 		out.RawString(prefix)
@@ -729,6 +866,20 @@ func easyjsonE2a549a6EncodeGithubComArduinoArduinoCliArduinoCoresPackageindex3(o
 		out.RawString(prefix)
 		out.String(string(in.Checksum))
 	}
+	if len(in.Mirrors) != 0 {
+		const prefix string = ",\"mirrors\":"
+		out.RawString(prefix)
+		{
+			out.RawByte('[')
+			for v47, v48 := range in.Mirrors {
+				if v47 > 0 {
+					out.RawByte(',')
+				}
+				out.String(string(v48))
+			}
+			out.RawByte(']')
+		}
+	}
 	{
 		const prefix string = ",\"size\":"
 		out.RawString(prefix)
@@ -755,6 +906,11 @@ func easyjsonE2a549a6EncodeGithubComArduinoArduinoCliArduinoCoresPackageindex3(o
 		out.RawString(prefix)
 		(in.Help).MarshalEasyJSON(out)
 	}
+	if in.ReleaseNotes != "" {
+		const prefix string = ",\"releaseNotes\":"
+		out.RawString(prefix)
+		out.String(string(in.ReleaseNotes))
+	}
 	{
 		const prefix string = ",\"toolsDependencies\":"
 		out.RawString(prefix)
@@ -1210,10 +1366,14 @@ func easyjsonE2a549a6DecodeGithubComArduinoArduinoCliArduinoCoresPackageindex6(i
 		switch key {
 		case "online":
 			out.Online = string(in.String())
+		case "changelog":
+			out.Changelog = string(in.String())
 		default:
 			switch strings.ToLower(key) {
 			case "online":
 				out.Online = string(in.String())
+			case "changelog":
+				out.Changelog = string(in.String())
 			default:
 				in.SkipRecursive()
 			}
@@ -1235,6 +1395,16 @@ func easyjsonE2a549a6EncodeGithubComArduinoArduinoCliArduinoCoresPackageindex6(o
 		out.RawString(prefix[1:])
 		out.String(string(in.Online))
 	}
+	if in.Changelog != "" {
+		const prefix string = ",\"changelog\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.String(string(in.Changelog))
+	}
 	out.RawByte('}')
 }
 
@@ -1593,6 +1763,29 @@ func easyjsonE2a549a6DecodeGithubComArduinoArduinoCliArduinoCoresPackageindex10(
 				}
 				in.Delim(']')
 			}
+		case "trustedDownloadHosts":
+			if in.IsNull() {
+				in.Skip()
+				out.TrustedDownloadHosts = nil
+			} else {
+				in.Delim('[')
+				if out.TrustedDownloadHosts == nil {
+					if !in.IsDelim(']') {
+						out.TrustedDownloadHosts = make([]string, 0, 4)
+					} else {
+						out.TrustedDownloadHosts = []string{}
+					}
+				} else {
+					out.TrustedDownloadHosts = (out.TrustedDownloadHosts)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v34 string
+					v34 = string(in.String())
+					out.TrustedDownloadHosts = append(out.TrustedDownloadHosts, v34)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
 		case "IsTrusted":
 			out.IsTrusted = bool(in.Bool())
 		default:
@@ -1613,17 +1806,40 @@ func easyjsonE2a549a6DecodeGithubComArduinoArduinoCliArduinoCoresPackageindex10(
 						out.Packages = (out.Packages)[:0]
 					}
 					for !in.IsDelim(']') {
-						var v34 *indexPackage
+						var v35 *indexPackage
 						if in.IsNull() {
 							in.Skip()
-							v34 = nil
+							v35 = nil
 						} else {
-							if v34 == nil {
-								v34 = new(indexPackage)
+							if v35 == nil {
+								v35 = new(indexPackage)
 							}
-							(*v34).UnmarshalEasyJSON(in)
+							(*v35).UnmarshalEasyJSON(in)
+						}
+						out.Packages = append(out.Packages, v35)
+						in.WantComma()
+					}
+					in.Delim(']')
+				}
+			case "trusteddownloadhosts":
+				if in.IsNull() {
+					in.Skip()
+					out.TrustedDownloadHosts = nil
+				} else {
+					in.Delim('[')
+					if out.TrustedDownloadHosts == nil {
+						if !in.IsDelim(']') {
+							out.TrustedDownloadHosts = make([]string, 0, 4)
+						} else {
+							out.TrustedDownloadHosts = []string{}
 						}
-						out.Packages = append(out.Packages, v34)
+					} else {
+						out.TrustedDownloadHosts = (out.TrustedDownloadHosts)[:0]
+					}
+					for !in.IsDelim(']') {
+						var v36 string
+						v36 = string(in.String())
+						out.TrustedDownloadHosts = append(out.TrustedDownloadHosts, v36)
 						in.WantComma()
 					}
 					in.Delim(']')
@@ -1652,15 +1868,29 @@ func easyjsonE2a549a6EncodeGithubComArduinoArduinoCliArduinoCoresPackageindex10(
 			out.RawString("null")
 		} else {
 			out.RawByte('[')
-			for v35, v36 := range in.Packages {
-				if v35 > 0 {
+			for v37, v38 := range in.Packages {
+				if v37 > 0 {
 					out.RawByte(',')
 				}
-				if v36 == nil {
+				if v38 == nil {
 					out.RawString("null")
 				} else {
-					(*v36).MarshalEasyJSON(out)
+					(*v38).MarshalEasyJSON(out)
+				}
+			}
+			out.RawByte(']')
+		}
+	}
+	if len(in.TrustedDownloadHosts) != 0 {
+		const prefix string = ",\"trustedDownloadHosts\":"
+		out.RawString(prefix)
+		{
+			out.RawByte('[')
+			for v39, v40 := range in.TrustedDownloadHosts {
+				if v39 > 0 {
+					out.RawByte(',')
 				}
+				out.String(string(v40))
 			}
 			out.RawByte(']')
 		}
@@ -16,12 +16,21 @@
 package packageindex
 
 import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
 	"testing"
 
+	"github.com/arduino/arduino-cli/arduino"
 	"github.com/arduino/arduino-cli/arduino/cores"
 	"github.com/arduino/arduino-cli/arduino/resources"
+	rpc "github.com/arduino/arduino-cli/rpc/cc/arduino/cli/commands/v1"
 	"github.com/arduino/go-paths-helper"
+	easyjson "github.com/mailru/easyjson"
 	"github.com/stretchr/testify/require"
+	"go.bug.st/downloader/v2"
 	semver "go.bug.st/relaxed-semver"
 )
 
@@ -34,11 +43,27 @@ func TestIndexParsing(t *testing.T) {
 		if indexFile.Ext() != ".json" {
 			continue
 		}
-		_, err := LoadIndex(indexFile)
+		_, err := LoadIndex(indexFile, SignatureVerification{}, 0)
 		require.NoError(t, err)
 	}
 }
 
+func TestIndexMaxSize(t *testing.T) {
+	indexFile := paths.New("testdata", "package_esp8266com_index.json")
+	size, err := indexFile.Stat()
+	require.NoError(t, err)
+
+	_, err = LoadIndex(indexFile, SignatureVerification{}, size.Size())
+	require.NoError(t, err)
+
+	_, err = LoadIndex(indexFile, SignatureVerification{}, size.Size()-1)
+	var limitErr *arduino.LimitExceededError
+	require.True(t, errors.As(err, &limitErr))
+	require.Equal(t, size.Size()-1, limitErr.Limit)
+	_, err = LoadIndexNoSign(indexFile, size.Size()-1)
+	require.True(t, errors.As(err, &limitErr))
+}
+
 func TestIndexFromPlatformRelease(t *testing.T) {
 	pr := &cores.PlatformRelease{
 		Resource: &resources.DownloadResource{
@@ -576,3 +601,156 @@ func TestIndexFromPlatformRelease(t *testing.T) {
 		}
 	}
 }
+
+func TestIndexTrustedDownloadHosts(t *testing.T) {
+	var serverURL *url.URL
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/trusted":
+			http.Redirect(w, r, "http://localhost:"+serverURL.Port()+"/archive.zip", http.StatusFound)
+		case "/untrusted":
+			http.Redirect(w, r, "http://127.0.0.2/archive.zip", http.StatusFound)
+		case "/archive.zip":
+			w.Write([]byte("archive"))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+	serverURL, err := url.Parse(server.URL)
+	require.NoError(t, err)
+	require.Equal(t, "127.0.0.1", serverURL.Hostname())
+
+	tmp := paths.New(t.TempDir())
+	indexFile := tmp.Join("package_test_index.json")
+	platform := func(arch, path string) string {
+		return fmt.Sprintf(`{"name": "Test", "architecture": "%s", "version": "1.0.0", "url": "%s%s",
+			"archiveFileName": "%s.zip", "checksum": "SHA-256:0eb3e36bfb24dcd9bb1d1bece1531216b59539a8fde17ee80224af0653c92aa3", "size": "7", "boards": [], "toolsDependencies": []}`,
+			arch, server.URL, path, arch)
+	}
+	require.NoError(t, indexFile.WriteFile([]byte(`{
+		"trustedDownloadHosts": ["localhost"],
+		"packages": [{"name": "test", "platforms": [`+platform("trusted", "/trusted")+`, `+platform("untrusted", "/untrusted")+`], "tools": []}]
+	}`)))
+	index, err := LoadIndexNoSign(indexFile, 0)
+	require.NoError(t, err)
+	require.Equal(t, []string{"localhost"}, index.TrustedDownloadHosts)
+	packages := cores.NewPackages()
+	index.MergeIntoPackages(packages)
+
+	download := func(arch string) error {
+		release := packages["test"].Platforms[arch].Releases["1.0.0"]
+		require.Equal(t, []string{"localhost"}, release.Resource.TrustedRedirectHosts)
+		return release.Resource.Download(tmp, &downloader.Config{}, "", func(*rpc.DownloadProgress) {}, "", resources.ArchiveSignatureVerification{})
+	}
+	require.NoError(t, download("trusted"))
+	require.True(t, tmp.Join("packages", "trusted.zip").Exist())
+	err = download("untrusted")
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "untrusted host 127.0.0.2")
+	require.False(t, tmp.Join("packages", "untrusted.zip").Exist())
+}
+
+func TestIndexToolVersionConstraint(t *testing.T) {
+	indexFile := paths.New(t.TempDir()).Join("package_test_index.json")
+	tool := func(version string) string {
+		return fmt.Sprintf(`{"name": "bossac", "version": "%s", "systems": [{"host": "x86_64-linux-gnu", "url": "http://example.com/bossac-%[1]s.tar.gz",
+			"archiveFileName": "bossac-%[1]s.tar.gz", "checksum": "SHA-256:0000", "size": "7"}]}`, version)
+	}
+	platform := func(arch, toolVersion string) string {
+		return fmt.Sprintf(`{"name": "Test", "architecture": "%s", "version": "1.0.0", "url": "http://example.com/%[1]s.zip",
+			"archiveFileName": "%[1]s.zip", "checksum": "SHA-256:0000", "size": "7", "boards": [],
+			"toolsDependencies": [{"packager": "test", "name": "bossac", "version": "%s"}]}`, arch, toolVersion)
+	}
+	require.NoError(t, indexFile.WriteFile([]byte(`{"packages": [{"name": "test",
+		"platforms": [`+platform("exact", "1.7.0")+`, `+platform("range", ">=1.7.0 <2.0.0")+`, `+platform("invalid", "^a.b")+`],
+		"tools": [`+tool("1.6.1")+`, `+tool("1.7.0")+`, `+tool("1.9.0")+`, `+tool("2.0.0")+`]
+	}]}`)))
+	index, err := LoadIndexNoSign(indexFile, 0)
+	require.NoError(t, err)
+	packages := cores.NewPackages()
+	index.MergeIntoPackages(packages)
+	platforms := packages["test"].Platforms
+
+	exact := platforms["exact"].Releases["1.0.0"]
+	require.Nil(t, exact.ToolDependencies[0].ToolVersionConstraint)
+	deps, err := packages.GetPlatformReleaseToolDependencies(exact)
+	require.NoError(t, err)
+	require.Equal(t, "test:bossac@1.7.0", deps[0].String())
+
+	// The newest release in the range is selected
+	ranged := platforms["range"].Releases["1.0.0"]
+	require.NotNil(t, ranged.ToolDependencies[0].ToolVersionConstraint)
+	deps, err = packages.GetPlatformReleaseToolDependencies(ranged)
+	require.NoError(t, err)
+	require.Equal(t, "test:bossac@1.9.0", deps[0].String())
+	// Only the selected release is required
+	require.True(t, ranged.RequiresToolRelease(packages["test"].Tools["bossac"].Releases["1.9.0"]))
+	require.False(t, ranged.RequiresToolRelease(packages["test"].Tools["bossac"].Releases["1.7.0"]))
+	require.False(t, ranged.RequiresToolRelease(packages["test"].Tools["bossac"].Releases["2.0.0"]))
+
+	// An invalid range is matched as an exact version
+	invalid := platforms["invalid"].Releases["1.0.0"]
+	require.Nil(t, invalid.ToolDependencies[0].ToolVersionConstraint)
+	_, err = packages.GetPlatformReleaseToolDependencies(invalid)
+	require.Error(t, err)
+}
+
+func TestIndexPlatformDeprecation(t *testing.T) {
+	indexFile := paths.New(t.TempDir()).Join("package_test_index.json")
+	platform := func(arch, deprecation string) string {
+		return fmt.Sprintf(`{"name": "Test", "architecture": "%s", "version": "1.0.0", "url": "http://example.com/%[1]s.zip",
+			"archiveFileName": "%[1]s.zip", "checksum": "SHA-256:0000", "size": "7", "boards": [], "toolsDependencies": []%s}`, arch, deprecation)
+	}
+	require.NoError(t, indexFile.WriteFile([]byte(`{"packages": [{"name": "test", "platforms": [`+
+		platform("old", `, "deprecated": true, "deprecationMessage": "see the migration guide", "replacedBy": "test:new", "endOfLife": "2024-06-30"`)+`, `+
+		platform("invalid", `, "endOfLife": "next year"`)+`, `+
+		platform("new", "")+
+		`], "tools": []}]}`)))
+	index, err := LoadIndexNoSign(indexFile, 0)
+	require.NoError(t, err)
+	packages := cores.NewPackages()
+	index.MergeIntoPackages(packages)
+	platforms := packages["test"].Platforms
+
+	old := platforms["old"]
+	require.True(t, old.Deprecated)
+	require.Equal(t, "see the migration guide", old.DeprecationMessage)
+	require.Equal(t, "test:new", old.ReplacedBy)
+	require.Equal(t, "2024-06-30", old.EndOfLife.Format("2006-01-02"))
+	require.True(t, old.EndOfLifeReached())
+
+	// An invalid date is ignored
+	require.True(t, platforms["invalid"].EndOfLife.IsZero())
+	require.False(t, platforms["invalid"].EndOfLifeReached())
+
+	require.False(t, platforms["new"].Deprecated)
+	require.Empty(t, platforms["new"].ReplacedBy)
+	require.True(t, platforms["new"].EndOfLife.IsZero())
+
+	// The deprecation info is kept in the installed.json
+	installed := IndexFromPlatformRelease(old.Releases["1.0.0"]).Packages[0].Platforms[0]
+	require.True(t, installed.Deprecated)
+	require.Equal(t, "see the migration guide", installed.DeprecationMessage)
+	require.Equal(t, "test:new", installed.ReplacedBy)
+	require.Equal(t, "2024-06-30", installed.EndOfLife)
+	data, err := easyjson.Marshal(installed)
+	require.NoError(t, err)
+	require.Contains(t, string(data), `"deprecationMessage":"see the migration guide","replacedBy":"test:new","endOfLife":"2024-06-30"`)
+
+	// The deprecation and the end of life are reported separately in the gRPC messages
+	oldRelease := old.Releases["1.0.0"]
+	oldRelease.InstallDir = paths.New(t.TempDir())
+	ref := oldRelease.ToRPCPlatformReference()
+	require.True(t, ref.Deprecated)
+	require.Equal(t, "2024-06-30", ref.EndOfLife)
+	require.True(t, ref.EndOfLifeReached)
+	require.Equal(t, "test:new", ref.ReplacedBy)
+	invalidRelease := platforms["invalid"].Releases["1.0.0"]
+	invalidRelease.InstallDir = paths.New(t.TempDir())
+	ref = invalidRelease.ToRPCPlatformReference()
+	require.False(t, ref.Deprecated)
+	require.Empty(t, ref.EndOfLife)
+	require.False(t, ref.EndOfLifeReached)
+	require.Empty(t, ref.ReplacedBy)
+}
@@ -0,0 +1,142 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2023 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package packageindex
+
+import (
+	"bytes"
+	"errors"
+
+	"github.com/ProtonMail/go-crypto/openpgp"
+	pgperrors "github.com/ProtonMail/go-crypto/openpgp/errors"
+	"github.com/arduino/arduino-cli/arduino"
+	"github.com/arduino/arduino-cli/arduino/security"
+	"github.com/arduino/go-paths-helper"
+	"github.com/sirupsen/logrus"
+)
+
+// SignatureVerification contains the settings used to verify the detached
+// signatures (.sig files placed next to the index files) of the package indexes.
+// The zero value trusts only the indexes signed with the bundled Arduino key and
+// loads the other ones as untrusted.
+type SignatureVerification struct {
+	// TrustedKeys is the path to a keyring, or to a directory of keyrings, with
+	// the public keys trusted to sign the package indexes, in addition to the
+	// bundled Arduino key.
+	TrustedKeys *paths.Path
+	// RequireSignature makes the indexes not signed with the Arduino key, or
+	// with one of the TrustedKeys, fail to load instead of being loaded as
+	// untrusted. The installed.json files of the installed platforms, copied
+	// from already loaded indexes, are not affected.
+	RequireSignature bool
+	// SkipCheck disables the verification of the signatures: all the indexes
+	// are loaded as untrusted, even if their signature is invalid.
+	SkipCheck bool
+}
+
+// trustedKeyrings returns the content of the keyrings found in the TrustedKeys
+// path: the path itself if it's a file, or the files contained in it if it's a
+// directory. The files that can't be read, or that are not keyrings, are
+// skipped with a warning.
+func (s SignatureVerification) trustedKeyrings() ([][]byte, error) {
+	if s.TrustedKeys == nil {
+		return nil, nil
+	}
+	if s.TrustedKeys.NotExist() {
+		return nil, errors.New(tr("keyring %s not found", s.TrustedKeys))
+	}
+	files := paths.PathList{s.TrustedKeys}
+	if s.TrustedKeys.IsDir() {
+		var err error
+		if files, err = s.TrustedKeys.ReadDir(); err != nil {
+			return nil, err
+		}
+		files.FilterOutDirs()
+		files.Sort()
+	}
+	keyrings := [][]byte{}
+	for _, file := range files {
+		data, err := file.ReadFile()
+		if err != nil {
+			logrus.WithField("keyring", file).WithError(err).Warn("Skipping unreadable keyring")
+			continue
+		}
+		if _, err := openpgp.ReadKeyRing(bytes.NewReader(data)); err != nil {
+			logrus.WithField("keyring", file).WithError(err).Warn("Skipping invalid keyring")
+			continue
+		}
+		keyrings = append(keyrings, data)
+	}
+	return keyrings, nil
+}
+
+// verifyIndexSignature checks the detached signature of the given index file
+// against the bundled Arduino key and the configured trusted keys, returning
+// true if the index is signed by one of them. An index without a signature, or
+// signed by an unknown key, is not trusted; an index whose signature doesn't
+// match the content (i.e. it has been tampered with) is an error.
+func verifyIndexSignature(jsonIndexFile *paths.Path, settings SignatureVerification) (bool, error) {
+	if settings.SkipCheck {
+		logrus.WithField("index", jsonIndexFile).Infof("Skipping signature check")
+		return false, nil
+	}
+
+	jsonSignatureFile := jsonIndexFile.Parent().Join(jsonIndexFile.Base() + ".sig")
+	if !jsonSignatureFile.Exist() {
+		if settings.RequireSignature {
+			return false, &arduino.SignatureVerificationFailedError{File: jsonIndexFile.String(), Cause: errors.New(tr("missing signature"))}
+		}
+		logrus.WithField("index", jsonIndexFile).Infof("Missing signature file")
+		return false, nil
+	}
+
+	keyrings, err := settings.trustedKeyrings()
+	if err != nil {
+		return false, &arduino.SignatureVerificationFailedError{File: jsonIndexFile.String(), Cause: err}
+	}
+	verifiers := []func() (bool, *openpgp.Entity, error){
+		func() (bool, *openpgp.Entity, error) {
+			return security.VerifyArduinoDetachedSignature(jsonIndexFile, jsonSignatureFile)
+		},
+	}
+	for _, keyring := range keyrings {
+		keyring := keyring
+		verifiers = append(verifiers, func() (bool, *openpgp.Entity, error) {
+			return security.VerifySignature(jsonIndexFile, jsonSignatureFile, bytes.NewReader(keyring))
+		})
+	}
+
+	for _, verify := range verifiers {
+		trusted, _, err := verify()
+		if trusted {
+			logrus.
+				WithField("index", jsonIndexFile).
+				WithField("signatureFile", jsonSignatureFile).
+				WithField("trusted", trusted).Infof("Checking signature")
+			return true, nil
+		}
+		if err != nil && !errors.Is(err, pgperrors.ErrUnknownIssuer) {
+			return false, &arduino.SignatureVerificationFailedError{File: jsonIndexFile.String(), Cause: err}
+		}
+	}
+	if settings.RequireSignature {
+		return false, &arduino.SignatureVerificationFailedError{File: jsonIndexFile.String(), Cause: errors.New(tr("signed by an unknown key"))}
+	}
+	logrus.
+		WithField("index", jsonIndexFile).
+		WithField("signatureFile", jsonSignatureFile).
+		Warnf("Index signed by an unknown key")
+	return false, nil
+}
@@ -0,0 +1,93 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2023 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package packageindex
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/ProtonMail/go-crypto/openpgp"
+	"github.com/arduino/arduino-cli/arduino"
+	"github.com/arduino/go-paths-helper"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIndexSignatureVerification(t *testing.T) {
+	// Create a key and put its public part in a keyrings directory, together
+	// with a file that is not a keyring
+	entity, err := openpgp.NewEntity("Test", "", "test@example.com", nil)
+	require.NoError(t, err)
+	tmp := paths.New(t.TempDir())
+	keysDir := tmp.Join("keys")
+	require.NoError(t, keysDir.MkdirAll())
+	var publicKey bytes.Buffer
+	require.NoError(t, entity.Serialize(&publicKey))
+	require.NoError(t, keysDir.Join("test.gpg").WriteFile(publicKey.Bytes()))
+	require.NoError(t, keysDir.Join("README.txt").WriteFile([]byte("not a keyring")))
+
+	// Sign an index with the key
+	indexContent, err := paths.New("testdata", "package_arduboy_index.json").ReadFile()
+	require.NoError(t, err)
+	indexFile := tmp.Join("package_arduboy_index.json")
+	require.NoError(t, indexFile.WriteFile(indexContent))
+	var signature bytes.Buffer
+	require.NoError(t, openpgp.DetachSign(&signature, entity, bytes.NewReader(indexContent), nil))
+	require.NoError(t, tmp.Join("package_arduboy_index.json.sig").WriteFile(signature.Bytes()))
+
+	// The key is not trusted
+	index, err := LoadIndex(indexFile, SignatureVerification{}, 0)
+	require.NoError(t, err)
+	require.False(t, index.IsTrusted)
+
+	// The key is trusted, either through the directory or the keyring file
+	index, err = LoadIndex(indexFile, SignatureVerification{TrustedKeys: keysDir}, 0)
+	require.NoError(t, err)
+	require.True(t, index.IsTrusted)
+	index, err = LoadIndex(indexFile, SignatureVerification{TrustedKeys: keysDir.Join("test.gpg")}, 0)
+	require.NoError(t, err)
+	require.True(t, index.IsTrusted)
+	index, err = LoadIndex(indexFile, SignatureVerification{TrustedKeys: keysDir, RequireSignature: true}, 0)
+	require.NoError(t, err)
+	require.True(t, index.IsTrusted)
+
+	// A missing keyring is an error
+	_, err = LoadIndex(indexFile, SignatureVerification{TrustedKeys: tmp.Join("missing")}, 0)
+	require.Error(t, err)
+
+	// An index signed with an unknown key fails to load if signatures are required
+	_, err = LoadIndex(indexFile, SignatureVerification{TrustedKeys: keysDir.Join("README.txt"), RequireSignature: true}, 0)
+	var signatureErr *arduino.SignatureVerificationFailedError
+	require.True(t, errors.As(err, &signatureErr))
+
+	// A tampered index is detected
+	require.NoError(t, indexFile.WriteFile(append(indexContent, '\n')))
+	_, err = LoadIndex(indexFile, SignatureVerification{TrustedKeys: keysDir}, 0)
+	require.True(t, errors.As(err, &signatureErr))
+
+	// ...unless the check is skipped
+	index, err = LoadIndex(indexFile, SignatureVerification{TrustedKeys: keysDir, SkipCheck: true}, 0)
+	require.NoError(t, err)
+	require.False(t, index.IsTrusted)
+
+	// An index without signature is not trusted, or fails to load if signatures are required
+	require.NoError(t, tmp.Join("package_arduboy_index.json.sig").Remove())
+	index, err = LoadIndex(indexFile, SignatureVerification{TrustedKeys: keysDir}, 0)
+	require.NoError(t, err)
+	require.False(t, index.IsTrusted)
+	_, err = LoadIndex(indexFile, SignatureVerification{TrustedKeys: keysDir, RequireSignature: true}, 0)
+	require.True(t, errors.As(err, &signatureErr))
+}
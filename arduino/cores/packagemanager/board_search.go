@@ -0,0 +1,149 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2023 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package packagemanager
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/arduino/arduino-cli/arduino/cores"
+	"github.com/arduino/arduino-cli/arduino/utils"
+)
+
+// BoardSearchResult is a board found by SearchBoards
+type BoardSearchResult struct {
+	// Name is the name of the board
+	Name string
+	// Board is the board definition, nil if the platform providing the board
+	// is not installed (in this case only the name, from the package index,
+	// is known)
+	Board *cores.Board
+	// PlatformRelease is the installed release of the platform providing the
+	// board or, if not installed, the latest release
+	PlatformRelease *cores.PlatformRelease
+	// Score is the relevance of the board for the query, higher is better
+	Score int
+}
+
+// Relevance of a board whose name matches the whole query exactly, begins
+// with the query or contains it. They are added to the score of the single
+// search terms so they always prevail on it.
+const (
+	boardSearchExactScore     = 3000
+	boardSearchPrefixScore    = 2000
+	boardSearchSubstringScore = 1000
+)
+
+// SearchBoards returns the boards matching the given query, sorted from the
+// most to the least relevant. The boards of the installed platforms and the
+// ones listed in the package indexes for the platforms not installed are
+// searched. Each search term must be found in the board name, the FQBN, the
+// USB VID:PID (with or without the 0x prefix) or the platform id and name.
+// The boards whose name (or FQBN or VID:PID) is exactly the query come first,
+// followed by the ones whose name begins with the query and the ones whose
+// name contains it; the remaining ties are broken by how well each term
+// matches (a whole word is better than the beginning of a word, that is better
+// than a substring). With an empty query all the boards are returned sorted
+// by name. Hidden boards are returned only if includeHidden is true.
+func (pme *Explorer) SearchBoards(query string, includeHidden bool) []*BoardSearchResult {
+	matcher := utils.NewMatcher(query)
+	normalizedQuery := strings.Join(utils.SearchTermsFromQueryString(query), " ")
+
+	res := []*BoardSearchResult{}
+	addIfMatching := func(result *BoardSearchResult, fqbn string, usbIDs []string) {
+		platform := result.PlatformRelease.Platform
+		fields := append([]string{result.Name, fqbn, platform.String(), platform.Name}, usbIDs...)
+		if !matcher.Match(strings.Join(fields, " ")) {
+			return
+		}
+		if normalizedQuery != "" {
+			name := strings.Join(utils.SearchTermsFromQueryString(result.Name), " ")
+			exact := name == normalizedQuery || strings.EqualFold(fqbn, normalizedQuery)
+			for _, id := range usbIDs {
+				exact = exact || id == normalizedQuery
+			}
+			switch {
+			case exact:
+				result.Score = boardSearchExactScore
+			case strings.HasPrefix(name, normalizedQuery):
+				result.Score = boardSearchPrefixScore
+			case strings.Contains(name, normalizedQuery):
+				result.Score = boardSearchSubstringScore
+			}
+			result.Score += matcher.Score(strings.Join(fields, " "))
+		}
+		res = append(res, result)
+	}
+
+	for _, targetPackage := range pme.packages {
+		for _, platform := range targetPackage.Platforms {
+			if installed := pme.GetInstalledPlatformRelease(platform); installed != nil {
+				for _, board := range installed.Boards {
+					if board.IsHidden() && !includeHidden {
+						continue
+					}
+					result := &BoardSearchResult{Name: board.Name(), Board: board, PlatformRelease: installed}
+					addIfMatching(result, board.FQBN(), boardUsbIDs(board))
+				}
+			} else if latest := platform.GetLatestRelease(); latest != nil {
+				for _, manifest := range latest.BoardsManifest {
+					usbIDs := []string{}
+					for _, id := range manifest.ID {
+						if vid, pid, ok := strings.Cut(id.USB, ":"); ok {
+							usbIDs = append(usbIDs, formatUsbIDs(vid, pid)...)
+						}
+					}
+					result := &BoardSearchResult{Name: strings.TrimSpace(manifest.Name), PlatformRelease: latest}
+					addIfMatching(result, "", usbIDs)
+				}
+			}
+		}
+	}
+
+	sort.SliceStable(res, func(i, j int) bool {
+		if res[i].Score != res[j].Score {
+			return res[i].Score > res[j].Score
+		}
+		if res[i].Name != res[j].Name {
+			return res[i].Name < res[j].Name
+		}
+		return res[i].PlatformRelease.Platform.String() < res[j].PlatformRelease.Platform.String()
+	})
+	return res
+}
+
+// boardUsbIDs returns the USB VID:PID declared by the board, see formatUsbIDs
+func boardUsbIDs(board *cores.Board) []string {
+	res := []string{}
+	for _, idProps := range board.GetIdentificationProperties() {
+		vid, hasVid := idProps.GetOk("vid")
+		pid, hasPid := idProps.GetOk("pid")
+		if hasVid && hasPid {
+			res = append(res, formatUsbIDs(vid, pid)...)
+		}
+	}
+	return res
+}
+
+// formatUsbIDs returns the USB VID:PID in lowercase, with and without the 0x
+// prefix (for example "0x2341:0x0043" and "2341:0043"), to match both forms
+func formatUsbIDs(vid, pid string) []string {
+	vid, pid = strings.ToLower(vid), strings.ToLower(pid)
+	return []string{
+		vid + ":" + pid,
+		strings.TrimPrefix(vid, "0x") + ":" + strings.TrimPrefix(pid, "0x"),
+	}
+}
@@ -0,0 +1,80 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2023 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package packagemanager
+
+import (
+	"testing"
+
+	"github.com/arduino/arduino-cli/arduino/cores"
+	"github.com/arduino/go-paths-helper"
+	"github.com/stretchr/testify/require"
+	semver "go.bug.st/relaxed-semver"
+)
+
+func TestSearchBoards(t *testing.T) {
+	hardwareDir := paths.New(t.TempDir())
+	platformDir := hardwareDir.Join("test", "avr")
+	require.NoError(t, platformDir.MkdirAll())
+	require.NoError(t, platformDir.Join("platform.txt").WriteFile([]byte("name=Test AVR\nversion=1.0.0\n")))
+	require.NoError(t, platformDir.Join("boards.txt").WriteFile([]byte(""+
+		"uno.name=Uno\n"+
+		"uno.upload_port.0.vid=0x2341\n"+
+		"uno.upload_port.0.pid=0x0043\n"+
+		"unowifi.name=Uno WiFi\n"+
+		"unomini.name=Arduino Uno Mini\n"+
+		"nano.name=Nano\n"+
+		"nano.hide=true\n")))
+
+	pmb := NewBuilder(hardwareDir, hardwareDir, hardwareDir, hardwareDir, "test")
+	require.Empty(t, pmb.LoadHardwareFromDirectory(hardwareDir))
+	// A platform available in the package index but not installed
+	indexed := pmb.GetOrCreatePackage("indexed").GetOrCreatePlatform("avr").GetOrCreateRelease(semver.MustParse("1.0.0"))
+	indexed.BoardsManifest = []*cores.BoardManifest{
+		{Name: "Uno Clone", ID: []*cores.BoardManifestID{{USB: "0x1a86:0x7523"}}},
+	}
+	pme, release := pmb.Build().NewExplorer()
+	defer release()
+
+	search := func(query string, includeHidden bool) []string {
+		res := []string{}
+		for _, found := range pme.SearchBoards(query, includeHidden) {
+			id := found.PlatformRelease.Platform.String() + " " + found.Name
+			if found.Board != nil {
+				id = found.Board.FQBN()
+			}
+			res = append(res, id)
+		}
+		return res
+	}
+
+	// Exact name, then name prefix, then name substring
+	require.Equal(t, []string{"test:avr:uno", "indexed:avr Uno Clone", "test:avr:unowifi", "test:avr:unomini"}, search("uno", false))
+	require.Equal(t, []string{"test:avr:unowifi"}, search("uno wifi", false))
+	// FQBN
+	require.Equal(t, []string{"test:avr:unomini"}, search("test:avr:unomini", false))
+	// USB VID:PID, with or without the 0x prefix
+	require.Equal(t, []string{"test:avr:uno"}, search("2341:0043", false))
+	require.Equal(t, []string{"test:avr:uno"}, search("0x2341:0x0043", false))
+	require.Equal(t, []string{"indexed:avr Uno Clone"}, search("1a86:7523", false))
+	// Platform
+	require.Equal(t, []string{"test:avr:unomini", "test:avr:uno", "test:avr:unowifi"}, search("test", false))
+	// Hidden boards
+	require.Empty(t, search("nano", false))
+	require.Equal(t, []string{"test:avr:nano"}, search("nano", true))
+	// All the boards sorted by name
+	require.Equal(t, []string{"test:avr:unomini", "test:avr:uno", "indexed:avr Uno Clone", "test:avr:unowifi"}, search("", false))
+	require.Empty(t, search("mega", false))
+}
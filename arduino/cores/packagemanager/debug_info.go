@@ -0,0 +1,99 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2023 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package packagemanager
+
+import (
+	"errors"
+
+	"github.com/arduino/arduino-cli/arduino/cores"
+	properties "github.com/arduino/go-properties-orderedmap"
+)
+
+// ErrNoDebugConfiguration is returned by DebugInfo when the board doesn't
+// declare any debug property
+var ErrNoDebugConfiguration = errors.New("no debug configuration")
+
+// DebugConfig is the debug configuration declared by a board and its platform.
+// The properties that depend on the sketch being debugged, like
+// {build.path} and {build.project_name}, are left unexpanded.
+type DebugConfig struct {
+	FQBN string
+	// Executable is the file to be debugged (debug.executable)
+	Executable string
+	// Tool is the name of the tool recipe used to debug (debug.tool), and
+	// ToolPattern its tools.TOOL.debug.pattern command line
+	Tool        string
+	ToolPattern string
+	// Server is the GDB server to use (debug.server) with its path and its
+	// specific configuration (debug.server.SERVER.*)
+	Server              string
+	ServerPath          string
+	ServerConfiguration map[string]string
+	// Toolchain is the toolchain providing GDB (debug.toolchain) with its path,
+	// prefix and specific configuration (debug.toolchain.TOOLCHAIN.*)
+	Toolchain              string
+	ToolchainPath          string
+	ToolchainPrefix        string
+	ToolchainConfiguration map[string]string
+	// Properties are all the debug.* properties, without the debug. prefix
+	Properties *properties.Map
+}
+
+// DebugInfo returns the debug configuration of the board with the given FQBN,
+// resolved with the same rules used for the build. If the board and its
+// platform don't declare any debug property ErrNoDebugConfiguration is returned.
+func (pme *Explorer) DebugInfo(fqbnIn string) (*DebugConfig, error) {
+	fqbn, err := cores.ParseFQBN(fqbnIn)
+	if err != nil {
+		return nil, err
+	}
+	_, _, _, buildProperties, _, err := pme.ResolveFQBN(fqbn)
+	if err != nil {
+		return nil, err
+	}
+
+	debugProperties := properties.NewMap()
+	for _, k := range buildProperties.SubTree("debug").Keys() {
+		debugProperties.Set(k, buildProperties.ExpandPropsInString(buildProperties.Get("debug."+k)))
+	}
+	if !debugProperties.ContainsKey("executable") && !debugProperties.ContainsKey("tool") && !debugProperties.ContainsKey("server") {
+		return nil, &kindError{kind: ErrNoDebugConfiguration, message: tr("no debug configuration for %s", fqbn)}
+	}
+
+	tool := debugProperties.Get("tool")
+	toolPattern := ""
+	if tool != "" {
+		toolProperties := buildProperties.Clone()
+		toolProperties.Merge(buildProperties.SubTree("tools." + tool))
+		toolPattern = toolProperties.ExpandPropsInString(toolProperties.Get("debug.pattern"))
+	}
+	server := debugProperties.Get("server")
+	toolchain := debugProperties.Get("toolchain")
+	return &DebugConfig{
+		FQBN:                   fqbn.String(),
+		Executable:             debugProperties.Get("executable"),
+		Tool:                   tool,
+		ToolPattern:            toolPattern,
+		Server:                 server,
+		ServerPath:             debugProperties.Get("server." + server + ".path"),
+		ServerConfiguration:    debugProperties.SubTree("server." + server).AsMap(),
+		Toolchain:              toolchain,
+		ToolchainPath:          debugProperties.Get("toolchain.path"),
+		ToolchainPrefix:        debugProperties.Get("toolchain.prefix"),
+		ToolchainConfiguration: debugProperties.SubTree("toolchain." + toolchain).AsMap(),
+		Properties:             debugProperties,
+	}, nil
+}
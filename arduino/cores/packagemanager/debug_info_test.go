@@ -0,0 +1,102 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2023 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package packagemanager
+
+import (
+	"testing"
+
+	"github.com/arduino/arduino-cli/arduino/cores"
+	"github.com/arduino/go-paths-helper"
+	properties "github.com/arduino/go-properties-orderedmap"
+	"github.com/stretchr/testify/require"
+	semver "go.bug.st/relaxed-semver"
+)
+
+func TestDebugInfo(t *testing.T) {
+	pmb := NewBuilder(nil, nil, nil, nil, "test")
+	pack := pmb.GetOrCreatePackage("test")
+	openocd := pack.GetOrCreateTool("openocd").GetOrCreateRelease(semver.ParseRelaxed("0.11.0"))
+	openocd.InstallDir = paths.New(t.TempDir())
+
+	platform := pack.GetOrCreatePlatform("arm").GetOrCreateRelease(semver.MustParse("1.0.0"))
+	platform.InstallDir = paths.New(t.TempDir())
+	platform.ToolDependencies = cores.ToolDependencies{
+		{ToolPackager: "test", ToolName: "openocd", ToolVersion: openocd.Version},
+	}
+	platform.Properties = properties.NewFromHashmap(map[string]string{
+		"debug.executable":                  "{build.path}/{build.project_name}.elf",
+		"debug.toolchain":                   "gcc",
+		"debug.toolchain.path":              "/opt/gcc/bin/",
+		"debug.toolchain.prefix":            "arm-none-eabi-",
+		"debug.server":                      "openocd",
+		"debug.server.openocd.path":         "{runtime.tools.openocd.path}/bin/openocd",
+		"debug.server.openocd.script":       "{runtime.platform.path}/variants/{build.variant}/openocd.cfg",
+		"tools.openocd.path":                "{runtime.tools.openocd.path}",
+		"tools.openocd.debug.pattern":       `"{path}/bin/openocd" -f "{build.variant}.cfg"`,
+		"tools.openocd.upload.pattern":      `"{path}/bin/openocd" -c program`,
+		"debug.toolchain.gcc.extra_options": "-q",
+	})
+	platform.Menus = properties.NewMap()
+	board := platform.GetOrCreateBoard("debuggable")
+	board.Properties = properties.NewFromHashmap(map[string]string{
+		"name":          "Debuggable",
+		"build.core":    "arduino",
+		"build.variant": "myboard",
+		"debug.tool":    "openocd",
+	})
+	platform.GetOrCreateBoard("plain").Properties = properties.NewFromHashmap(map[string]string{
+		"name":       "Plain",
+		"build.core": "arduino",
+	})
+	other := pack.GetOrCreatePlatform("avr").GetOrCreateRelease(semver.MustParse("1.0.0"))
+	other.InstallDir = paths.New(t.TempDir())
+	other.Properties = properties.NewMap()
+	other.Menus = properties.NewMap()
+	other.GetOrCreateBoard("plain").Properties = properties.NewFromHashmap(map[string]string{
+		"name":       "Plain",
+		"build.core": "arduino",
+	})
+	pm := pmb.Build()
+	pme, release := pm.NewExplorer()
+	defer release()
+
+	config, err := pme.DebugInfo("test:arm:debuggable")
+	require.NoError(t, err)
+	require.Equal(t, "test:arm:debuggable", config.FQBN)
+	require.Equal(t, "{build.path}/{build.project_name}.elf", config.Executable)
+	require.Equal(t, "openocd", config.Tool)
+	require.Equal(t, `"`+openocd.InstallDir.String()+`/bin/openocd" -f "myboard.cfg"`, config.ToolPattern)
+	require.Equal(t, "openocd", config.Server)
+	require.Equal(t, openocd.InstallDir.String()+"/bin/openocd", config.ServerPath)
+	require.Equal(t, platform.InstallDir.String()+"/variants/myboard/openocd.cfg", config.ServerConfiguration["script"])
+	require.Equal(t, "gcc", config.Toolchain)
+	require.Equal(t, "/opt/gcc/bin/", config.ToolchainPath)
+	require.Equal(t, "arm-none-eabi-", config.ToolchainPrefix)
+	require.Equal(t, map[string]string{"extra_options": "-q"}, config.ToolchainConfiguration)
+
+	// The platform properties are inherited by all its boards
+	config, err = pme.DebugInfo("test:arm:plain")
+	require.NoError(t, err)
+	require.Empty(t, config.Tool)
+	require.Equal(t, "openocd", config.Server)
+
+	_, err = pme.DebugInfo("test:avr:plain")
+	require.ErrorIs(t, err, ErrNoDebugConfiguration)
+
+	_, err = pme.DebugInfo("test:avr:missing")
+	require.Error(t, err)
+	require.NotErrorIs(t, err, ErrNoDebugConfiguration)
+}
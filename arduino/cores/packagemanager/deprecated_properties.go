@@ -0,0 +1,84 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2023 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package packagemanager
+
+import (
+	"strings"
+
+	"github.com/arduino/go-properties-orderedmap"
+)
+
+// deprecatedBuildPropertiesKeys maps the deprecated build properties keys to their
+// replacement. A deprecated key is reported only if the replacement is not defined,
+// since platforms may keep both for backward compatibility with older IDEs.
+var deprecatedBuildPropertiesKeys = []struct{ deprecated, replacement string }{
+	{"recipe.preproc.includes", "recipe.preproc.macros"},
+	{"upload.tool", "upload.tool.default"},
+	{"bootloader.tool", "bootloader.tool.default"},
+	{"program.tool", "program.tool.default"},
+}
+
+// deprecatedBuildPropertiesPlaceholders maps the deprecated placeholders to their
+// replacement. A deprecated placeholder is reported when used in any build property.
+var deprecatedBuildPropertiesPlaceholders = []struct{ deprecated, replacement string }{
+	{"{ide_version}", "{runtime.ide.version}"},
+}
+
+// DeprecatedBuildProperty is a warning about the usage of a deprecated build property
+type DeprecatedBuildProperty struct {
+	// Key is the build property that defines or uses the deprecated property
+	Key string
+	// Deprecated is the deprecated property key or placeholder
+	Deprecated string
+	// Replacement is the property key or placeholder that should be used instead
+	Replacement string
+}
+
+func (d *DeprecatedBuildProperty) String() string {
+	if d.Key == d.Deprecated {
+		return tr("Property '%[1]s' is deprecated, use '%[2]s' instead", d.Deprecated, d.Replacement)
+	}
+	return tr("Property '%[1]s' uses deprecated '%[2]s', use '%[3]s' instead", d.Key, d.Deprecated, d.Replacement)
+}
+
+// FindDeprecatedBuildProperties checks the given (resolved) build properties for
+// the usage of deprecated keys or placeholders and returns a warning for each one
+// found, together with the recommended replacement.
+func FindDeprecatedBuildProperties(buildProperties *properties.Map) []*DeprecatedBuildProperty {
+	res := []*DeprecatedBuildProperty{}
+	for _, key := range deprecatedBuildPropertiesKeys {
+		if buildProperties.ContainsKey(key.deprecated) && !buildProperties.ContainsKey(key.replacement) {
+			res = append(res, &DeprecatedBuildProperty{
+				Key:         key.deprecated,
+				Deprecated:  key.deprecated,
+				Replacement: key.replacement,
+			})
+		}
+	}
+	for _, key := range buildProperties.Keys() {
+		value := buildProperties.Get(key)
+		for _, placeholder := range deprecatedBuildPropertiesPlaceholders {
+			if strings.Contains(value, placeholder.deprecated) {
+				res = append(res, &DeprecatedBuildProperty{
+					Key:         key,
+					Deprecated:  placeholder.deprecated,
+					Replacement: placeholder.replacement,
+				})
+			}
+		}
+	}
+	return res
+}
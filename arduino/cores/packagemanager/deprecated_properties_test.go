@@ -0,0 +1,66 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2023 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package packagemanager
+
+import (
+	"testing"
+
+	"github.com/arduino/arduino-cli/arduino/cores"
+	"github.com/arduino/go-paths-helper"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFindDeprecatedBuildProperties(t *testing.T) {
+	hardwareDir := paths.New(t.TempDir())
+	platformDir := hardwareDir.Join("test", "avr")
+	require.NoError(t, platformDir.MkdirAll())
+	require.NoError(t, platformDir.Join("boards.txt").WriteFile([]byte(
+		"uno.name=Test Uno\n"+
+			"uno.build.board=AVR_UNO\n"+
+			"uno.upload.tool=avrdude\n")))
+	require.NoError(t, platformDir.Join("platform.txt").WriteFile([]byte(
+		"name=Test AVR\n"+
+			"version=1.0.0\n"+
+			"pluggable_discovery.required=builtin:serial-discovery\n"+
+			"recipe.preproc.includes=gcc -E -DARDUINO={ide_version} {source_file}\n"+
+			"recipe.preproc.macros=gcc -E -DARDUINO={runtime.ide.version} {source_file}\n")))
+
+	pmb := NewBuilder(hardwareDir, hardwareDir, hardwareDir, hardwareDir, "test")
+	require.Empty(t, pmb.LoadHardwareFromDirectory(hardwareDir))
+	pm := pmb.Build()
+	pme, release := pm.NewExplorer()
+	defer release()
+
+	fqbn, err := cores.ParseFQBN("test:avr:uno")
+	require.NoError(t, err)
+	_, _, _, buildProperties, _, err := pme.ResolveFQBN(fqbn)
+	require.NoError(t, err)
+
+	deprecated := FindDeprecatedBuildProperties(buildProperties)
+	require.Len(t, deprecated, 2)
+	require.Equal(t, "upload.tool", deprecated[0].Deprecated)
+	require.Equal(t, "upload.tool.default", deprecated[0].Replacement)
+	require.Equal(t, "Property 'upload.tool' is deprecated, use 'upload.tool.default' instead", deprecated[0].String())
+	require.Equal(t, "recipe.preproc.includes", deprecated[1].Key)
+	require.Equal(t, "{ide_version}", deprecated[1].Deprecated)
+	require.Equal(t, "{runtime.ide.version}", deprecated[1].Replacement)
+	require.Equal(t, "Property 'recipe.preproc.includes' uses deprecated '{ide_version}', use '{runtime.ide.version}' instead", deprecated[1].String())
+
+	// recipe.preproc.includes is not reported since the replacement is defined
+	for _, d := range deprecated {
+		require.NotEqual(t, "recipe.preproc.includes", d.Deprecated)
+	}
+}
@@ -17,10 +17,13 @@ package packagemanager
 
 import (
 	"fmt"
+	"sort"
+	"strings"
 
 	"github.com/arduino/arduino-cli/arduino"
 	"github.com/arduino/arduino-cli/arduino/cores"
 	rpc "github.com/arduino/arduino-cli/rpc/cc/arduino/cli/commands/v1"
+	"github.com/arduino/go-paths-helper"
 	"github.com/pkg/errors"
 	"go.bug.st/downloader/v2"
 	semver "go.bug.st/relaxed-semver"
@@ -127,7 +130,7 @@ func (pme *Explorer) DownloadToolRelease(tool *cores.ToolRelease, config *downlo
 			Message: tr("Error downloading tool %s", tool),
 			Cause:   errors.New(tr("no versions available for the current OS, try contacting %s", tool.Tool.Package.Email))}
 	}
-	return resource.Download(pme.DownloadDir, config, tool.String(), progressCB, "")
+	return resource.Download(pme.DownloadDir, config, tool.String(), pme.eventBus.wrapDownloadProgressCB(tool.String(), progressCB), "", pme.archiveSignatures)
 }
 
 // DownloadPlatformRelease downloads a PlatformRelease. If the platform is already downloaded a
@@ -136,5 +139,56 @@ func (pme *Explorer) DownloadPlatformRelease(platform *cores.PlatformRelease, co
 	if platform.Resource == nil {
 		return &arduino.PlatformNotFoundError{Platform: platform.String()}
 	}
-	return platform.Resource.Download(pme.DownloadDir, config, platform.String(), progressCB, "")
+	return platform.Resource.Download(pme.DownloadDir, config, platform.String(), pme.eventBus.wrapDownloadProgressCB(platform.String(), progressCB), "", pme.archiveSignatures)
+}
+
+// DownloadPlatformReleaseByReference looks up in the loaded package indexes the
+// PlatformRelease matching the given reference (the latest release if the
+// version is not specified), downloads its archive in the download directory
+// and verifies its checksum. The PlatformRelease and the path of the archive,
+// ready to be installed, are returned. If the requested version is not
+// available the error reports the available versions.
+func (pme *Explorer) DownloadPlatformReleaseByReference(ref *PlatformReference, config *downloader.Config, progressCB rpc.DownloadProgressCB) (*cores.PlatformRelease, *paths.Path, error) {
+	platform := pme.FindPlatform(ref)
+	if platform == nil {
+		return nil, nil, &arduino.PlatformNotFoundError{Platform: ref.String()}
+	}
+
+	var release *cores.PlatformRelease
+	if ref.PlatformVersion != nil {
+		release = platform.FindReleaseWithVersion(ref.PlatformVersion)
+	} else {
+		release = platform.GetLatestRelease()
+	}
+	if release == nil {
+		versions := platform.GetAllReleasesVersions()
+		if len(versions) == 0 {
+			return nil, nil, &arduino.PlatformNotFoundError{
+				Platform: ref.String(),
+				Cause:    errors.New(tr("platform %s has no available releases", platform))}
+		}
+		sort.Slice(versions, func(i, j int) bool { return versions[i].LessThan(versions[j]) })
+		available := []string{}
+		for _, version := range versions {
+			available = append(available, version.String())
+		}
+		return nil, nil, &arduino.PlatformNotFoundError{
+			Platform: ref.String(),
+			Cause:    errors.New(tr("available versions are: %s", strings.Join(available, ", ")))}
+	}
+
+	if err := pme.DownloadPlatformRelease(release, config, progressCB); err != nil {
+		return nil, nil, err
+	}
+
+	archive, err := release.Resource.ArchivePath(pme.DownloadDir)
+	if err != nil {
+		return nil, nil, err
+	}
+	if ok, err := release.Resource.TestLocalArchiveIntegrity(pme.DownloadDir); err != nil {
+		return nil, nil, &arduino.FailedDownloadError{Message: tr("Error downloading platform %s", release), Cause: err}
+	} else if !ok {
+		return nil, nil, &arduino.FailedDownloadError{Message: tr("Error downloading platform %s", release), Cause: errors.New(tr("checksum mismatch"))}
+	}
+	return release, archive, nil
 }
@@ -0,0 +1,103 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2023 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package packagemanager
+
+import (
+	"context"
+	"runtime"
+	"sort"
+	"sync"
+
+	"github.com/arduino/arduino-cli/arduino/resources"
+	"go.bug.st/downloader/v2"
+)
+
+// UnreachableDownload is a platform or tool archive whose download URL,
+// declared in the package indexes, can't be reached
+type UnreachableDownload struct {
+	// Release is the platform or tool release providing the archive
+	Release string
+	URL     string
+	// StatusCode is the HTTP status returned by the server, 0 if the server
+	// could not be contacted (in this case Err is set)
+	StatusCode int
+	Err        error
+}
+
+// FindUnreachableDownloads checks the download URL of the archive of every
+// platform and tool release in the loaded package indexes, using HEAD requests,
+// and returns the ones that are not reachable or that respond with an error
+// status. At most jobs requests are made at the same time, if jobs is 0 or less
+// the number of available CPUs is used. The result is sorted by release.
+func (pme *Explorer) FindUnreachableDownloads(ctx context.Context, config *downloader.Config, jobs int) []*UnreachableDownload {
+	type download struct {
+		release  string
+		resource *resources.DownloadResource
+	}
+	downloads := []*download{}
+	for _, targetPackage := range pme.packages {
+		for _, platform := range targetPackage.Platforms {
+			for _, release := range platform.Releases {
+				if release.Resource != nil && release.Resource.URL != "" {
+					downloads = append(downloads, &download{release.String(), release.Resource})
+				}
+			}
+		}
+		for _, tool := range targetPackage.Tools {
+			for _, release := range tool.Releases {
+				for _, flavor := range release.Flavors {
+					if flavor.Resource != nil && flavor.Resource.URL != "" {
+						downloads = append(downloads, &download{release.String(), flavor.Resource})
+					}
+				}
+			}
+		}
+	}
+
+	if jobs <= 0 {
+		jobs = runtime.NumCPU()
+	}
+	res := []*UnreachableDownload{}
+	var resMux sync.Mutex
+	var wg sync.WaitGroup
+	semaphore := make(chan struct{}, jobs)
+	for _, d := range downloads {
+		wg.Add(1)
+		semaphore <- struct{}{}
+		go func(d *download) {
+			defer func() {
+				<-semaphore
+				wg.Done()
+			}()
+			statusCode, err := d.resource.CheckReachable(ctx, config)
+			if err == nil && statusCode < 400 {
+				return
+			}
+			resMux.Lock()
+			res = append(res, &UnreachableDownload{Release: d.release, URL: d.resource.URL, StatusCode: statusCode, Err: err})
+			resMux.Unlock()
+		}(d)
+	}
+	wg.Wait()
+
+	sort.Slice(res, func(i, j int) bool {
+		if res[i].Release != res[j].Release {
+			return res[i].Release < res[j].Release
+		}
+		return res[i].URL < res[j].URL
+	})
+	return res
+}
@@ -0,0 +1,79 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2023 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package packagemanager
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/arduino/go-paths-helper"
+	"github.com/stretchr/testify/require"
+	"go.bug.st/downloader/v2"
+)
+
+func TestFindUnreachableDownloads(t *testing.T) {
+	var running, maxRunning atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := running.Add(1)
+		defer running.Add(-1)
+		for {
+			current := maxRunning.Load()
+			if n <= current || maxRunning.CompareAndSwap(current, n) {
+				break
+			}
+		}
+		time.Sleep(10 * time.Millisecond)
+		if r.URL.Path == "/missing.tar.bz2" {
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	platform := func(arch, file string) string {
+		return fmt.Sprintf(`{"name": "Test", "architecture": "%s", "version": "1.0.0", "url": "%s/%s",
+			"archiveFileName": "%s", "checksum": "SHA-256:0000", "size": "1", "boards": [], "toolsDependencies": []}`,
+			arch, server.URL, file, file)
+	}
+	indexFile := paths.New(t.TempDir(), "package_test_index.json")
+	require.NoError(t, indexFile.WriteFile([]byte(`{"packages": [{"name": "test",
+		"platforms": [`+platform("avr", "avr.tar.bz2")+`, `+platform("samd", "missing.tar.bz2")+`,
+			`+platform("sam", "sam.tar.bz2")+`, `+platform("megaavr", "megaavr.tar.bz2")+`],
+		"tools": [{"name": "gcc", "version": "1.0.0", "systems": [
+			{"host": "x86_64-pc-linux-gnu", "url": "http://127.0.0.1:1/gcc.tar.bz2", "archiveFileName": "gcc.tar.bz2", "checksum": "SHA-256:0000", "size": "1"}
+		]}]}]}`)))
+
+	pmb := NewBuilder(nil, nil, nil, nil, "test")
+	_, err := pmb.LoadPackageIndexFromFile(indexFile)
+	require.NoError(t, err)
+	pme, release := pmb.Build().NewExplorer()
+	defer release()
+
+	unreachable := pme.FindUnreachableDownloads(context.Background(), &downloader.Config{}, 2)
+	require.Len(t, unreachable, 2)
+	require.Equal(t, "test:gcc@1.0.0", unreachable[0].Release)
+	require.Equal(t, 0, unreachable[0].StatusCode)
+	require.Error(t, unreachable[0].Err)
+	require.Equal(t, "test:samd@1.0.0", unreachable[1].Release)
+	require.Equal(t, server.URL+"/missing.tar.bz2", unreachable[1].URL)
+	require.Equal(t, http.StatusNotFound, unreachable[1].StatusCode)
+	require.NoError(t, unreachable[1].Err)
+	require.LessOrEqual(t, maxRunning.Load(), int32(2))
+}
@@ -0,0 +1,138 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2023 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package packagemanager
+
+import (
+	"sync"
+
+	"github.com/arduino/arduino-cli/arduino/cores"
+	rpc "github.com/arduino/arduino-cli/rpc/cc/arduino/cli/commands/v1"
+	"go.bug.st/downloader/v2"
+)
+
+// SetParallelDownloads sets the maximum number of archives downloaded at the
+// same time by DownloadPlatformAndTools. With 1 or less the archives are
+// downloaded one at a time.
+func (pmb *Builder) SetParallelDownloads(n int) {
+	pmb.parallelDownloads = n
+}
+
+// DownloadPlatformAndTools downloads the archives of the given tools and of
+// the given platform release (that may be nil). The archives are downloaded
+// concurrently, as configured with SetParallelDownloads: in this case the
+// progress of the downloads is reported to progressCB as a single download,
+// with the total size of all the archives. The first error, in the order of the
+// tools followed by the platform, is returned.
+func (pme *Explorer) DownloadPlatformAndTools(platformRelease *cores.PlatformRelease, tools []*cores.ToolRelease, config *downloader.Config, progressCB rpc.DownloadProgressCB) error {
+	downloads := []func(rpc.DownloadProgressCB) error{}
+	sizes := []int64{}
+	for _, tool := range tools {
+		tool := tool
+		downloads = append(downloads, func(cb rpc.DownloadProgressCB) error {
+			return pme.DownloadToolRelease(tool, config, cb)
+		})
+		var size int64
+		if resource := tool.GetCompatibleFlavour(); resource != nil {
+			size = resource.Size
+		}
+		sizes = append(sizes, size)
+	}
+	if platformRelease != nil {
+		downloads = append(downloads, func(cb rpc.DownloadProgressCB) error {
+			return pme.DownloadPlatformRelease(platformRelease, config, cb)
+		})
+		var size int64
+		if platformRelease.Resource != nil {
+			size = platformRelease.Resource.Size
+		}
+		sizes = append(sizes, size)
+	}
+
+	jobs := pme.parallelDownloads
+	if jobs <= 1 || len(downloads) <= 1 {
+		for _, download := range downloads {
+			if err := download(progressCB); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	progress := &aggregatedDownloadProgress{
+		progressCB: progressCB,
+		sizes:      sizes,
+		downloaded: make([]int64, len(downloads)),
+	}
+	progressCB.Start("", tr("Downloading %d archives", len(downloads)))
+	errs := make([]error, len(downloads))
+	var wg sync.WaitGroup
+	semaphore := make(chan struct{}, jobs)
+	for i, download := range downloads {
+		wg.Add(1)
+		semaphore <- struct{}{}
+		go func(i int, download func(rpc.DownloadProgressCB) error) {
+			defer func() {
+				<-semaphore
+				wg.Done()
+			}()
+			errs[i] = download(progress.itemProgressCB(i))
+		}(i, download)
+	}
+	wg.Wait()
+
+	for _, err := range errs {
+		if err != nil {
+			progressCB.End(false, err.Error())
+			return err
+		}
+	}
+	progressCB.End(true, tr("%d archives downloaded", len(downloads)))
+	return nil
+}
+
+// aggregatedDownloadProgress sums up the progress of concurrent downloads
+type aggregatedDownloadProgress struct {
+	mux        sync.Mutex
+	progressCB rpc.DownloadProgressCB
+	sizes      []int64
+	downloaded []int64
+}
+
+// itemProgressCB returns the DownloadProgressCB of the i-th download: its
+// progress is added to the others and forwarded as a single update.
+func (p *aggregatedDownloadProgress) itemProgressCB(i int) rpc.DownloadProgressCB {
+	return func(progress *rpc.DownloadProgress) {
+		p.mux.Lock()
+		defer p.mux.Unlock()
+		if update := progress.GetUpdate(); update != nil {
+			p.downloaded[i] = update.GetDownloaded()
+			if update.GetTotalSize() > 0 {
+				p.sizes[i] = update.GetTotalSize()
+			}
+		} else if end := progress.GetEnd(); end != nil && end.GetSuccess() {
+			// Cached archives don't send any update
+			p.downloaded[i] = p.sizes[i]
+		} else {
+			return
+		}
+		var downloaded, total int64
+		for j := range p.sizes {
+			downloaded += p.downloaded[j]
+			total += p.sizes[j]
+		}
+		p.progressCB.Update(downloaded, total)
+	}
+}
@@ -0,0 +1,108 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2023 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package packagemanager
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/arduino/arduino-cli/arduino/cores"
+	"github.com/arduino/arduino-cli/arduino/httpclient"
+	"github.com/arduino/arduino-cli/arduino/resources"
+	rpc "github.com/arduino/arduino-cli/rpc/cc/arduino/cli/commands/v1"
+	"github.com/arduino/go-paths-helper"
+	"github.com/stretchr/testify/require"
+	"go.bug.st/downloader/v2"
+	semver "go.bug.st/relaxed-semver"
+)
+
+func TestDownloadPlatformAndTools(t *testing.T) {
+	archive := bytes.Repeat([]byte("archive"), 1000)
+	checksum := sha256.Sum256(archive)
+	var running, maxRunning int
+	var runningMux sync.Mutex
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		runningMux.Lock()
+		running++
+		maxRunning = max(maxRunning, running)
+		runningMux.Unlock()
+		time.Sleep(100 * time.Millisecond)
+		runningMux.Lock()
+		running--
+		runningMux.Unlock()
+		if r.URL.Path == "/missing.zip" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Write(archive)
+	}))
+	defer srv.Close()
+
+	tmp := paths.New(t.TempDir())
+	resource := func(name string) *resources.DownloadResource {
+		return &resources.DownloadResource{
+			URL:             srv.URL + "/" + name,
+			ArchiveFileName: name,
+			Checksum:        "SHA-256:" + hex.EncodeToString(checksum[:]),
+			Size:            int64(len(archive)),
+			CachePath:       "packages",
+		}
+	}
+	pmb := NewBuilder(tmp, tmp.Join("packages"), tmp.Join("staging"), tmp.Join("tmp"), "test")
+	pmb.SetParallelDownloads(3)
+	tools := []*cores.ToolRelease{}
+	for _, name := range []string{"gcc", "gdb", "openocd", "bossac"} {
+		tool := pmb.GetOrCreatePackage("test").GetOrCreateTool(name).GetOrCreateRelease(semver.ParseRelaxed("1.0.0"))
+		tool.Flavors = []*cores.Flavor{{OS: "all", Resource: resource(name + ".zip")}}
+		tools = append(tools, tool)
+	}
+	platformRelease := pmb.GetOrCreatePackage("test").GetOrCreatePlatform("avr").GetOrCreateRelease(semver.MustParse("1.0.0"))
+	platformRelease.Resource = resource("avr.zip")
+	pme, release := pmb.Build().NewExplorer()
+	defer release()
+
+	config := &downloader.Config{HttpClient: *httpclient.NewWithConfig(&httpclient.Config{UserAgent: "test"})}
+	events := []*rpc.DownloadProgress{}
+	progressCB := func(progress *rpc.DownloadProgress) { events = append(events, progress) }
+
+	// The downloads run concurrently, their progress is reported as a single download
+	require.NoError(t, pme.DownloadPlatformAndTools(platformRelease, tools, config, progressCB))
+	require.Equal(t, 3, maxRunning)
+	for _, tool := range tools {
+		require.FileExists(t, tmp.Join("staging", "packages", tool.Tool.Name+".zip").String())
+	}
+	require.FileExists(t, tmp.Join("staging", "packages", "avr.zip").String())
+	require.Equal(t, "Downloading 5 archives", events[0].GetStart().GetLabel())
+	last := events[len(events)-2].GetUpdate()
+	require.Equal(t, int64(5*len(archive)), last.GetDownloaded())
+	require.Equal(t, int64(5*len(archive)), last.GetTotalSize())
+	require.True(t, events[len(events)-1].GetEnd().GetSuccess())
+	for _, event := range events[1 : len(events)-1] {
+		require.NotNil(t, event.GetUpdate())
+	}
+
+	// A failed download is reported
+	platformRelease.Resource = resource("missing.zip")
+	events = events[:0]
+	require.Error(t, pme.DownloadPlatformAndTools(platformRelease, tools, config, progressCB))
+	require.False(t, events[len(events)-1].GetEnd().GetSuccess())
+}
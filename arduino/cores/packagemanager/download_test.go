@@ -0,0 +1,105 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2023 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package packagemanager
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/arduino/arduino-cli/arduino"
+	"github.com/arduino/arduino-cli/arduino/httpclient"
+	rpc "github.com/arduino/arduino-cli/rpc/cc/arduino/cli/commands/v1"
+	"github.com/arduino/go-paths-helper"
+	"github.com/stretchr/testify/require"
+	"go.bug.st/downloader/v2"
+	semver "go.bug.st/relaxed-semver"
+)
+
+func TestDownloadPlatformReleaseByReference(t *testing.T) {
+	archive := []byte("platform archive content")
+	checksum := sha256.Sum256(archive)
+	requests := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if r.URL.Path == "/test-avr-1.2.0.tar.bz2" {
+			w.Write(archive)
+			return
+		}
+		w.Write([]byte("corrupted"))
+	}))
+	defer srv.Close()
+
+	platformIndex := func(version, archiveName string) string {
+		return fmt.Sprintf(`{
+			"name": "Test AVR", "architecture": "avr", "version": "%[1]s", "category": "Test",
+			"url": "%[2]s/%[3]s", "archiveFileName": "%[3]s", "checksum": "SHA-256:%[4]s", "size": "%[5]d",
+			"boards": [], "toolsDependencies": []
+		}`, version, srv.URL, archiveName, hex.EncodeToString(checksum[:]), len(archive))
+	}
+	dataDir := paths.New(t.TempDir())
+	indexFile := dataDir.Join("package_test_index.json")
+	require.NoError(t, indexFile.WriteFile([]byte(`{"packages": [{
+		"name": "test", "maintainer": "Test", "websiteURL": "", "email": "", "help": {"online": ""},
+		"platforms": [`+
+		platformIndex("1.0.0", "test-avr-1.0.0.tar.bz2")+","+
+		platformIndex("1.2.0", "test-avr-1.2.0.tar.bz2")+`],
+		"tools": []
+	}]}`)))
+
+	pmb := NewBuilder(dataDir, dataDir.Join("packages"), dataDir.Join("staging"), dataDir.Join("tmp"), "test")
+	_, err := pmb.LoadPackageIndexFromFile(indexFile)
+	require.NoError(t, err)
+	pm := pmb.Build()
+	pme, release := pm.NewExplorer()
+	defer release()
+
+	config := &downloader.Config{HttpClient: *httpclient.NewWithConfig(&httpclient.Config{UserAgent: "test"})}
+	progressCB := func(progress *rpc.DownloadProgress) {}
+
+	ref := &PlatformReference{Package: "test", PlatformArchitecture: "avr", PlatformVersion: semver.MustParse("1.2.0")}
+	platformRelease, archivePath, err := pme.DownloadPlatformReleaseByReference(ref, config, progressCB)
+	require.NoError(t, err)
+	require.Equal(t, "test:avr@1.2.0", platformRelease.String())
+	require.Equal(t, dataDir.Join("staging", "packages", "test-avr-1.2.0.tar.bz2"), archivePath)
+	content, err := archivePath.ReadFile()
+	require.NoError(t, err)
+	require.Equal(t, archive, content)
+	require.Equal(t, 1, requests)
+
+	// The archive is already staged, it's not downloaded again
+	_, _, err = pme.DownloadPlatformReleaseByReference(ref, config, progressCB)
+	require.NoError(t, err)
+	require.Equal(t, 1, requests)
+
+	// Missing version
+	ref.PlatformVersion = semver.MustParse("1.1.0")
+	_, _, err = pme.DownloadPlatformReleaseByReference(ref, config, progressCB)
+	require.ErrorAs(t, err, new(*arduino.PlatformNotFoundError))
+	require.EqualError(t, err, "Platform 'test:avr@1.1.0' not found: available versions are: 1.0.0, 1.2.0")
+
+	// Missing platform
+	_, _, err = pme.DownloadPlatformReleaseByReference(&PlatformReference{Package: "test", PlatformArchitecture: "samd"}, config, progressCB)
+	require.ErrorAs(t, err, new(*arduino.PlatformNotFoundError))
+
+	// Checksum mismatch
+	ref.PlatformVersion = semver.MustParse("1.0.0")
+	_, _, err = pme.DownloadPlatformReleaseByReference(ref, config, progressCB)
+	require.ErrorAs(t, err, new(*arduino.FailedDownloadError))
+}
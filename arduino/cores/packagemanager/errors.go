@@ -0,0 +1,34 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2023 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package packagemanager
+
+// kindError is an error carrying a translated message that can be checked,
+// with errors.Is, against one of the untranslated ErrXxx sentinels of this
+// package. The sentinels are kept untranslated because they are created at
+// package initialization, before the locale is set.
+type kindError struct {
+	kind    error
+	message string
+}
+
+func (e *kindError) Error() string {
+	return e.message
+}
+
+// Is returns true if target is the kind of this error
+func (e *kindError) Is(target error) bool {
+	return target == e.kind
+}
@@ -0,0 +1,143 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2023 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package packagemanager
+
+import (
+	"sync"
+
+	rpc "github.com/arduino/arduino-cli/rpc/cc/arduino/cli/commands/v1"
+)
+
+// EventKind is the kind of operation reported by an Event
+type EventKind string
+
+const (
+	// EventDownloadStarted is sent when the download of a platform or tool archive starts
+	EventDownloadStarted EventKind = "download-started"
+	// EventDownloadProgress is sent while an archive is being downloaded
+	EventDownloadProgress EventKind = "download-progress"
+	// EventDownloadCompleted is sent when the download of an archive ends,
+	// successfully or not
+	EventDownloadCompleted EventKind = "download-completed"
+	// EventInstalled is sent when a platform or tool release has been installed
+	EventInstalled EventKind = "installed"
+	// EventUninstalled is sent when a platform or tool release has been uninstalled
+	EventUninstalled EventKind = "uninstalled"
+	// EventIndexLoaded is sent when a package index has been loaded
+	EventIndexLoaded EventKind = "index-loaded"
+)
+
+// Event describes an operation performed by the PackageManager
+type Event struct {
+	Kind EventKind
+	// Subject is the platform or tool release (for example "arduino:avr@1.8.6")
+	// or, for EventIndexLoaded, the URL of the package index
+	Subject string
+	// Downloaded and TotalSize are the progress of the download, set for the
+	// EventDownloadProgress events
+	Downloaded int64
+	TotalSize  int64
+	// Success and Message are the outcome of the download, set for the
+	// EventDownloadCompleted events
+	Success bool
+	Message string
+}
+
+// EventHandler receives the events of a PackageManager
+type EventHandler interface {
+	HandleEvent(event *Event)
+}
+
+// EventHandlerFunc is an adapter to use a function as an EventHandler
+type EventHandlerFunc func(event *Event)
+
+// HandleEvent calls f(event)
+func (f EventHandlerFunc) HandleEvent(event *Event) {
+	f(event)
+}
+
+// eventBus dispatches the events to the registered handlers. It's shared by
+// a PackageManager, its Builders and its Explorers.
+type eventBus struct {
+	handlersMux sync.RWMutex
+	handlers    []*registeredEventHandler
+}
+
+type registeredEventHandler struct {
+	handler EventHandler
+}
+
+// RegisterEventHandler adds a handler that receives all the events of the
+// PackageManager, and returns a function to unregister it. Any number of
+// handlers can be registered, they are called synchronously in registration
+// order from the goroutine performing the operation, so they should not block.
+func (pm *PackageManager) RegisterEventHandler(handler EventHandler) (unregister func()) {
+	return pm.eventBus.register(handler)
+}
+
+func (bus *eventBus) register(handler EventHandler) func() {
+	registered := &registeredEventHandler{handler: handler}
+	bus.handlersMux.Lock()
+	bus.handlers = append(bus.handlers, registered)
+	bus.handlersMux.Unlock()
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			bus.handlersMux.Lock()
+			defer bus.handlersMux.Unlock()
+			for i, h := range bus.handlers {
+				if h == registered {
+					bus.handlers = append(bus.handlers[:i:i], bus.handlers[i+1:]...)
+					break
+				}
+			}
+		})
+	}
+}
+
+// emit sends the event to all the registered handlers
+func (bus *eventBus) emit(event *Event) {
+	if bus == nil {
+		return
+	}
+	bus.handlersMux.RLock()
+	handlers := bus.handlers
+	bus.handlersMux.RUnlock()
+	for _, h := range handlers {
+		h.handler.HandleEvent(event)
+	}
+}
+
+// wrapDownloadProgressCB returns a DownloadProgressCB that sends the download
+// events of the given subject and then forwards the progress to downloadCB,
+// if not nil.
+func (bus *eventBus) wrapDownloadProgressCB(subject string, downloadCB rpc.DownloadProgressCB) rpc.DownloadProgressCB {
+	return func(progress *rpc.DownloadProgress) {
+		if start := progress.GetStart(); start != nil {
+			bus.emit(&Event{Kind: EventDownloadStarted, Subject: subject})
+		}
+		if update := progress.GetUpdate(); update != nil {
+			bus.emit(&Event{Kind: EventDownloadProgress, Subject: subject, Downloaded: update.GetDownloaded(), TotalSize: update.GetTotalSize()})
+		}
+		if end := progress.GetEnd(); end != nil {
+			bus.emit(&Event{Kind: EventDownloadCompleted, Subject: subject, Success: end.GetSuccess(), Message: end.GetMessage()})
+		}
+		if downloadCB != nil {
+			downloadCB(progress)
+		}
+	}
+}
@@ -0,0 +1,100 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2023 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package packagemanager
+
+import (
+	"archive/zip"
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"testing"
+
+	"github.com/arduino/arduino-cli/arduino/cores"
+	"github.com/arduino/arduino-cli/arduino/resources"
+	rpc "github.com/arduino/arduino-cli/rpc/cc/arduino/cli/commands/v1"
+	"github.com/arduino/go-paths-helper"
+	"github.com/stretchr/testify/require"
+	semver "go.bug.st/relaxed-semver"
+)
+
+func TestEventHandlers(t *testing.T) {
+	dataDir := paths.New(t.TempDir())
+
+	// Prepare the archive of a tool, already downloaded
+	var archive bytes.Buffer
+	w := zip.NewWriter(&archive)
+	_, err := w.Create("tool/file.txt")
+	require.NoError(t, err)
+	require.NoError(t, w.Close())
+	sum := sha256.Sum256(archive.Bytes())
+	require.NoError(t, dataDir.Join("staging", "packages").MkdirAll())
+	require.NoError(t, dataDir.Join("staging", "packages", "tool-1.0.0.zip").WriteFile(archive.Bytes()))
+
+	pm := NewBuilder(dataDir, dataDir.Join("packages"), dataDir.Join("staging"), dataDir.Join("tmp"), "test").Build()
+	received := map[string][]*Event{}
+	recorder := func(name string) EventHandler {
+		return EventHandlerFunc(func(event *Event) {
+			received[name] = append(received[name], event)
+		})
+	}
+	unregisterFirst := pm.RegisterEventHandler(recorder("first"))
+	pm.RegisterEventHandler(recorder("second"))
+
+	// The builders created from the PackageManager share its handlers
+	pmb, commit := pm.NewBuilder()
+	_, err = pmb.LoadPackageIndexFromFile(dataDir1.Join("package_test_index.json"))
+	require.NoError(t, err)
+	toolRelease := pmb.GetOrCreatePackage("test").GetOrCreateTool("tool").GetOrCreateRelease(semver.ParseRelaxed("1.0.0"))
+	toolRelease.Flavors = []*cores.Flavor{{OS: "all", Resource: &resources.DownloadResource{
+		URL:             "https://example.com/tool-1.0.0.zip",
+		ArchiveFileName: "tool-1.0.0.zip",
+		Checksum:        "SHA-256:" + hex.EncodeToString(sum[:]),
+		Size:            int64(archive.Len()),
+		CachePath:       "packages",
+	}}}
+	commit()
+
+	pme, release := pm.NewExplorer()
+	forwarded := 0
+	require.NoError(t, pme.DownloadToolRelease(toolRelease, nil, func(*rpc.DownloadProgress) { forwarded++ }))
+	require.NoError(t, pme.InstallTool(toolRelease, func(*rpc.TaskProgress) {}, true))
+	require.NoError(t, pme.UninstallTool(toolRelease, func(*rpc.TaskProgress) {}, true))
+	release()
+	require.Equal(t, 2, forwarded)
+
+	expected := []EventKind{EventIndexLoaded, EventDownloadStarted, EventDownloadCompleted, EventInstalled, EventUninstalled}
+	for _, name := range []string{"first", "second"} {
+		kinds := []EventKind{}
+		for _, event := range received[name] {
+			kinds = append(kinds, event.Kind)
+		}
+		require.Equal(t, expected, kinds, name)
+		require.Equal(t, dataDir1.Join("package_test_index.json").String(), received[name][0].Subject)
+		require.Equal(t, "test:tool@1.0.0", received[name][1].Subject)
+		require.True(t, received[name][2].Success)
+		require.Equal(t, "test:tool@1.0.0", received[name][3].Subject)
+	}
+
+	// An unregistered handler doesn't receive events anymore
+	unregisterFirst()
+	unregisterFirst()
+	pme, release = pm.NewExplorer()
+	require.NoError(t, pme.InstallTool(toolRelease, func(*rpc.TaskProgress) {}, true))
+	release()
+	require.Len(t, received["first"], 5)
+	require.Len(t, received["second"], 6)
+	require.Equal(t, EventInstalled, received["second"][5].Kind)
+}
@@ -0,0 +1,105 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2023 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package packagemanager
+
+import (
+	"errors"
+
+	"github.com/arduino/arduino-cli/arduino/cores"
+)
+
+// The reasons why ResolveFQBN may fail, they can be checked with errors.Is on
+// the returned error. More details and the partial resolution are available
+// by extracting the FQBNResolutionError with errors.As.
+var (
+	// ErrPackageNotFound means that the package of the FQBN is unknown
+	ErrPackageNotFound = errors.New("package not found")
+	// ErrPlatformNotFound means that the platform of the FQBN is unknown
+	ErrPlatformNotFound = errors.New("platform not found")
+	// ErrPlatformNotInstalled means that the platform of the FQBN is known,
+	// from the package indexes, but not installed
+	ErrPlatformNotInstalled = errors.New("platform not installed")
+	// ErrBoardNotFound means that the installed platform has no such board
+	ErrBoardNotFound = errors.New("board not found")
+	// ErrInvalidBoardConfiguration means that the config options of the FQBN
+	// are not valid for the board
+	ErrInvalidBoardConfiguration = errors.New("invalid board configuration")
+	// ErrInvalidReferencedPlatform means that the board refers, with
+	// build.core and build.variant, to two different platforms
+	ErrInvalidReferencedPlatform = errors.New("invalid referenced platform")
+	// ErrReferencedPlatformNotFound means that the platform providing the
+	// core or the variant used by the board is unknown
+	ErrReferencedPlatformNotFound = errors.New("referenced platform not found")
+	// ErrReferencedPlatformNotInstalled means that the platform providing the
+	// core or the variant used by the board is known but not installed
+	ErrReferencedPlatformNotInstalled = errors.New("referenced platform not installed")
+	// ErrRequiredToolNotFound means that a tool required to build for the
+	// board is not available
+	ErrRequiredToolNotFound = errors.New("required tool not found")
+)
+
+// FQBNResolutionError is the error returned by ResolveFQBN. It carries the
+// reason of the failure (Kind) and what has been resolved before the failure,
+// so the caller can suggest the right fix: for example installing the platform,
+// or the referenced platform, or choosing another board of the platform.
+type FQBNResolutionError struct {
+	// Kind is one of the ErrXxx values of this package
+	Kind error
+	// FQBN is the FQBN being resolved
+	FQBN *cores.FQBN
+	// Package is the package of the FQBN, nil if unknown
+	Package *cores.Package
+	// Platform is the platform of the FQBN, nil if unknown
+	Platform *cores.Platform
+	// PlatformRelease is the installed release of Platform, nil if not installed
+	PlatformRelease *cores.PlatformRelease
+	// Board is the board of the FQBN, nil if not found
+	Board *cores.Board
+	// ReferencedPlatform is the platform (PACKAGER:ARCH) referenced by the
+	// board for the core or the variant, empty if the board doesn't refer to
+	// another platform
+	ReferencedPlatform string
+	// Cause is the underlying error, if any
+	Cause   error
+	message string
+}
+
+func (e *FQBNResolutionError) Error() string {
+	return e.message
+}
+
+// Unwrap returns the underlying error
+func (e *FQBNResolutionError) Unwrap() error {
+	return e.Cause
+}
+
+// Is returns true if target is the Kind of this error
+func (e *FQBNResolutionError) Is(target error) bool {
+	return target == e.Kind
+}
+
+// fillPartialResolution completes the error with the resolution made so far by
+// ResolveFQBN
+func (e *FQBNResolutionError) fillPartialResolution(fqbn *cores.FQBN, targetPackage *cores.Package, platformRelease *cores.PlatformRelease, board *cores.Board) *FQBNResolutionError {
+	e.FQBN = fqbn
+	e.Package = targetPackage
+	if platformRelease != nil {
+		e.Platform = platformRelease.Platform
+	}
+	e.PlatformRelease = platformRelease
+	e.Board = board
+	return e
+}
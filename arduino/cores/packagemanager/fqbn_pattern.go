@@ -0,0 +1,78 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2023 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package packagemanager
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/arduino/arduino-cli/arduino/cores"
+)
+
+// ExpandFQBNPattern returns the FQBN of all the boards matching the given
+// pattern, sorted alphabetically. The pattern may be a package ("arduino" or
+// "arduino:"), a platform ("arduino:avr" or "arduino:avr:") or a board FQBN:
+// a package or a platform expands to all the boards of the installed platforms
+// beneath it, a board FQBN (or an alias) returns the FQBN itself.
+func (pme *Explorer) ExpandFQBNPattern(pattern string) ([]string, error) {
+	parts := strings.Split(strings.TrimSuffix(pattern, ":"), ":")
+	if len(parts) > 2 {
+		if _, err := pme.FindBoardWithFQBN(pattern); err != nil {
+			return nil, err
+		}
+		fqbn, err := pme.ParseFQBN(pattern)
+		if err != nil {
+			return nil, err
+		}
+		return []string{fqbn.String()}, nil
+	}
+	if parts[0] == "" {
+		return nil, fmt.Errorf(tr("invalid FQBN pattern: %s"), pattern)
+	}
+	if aliased, ok := pme.fqbnAliases[strings.ToLower(pattern)]; ok {
+		return pme.ExpandFQBNPattern(aliased)
+	}
+
+	targetPackage, ok := pme.packages[parts[0]]
+	if !ok {
+		return nil, fmt.Errorf(tr("unknown package %s"), parts[0])
+	}
+	platforms := targetPackage.Platforms
+	if len(parts) == 2 {
+		platform, ok := targetPackage.Platforms[parts[1]]
+		if !ok {
+			return nil, fmt.Errorf(tr("unknown platform %s:%s"), parts[0], parts[1])
+		}
+		platforms = map[string]*cores.Platform{parts[1]: platform}
+	}
+
+	res := []string{}
+	for _, platform := range platforms {
+		release := pme.GetInstalledPlatformRelease(platform)
+		if release == nil {
+			continue
+		}
+		for _, board := range release.Boards {
+			res = append(res, board.FQBN())
+		}
+	}
+	if len(res) == 0 {
+		return nil, fmt.Errorf(tr("no installed platform matches %s"), pattern)
+	}
+	sort.Strings(res)
+	return res, nil
+}
@@ -0,0 +1,69 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2023 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package packagemanager
+
+import (
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestExpandFQBNPattern(t *testing.T) {
+	pmb := NewBuilder(customHardware, customHardware, customHardware, customHardware, "test")
+	pmb.LoadHardwareFromDirectory(customHardware)
+	pmb.AddFQBNAlias("MyMega", "arduino:avr:mega:cpu=atmega1280")
+	pm := pmb.Build()
+	pme, release := pm.NewExplorer()
+	defer release()
+
+	avrBoards := []string{}
+	for boardID := range pme.GetPackages()["arduino"].Platforms["avr"].GetAllInstalled()[0].Boards {
+		avrBoards = append(avrBoards, "arduino:avr:"+boardID)
+	}
+	sort.Strings(avrBoards)
+	require.Contains(t, avrBoards, "arduino:avr:uno")
+	require.Contains(t, avrBoards, "arduino:avr:mega")
+
+	for _, pattern := range []string{"arduino:avr", "arduino:avr:"} {
+		fqbns, err := pme.ExpandFQBNPattern(pattern)
+		require.NoError(t, err)
+		require.Equal(t, avrBoards, fqbns)
+	}
+
+	// A package expands to the boards of all its platforms
+	fqbns, err := pme.ExpandFQBNPattern("arduino:")
+	require.NoError(t, err)
+	require.Subset(t, fqbns, avrBoards)
+	require.Contains(t, fqbns, "arduino:sam:arduino_due_x")
+	require.True(t, sort.StringsAreSorted(fqbns))
+	fqbns2, err := pme.ExpandFQBNPattern("arduino")
+	require.NoError(t, err)
+	require.Equal(t, fqbns, fqbns2)
+
+	// An exact FQBN returns itself
+	fqbns, err = pme.ExpandFQBNPattern("arduino:avr:uno")
+	require.NoError(t, err)
+	require.Equal(t, []string{"arduino:avr:uno"}, fqbns)
+	fqbns, err = pme.ExpandFQBNPattern("MyMega")
+	require.NoError(t, err)
+	require.Equal(t, []string{"arduino:avr:mega:cpu=atmega1280"}, fqbns)
+
+	for _, pattern := range []string{"", ":", "missing", "arduino:missing", "arduino:avr:missing"} {
+		_, err := pme.ExpandFQBNPattern(pattern)
+		require.Error(t, err, pattern)
+	}
+}
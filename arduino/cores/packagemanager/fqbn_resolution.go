@@ -0,0 +1,91 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2023 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package packagemanager
+
+// FQBNResolution is the result of the resolution of an FQBN against the
+// loaded package indexes and the installed platforms. It doesn't keep any
+// reference to the PackageManager so it can be compared with the resolution
+// made after reloading the indexes.
+type FQBNResolution struct {
+	FQBN string
+	// Platform is the platform release providing the board, in the form
+	// PACKAGER:ARCH@VERSION
+	Platform string
+	// BuildPlatform is the platform release providing the core used to build
+	// for the board, in the form PACKAGER:ARCH@VERSION
+	BuildPlatform string
+	// Err is the reason why the FQBN can't be resolved, if not nil the other
+	// fields are empty
+	Err error
+}
+
+// FQBNResolutionChange describes how the resolution of an FQBN is changed
+type FQBNResolutionChange struct {
+	FQBN   string
+	Before *FQBNResolution
+	After  *FQBNResolution
+	// VersionChanged is true if the FQBN now resolves to another release of
+	// the platform providing the board
+	VersionChanged bool
+	// BuildPlatformChanged is true if the core used to build is now provided
+	// by another platform release
+	BuildPlatformChanged bool
+	// ResolvabilityChanged is true if the FQBN was resolved before and can't
+	// be resolved anymore, or vice versa
+	ResolvabilityChanged bool
+}
+
+// ResolveFQBNs resolves each one of the given FQBN with the same rules used by
+// PlanForFQBNs: the installed platform release is used if available, otherwise
+// the latest release available in the package indexes.
+func (pme *Explorer) ResolveFQBNs(fqbns []string) map[string]*FQBNResolution {
+	res := map[string]*FQBNResolution{}
+	for _, fqbn := range fqbns {
+		resolution := &FQBNResolution{FQBN: fqbn}
+		if board, core, _, err := pme.resolveFQBNPlatformReleases(fqbn); err != nil {
+			resolution.Err = err
+		} else {
+			resolution.Platform = board.String()
+			resolution.BuildPlatform = core.String()
+		}
+		res[fqbn] = resolution
+	}
+	return res
+}
+
+// DiffFQBNResolutions compares two resolutions of the same set of FQBN, made for
+// example before and after loading a new package index, and returns the FQBN
+// whose resolution is changed, in the order of the given list.
+func DiffFQBNResolutions(fqbns []string, before, after map[string]*FQBNResolution) []*FQBNResolutionChange {
+	res := []*FQBNResolutionChange{}
+	for _, fqbn := range fqbns {
+		prev, next := before[fqbn], after[fqbn]
+		if prev == nil || next == nil {
+			continue
+		}
+		change := &FQBNResolutionChange{FQBN: fqbn, Before: prev, After: next}
+		if (prev.Err == nil) != (next.Err == nil) {
+			change.ResolvabilityChanged = true
+		} else if prev.Err == nil {
+			change.VersionChanged = prev.Platform != next.Platform
+			change.BuildPlatformChanged = prev.BuildPlatform != next.BuildPlatform
+		}
+		if change.VersionChanged || change.BuildPlatformChanged || change.ResolvabilityChanged {
+			res = append(res, change)
+		}
+	}
+	return res
+}
@@ -0,0 +1,58 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2023 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package packagemanager
+
+import (
+	"testing"
+
+	"github.com/arduino/go-paths-helper"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDiffFQBNResolutions(t *testing.T) {
+	fqbns := []string{"test:avr:uno", "test:samd:zero", "test:megaavr:nona4809"}
+	resolve := func(indexes ...string) map[string]*FQBNResolution {
+		pmb := NewBuilder(nil, nil, nil, nil, "test")
+		for _, index := range indexes {
+			_, err := pmb.LoadPackageIndexFromFile(paths.New("testdata", index))
+			require.NoError(t, err)
+		}
+		pme, release := pmb.Build().NewExplorer()
+		defer release()
+		return pme.ResolveFQBNs(fqbns)
+	}
+
+	before := resolve("package_provision_index.json")
+	require.Equal(t, "test:samd@1.0.0", before["test:samd:zero"].Platform)
+	require.Equal(t, "test:samd@1.0.0", before["test:samd:zero"].BuildPlatform)
+	require.Error(t, before["test:megaavr:nona4809"].Err)
+	require.Empty(t, DiffFQBNResolutions(fqbns, before, resolve("package_provision_index.json")))
+
+	after := resolve("package_provision_index.json", "package_provision_update_index.json")
+	changes := DiffFQBNResolutions(fqbns, before, after)
+	require.Len(t, changes, 2)
+
+	require.Equal(t, "test:samd:zero", changes[0].FQBN)
+	require.True(t, changes[0].VersionChanged)
+	require.True(t, changes[0].BuildPlatformChanged)
+	require.False(t, changes[0].ResolvabilityChanged)
+	require.Equal(t, "test:samd@1.1.0", changes[0].After.Platform)
+
+	require.Equal(t, "test:megaavr:nona4809", changes[1].FQBN)
+	require.True(t, changes[1].ResolvabilityChanged)
+	require.NoError(t, changes[1].After.Err)
+	require.Equal(t, "test:megaavr@1.0.0", changes[1].After.Platform)
+}
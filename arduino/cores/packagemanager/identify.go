@@ -16,6 +16,9 @@
 package packagemanager
 
 import (
+	"sort"
+	"strings"
+
 	"github.com/arduino/arduino-cli/arduino/cores"
 	properties "github.com/arduino/go-properties-orderedmap"
 )
@@ -36,6 +39,87 @@ func (pme *Explorer) IdentifyBoard(idProps *properties.Map) []*cores.Board {
 	return foundBoards
 }
 
+// FindBoardsByIdentification returns the installed boards declaring a set of
+// identification properties (upload_port.N.xxx, for example vid, pid,
+// serialNumber or a USB product string) that is a subset of the given upload
+// port properties. The values declared by the boards may be shell patterns.
+// The boards are sorted from the most specific match (the one matching more
+// properties) to the least specific, so a board distinguished by its serial
+// number comes before the boards sharing only the same VID/PID.
+func (pme *Explorer) FindBoardsByIdentification(props map[string]string) []*cores.Board {
+	query := properties.NewFromHashmap(props)
+	if query.Size() == 0 {
+		return []*cores.Board{}
+	}
+	foundBoards := []*cores.Board{}
+	specificity := map[*cores.Board]int{}
+	for _, board := range pme.InstalledBoards() {
+		if s := board.IdentificationSpecificity(query); s > 0 {
+			foundBoards = append(foundBoards, board)
+			specificity[board] = s
+		}
+	}
+	sort.SliceStable(foundBoards, func(i, j int) bool {
+		return specificity[foundBoards[i]] > specificity[foundBoards[j]]
+	})
+	return foundBoards
+}
+
+// BoardCandidate is a board that may be the one with a given USB VID/PID, see
+// FindBoardCandidatesWithVidPid
+type BoardCandidate struct {
+	Board *cores.Board
+	Match cores.UsbIDMatch
+	// Confidence is the estimated probability, between 0 and 1, that the
+	// device is this board
+	Confidence float64
+}
+
+// usbIDMatchConfidence is the confidence of each kind of match when a single
+// board is found
+var usbIDMatchConfidence = map[cores.UsbIDMatch]float64{
+	cores.UsbIDExactMatch:    1.0,
+	cores.UsbIDWildcardMatch: 0.75,
+	cores.UsbIDVendorMatch:   0.25,
+}
+
+// FindBoardCandidatesWithVidPid returns the installed boards that may be the
+// device with the given USB VID/PID, sorted by decreasing confidence. The
+// candidates are the boards declaring the VID/PID and the ones matching it with
+// a wildcard pattern (the PID may be a shell pattern, for example "0x80*" or
+// "*"). If none of them is found the boards of the same vendor (VID) are
+// returned, so a device with a generic USB-serial chip gets a list of
+// candidates instead of nothing. The confidence of each candidate depends on
+// the kind of match and is divided among the boards with the same kind of
+// match: for example, two boards declaring the same VID/PID have a confidence
+// of 0.5 each.
+func (pme *Explorer) FindBoardCandidatesWithVidPid(vid, pid string) []*BoardCandidate {
+	byMatch := map[cores.UsbIDMatch][]*cores.Board{}
+	for _, board := range pme.InstalledBoards() {
+		if match := board.MatchUsbID(vid, pid); match != cores.UsbIDNoMatch {
+			byMatch[match] = append(byMatch[match], board)
+		}
+	}
+	if len(byMatch[cores.UsbIDExactMatch])+len(byMatch[cores.UsbIDWildcardMatch]) > 0 {
+		delete(byMatch, cores.UsbIDVendorMatch)
+	}
+
+	res := []*BoardCandidate{}
+	for _, match := range []cores.UsbIDMatch{cores.UsbIDExactMatch, cores.UsbIDWildcardMatch, cores.UsbIDVendorMatch} {
+		boards := byMatch[match]
+		sort.Slice(boards, func(i, j int) bool { return boards[i].FQBN() < boards[j].FQBN() })
+		for _, board := range boards {
+			res = append(res, &BoardCandidate{
+				Board:      board,
+				Match:      match,
+				Confidence: usbIDMatchConfidence[match] / float64(len(boards)),
+			})
+		}
+	}
+	sort.SliceStable(res, func(i, j int) bool { return res[i].Confidence > res[j].Confidence })
+	return res
+}
+
 // IdentifyBoardConfiguration returns the configuration of the board that can be
 // deduced from the given upload port identification properties
 func (pm *PackageManager) IdentifyBoardConfiguration(idProps *properties.Map, board *cores.Board) *properties.Map {
@@ -44,3 +128,50 @@ func (pm *PackageManager) IdentifyBoardConfiguration(idProps *properties.Map, bo
 	}
 	return board.IdentifyBoardConfiguration(idProps)
 }
+
+// UsbID is a USB VID/PID pair, the values are in lowercase (for example "0x2341")
+type UsbID struct {
+	VID string
+	PID string
+}
+
+func (id UsbID) String() string {
+	return id.VID + ":" + id.PID
+}
+
+// ConflictingUsbIDs returns the USB VID/PID pairs that are claimed by boards of
+// more than one installed platform, together with the competing boards. When a
+// connected board has one of these VID/PID the identification is ambiguous.
+func (pme *Explorer) ConflictingUsbIDs() map[UsbID][]*cores.Board {
+	claims := map[UsbID][]*cores.Board{}
+	for _, board := range pme.InstalledBoards() {
+		claimed := map[UsbID]bool{}
+		for _, idProps := range board.GetIdentificationProperties() {
+			vid, hasVid := idProps.GetOk("vid")
+			pid, hasPid := idProps.GetOk("pid")
+			if !hasVid || !hasPid {
+				continue
+			}
+			id := UsbID{VID: strings.ToLower(vid), PID: strings.ToLower(pid)}
+			if claimed[id] {
+				continue
+			}
+			claimed[id] = true
+			claims[id] = append(claims[id], board)
+		}
+	}
+
+	res := map[UsbID][]*cores.Board{}
+	for id, boards := range claims {
+		platforms := map[*cores.Platform]bool{}
+		for _, board := range boards {
+			platforms[board.PlatformRelease.Platform] = true
+		}
+		if len(platforms) < 2 {
+			continue
+		}
+		sort.Slice(boards, func(i, j int) bool { return boards[i].FQBN() < boards[j].FQBN() })
+		res[id] = boards
+	}
+	return res
+}
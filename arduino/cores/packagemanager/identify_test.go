@@ -0,0 +1,162 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2023 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package packagemanager
+
+import (
+	"testing"
+
+	"github.com/arduino/go-paths-helper"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConflictingUsbIDs(t *testing.T) {
+	hardwareDir := paths.New(t.TempDir())
+	createPlatform := func(packager, boards string) {
+		platformDir := hardwareDir.Join(packager, "avr")
+		require.NoError(t, platformDir.MkdirAll())
+		require.NoError(t, platformDir.Join("boards.txt").WriteFile([]byte(boards)))
+		require.NoError(t, platformDir.Join("platform.txt").WriteFile([]byte(
+			"name=Test AVR\n"+
+				"version=1.0.0\n")))
+	}
+	createPlatform("first", ""+
+		"uno.name=First Uno\n"+
+		"uno.vid.0=0x2341\n"+
+		"uno.pid.0=0x0043\n"+
+		"uno.vid.1=0x2341\n"+
+		"uno.pid.1=0x0001\n"+
+		"mega.name=First Mega\n"+
+		"mega.vid.0=0x2341\n"+
+		"mega.pid.0=0x0010\n"+
+		"mega2.name=First Mega Clone\n"+
+		"mega2.vid.0=0x2341\n"+
+		"mega2.pid.0=0x0010\n")
+	createPlatform("second", ""+
+		"clone.name=Second Uno Clone\n"+
+		"clone.upload_port.0.vid=0x2341\n"+
+		"clone.upload_port.0.pid=0x0043\n")
+
+	pmb := NewBuilder(hardwareDir, hardwareDir, hardwareDir, hardwareDir, "test")
+	require.Empty(t, pmb.LoadHardwareFromDirectory(hardwareDir))
+	pm := pmb.Build()
+	pme, release := pm.NewExplorer()
+	defer release()
+
+	conflicts := pme.ConflictingUsbIDs()
+	// 0x2341:0x0010 is claimed twice by the same platform, this is not a conflict
+	require.Len(t, conflicts, 1)
+	boards := conflicts[UsbID{VID: "0x2341", PID: "0x0043"}]
+	require.Len(t, boards, 2)
+	require.Equal(t, "first:avr:uno", boards[0].FQBN())
+	require.Equal(t, "second:avr:clone", boards[1].FQBN())
+	require.Equal(t, "0x2341:0x0043", UsbID{VID: "0x2341", PID: "0x0043"}.String())
+}
+
+func TestFindBoardsByIdentification(t *testing.T) {
+	hardwareDir := paths.New(t.TempDir())
+	platformDir := hardwareDir.Join("test", "samd")
+	require.NoError(t, platformDir.MkdirAll())
+	require.NoError(t, platformDir.Join("platform.txt").WriteFile([]byte("name=Test SAMD\nversion=1.0.0\n")))
+	require.NoError(t, platformDir.Join("boards.txt").WriteFile([]byte(""+
+		"generic.name=Generic SAMD\n"+
+		"generic.upload_port.0.vid=0x239a\n"+
+		"generic.upload_port.0.pid=0x800b\n"+
+		"special.name=Special SAMD\n"+
+		"special.upload_port.0.vid=0x239A\n"+
+		"special.upload_port.0.pid=0x800B\n"+
+		"special.upload_port.0.serialNumber=SPC-*\n"+
+		"named.name=Named SAMD\n"+
+		"named.upload_port.0.product=Named Board\n")))
+
+	pmb := NewBuilder(hardwareDir, hardwareDir, hardwareDir, hardwareDir, "test")
+	require.Empty(t, pmb.LoadHardwareFromDirectory(hardwareDir))
+	pm := pmb.Build()
+	pme, release := pm.NewExplorer()
+	defer release()
+
+	fqbns := func(props map[string]string) []string {
+		res := []string{}
+		for _, board := range pme.FindBoardsByIdentification(props) {
+			res = append(res, board.FQBN())
+		}
+		return res
+	}
+
+	// The serial number distinguishes the board from its VID/PID sibling
+	require.Equal(t, []string{"test:samd:special", "test:samd:generic"},
+		fqbns(map[string]string{"vid": "0x239a", "pid": "0x800b", "serialNumber": "spc-0042"}))
+	require.Equal(t, []string{"test:samd:generic"},
+		fqbns(map[string]string{"vid": "0x239a", "pid": "0x800b", "serialNumber": "ABC-0042"}))
+	require.Equal(t, []string{"test:samd:generic"},
+		fqbns(map[string]string{"vid": "0x239a", "pid": "0x800b"}))
+	require.Equal(t, []string{"test:samd:named"},
+		fqbns(map[string]string{"product": "Named Board", "serialNumber": "1234"}))
+	require.Empty(t, fqbns(map[string]string{}))
+}
+
+func TestFindBoardCandidatesWithVidPid(t *testing.T) {
+	hardwareDir := paths.New(t.TempDir())
+	platformDir := hardwareDir.Join("test", "avr")
+	require.NoError(t, platformDir.MkdirAll())
+	require.NoError(t, platformDir.Join("platform.txt").WriteFile([]byte("name=Test AVR\nversion=1.0.0\n")))
+	require.NoError(t, platformDir.Join("boards.txt").WriteFile([]byte(""+
+		"uno.name=Uno\n"+
+		"uno.vid.0=0x2341\n"+
+		"uno.pid.0=0x0043\n"+
+		"nano.name=Nano\n"+
+		"nano.vid.0=0x1a86\n"+
+		"nano.pid.0=0x7523\n"+
+		"clone.name=Nano Clone\n"+
+		"clone.upload_port.0.vid=0x1A86\n"+
+		"clone.upload_port.0.pid=0x7523\n"+
+		"family.name=Bootloader Family\n"+
+		"family.upload_port.0.vid=0x2341\n"+
+		"family.upload_port.0.pid=0x80*\n")))
+
+	pmb := NewBuilder(hardwareDir, hardwareDir, hardwareDir, hardwareDir, "test")
+	require.Empty(t, pmb.LoadHardwareFromDirectory(hardwareDir))
+	pme, release := pmb.Build().NewExplorer()
+	defer release()
+
+	type candidate struct {
+		FQBN       string
+		Confidence float64
+	}
+	find := func(vid, pid string) []candidate {
+		res := []candidate{}
+		for _, c := range pme.FindBoardCandidatesWithVidPid(vid, pid) {
+			res = append(res, candidate{c.Board.FQBN(), c.Confidence})
+		}
+		return res
+	}
+
+	// Exact match
+	require.Equal(t, []candidate{{"test:avr:uno", 1}}, find("0x2341", "0x0043"))
+	// Boards sharing a generic USB-serial chip
+	require.Equal(t, []candidate{{"test:avr:clone", 0.5}, {"test:avr:nano", 0.5}}, find("0x1a86", "0x7523"))
+	// Wildcard PID declared by the board
+	require.Equal(t, []candidate{{"test:avr:family", 0.75}}, find("0x2341", "0x8037"))
+	// Wildcard PID requested
+	require.Equal(t, []candidate{{"test:avr:family", 0.375}, {"test:avr:uno", 0.375}}, find("0x2341", "*"))
+	// Vendor only match
+	require.Equal(t, []candidate{{"test:avr:family", 0.125}, {"test:avr:uno", 0.125}}, find("0x2341", "0x1234"))
+	// Unknown vendor
+	require.Empty(t, find("0x0000", "0x0043"))
+
+	// FindBoardsWithVidPid returns the exact and wildcard matches
+	require.Len(t, pme.FindBoardsWithVidPid("0x2341", "0x8037"), 1)
+	require.Empty(t, pme.FindBoardsWithVidPid("0x2341", "0x1234"))
+}
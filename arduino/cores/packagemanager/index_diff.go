@@ -0,0 +1,204 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2023 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package packagemanager
+
+import (
+	"fmt"
+	"net/url"
+	"sort"
+
+	"github.com/arduino/arduino-cli/arduino/cores"
+	"github.com/arduino/arduino-cli/arduino/cores/packageindex"
+	"github.com/arduino/go-paths-helper"
+)
+
+// IndexDiff is the difference between two snapshots of the package indexes.
+// The platforms are identified as PACKAGER:ARCHITECTURE, the platform and
+// tool releases as PACKAGER:ARCHITECTURE@VERSION and PACKAGER:TOOL@VERSION.
+type IndexDiff struct {
+	NewPlatforms            []string
+	RemovedPlatforms        []string
+	NewPlatformReleases     []string
+	RemovedPlatformReleases []string
+	NewToolReleases         []string
+	RemovedToolReleases     []string
+	ChangedChecksums        []*ChecksumChange
+}
+
+// ChecksumChange is an archive, of a platform or tool release available in
+// both the snapshots, whose checksum has changed.
+type ChecksumChange struct {
+	// Release is the platform or tool release
+	Release string
+	// Host is the host of the tool flavour, empty for platforms
+	Host        string
+	OldChecksum string
+	NewChecksum string
+}
+
+// IsEmpty returns true if the snapshots have the same content
+func (diff *IndexDiff) IsEmpty() bool {
+	return len(diff.NewPlatforms) == 0 && len(diff.RemovedPlatforms) == 0 &&
+		len(diff.NewPlatformReleases) == 0 && len(diff.RemovedPlatformReleases) == 0 &&
+		len(diff.NewToolReleases) == 0 && len(diff.RemovedToolReleases) == 0 &&
+		len(diff.ChangedChecksums) == 0
+}
+
+// IndexSnapshot reads, as they are on disk now, the package indexes with the
+// given URLs into a new set of packages, not bound to the PackageManager and
+// not including the installed platforms and tools. The snapshots taken before
+// and after an update of the indexes can be compared with DiffIndexSnapshots.
+// The indexes not downloaded yet, or that can't be read, are skipped.
+func (pme *Explorer) IndexSnapshot(URLs []*url.URL) cores.Packages {
+	packages := cores.NewPackages()
+	for _, URL := range URLs {
+		indexPath := paths.New(URL.Path)
+		if URL.Scheme != "file" {
+			var err error
+			if indexPath, err = localPackageIndexPath(pme.IndexDir, URL); err != nil {
+				continue
+			}
+		}
+		if indexPath.NotExist() {
+			continue
+		}
+		index, err := packageindex.LoadIndexNoSign(indexPath, pme.maxIndexSize)
+		if err != nil {
+			pme.log.WithError(err).WithField("index", indexPath).Warn("Cannot read package index for snapshot")
+			continue
+		}
+		index.MergeIntoPackages(packages)
+	}
+	return packages
+}
+
+// DiffIndexSnapshots returns the changes from the before to the after
+// snapshot of the package indexes, sorted by name.
+func DiffIndexSnapshots(before, after cores.Packages) *IndexDiff {
+	diff := &IndexDiff{}
+	beforePlatforms := indexedPlatformReleases(before)
+	afterPlatforms := indexedPlatformReleases(after)
+	for id, releases := range afterPlatforms {
+		if _, ok := beforePlatforms[id]; !ok {
+			diff.NewPlatforms = append(diff.NewPlatforms, id)
+		}
+		for version, release := range releases {
+			oldRelease, ok := beforePlatforms[id][version]
+			if !ok {
+				diff.NewPlatformReleases = append(diff.NewPlatformReleases, release.String())
+				continue
+			}
+			if oldRelease.Resource.Checksum != release.Resource.Checksum {
+				diff.ChangedChecksums = append(diff.ChangedChecksums, &ChecksumChange{
+					Release:     release.String(),
+					OldChecksum: oldRelease.Resource.Checksum,
+					NewChecksum: release.Resource.Checksum,
+				})
+			}
+		}
+	}
+	for id, releases := range beforePlatforms {
+		if _, ok := afterPlatforms[id]; !ok {
+			diff.RemovedPlatforms = append(diff.RemovedPlatforms, id)
+		}
+		for version, release := range releases {
+			if _, ok := afterPlatforms[id][version]; !ok {
+				diff.RemovedPlatformReleases = append(diff.RemovedPlatformReleases, release.String())
+			}
+		}
+	}
+
+	beforeTools := indexedToolReleases(before)
+	afterTools := indexedToolReleases(after)
+	for id, release := range afterTools {
+		oldRelease, ok := beforeTools[id]
+		if !ok {
+			diff.NewToolReleases = append(diff.NewToolReleases, id)
+			continue
+		}
+		oldChecksums := map[string]string{}
+		for _, flavour := range oldRelease.Flavors {
+			oldChecksums[flavour.OS] = flavour.Resource.Checksum
+		}
+		for _, flavour := range release.Flavors {
+			if oldChecksum, ok := oldChecksums[flavour.OS]; ok && oldChecksum != flavour.Resource.Checksum {
+				diff.ChangedChecksums = append(diff.ChangedChecksums, &ChecksumChange{
+					Release:     id,
+					Host:        flavour.OS,
+					OldChecksum: oldChecksum,
+					NewChecksum: flavour.Resource.Checksum,
+				})
+			}
+		}
+	}
+	for id := range beforeTools {
+		if _, ok := afterTools[id]; !ok {
+			diff.RemovedToolReleases = append(diff.RemovedToolReleases, id)
+		}
+	}
+
+	for _, list := range [][]string{
+		diff.NewPlatforms, diff.RemovedPlatforms,
+		diff.NewPlatformReleases, diff.RemovedPlatformReleases,
+		diff.NewToolReleases, diff.RemovedToolReleases,
+	} {
+		sort.Strings(list)
+	}
+	sort.Slice(diff.ChangedChecksums, func(i, j int) bool {
+		a, b := diff.ChangedChecksums[i], diff.ChangedChecksums[j]
+		if a.Release != b.Release {
+			return a.Release < b.Release
+		}
+		return a.Host < b.Host
+	})
+	return diff
+}
+
+// indexedPlatformReleases returns the platform releases with a downloadable
+// archive, by platform id and version
+func indexedPlatformReleases(packages cores.Packages) map[string]map[string]*cores.PlatformRelease {
+	res := map[string]map[string]*cores.PlatformRelease{}
+	for _, targetPackage := range packages {
+		for _, platform := range targetPackage.Platforms {
+			for _, release := range platform.Releases {
+				if release.Resource == nil {
+					continue
+				}
+				id := fmt.Sprintf("%s:%s", targetPackage.Name, platform.Architecture)
+				if res[id] == nil {
+					res[id] = map[string]*cores.PlatformRelease{}
+				}
+				res[id][release.Version.String()] = release
+			}
+		}
+	}
+	return res
+}
+
+// indexedToolReleases returns the tool releases with at least a flavour, by release id
+func indexedToolReleases(packages cores.Packages) map[string]*cores.ToolRelease {
+	res := map[string]*cores.ToolRelease{}
+	for _, targetPackage := range packages {
+		for _, tool := range targetPackage.Tools {
+			for _, release := range tool.Releases {
+				if len(release.Flavors) > 0 {
+					res[release.String()] = release
+				}
+			}
+		}
+	}
+	return res
+}
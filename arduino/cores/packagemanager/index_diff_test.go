@@ -0,0 +1,83 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2023 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package packagemanager
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/arduino/go-paths-helper"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDiffIndexSnapshots(t *testing.T) {
+	tmp := paths.New(t.TempDir())
+	platform := func(arch, version, checksum string) string {
+		return fmt.Sprintf(`{"name": "Test", "architecture": "%[1]s", "version": "%[2]s", "category": "Test",
+			"url": "http://example.com/%[1]s-%[2]s.zip", "archiveFileName": "%[1]s-%[2]s.zip", "checksum": "%[3]s", "size": "7",
+			"boards": [], "toolsDependencies": []}`, arch, version, checksum)
+	}
+	tool := func(version, checksum string) string {
+		return fmt.Sprintf(`{"name": "gcc", "version": "%[1]s", "systems": [{"host": "x86_64-linux-gnu", "url": "http://example.com/gcc-%[1]s.tar.bz2",
+			"archiveFileName": "gcc-%[1]s.tar.bz2", "checksum": "%[2]s", "size": "7"}]}`, version, checksum)
+	}
+	writeIndex := func(platforms, tools []string) {
+		require.NoError(t, tmp.Join("package_test_index.json").WriteFile([]byte(`{"packages": [{"name": "test",
+			"platforms": [`+strings.Join(platforms, ",")+`], "tools": [`+strings.Join(tools, ",")+`]}]}`)))
+	}
+
+	pmb := NewBuilder(tmp, tmp.Join("packages"), nil, nil, "test")
+	pme, release := pmb.Build().NewExplorer()
+	defer release()
+	indexURL, err := url.Parse("https://example.com/package_test_index.json")
+	require.NoError(t, err)
+	missingURL, err := url.Parse("https://example.com/package_missing_index.json")
+	require.NoError(t, err)
+	urls := []*url.URL{indexURL, missingURL}
+
+	// The index not downloaded yet is empty
+	before := pme.IndexSnapshot(urls)
+	require.Empty(t, before)
+
+	writeIndex(
+		[]string{platform("avr", "1.0.0", "SHA-256:01"), platform("samd", "1.0.0", "SHA-256:02")},
+		[]string{tool("1.0.0", "SHA-256:03")})
+	after := pme.IndexSnapshot(urls)
+	diff := DiffIndexSnapshots(before, after)
+	require.Equal(t, []string{"test:avr", "test:samd"}, diff.NewPlatforms)
+	require.Equal(t, []string{"test:avr@1.0.0", "test:samd@1.0.0"}, diff.NewPlatformReleases)
+	require.Equal(t, []string{"test:gcc@1.0.0"}, diff.NewToolReleases)
+	require.True(t, DiffIndexSnapshots(after, pme.IndexSnapshot(urls)).IsEmpty())
+
+	before = after
+	writeIndex(
+		[]string{platform("avr", "1.0.0", "SHA-256:11"), platform("avr", "1.1.0", "SHA-256:12")},
+		[]string{tool("1.0.0", "SHA-256:13"), tool("2.0.0", "SHA-256:14")})
+	diff = DiffIndexSnapshots(before, pme.IndexSnapshot(urls))
+	require.False(t, diff.IsEmpty())
+	require.Empty(t, diff.NewPlatforms)
+	require.Equal(t, []string{"test:samd"}, diff.RemovedPlatforms)
+	require.Equal(t, []string{"test:avr@1.1.0"}, diff.NewPlatformReleases)
+	require.Equal(t, []string{"test:samd@1.0.0"}, diff.RemovedPlatformReleases)
+	require.Equal(t, []string{"test:gcc@2.0.0"}, diff.NewToolReleases)
+	require.Empty(t, diff.RemovedToolReleases)
+	require.Equal(t, []*ChecksumChange{
+		{Release: "test:avr@1.0.0", OldChecksum: "SHA-256:01", NewChecksum: "SHA-256:11"},
+		{Release: "test:gcc@1.0.0", Host: "x86_64-linux-gnu", OldChecksum: "SHA-256:03", NewChecksum: "SHA-256:13"},
+	}, diff.ChangedChecksums)
+}
@@ -0,0 +1,146 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2023 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package packagemanager
+
+import (
+	"context"
+	"errors"
+	"io/fs"
+	"path/filepath"
+	"strings"
+
+	"github.com/arduino/arduino-cli/arduino"
+	rpc "github.com/arduino/arduino-cli/rpc/cc/arduino/cli/commands/v1"
+	"github.com/arduino/go-paths-helper"
+	semver "go.bug.st/relaxed-semver"
+	"gopkg.in/src-d/go-git.v4"
+	"gopkg.in/src-d/go-git.v4/plumbing"
+)
+
+// InstallGitPlatform clones the platform hosted on the given git repository and
+// installs it as the packager:architecture platform, see InstallLocalPlatform.
+// A branch, a tag or a commit may be selected by adding it as the URL fragment
+// (ex: https://github.com/vendor/core.git#v2.1.0), otherwise the default branch
+// is used. Branches and tags are cloned shallowly, the submodules are cloned too.
+func (pme *Explorer) InstallGitPlatform(ctx context.Context, packager, architecture, gitURL string, overwrite bool, taskCB rpc.TaskProgressCB, skipPostInstall bool) (*semver.Version, error) {
+	repoURL, ref, _ := strings.Cut(gitURL, "#")
+	if repoURL == "" {
+		return nil, &arduino.InvalidURLError{Cause: errors.New(tr("invalid git url"))}
+	}
+
+	if err := pme.tempDir.MkdirAll(); err != nil {
+		return nil, &arduino.TempDirCreationFailedError{Cause: err}
+	}
+	tmpDir, err := paths.MkTempDir(pme.tempDir.String(), "git-platform")
+	if err != nil {
+		return nil, &arduino.TempDirCreationFailedError{Cause: err}
+	}
+	defer tmpDir.RemoveAll()
+	repoDir := tmpDir.Join("platform")
+
+	taskCB(&rpc.TaskProgress{Name: tr("Cloning %s", gitURL)})
+	if err := cloneGitPlatform(ctx, repoURL, ref, repoDir); err != nil {
+		return nil, &arduino.FailedInstallError{Message: tr("Error cloning %s", gitURL), Cause: err}
+	}
+
+	// We don't want the installed platform to be a git repository
+	if err := removeGitMetadata(repoDir); err != nil {
+		return nil, &arduino.FailedInstallError{Message: tr("Error cloning %s", gitURL), Cause: err}
+	}
+	return pme.InstallLocalPlatform(ctx, packager, architecture, repoDir, overwrite, taskCB, skipPostInstall)
+}
+
+// cloneGitPlatform clones the given ref (or the default branch if empty) of the
+// repository, and its submodules, in destDir.
+func cloneGitPlatform(ctx context.Context, repoURL, ref string, destDir *paths.Path) error {
+	opts := &git.CloneOptions{
+		URL:               repoURL,
+		Depth:             1,
+		RecurseSubmodules: git.DefaultSubmoduleRecursionDepth,
+	}
+	if ref == "" {
+		_, err := git.PlainCloneContext(ctx, destDir.String(), false, opts)
+		return err
+	}
+
+	// Try a shallow clone of the ref as a tag and then as a branch...
+	opts.SingleBranch = true
+	for _, refName := range []plumbing.ReferenceName{plumbing.NewTagReferenceName(ref), plumbing.NewBranchReferenceName(ref)} {
+		opts.ReferenceName = refName
+		if _, err := git.PlainCloneContext(ctx, destDir.String(), false, opts); err == nil {
+			return nil
+		}
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if err := destDir.RemoveAll(); err != nil {
+			return err
+		}
+	}
+
+	// ...otherwise it should be a commit, that requires the full history
+	repo, err := git.PlainCloneContext(ctx, destDir.String(), false, &git.CloneOptions{URL: repoURL})
+	if err != nil {
+		return err
+	}
+	hash, err := repo.ResolveRevision(plumbing.Revision(ref))
+	if err != nil {
+		return err
+	}
+	worktree, err := repo.Worktree()
+	if err != nil {
+		return err
+	}
+	if err := worktree.Checkout(&git.CheckoutOptions{Hash: *hash}); err != nil {
+		return err
+	}
+	submodules, err := worktree.Submodules()
+	if err != nil {
+		return err
+	}
+	return submodules.UpdateContext(ctx, &git.SubmoduleUpdateOptions{
+		Init:              true,
+		RecurseSubmodules: git.DefaultSubmoduleRecursionDepth,
+	})
+}
+
+// removeGitMetadata removes the .git directories (or files, in case of
+// submodules) from the given directory tree.
+func removeGitMetadata(dir *paths.Path) error {
+	gitMetadata := paths.PathList{}
+	err := filepath.WalkDir(dir.String(), func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.Name() != ".git" {
+			return nil
+		}
+		gitMetadata.Add(paths.New(path))
+		if d.IsDir() {
+			return filepath.SkipDir
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	for _, path := range gitMetadata {
+		if err := path.RemoveAll(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,83 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2023 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package packagemanager
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	rpc "github.com/arduino/arduino-cli/rpc/cc/arduino/cli/commands/v1"
+	"github.com/arduino/go-paths-helper"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/src-d/go-git.v4"
+	"gopkg.in/src-d/go-git.v4/plumbing"
+	"gopkg.in/src-d/go-git.v4/plumbing/object"
+)
+
+func TestInstallGitPlatform(t *testing.T) {
+	tmp := paths.New(t.TempDir())
+	packagesDir := tmp.Join("packages")
+	taskCB := func(*rpc.TaskProgress) {}
+
+	// Prepare a git repository with two versions of a platform
+	repoDir := tmp.Join("repo")
+	repo, err := git.PlainInit(repoDir.String(), false)
+	require.NoError(t, err)
+	worktree, err := repo.Worktree()
+	require.NoError(t, err)
+	commitVersion := func(version string) plumbing.Hash {
+		require.NoError(t, repoDir.Join("boards.txt").WriteFile([]byte("myboard.name=My Board\n")))
+		require.NoError(t, repoDir.Join("platform.txt").WriteFile([]byte("name=My Platform\nversion="+version+"\n")))
+		_, err := worktree.Add(".")
+		require.NoError(t, err)
+		hash, err := worktree.Commit("Release "+version, &git.CommitOptions{
+			Author: &object.Signature{Name: "Test", Email: "test@example.com", When: time.Now()},
+		})
+		require.NoError(t, err)
+		return hash
+	}
+	firstCommit := commitVersion("2.1.0")
+	_, err = repo.CreateTag("v2.1.0", firstCommit, nil)
+	require.NoError(t, err)
+	commitVersion("3.0.0")
+
+	pm := NewBuilder(tmp, packagesDir, tmp.Join("staging"), tmp.Join("tmp"), "test").Build()
+	pme, release := pm.NewExplorer()
+	defer release()
+
+	// The default branch
+	version, err := pme.InstallGitPlatform(context.Background(), "vendor", "arch", repoDir.String(), false, taskCB, true)
+	require.NoError(t, err)
+	require.Equal(t, "3.0.0+local", version.String())
+	installDir := packagesDir.Join("vendor", "hardware", "arch", "3.0.0+local")
+	require.True(t, installDir.Join("boards.txt").Exist())
+	require.False(t, installDir.Join(".git").Exist())
+
+	// A tag
+	version, err = pme.InstallGitPlatform(context.Background(), "vendor", "arch", repoDir.String()+"#v2.1.0", false, taskCB, true)
+	require.NoError(t, err)
+	require.Equal(t, "2.1.0+local", version.String())
+
+	// A commit
+	version, err = pme.InstallGitPlatform(context.Background(), "vendor", "arch", repoDir.String()+"#"+firstCommit.String(), true, taskCB, true)
+	require.NoError(t, err)
+	require.Equal(t, "2.1.0+local", version.String())
+
+	// A missing ref
+	_, err = pme.InstallGitPlatform(context.Background(), "vendor", "arch", repoDir.String()+"#missing", true, taskCB, true)
+	require.Error(t, err)
+}
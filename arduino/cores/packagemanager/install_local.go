@@ -0,0 +1,151 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2023 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package packagemanager
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/arduino/arduino-cli/arduino"
+	rpc "github.com/arduino/arduino-cli/rpc/cc/arduino/cli/commands/v1"
+	"github.com/arduino/go-paths-helper"
+	"github.com/arduino/go-properties-orderedmap"
+	"github.com/codeclysm/extract/v3"
+	semver "go.bug.st/relaxed-semver"
+)
+
+// LocalPlatformBuildMetadata is the build metadata added to the version of the
+// platforms installed from a local directory or archive.
+const LocalPlatformBuildMetadata = "local"
+
+// InstallLocalPlatform installs the platform contained in the given directory
+// or archive (.zip, .tar.bz2, .tar.gz, .tar.xz, .tar.zst...) as the packager:architecture platform.
+// The platform is copied in the packages directory with a synthetic version,
+// made of the version in its platform.txt (or 0.0.0 if missing) plus the
+// LocalPlatformBuildMetadata, so it's loaded as any other installed platform
+// when the PackageManager is reloaded. An installed platform with the same
+// version is replaced only if overwrite is true. The version of the installed
+// platform is returned.
+func (pme *Explorer) InstallLocalPlatform(ctx context.Context, packager, architecture string, source *paths.Path, overwrite bool, taskCB rpc.TaskProgressCB, skipPostInstall bool) (*semver.Version, error) {
+	for _, name := range []string{packager, architecture} {
+		if name == "" || name == "." || name == ".." || strings.ContainsAny(name, `/\:`) {
+			return nil, &arduino.InvalidArgumentError{Message: tr("Invalid platform name: %s", packager+":"+architecture)}
+		}
+	}
+	if source.NotExist() {
+		return nil, &arduino.NotFoundError{Message: tr("Path %s not found", source)}
+	}
+
+	platformDir := source
+	if !source.IsDir() {
+		if err := pme.tempDir.MkdirAll(); err != nil {
+			return nil, &arduino.TempDirCreationFailedError{Cause: err}
+		}
+		tmpDir, err := paths.MkTempDir(pme.tempDir.String(), "local-platform")
+		if err != nil {
+			return nil, &arduino.TempDirCreationFailedError{Cause: err}
+		}
+		defer tmpDir.RemoveAll()
+		if platformDir, err = extractLocalPlatform(ctx, source, tmpDir); err != nil {
+			return nil, &arduino.FailedInstallError{Message: tr("Error extracting %s", source), Cause: err}
+		}
+	}
+	if !platformDir.Join("boards.txt").Exist() {
+		return nil, &arduino.FailedInstallError{Message: tr("Invalid platform %s", source), Cause: errors.New(tr("boards.txt not found"))}
+	}
+
+	version, err := localPlatformVersion(platformDir)
+	if err != nil {
+		return nil, &arduino.InvalidVersionError{Cause: err}
+	}
+	platformID := packager + ":" + architecture + "@" + version.String()
+	installDir := pme.PackagesDir.Join(packager, "hardware", architecture, version.String())
+	if installDir.Exist() {
+		if !overwrite {
+			return nil, &arduino.FailedInstallError{Message: tr("Platform %s already installed", platformID)}
+		}
+		if err := installDir.RemoveAll(); err != nil {
+			return nil, &arduino.FailedInstallError{Message: tr("Cannot remove %s", installDir), Cause: err}
+		}
+	}
+
+	taskCB(&rpc.TaskProgress{Name: tr("Installing platform %s", platformID)})
+	if err := installDir.Parent().MkdirAll(); err != nil {
+		return nil, &arduino.PermissionDeniedError{Message: tr("Cannot create directory %s", installDir.Parent()), Cause: err}
+	}
+	if err := platformDir.CopyDirTo(installDir); err != nil {
+		_ = installDir.RemoveAll()
+		return nil, &arduino.FailedInstallError{Message: tr("Cannot install platform"), Cause: err}
+	}
+	pme.eventBus.emit(&Event{Kind: EventInstalled, Subject: platformID})
+
+	if !pme.SkipScripts(skipPostInstall, packager+":"+architecture, taskCB) {
+		taskCB(&rpc.TaskProgress{Message: tr("Configuring platform.")})
+		stdout, stderr, err := pme.RunPreOrPostScript(installDir, "post_install")
+		skipEmptyMessageTaskProgressCB(taskCB)(&rpc.TaskProgress{Message: string(stdout), Completed: true})
+		skipEmptyMessageTaskProgressCB(taskCB)(&rpc.TaskProgress{Message: string(stderr), Completed: true})
+		if err != nil {
+			taskCB(&rpc.TaskProgress{Message: tr("WARNING cannot configure platform: %s", err), Completed: true})
+		}
+	}
+
+	taskCB(&rpc.TaskProgress{Message: tr("Platform %s installed", platformID), Completed: true})
+	return version, nil
+}
+
+// extractLocalPlatform extracts the given archive in destDir and returns the
+// root directory of the platform: the only directory in the archive top level,
+// or destDir itself if the platform files are directly in the top level.
+func extractLocalPlatform(ctx context.Context, archive, destDir *paths.Path) (*paths.Path, error) {
+	file, err := archive.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	if err := extract.Archive(ctx, file, destDir.String(), nil); err != nil {
+		return nil, err
+	}
+
+	files, err := destDir.ReadDir()
+	if err != nil {
+		return nil, err
+	}
+	files.FilterOutPrefix("__MACOSX") // Ignores metadata from Mac OS X
+	if len(files) == 1 && files[0].IsDir() {
+		return files[0], nil
+	}
+	return destDir, nil
+}
+
+// localPlatformVersion returns the synthetic version of the platform in the given directory
+func localPlatformVersion(platformDir *paths.Path) (*semver.Version, error) {
+	platformTxt, err := properties.SafeLoad(platformDir.Join("platform.txt").String())
+	if err != nil {
+		return nil, err
+	}
+	versionString := platformTxt.ExpandPropsInString(platformTxt.Get("version"))
+	if versionString == "" {
+		versionString = "0.0.0"
+	}
+	version, err := semver.Parse(versionString)
+	if err != nil {
+		return nil, err
+	}
+	// Drop any build metadata of the declared version
+	versionString, _, _ = strings.Cut(version.String(), "+")
+	return semver.Parse(versionString + "+" + LocalPlatformBuildMetadata)
+}
@@ -0,0 +1,94 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2023 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package packagemanager
+
+import (
+	"archive/zip"
+	"context"
+	"testing"
+
+	"github.com/arduino/arduino-cli/arduino/cores"
+	rpc "github.com/arduino/arduino-cli/rpc/cc/arduino/cli/commands/v1"
+	"github.com/arduino/go-paths-helper"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInstallLocalPlatform(t *testing.T) {
+	tmp := paths.New(t.TempDir())
+	packagesDir := tmp.Join("packages")
+	taskCB := func(*rpc.TaskProgress) {}
+
+	// Prepare a platform in a local directory and in a zip archive
+	platformDir := tmp.Join("myplatform")
+	require.NoError(t, platformDir.MkdirAll())
+	require.NoError(t, platformDir.Join("boards.txt").WriteFile([]byte("myboard.name=My Board\nmyboard.build.core=mycore\n")))
+	require.NoError(t, platformDir.Join("platform.txt").WriteFile([]byte("name=My Platform\nversion=1.2.3\n")))
+	archive := tmp.Join("myplatform.zip")
+	archiveFile, err := archive.Create()
+	require.NoError(t, err)
+	w := zip.NewWriter(archiveFile)
+	for _, name := range []string{"boards.txt", "platform.txt"} {
+		f, err := w.Create("myplatform-main/" + name)
+		require.NoError(t, err)
+		data, err := platformDir.Join(name).ReadFile()
+		require.NoError(t, err)
+		_, err = f.Write(data)
+		require.NoError(t, err)
+	}
+	require.NoError(t, w.Close())
+	require.NoError(t, archiveFile.Close())
+
+	pm := NewBuilder(tmp, packagesDir, tmp.Join("staging"), tmp.Join("tmp"), "test").Build()
+	pme, release := pm.NewExplorer()
+	defer release()
+
+	// Install from the directory
+	version, err := pme.InstallLocalPlatform(context.Background(), "local", "myarch", platformDir, false, taskCB, true)
+	require.NoError(t, err)
+	require.Equal(t, "1.2.3+local", version.String())
+	require.True(t, packagesDir.Join("local", "hardware", "myarch", "1.2.3+local", "boards.txt").Exist())
+
+	// Installing again the same version is allowed only when overwriting
+	_, err = pme.InstallLocalPlatform(context.Background(), "local", "myarch", archive, false, taskCB, true)
+	require.Error(t, err)
+	_, err = pme.InstallLocalPlatform(context.Background(), "local", "myarch", archive, true, taskCB, true)
+	require.NoError(t, err)
+
+	// Install from the archive with another name
+	version, err = pme.InstallLocalPlatform(context.Background(), "local", "other", archive, false, taskCB, true)
+	require.NoError(t, err)
+	require.Equal(t, "1.2.3+local", version.String())
+
+	// The installed platforms are loaded as any other platform
+	pmb := NewBuilder(tmp, packagesDir, tmp.Join("staging"), tmp.Join("tmp"), "test")
+	require.Empty(t, pmb.LoadHardwareFromDirectory(packagesDir))
+	pme2, release2 := pmb.Build().NewExplorer()
+	defer release2()
+	fqbn, err := cores.ParseFQBN("local:myarch:myboard")
+	require.NoError(t, err)
+	_, platformRelease, board, _, _, err := pme2.ResolveFQBN(fqbn)
+	require.NoError(t, err)
+	require.Equal(t, "My Board", board.Name())
+	require.Equal(t, "1.2.3+local", platformRelease.Version.String())
+
+	// Invalid sources and names are refused
+	_, err = pme.InstallLocalPlatform(context.Background(), "local", "myarch", tmp.Join("missing"), true, taskCB, true)
+	require.Error(t, err)
+	_, err = pme.InstallLocalPlatform(context.Background(), "local", "myarch", tmp.Join("staging"), true, taskCB, true)
+	require.Error(t, err)
+	_, err = pme.InstallLocalPlatform(context.Background(), "..", "myarch", platformDir, true, taskCB, true)
+	require.Error(t, err)
+}
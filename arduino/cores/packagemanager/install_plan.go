@@ -0,0 +1,142 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2023 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package packagemanager
+
+import (
+	"errors"
+
+	"github.com/arduino/arduino-cli/arduino"
+	"github.com/arduino/arduino-cli/arduino/cores"
+	"github.com/arduino/arduino-cli/arduino/resources"
+	"github.com/arduino/go-paths-helper"
+)
+
+// InstallPlan describes what DownloadAndInstallPlatformAndTools would do to
+// install a platform release, it's computed without downloading anything.
+type InstallPlan struct {
+	// Platform is the platform release to install, nil if already installed
+	Platform *PlannedRelease
+	// ReplacedPlatform is the installed release of the platform that would be
+	// replaced, empty if the platform is not installed
+	ReplacedPlatform string
+	// Tools are the required tools that would be installed, empty if the
+	// platform is already installed
+	Tools []*PlannedRelease
+	// InstalledTools are the required tools that are already installed
+	InstalledTools []string
+	// DownloadSize is the number of bytes to download, the archives already
+	// downloaded are excluded
+	DownloadSize int64
+	// DiskSpace is the disk space needed by the downloaded archives and by the
+	// installed files
+	DiskSpace int64
+	// DiskSpaceIsEstimate is true if the size of the installed files is not
+	// known for some archive, because not yet downloaded: in this case the size
+	// of the archive is counted in its place, so DiskSpace is a lower bound
+	DiskSpaceIsEstimate bool
+}
+
+// PlannedRelease is a platform or tool release that would be installed
+type PlannedRelease struct {
+	// Release is the platform (PACKAGER:ARCH@VERSION) or tool
+	// (PACKAGER:TOOL@VERSION) release
+	Release string
+	// URL is the URL of the archive
+	URL string
+	// Size is the size of the archive
+	Size int64
+	// Cached is true if the archive is already in the download directory
+	Cached bool
+	// InstalledSize is the size of the installed files, it's known only if
+	// the archive is cached
+	InstalledSize int64
+}
+
+// PlanPlatformInstall resolves what installing the given platform release,
+// and the tools it requires, would download and install. Nothing is downloaded
+// or changed on disk. If the platform release is already installed the plan is
+// empty, since the installation doesn't touch the required tools either.
+func (pme *Explorer) PlanPlatformInstall(platformRelease *cores.PlatformRelease, requiredTools []*cores.ToolRelease) (*InstallPlan, error) {
+	plan := &InstallPlan{
+		Tools:          []*PlannedRelease{},
+		InstalledTools: []string{},
+	}
+	if platformRelease.IsInstalled() {
+		return plan, nil
+	}
+	for _, tool := range requiredTools {
+		if tool.IsInstalled() {
+			plan.InstalledTools = append(plan.InstalledTools, tool.String())
+			continue
+		}
+		resource := tool.GetCompatibleFlavour()
+		if resource == nil {
+			return nil, &arduino.FailedDownloadError{
+				Message: tr("Error downloading tool %s", tool),
+				Cause:   errors.New(tr("no versions available for the current OS, try contacting %s", tool.Tool.Package.Email))}
+		}
+		destDir := pme.PackagesDir.Join(tool.Tool.Package.Name, "tools", tool.Tool.Name, tool.Version.String())
+		plan.Tools = append(plan.Tools, pme.planRelease(plan, tool.String(), resource, destDir))
+	}
+
+	if platformRelease.Resource == nil {
+		return nil, &arduino.PlatformNotFoundError{Platform: platformRelease.String()}
+	}
+	if installed := pme.GetInstalledPlatformRelease(platformRelease.Platform); installed != nil {
+		plan.ReplacedPlatform = installed.String()
+	}
+	plan.Platform = pme.planRelease(plan, platformRelease.String(), platformRelease.Resource, pme.platformReleaseInstallDir(platformRelease))
+	return plan, nil
+}
+
+// planRelease adds to the plan the sizes of the given release, that would be
+// installed in destDir.
+func (pme *Explorer) planRelease(plan *InstallPlan, release string, resource *resources.DownloadResource, destDir *paths.Path) *PlannedRelease {
+	res := &PlannedRelease{
+		Release: release,
+		URL:     resource.URL,
+		Size:    resource.Size,
+	}
+	// The archive path is computed here, since resource.ArchivePath creates
+	// the download directory
+	if pme.DownloadDir.Join(resource.CachePath, resource.ArchiveFileName).Exist() {
+		if ok, err := resource.TestLocalArchiveIntegrity(pme.DownloadDir); err == nil && ok {
+			res.Cached = true
+		}
+	}
+	if !res.Cached {
+		// The archive is stored in the download directory and the installed
+		// files are at least as big as the archive
+		plan.DownloadSize += res.Size
+		plan.DiskSpace += 2 * res.Size
+		plan.DiskSpaceIsEstimate = true
+		return res
+	}
+	entries, err := resource.ListArchiveContents(pme.DownloadDir, destDir)
+	if err != nil {
+		pme.log.WithError(err).WithField("release", release).Warn("Cannot read archive contents")
+		plan.DiskSpace += res.Size
+		plan.DiskSpaceIsEstimate = true
+		return res
+	}
+	for _, entry := range entries {
+		if !entry.IsDir {
+			res.InstalledSize += entry.Size
+		}
+	}
+	plan.DiskSpace += res.InstalledSize
+	return res
+}
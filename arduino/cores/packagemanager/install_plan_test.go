@@ -0,0 +1,102 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2023 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package packagemanager
+
+import (
+	"archive/zip"
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"testing"
+
+	"github.com/arduino/arduino-cli/arduino/cores"
+	"github.com/arduino/arduino-cli/arduino/resources"
+	"github.com/arduino/go-paths-helper"
+	"github.com/stretchr/testify/require"
+	semver "go.bug.st/relaxed-semver"
+)
+
+func TestPlanPlatformInstall(t *testing.T) {
+	tmp := paths.New(t.TempDir())
+	pmb := NewBuilder(tmp, tmp.Join("packages"), tmp.Join("staging"), tmp.Join("tmp"), "test")
+	pack := pmb.GetOrCreatePackage("test")
+
+	// The archive of gdb is already downloaded
+	content := bytes.Repeat([]byte("gdb"), 100)
+	archive := &bytes.Buffer{}
+	zw := zip.NewWriter(archive)
+	w, err := zw.Create("gdb/bin/gdb")
+	require.NoError(t, err)
+	_, err = w.Write(content)
+	require.NoError(t, err)
+	require.NoError(t, zw.Close())
+	require.NoError(t, tmp.Join("staging", "packages").MkdirAll())
+	require.NoError(t, tmp.Join("staging", "packages", "gdb.zip").WriteFile(archive.Bytes()))
+	checksum := sha256.Sum256(archive.Bytes())
+
+	tool := func(name string, resource *resources.DownloadResource) *cores.ToolRelease {
+		release := pack.GetOrCreateTool(name).GetOrCreateRelease(semver.ParseRelaxed("1.0.0"))
+		release.Flavors = []*cores.Flavor{{OS: "all", Resource: resource}}
+		return release
+	}
+	gcc := tool("gcc", &resources.DownloadResource{URL: "https://example.com/gcc.zip", ArchiveFileName: "gcc.zip", Size: 3000, CachePath: "packages"})
+	gcc.InstallDir = tmp.Join("packages", "test", "tools", "gcc", "1.0.0")
+	gdb := tool("gdb", &resources.DownloadResource{
+		URL:             "https://example.com/gdb.zip",
+		ArchiveFileName: "gdb.zip",
+		Checksum:        "SHA-256:" + hex.EncodeToString(checksum[:]),
+		Size:            int64(archive.Len()),
+		CachePath:       "packages",
+	})
+	openocd := tool("openocd", &resources.DownloadResource{URL: "https://example.com/openocd.zip", ArchiveFileName: "openocd.zip", Size: 5000, CachePath: "packages"})
+
+	platform := pack.GetOrCreatePlatform("avr")
+	installed := platform.GetOrCreateRelease(semver.MustParse("1.0.0"))
+	installed.InstallDir = tmp.Join("packages", "test", "hardware", "avr", "1.0.0")
+	upgrade := platform.GetOrCreateRelease(semver.MustParse("2.0.0"))
+	upgrade.Resource = &resources.DownloadResource{URL: "https://example.com/avr.zip", ArchiveFileName: "avr.zip", Size: 1000, CachePath: "packages"}
+
+	pme, release := pmb.Build().NewExplorer()
+	defer release()
+
+	plan, err := pme.PlanPlatformInstall(upgrade, []*cores.ToolRelease{gcc, gdb, openocd})
+	require.NoError(t, err)
+	require.Equal(t, &PlannedRelease{Release: "test:avr@2.0.0", URL: "https://example.com/avr.zip", Size: 1000}, plan.Platform)
+	require.Equal(t, "test:avr@1.0.0", plan.ReplacedPlatform)
+	require.Equal(t, []string{"test:gcc@1.0.0"}, plan.InstalledTools)
+	require.Equal(t, []*PlannedRelease{
+		{Release: "test:gdb@1.0.0", URL: "https://example.com/gdb.zip", Size: int64(archive.Len()), Cached: true, InstalledSize: int64(len(content))},
+		{Release: "test:openocd@1.0.0", URL: "https://example.com/openocd.zip", Size: 5000},
+	}, plan.Tools)
+	require.Equal(t, int64(6000), plan.DownloadSize)
+	require.Equal(t, int64(12000+len(content)), plan.DiskSpace)
+	require.True(t, plan.DiskSpaceIsEstimate)
+
+	// Nothing is downloaded or installed
+	require.NoFileExists(t, tmp.Join("staging", "packages", "avr.zip").String())
+	require.NoDirExists(t, tmp.Join("packages", "test", "hardware", "avr", "2.0.0").String())
+
+	// An installed platform requires nothing, even if some tool is missing,
+	// since its installation doesn't install the tools
+	plan, err = pme.PlanPlatformInstall(installed, []*cores.ToolRelease{gcc, openocd})
+	require.NoError(t, err)
+	require.Nil(t, plan.Platform)
+	require.Empty(t, plan.Tools)
+	require.Empty(t, plan.InstalledTools)
+	require.Zero(t, plan.DownloadSize)
+	require.Zero(t, plan.DiskSpace)
+	require.False(t, plan.DiskSpaceIsEstimate)
+}
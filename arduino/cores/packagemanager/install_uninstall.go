@@ -16,7 +16,7 @@
 package packagemanager
 
 import (
-	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"runtime"
@@ -24,6 +24,7 @@ import (
 	"github.com/arduino/arduino-cli/arduino"
 	"github.com/arduino/arduino-cli/arduino/cores"
 	"github.com/arduino/arduino-cli/arduino/cores/packageindex"
+	"github.com/arduino/arduino-cli/arduino/resources"
 	"github.com/arduino/arduino-cli/executils"
 	rpc "github.com/arduino/arduino-cli/rpc/cc/arduino/cli/commands/v1"
 	"github.com/arduino/go-paths-helper"
@@ -53,6 +54,9 @@ func (pme *Explorer) DownloadAndInstallPlatformUpgrades(
 	if installed == nil {
 		return nil, &arduino.PlatformNotFoundError{Platform: platformRef.String()}
 	}
+	if pinned := pme.PinnedPlatformVersion(platform); pinned != nil {
+		return installed, &arduino.PlatformPinnedError{Platform: platformRef.String(), Version: pinned.String()}
+	}
 	latest := platform.GetLatestRelease()
 	if !latest.Version.GreaterThan(installed.Version) {
 		return installed, &arduino.PlatformAlreadyAtTheLatestVersionError{Platform: platformRef.String()}
@@ -92,12 +96,7 @@ func (pme *Explorer) DownloadAndInstallPlatformAndTools(
 
 	// Package download
 	taskCB(&rpc.TaskProgress{Name: tr("Downloading packages")})
-	for _, tool := range toolsToInstall {
-		if err := pme.DownloadToolRelease(tool, nil, downloadCB); err != nil {
-			return err
-		}
-	}
-	if err := pme.DownloadPlatformRelease(platformRelease, nil, downloadCB); err != nil {
+	if err := pme.DownloadPlatformAndTools(platformRelease, toolsToInstall, nil, downloadCB); err != nil {
 		return err
 	}
 	taskCB(&rpc.TaskProgress{Completed: true})
@@ -141,9 +140,14 @@ func (pme *Explorer) DownloadAndInstallPlatformAndTools(
 		return &arduino.FailedInstallError{Message: tr("Cannot install platform"), Cause: err}
 	}
 
-	// If upgrading remove previous release
+	// If upgrading remove, or keep for a rollback, the previous release
 	if installed != nil {
-		uninstallErr := pme.UninstallPlatform(installed, taskCB, skipPreUninstall)
+		var uninstallErr error
+		if pme.keepPreviousReleases > 0 {
+			uninstallErr = pme.keepPreviousRelease(installed, pme.keepPreviousReleases, taskCB, skipPreUninstall)
+		} else {
+			uninstallErr = pme.UninstallPlatform(installed, taskCB, skipPreUninstall)
+		}
 
 		// In case of error try to rollback
 		if uninstallErr != nil {
@@ -170,7 +174,7 @@ func (pme *Explorer) DownloadAndInstallPlatformAndTools(
 	}
 
 	// Perform post install
-	if !skipPostInstall {
+	if !pme.SkipScripts(skipPostInstall, platformRelease.Platform.String(), taskCB) {
 		log.Info("Running post_install script")
 		taskCB(&rpc.TaskProgress{Message: tr("Configuring platform.")})
 		if !platformRelease.IsInstalled() {
@@ -195,17 +199,32 @@ func (pme *Explorer) DownloadAndInstallPlatformAndTools(
 
 // InstallPlatform installs a specific release of a platform.
 func (pme *Explorer) InstallPlatform(platformRelease *cores.PlatformRelease) error {
-	destDir := pme.PackagesDir.Join(
+	return pme.InstallPlatformInDirectory(platformRelease, pme.platformReleaseInstallDir(platformRelease))
+}
+
+// platformReleaseInstallDir returns the directory where the platform release is installed by InstallPlatform
+func (pme *Explorer) platformReleaseInstallDir(platformRelease *cores.PlatformRelease) *paths.Path {
+	return pme.PackagesDir.Join(
 		platformRelease.Platform.Package.Name,
 		"hardware",
 		platformRelease.Platform.Architecture,
 		platformRelease.Version.String())
-	return pme.InstallPlatformInDirectory(platformRelease, destDir)
+}
+
+// ListPlatformReleaseArchiveContents returns the files that would be installed by
+// InstallPlatform. The archive of the platform release must be already downloaded.
+// The entries of the archive that would be placed outside the platform installation
+// directory are flagged.
+func (pme *Explorer) ListPlatformReleaseArchiveContents(platformRelease *cores.PlatformRelease) ([]*resources.ArchiveEntry, error) {
+	if platformRelease.Resource == nil {
+		return nil, &arduino.PlatformNotFoundError{Platform: platformRelease.String()}
+	}
+	return platformRelease.Resource.ListArchiveContents(pme.DownloadDir, pme.platformReleaseInstallDir(platformRelease))
 }
 
 // InstallPlatformInDirectory installs a specific release of a platform in a specific directory.
 func (pme *Explorer) InstallPlatformInDirectory(platformRelease *cores.PlatformRelease, destDir *paths.Path) error {
-	if err := platformRelease.Resource.Install(pme.DownloadDir, pme.tempDir, destDir); err != nil {
+	if err := platformRelease.Resource.Install(pme.DownloadDir, pme.tempDir, destDir, pme.archiveSignatures); err != nil {
 		return errors.Errorf(tr("installing platform %[1]s: %[2]s"), platformRelease, err)
 	}
 	if d, err := destDir.Abs(); err == nil {
@@ -216,6 +235,7 @@ func (pme *Explorer) InstallPlatformInDirectory(platformRelease *cores.PlatformR
 	if err := pme.cacheInstalledJSON(platformRelease); err != nil {
 		return errors.Errorf(tr("creating installed.json in %[1]s: %[2]s"), platformRelease.InstallDir, err)
 	}
+	pme.eventBus.emit(&Event{Kind: EventInstalled, Subject: platformRelease.String()})
 	return nil
 }
 
@@ -231,7 +251,9 @@ func (pme *Explorer) cacheInstalledJSON(platformRelease *cores.PlatformRelease)
 }
 
 // RunPreOrPostScript runs either the post_install.sh (or post_install.bat) or the pre_uninstall.sh (or pre_uninstall.bat)
-// script for the specified platformRelease or toolRelease.
+// script for the specified platformRelease or toolRelease. The output of the script is captured and returned.
+// The script is killed if it runs longer than the timeout of the ScriptsPolicy, and it's not run at all,
+// returning ErrScriptsDisabled, if the execution of the scripts is disabled by the ScriptsPolicy.
 func (pme *Explorer) RunPreOrPostScript(installDir *paths.Path, prefix string) ([]byte, []byte, error) {
 	scriptFilename := prefix + ".sh"
 	if runtime.GOOS == "windows" {
@@ -239,16 +261,27 @@ func (pme *Explorer) RunPreOrPostScript(installDir *paths.Path, prefix string) (
 	}
 	script := installDir.Join(scriptFilename)
 	if script.Exist() && script.IsNotDir() {
+		if pme.scriptsPolicy.Disabled {
+			return []byte{}, []byte{}, &kindError{kind: ErrScriptsDisabled, message: tr("the execution of the install scripts is disabled")}
+		}
 		cmd, err := executils.NewProcessFromPath(pme.GetEnvVarsForSpawnedProcess(), script)
 		if err != nil {
 			return []byte{}, []byte{}, err
 		}
-		cmdStdout, cmdStderr := bytes.NewBuffer([]byte{}), bytes.NewBuffer([]byte{})
-		cmd.RedirectStdoutTo(cmdStdout)
-		cmd.RedirectStderrTo(cmdStderr)
 		cmd.SetDirFromPath(installDir)
-		err = cmd.Run()
-		return cmdStdout.Bytes(), cmdStderr.Bytes(), err
+		ctx := context.Background()
+		if timeout := pme.scriptsPolicy.Timeout; timeout > 0 {
+			var cancel context.CancelFunc
+			ctx, cancel = context.WithTimeout(ctx, timeout)
+			defer cancel()
+			// Don't wait for the processes spawned by the script that keep the output open
+			cmd.SetWaitDelay(scriptsWaitDelay)
+		}
+		stdout, stderr, err := cmd.RunAndCaptureOutput(ctx)
+		if ctx.Err() == context.DeadlineExceeded {
+			err = fmt.Errorf(tr("%[1]s killed after running for %[2]s"), scriptFilename, pme.scriptsPolicy.Timeout)
+		}
+		return stdout, stderr, err
 	}
 	return []byte{}, []byte{}, nil
 }
@@ -290,7 +323,7 @@ func (pme *Explorer) UninstallPlatform(platformRelease *cores.PlatformRelease, t
 		return &arduino.FailedUninstallError{Message: err.Error()}
 	}
 
-	if !skipPreUninstall {
+	if !pme.SkipScripts(skipPreUninstall, platformRelease.Platform.String(), taskCB) {
 		log.Info("Running pre_uninstall script")
 		taskCB(&rpc.TaskProgress{Message: tr("Running pre_uninstall script.")})
 		stdout, stderr, err := pme.RunPreOrPostScript(platformRelease.InstallDir, "pre_uninstall")
@@ -311,6 +344,7 @@ func (pme *Explorer) UninstallPlatform(platformRelease *cores.PlatformRelease, t
 	}
 
 	platformRelease.InstallDir = nil
+	pme.eventBus.emit(&Event{Kind: EventUninstalled, Subject: platformRelease.String()})
 
 	log.Info("Platform uninstalled")
 	taskCB(&rpc.TaskProgress{Message: tr("Platform %s uninstalled", platformRelease), Completed: true})
@@ -340,7 +374,7 @@ func (pme *Explorer) InstallTool(toolRelease *cores.ToolRelease, taskCB rpc.Task
 		"tools",
 		toolRelease.Tool.Name,
 		toolRelease.Version.String())
-	err := toolResource.Install(pme.DownloadDir, pme.tempDir, destDir)
+	err := toolResource.Install(pme.DownloadDir, pme.tempDir, destDir, pme.archiveSignatures)
 	if err != nil {
 		log.WithError(err).Warn("Cannot install tool")
 		return &arduino.FailedInstallError{Message: tr("Cannot install tool %s", toolRelease), Cause: err}
@@ -350,8 +384,9 @@ func (pme *Explorer) InstallTool(toolRelease *cores.ToolRelease, taskCB rpc.Task
 	} else {
 		return err
 	}
+	pme.eventBus.emit(&Event{Kind: EventInstalled, Subject: toolRelease.String()})
 	// Perform post install
-	if !skipPostInstall {
+	if !pme.SkipScripts(skipPostInstall, "", taskCB) {
 		log.Info("Running tool post_install script")
 		taskCB(&rpc.TaskProgress{Message: tr("Configuring tool.")})
 		stdout, stderr, err := pme.RunPreOrPostScript(toolRelease.InstallDir, "post_install")
@@ -403,7 +438,7 @@ func (pme *Explorer) UninstallTool(toolRelease *cores.ToolRelease, taskCB rpc.Ta
 		return err
 	}
 
-	if !skipPreUninstall {
+	if !pme.SkipScripts(skipPreUninstall, "", taskCB) {
 		log.Info("Running pre_uninstall script")
 		taskCB(&rpc.TaskProgress{Message: tr("Running pre_uninstall script.")})
 		stdout, stderr, err := pme.RunPreOrPostScript(toolRelease.InstallDir, "pre_uninstall")
@@ -424,6 +459,7 @@ func (pme *Explorer) UninstallTool(toolRelease *cores.ToolRelease, taskCB rpc.Ta
 	}
 
 	toolRelease.InstallDir = nil
+	pme.eventBus.emit(&Event{Kind: EventUninstalled, Subject: toolRelease.String()})
 
 	log.Info("Tool uninstalled")
 	taskCB(&rpc.TaskProgress{Message: tr("Tool %s uninstalled", toolRelease), Completed: true})
@@ -433,17 +469,24 @@ func (pme *Explorer) UninstallTool(toolRelease *cores.ToolRelease, taskCB rpc.Ta
 // IsToolRequired returns true if any of the installed platforms requires the toolRelease
 // passed as parameter
 func (pme *Explorer) IsToolRequired(toolRelease *cores.ToolRelease) bool {
+	return len(pme.platformReleasesRequiringTool(toolRelease)) > 0
+}
+
+// platformReleasesRequiringTool returns the installed platform releases that
+// require the toolRelease passed as parameter
+func (pme *Explorer) platformReleasesRequiringTool(toolRelease *cores.ToolRelease) []*cores.PlatformRelease {
+	res := []*cores.PlatformRelease{}
 	// Search in all installed platforms
 	for _, targetPackage := range pme.packages {
 		for _, platform := range targetPackage.Platforms {
 			if platformRelease := pme.GetInstalledPlatformRelease(platform); platformRelease != nil {
 				if platformRelease.RequiresToolRelease(toolRelease) {
-					return true
+					res = append(res, platformRelease)
 				}
 			}
 		}
 	}
-	return false
+	return res
 }
 
 func skipEmptyMessageTaskProgressCB(taskCB rpc.TaskProgressCB) rpc.TaskProgressCB {
@@ -0,0 +1,112 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2023 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package packagemanager
+
+import (
+	"io/fs"
+	"path/filepath"
+	"sort"
+
+	"github.com/arduino/arduino-cli/arduino/cores"
+	"github.com/arduino/go-paths-helper"
+	semver "go.bug.st/relaxed-semver"
+)
+
+// PlatformInventory describes the state of a platform: the installed release,
+// if any, and the releases available from the package indexes.
+type PlatformInventory struct {
+	Platform *cores.Platform
+	// Installed is the installed release in use, nil if the platform is not
+	// installed
+	Installed *cores.PlatformRelease
+	// Latest is the latest release, installed or available from the package
+	// indexes, nil if the platform has no releases
+	Latest *cores.PlatformRelease
+	// Updatable is true if the platform is installed and a newer release is
+	// available
+	Updatable bool
+	// Deprecated is true if the platform is marked as deprecated in the
+	// package index
+	Deprecated bool
+	// ManuallyInstalled is true if the platform is installed in the sketchbook
+	// hardware directory instead of the packages directory
+	ManuallyInstalled bool
+	// Pinned is the version the platform is pinned to, nil if not pinned
+	Pinned *semver.Version
+	// PreviousReleases are the versions of the previous releases kept on disk
+	// for a rollback, from the most recently replaced to the oldest
+	PreviousReleases []*semver.Version
+	// InstallDir is the installation directory of Installed, nil if the
+	// platform is not installed
+	InstallDir *paths.Path
+}
+
+// Inventory returns a snapshot of all the platforms known to the
+// PackageManager, installed or available from the package indexes, sorted by
+// platform id (PACKAGER:ARCH).
+func (pme *Explorer) Inventory() []*PlatformInventory {
+	res := []*PlatformInventory{}
+	for _, targetPackage := range pme.packages {
+		for _, platform := range targetPackage.Platforms {
+			entry := &PlatformInventory{
+				Platform:          platform,
+				Installed:         pme.GetInstalledPlatformRelease(platform),
+				Latest:            platform.GetLatestRelease(),
+				Deprecated:        platform.Deprecated,
+				ManuallyInstalled: platform.ManuallyInstalled,
+			}
+			if entry.Installed == nil && entry.Latest == nil {
+				continue
+			}
+			if entry.Installed != nil {
+				entry.InstallDir = entry.Installed.InstallDir
+				entry.Updatable = entry.Latest != nil && entry.Latest.Version.GreaterThan(entry.Installed.Version)
+				entry.Pinned = pme.PinnedPlatformVersion(platform)
+				entry.PreviousReleases = pme.PreviousPlatformReleases(platform)
+			}
+			res = append(res, entry)
+		}
+	}
+	sort.Slice(res, func(i, j int) bool {
+		return res[i].Platform.String() < res[j].Platform.String()
+	})
+	return res
+}
+
+// SizeOnDisk returns the total size of the files of the installed release, or
+// 0 if the platform is not installed. The size is computed, at each call, by
+// walking the installation directory, symbolic links are not followed.
+func (p *PlatformInventory) SizeOnDisk() (int64, error) {
+	if p.InstallDir == nil {
+		return 0, nil
+	}
+	var size int64
+	err := filepath.WalkDir(p.InstallDir.String(), func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !d.Type().IsRegular() {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		size += info.Size()
+		return nil
+	})
+	return size, err
+}
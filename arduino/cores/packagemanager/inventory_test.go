@@ -0,0 +1,85 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2023 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package packagemanager
+
+import (
+	"testing"
+
+	"github.com/arduino/go-paths-helper"
+	"github.com/stretchr/testify/require"
+	semver "go.bug.st/relaxed-semver"
+)
+
+func TestInventory(t *testing.T) {
+	tmp := paths.New(t.TempDir())
+	pmb := NewBuilder(tmp, tmp.Join("packages"), nil, nil, "test")
+	pack := pmb.GetOrCreatePackage("test")
+
+	// Installed with an update available
+	avr := pack.GetOrCreatePlatform("avr")
+	avrInstalled := avr.GetOrCreateRelease(semver.MustParse("1.0.0"))
+	avrInstalled.InstallDir = tmp.Join("packages", "test", "hardware", "avr", "1.0.0")
+	require.NoError(t, avrInstalled.InstallDir.Join("cores").MkdirAll())
+	require.NoError(t, avrInstalled.InstallDir.Join("platform.txt").WriteFile(make([]byte, 100)))
+	require.NoError(t, avrInstalled.InstallDir.Join("cores", "main.cpp").WriteFile(make([]byte, 20)))
+	avr.GetOrCreateRelease(semver.MustParse("2.0.0"))
+
+	// Installed and up to date
+	samd := pack.GetOrCreatePlatform("samd")
+	samdInstalled := samd.GetOrCreateRelease(semver.MustParse("1.0.0"))
+	samdInstalled.InstallDir = tmp.Join("packages", "test", "hardware", "samd", "1.0.0")
+
+	// Not installed and deprecated
+	esp := pack.GetOrCreatePlatform("esp")
+	esp.Deprecated = true
+	esp.GetOrCreateRelease(semver.MustParse("3.0.0"))
+
+	// Without releases
+	pack.GetOrCreatePlatform("empty")
+
+	pme, release := pmb.Build().NewExplorer()
+	defer release()
+	require.NoError(t, pme.PinPlatform(samd, semver.MustParse("1.0.0")))
+
+	inventory := pme.Inventory()
+	require.Len(t, inventory, 3)
+
+	require.Equal(t, avr, inventory[0].Platform)
+	require.Equal(t, avrInstalled, inventory[0].Installed)
+	require.Equal(t, "2.0.0", inventory[0].Latest.Version.String())
+	require.True(t, inventory[0].Updatable)
+	require.False(t, inventory[0].Deprecated)
+	require.Nil(t, inventory[0].Pinned)
+	require.Equal(t, avrInstalled.InstallDir, inventory[0].InstallDir)
+	size, err := inventory[0].SizeOnDisk()
+	require.NoError(t, err)
+	require.Equal(t, int64(120), size)
+
+	require.Equal(t, esp, inventory[1].Platform)
+	require.Nil(t, inventory[1].Installed)
+	require.Equal(t, "3.0.0", inventory[1].Latest.Version.String())
+	require.False(t, inventory[1].Updatable)
+	require.True(t, inventory[1].Deprecated)
+	require.Nil(t, inventory[1].InstallDir)
+	size, err = inventory[1].SizeOnDisk()
+	require.NoError(t, err)
+	require.Zero(t, size)
+
+	require.Equal(t, samd, inventory[2].Platform)
+	require.Equal(t, samdInstalled, inventory[2].Installed)
+	require.False(t, inventory[2].Updatable)
+	require.Equal(t, "1.0.0", inventory[2].Pinned.String())
+}
@@ -0,0 +1,54 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2023 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package packagemanager
+
+import (
+	"net/url"
+	"sync"
+)
+
+// lazyPackageIndex guards the lazy loading of a package index
+type lazyPackageIndex struct {
+	once  sync.Once
+	err   error
+	loads int
+}
+
+// EnsurePackageIndexLoaded loads the package index with the given URL into the
+// PackageManager the first time it's called for that URL. Concurrent calls for
+// the same URL wait for the first one to complete and return its result, so the
+// index is parsed exactly once. Successive changes to the index must be loaded
+// through a new Builder (for example by reinitializing the instance), after that
+// the lazy loading of each index is allowed again.
+// This method must not be called while holding an Explorer, since it needs to
+// acquire the write lock on the PackageManager.
+func (pm *PackageManager) EnsurePackageIndexLoaded(URL *url.URL) error {
+	pm.lazyIndexesMux.Lock()
+	lazyIndex, ok := pm.lazyIndexes[URL.String()]
+	if !ok {
+		lazyIndex = &lazyPackageIndex{}
+		pm.lazyIndexes[URL.String()] = lazyIndex
+	}
+	pm.lazyIndexesMux.Unlock()
+
+	lazyIndex.once.Do(func() {
+		pm.packagesLock.Lock()
+		defer pm.packagesLock.Unlock()
+		lazyIndex.loads++
+		lazyIndex.err = (*Builder)(pm).LoadPackageIndex(URL)
+	})
+	return lazyIndex.err
+}
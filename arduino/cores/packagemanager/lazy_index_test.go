@@ -0,0 +1,66 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2023 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package packagemanager
+
+import (
+	"net/url"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestEnsurePackageIndexLoaded(t *testing.T) {
+	pm := NewBuilder(dataDir1, nil, nil, nil, "test").Build()
+	indexURL, err := url.Parse("https://test.com/package_test_index.json")
+	require.NoError(t, err)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := pm.EnsurePackageIndexLoaded(indexURL); err != nil {
+				t.Error(err)
+				return
+			}
+			pme, release := pm.NewExplorer()
+			defer release()
+			if pme.FindPlatform(&PlatformReference{Package: "test", PlatformArchitecture: "avr"}) == nil {
+				t.Error("platform test:avr not found")
+			}
+		}()
+	}
+	wg.Wait()
+	require.Equal(t, 1, pm.lazyIndexes[indexURL.String()].loads)
+
+	// A missing index reports the same error to all the callers
+	missingURL, err := url.Parse("https://test.com/package_missing_index.json")
+	require.NoError(t, err)
+	err1 := pm.EnsurePackageIndexLoaded(missingURL)
+	require.Error(t, err1)
+	require.Equal(t, err1, pm.EnsurePackageIndexLoaded(missingURL))
+	require.Equal(t, 1, pm.lazyIndexes[missingURL.String()].loads)
+
+	// After a reload the index may be lazily loaded again
+	_, commit := pm.NewBuilder()
+	commit()
+	require.NoError(t, pm.EnsurePackageIndexLoaded(indexURL))
+	require.Equal(t, 1, pm.lazyIndexes[indexURL.String()].loads)
+	pme, release := pm.NewExplorer()
+	defer release()
+	require.NotNil(t, pme.FindPlatform(&PlatformReference{Package: "test", PlatformArchitecture: "avr"}))
+}
@@ -26,6 +26,7 @@ import (
 	"github.com/arduino/arduino-cli/arduino"
 	"github.com/arduino/arduino-cli/arduino/cores"
 	"github.com/arduino/arduino-cli/arduino/discovery"
+	"github.com/arduino/arduino-cli/arduino/utils"
 	"github.com/arduino/arduino-cli/configuration"
 	"github.com/arduino/go-paths-helper"
 	properties "github.com/arduino/go-properties-orderedmap"
@@ -33,6 +34,12 @@ import (
 	semver "go.bug.st/relaxed-semver"
 )
 
+// SetSymlinkPolicy sets which symlinks are followed while scanning the
+// hardware directories for packagers and platforms.
+func (pmb *Builder) SetSymlinkPolicy(policy utils.SymlinkPolicy) {
+	pmb.symlinkPolicy = policy
+}
+
 // LoadHardware read all plaforms from the configured paths
 func (pm *Builder) LoadHardware() []error {
 	hardwareDirs := configuration.HardwareDirectories(configuration.Settings)
@@ -66,7 +73,7 @@ func (pm *Builder) LoadHardwareFromDirectory(path *paths.Path) []error {
 	}
 
 	// Scan subdirs
-	packagersPaths, err := path.ReadDir()
+	packagersPaths, err := path.ReadDir(pm.symlinkPolicy.FilterSymlinks(path))
 	if err != nil {
 		return append(merr, fmt.Errorf("%s: %w", tr("reading directory %s", path), err))
 	}
@@ -148,7 +155,7 @@ func (pm *Builder) loadPlatforms(targetPackage *cores.Package, packageDir *paths
 
 	var merr []error
 
-	platformsDirs, err := packageDir.ReadDir()
+	platformsDirs, err := packageDir.ReadDir(pm.symlinkPolicy.FilterSymlinks(packageDir))
 	if err != nil {
 		return append(merr, fmt.Errorf("%s: %w", tr("reading directory %s", packageDir), err))
 	}
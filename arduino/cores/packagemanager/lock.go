@@ -0,0 +1,101 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2023 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package packagemanager
+
+import (
+	"errors"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/arduino/arduino-cli/arduino"
+	rpc "github.com/arduino/arduino-cli/rpc/cc/arduino/cli/commands/v1"
+)
+
+// packagesLockFileName is the name of the lock file, in the IndexDir, used to
+// serialize the changes to the installed platforms, tools and package indexes
+// between different processes.
+const packagesLockFileName = ".packages.lock"
+
+// packagesLockPollInterval is how often a held lock is checked for release.
+const packagesLockPollInterval = 100 * time.Millisecond
+
+// errFileLocked is returned by tryLockFile if the file is locked by another
+// process (or by another open file of the same process).
+var errFileLocked = errors.New("file locked")
+
+// SetPackagesLockTimeout sets how long LockPackages waits for another process
+// to release the lock before failing. With 0 or less LockPackages fails
+// immediately if the lock is held.
+func (pmb *Builder) SetPackagesLockTimeout(timeout time.Duration) {
+	pmb.packagesLockTimeout = timeout
+}
+
+// LockPackages takes the advisory lock that must be held while changing the
+// installed platforms, tools or package indexes, so that two processes (or two
+// concurrent commands of the same process) don't corrupt each other's changes.
+// If the lock is held by someone else LockPackages waits, up to the configured
+// timeout, for its release, notifying the wait to taskCB (that may be nil).
+// The returned function releases the lock.
+func (pme *Explorer) LockPackages(taskCB rpc.TaskProgressCB) (func(), error) {
+	if err := pme.IndexDir.MkdirAll(); err != nil {
+		return nil, &arduino.PermissionDeniedError{Message: tr("Error creating data directory"), Cause: err}
+	}
+	lockFile := pme.IndexDir.Join(packagesLockFileName)
+	file, err := os.OpenFile(lockFile.String(), os.O_RDWR|os.O_CREATE, 0644)
+	if err != nil {
+		return nil, &arduino.PermissionDeniedError{Message: tr("Error opening lock file %s", lockFile), Cause: err}
+	}
+
+	deadline := time.Now().Add(pme.packagesLockTimeout)
+	waiting := false
+	for {
+		err := tryLockFile(file)
+		if err == nil {
+			break
+		}
+		if !errors.Is(err, errFileLocked) {
+			file.Close()
+			return nil, &arduino.PermissionDeniedError{Message: tr("Error locking file %s", lockFile), Cause: err}
+		}
+		if !time.Now().Before(deadline) {
+			file.Close()
+			owner, _ := lockFile.ReadFile()
+			return nil, &arduino.PackagesLockedError{LockFile: lockFile, Owner: strings.TrimSpace(string(owner))}
+		}
+		if !waiting && taskCB != nil {
+			taskCB(&rpc.TaskProgress{Name: tr("Waiting for another process installing or updating the platforms...")})
+		}
+		waiting = true
+		time.Sleep(packagesLockPollInterval)
+	}
+
+	// Record the owner of the lock, for the error reported to the other processes
+	if err := file.Truncate(0); err == nil {
+		_, _ = file.WriteAt([]byte(strconv.Itoa(os.Getpid())), 0)
+	}
+	pme.log.Debugf("Acquired lock %s", lockFile)
+
+	return func() {
+		_ = file.Truncate(0)
+		if err := unlockFile(file); err != nil {
+			pme.log.Warnf("Error releasing lock %s: %s", lockFile, err)
+		}
+		file.Close()
+		pme.log.Debugf("Released lock %s", lockFile)
+	}, nil
+}
@@ -0,0 +1,72 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2023 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package packagemanager
+
+import (
+	"os"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/arduino/arduino-cli/arduino"
+	rpc "github.com/arduino/arduino-cli/rpc/cc/arduino/cli/commands/v1"
+	"github.com/arduino/go-paths-helper"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLockPackages(t *testing.T) {
+	tmp := paths.New(t.TempDir()).Join("data")
+	explorer := func(timeout time.Duration) *Explorer {
+		pmb := NewBuilder(tmp, tmp.Join("packages"), tmp.Join("staging"), tmp.Join("tmp"), "test")
+		pmb.SetPackagesLockTimeout(timeout)
+		pme, release := pmb.Build().NewExplorer()
+		t.Cleanup(release)
+		return pme
+	}
+	pme := explorer(0)
+
+	unlock, err := pme.LockPackages(nil)
+	require.NoError(t, err)
+	require.FileExists(t, tmp.Join(packagesLockFileName).String())
+
+	// Without a timeout the second lock fails immediately, reporting the owner
+	_, err = explorer(0).LockPackages(nil)
+	var lockedErr *arduino.PackagesLockedError
+	require.ErrorAs(t, err, &lockedErr)
+	require.Equal(t, strconv.Itoa(os.Getpid()), lockedErr.Owner)
+	require.Contains(t, err.Error(), tmp.Join(packagesLockFileName).String())
+
+	unlock()
+	firstUnlock, err := pme.LockPackages(nil)
+	require.NoError(t, err)
+
+	// With a timeout the second lock waits for the release of the first one
+	go func() {
+		time.Sleep(300 * time.Millisecond)
+		firstUnlock()
+	}()
+	waiting := 0
+	start := time.Now()
+	unlock, err = explorer(5 * time.Second).LockPackages(func(*rpc.TaskProgress) { waiting++ })
+	require.NoError(t, err)
+	require.Equal(t, 1, waiting)
+	require.GreaterOrEqual(t, time.Since(start), 300*time.Millisecond)
+
+	// The wait is limited by the timeout
+	_, err = explorer(200 * time.Millisecond).LockPackages(nil)
+	require.ErrorAs(t, err, &lockedErr)
+	unlock()
+}
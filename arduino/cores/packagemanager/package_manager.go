@@ -21,6 +21,8 @@ import (
 	"os"
 	"path"
 	"path/filepath"
+	"runtime"
+	"sort"
 	"strconv"
 	"strings"
 	"sync"
@@ -30,7 +32,9 @@ import (
 	"github.com/arduino/arduino-cli/arduino/cores"
 	"github.com/arduino/arduino-cli/arduino/cores/packageindex"
 	"github.com/arduino/arduino-cli/arduino/discovery/discoverymanager"
+	"github.com/arduino/arduino-cli/arduino/resources"
 	"github.com/arduino/arduino-cli/arduino/sketch"
+	"github.com/arduino/arduino-cli/arduino/utils"
 	"github.com/arduino/arduino-cli/i18n"
 	paths "github.com/arduino/go-paths-helper"
 	properties "github.com/arduino/go-properties-orderedmap"
@@ -58,6 +62,21 @@ type PackageManager struct {
 	profile          *sketch.Profile
 	discoveryManager *discoverymanager.DiscoveryManager
 	userAgent        string
+	fqbnAliases      map[string]string
+	indexProvenance  map[string][]*IndexProvenance
+	loadedIndexes    []*IndexStatus
+	eventBus         *eventBus
+	lazyIndexesMux   sync.Mutex // Protects lazyIndexes
+	lazyIndexes      map[string]*lazyPackageIndex
+
+	keepPreviousReleases int                                    // Number of replaced releases of each platform kept on disk
+	parallelDownloads    int                                    // Maximum number of archives downloaded at the same time
+	packagesLockTimeout  time.Duration                          // How long LockPackages waits for the lock held by another process
+	scriptsPolicy        ScriptsPolicy                          // Controls the execution of the post_install and pre_uninstall scripts
+	symlinkPolicy        utils.SymlinkPolicy                    // Which symlinks are followed while scanning the hardware directories
+	indexSignatures      packageindex.SignatureVerification     // How the signatures of the package indexes are verified
+	archiveSignatures    resources.ArchiveSignatureVerification // How the signatures of the downloaded archives are verified
+	maxIndexSize         int64                                  // Maximum size of the package index files, 0 for the default
 }
 
 // Builder is used to create a new PackageManager. The builder
@@ -84,6 +103,10 @@ func NewBuilder(indexDir, packagesDir, downloadDir, tempDir *paths.Path, userAge
 		packagesCustomGlobalProperties: properties.NewMap(),
 		discoveryManager:               discoverymanager.New(),
 		userAgent:                      userAgent,
+		fqbnAliases:                    map[string]string{},
+		indexProvenance:                map[string][]*IndexProvenance{},
+		lazyIndexes:                    map[string]*lazyPackageIndex{},
+		eventBus:                       &eventBus{},
 	}
 }
 
@@ -103,6 +126,20 @@ func (pmb *Builder) BuildIntoExistingPackageManager(target *PackageManager) {
 	target.discoveryManager.Clear()
 	target.discoveryManager.AddAllDiscoveriesFrom(pmb.discoveryManager)
 	target.userAgent = pmb.userAgent
+	target.fqbnAliases = pmb.fqbnAliases
+	target.indexProvenance = pmb.indexProvenance
+	target.loadedIndexes = pmb.loadedIndexes
+	target.keepPreviousReleases = pmb.keepPreviousReleases
+	target.parallelDownloads = pmb.parallelDownloads
+	target.packagesLockTimeout = pmb.packagesLockTimeout
+	target.scriptsPolicy = pmb.scriptsPolicy
+	target.symlinkPolicy = pmb.symlinkPolicy
+	target.indexSignatures = pmb.indexSignatures
+	target.archiveSignatures = pmb.archiveSignatures
+	target.maxIndexSize = pmb.maxIndexSize
+	target.lazyIndexesMux.Lock()
+	target.lazyIndexes = map[string]*lazyPackageIndex{}
+	target.lazyIndexesMux.Unlock()
 }
 
 // Build builds a new PackageManager.
@@ -118,6 +155,19 @@ func (pmb *Builder) Build() *PackageManager {
 		profile:                        pmb.profile,
 		discoveryManager:               pmb.discoveryManager,
 		userAgent:                      pmb.userAgent,
+		fqbnAliases:                    pmb.fqbnAliases,
+		indexProvenance:                pmb.indexProvenance,
+		loadedIndexes:                  pmb.loadedIndexes,
+		lazyIndexes:                    map[string]*lazyPackageIndex{},
+		eventBus:                       pmb.eventBus,
+		keepPreviousReleases:           pmb.keepPreviousReleases,
+		parallelDownloads:              pmb.parallelDownloads,
+		packagesLockTimeout:            pmb.packagesLockTimeout,
+		scriptsPolicy:                  pmb.scriptsPolicy,
+		symlinkPolicy:                  pmb.symlinkPolicy,
+		indexSignatures:                pmb.indexSignatures,
+		archiveSignatures:              pmb.archiveSignatures,
+		maxIndexSize:                   pmb.maxIndexSize,
 	}
 }
 
@@ -127,6 +177,16 @@ func (pmb *Builder) Build() *PackageManager {
 // PackageManager.
 func (pm *PackageManager) NewBuilder() (builder *Builder, commit func()) {
 	pmb := NewBuilder(pm.IndexDir, pm.PackagesDir, pm.DownloadDir, pm.tempDir, pm.userAgent)
+	// The events of the builder are sent to the handlers of this PackageManager
+	pmb.eventBus = pm.eventBus
+	pmb.keepPreviousReleases = pm.keepPreviousReleases
+	pmb.parallelDownloads = pm.parallelDownloads
+	pmb.packagesLockTimeout = pm.packagesLockTimeout
+	pmb.scriptsPolicy = pm.scriptsPolicy
+	pmb.symlinkPolicy = pm.symlinkPolicy
+	pmb.indexSignatures = pm.indexSignatures
+	pmb.archiveSignatures = pm.archiveSignatures
+	pmb.maxIndexSize = pm.maxIndexSize
 	return pmb, func() {
 		pmb.BuildIntoExistingPackageManager(pm)
 	}
@@ -149,6 +209,18 @@ func (pm *PackageManager) NewExplorer() (explorer *Explorer, release func()) {
 		profile:                        pm.profile,
 		discoveryManager:               pm.discoveryManager,
 		userAgent:                      pm.userAgent,
+		fqbnAliases:                    pm.fqbnAliases,
+		indexProvenance:                pm.indexProvenance,
+		loadedIndexes:                  pm.loadedIndexes,
+		eventBus:                       pm.eventBus,
+		keepPreviousReleases:           pm.keepPreviousReleases,
+		parallelDownloads:              pm.parallelDownloads,
+		packagesLockTimeout:            pm.packagesLockTimeout,
+		scriptsPolicy:                  pm.scriptsPolicy,
+		symlinkPolicy:                  pm.symlinkPolicy,
+		indexSignatures:                pm.indexSignatures,
+		archiveSignatures:              pm.archiveSignatures,
+		maxIndexSize:                   pm.maxIndexSize,
 	}, pm.packagesLock.RUnlock
 }
 
@@ -211,14 +283,18 @@ func (pme *Explorer) FindPlatformReleaseProvidingBoardsWithVidPid(vid, pid strin
 	return res
 }
 
-// FindBoardsWithVidPid FIXMEDOC
+// FindBoardsWithVidPid returns the installed boards declaring the given USB
+// VID/PID. The PID may be a shell pattern (for example "0x80*"), the boards
+// declaring a PID pattern matching the given PID are returned too. See
+// FindBoardCandidatesWithVidPid for a ranked list that includes the boards
+// of the same vendor.
 func (pme *Explorer) FindBoardsWithVidPid(vid, pid string) []*cores.Board {
 	res := []*cores.Board{}
 	for _, targetPackage := range pme.packages {
 		for _, targetPlatform := range targetPackage.Platforms {
 			if platform := pme.GetInstalledPlatformRelease(targetPlatform); platform != nil {
 				for _, board := range platform.Boards {
-					if board.HasUsbID(vid, pid) {
+					if board.MatchUsbID(vid, pid) >= cores.UsbIDWildcardMatch {
 						res = append(res, board)
 					}
 				}
@@ -245,9 +321,35 @@ func (pme *Explorer) FindBoardsWithID(id string) []*cores.Board {
 	return res
 }
 
-// FindBoardWithFQBN returns the board identified by the fqbn, or an error
-func (pme *Explorer) FindBoardWithFQBN(fqbnIn string) (*cores.Board, error) {
+// AddFQBNAlias adds an alias that can be used in place of the given fqbn.
+// Aliases are case insensitive.
+func (pmb *Builder) AddFQBNAlias(alias, fqbn string) {
+	pmb.fqbnAliases[strings.ToLower(alias)] = fqbn
+}
+
+// ParseFQBN parses the given fqbn. If the string is not a valid fqbn but it
+// matches one of the user defined aliases, the fqbn pointed by the alias is
+// returned instead.
+func (pme *Explorer) ParseFQBN(fqbnIn string) (*cores.FQBN, error) {
 	fqbn, err := cores.ParseFQBN(fqbnIn)
+	if err == nil {
+		return fqbn, nil
+	}
+	aliased, ok := pme.fqbnAliases[strings.ToLower(fqbnIn)]
+	if !ok {
+		return nil, err
+	}
+	fqbn, err = cores.ParseFQBN(aliased)
+	if err != nil {
+		return nil, fmt.Errorf(tr("alias %[1]s points to an invalid fqbn %[2]s: %[3]s"), fqbnIn, aliased, err)
+	}
+	return fqbn, nil
+}
+
+// FindBoardWithFQBN returns the board identified by the fqbn (or by one of
+// the fqbn aliases), or an error
+func (pme *Explorer) FindBoardWithFQBN(fqbnIn string) (*cores.Board, error) {
+	fqbn, err := pme.ParseFQBN(fqbnIn)
 	if err != nil {
 		return nil, fmt.Errorf(tr("parsing fqbn: %s"), err)
 	}
@@ -256,6 +358,125 @@ func (pme *Explorer) FindBoardWithFQBN(fqbnIn string) (*cores.Board, error) {
 	return board, err
 }
 
+// BoardName returns the human-readable name of the installed board with the
+// given FQBN (the board's "name" property).
+func (pme *Explorer) BoardName(fqbnIn string) (string, error) {
+	board, err := pme.findBoardForName(fqbnIn)
+	if err != nil {
+		return "", err
+	}
+	return board.Name(), nil
+}
+
+// BoardFullName returns the human-readable name of the installed board with the
+// given FQBN prefixed by the name of its platform, for example
+// "Arduino AVR Boards / Arduino Uno".
+func (pme *Explorer) BoardFullName(fqbnIn string) (string, error) {
+	board, err := pme.findBoardForName(fqbnIn)
+	if err != nil {
+		return "", err
+	}
+	platformName := board.PlatformRelease.Platform.Name
+	if platformName == "" {
+		platformName = board.PlatformRelease.Platform.Package.Maintainer
+	}
+	if platformName == "" {
+		return board.Name(), nil
+	}
+	return platformName + " / " + board.Name(), nil
+}
+
+// findBoardForName returns the board with the given FQBN, the errors that
+// don't prevent to find the board (like a missing tool) are ignored
+func (pme *Explorer) findBoardForName(fqbnIn string) (*cores.Board, error) {
+	fqbn, err := pme.ParseFQBN(fqbnIn)
+	if err != nil {
+		return nil, fmt.Errorf(tr("parsing fqbn: %s"), err)
+	}
+	_, _, board, _, _, err := pme.ResolveFQBN(fqbn)
+	if board == nil {
+		return nil, fmt.Errorf(tr("board %[1]s not found: %[2]s"), fqbnIn, err)
+	}
+	return board, nil
+}
+
+// DiffBuildProperties resolves the two given fqbn and returns all the board build
+// properties that have a different value between them. The result maps each differing
+// key to the pair of values, in the same order as the fqbn are given (a property
+// missing from one of the boards is reported with an empty value).
+func (pme *Explorer) DiffBuildProperties(fqbnA, fqbnB string) (map[string][2]string, error) {
+	getBuildProperties := func(fqbnIn string) (*properties.Map, error) {
+		fqbn, err := pme.ParseFQBN(fqbnIn)
+		if err != nil {
+			return nil, fmt.Errorf(tr("parsing fqbn: %s"), err)
+		}
+		_, _, board, _, _, err := pme.ResolveFQBN(fqbn)
+		if err != nil {
+			return nil, err
+		}
+		return board.GetBuildProperties(fqbn)
+	}
+
+	propsA, err := getBuildProperties(fqbnA)
+	if err != nil {
+		return nil, err
+	}
+	propsB, err := getBuildProperties(fqbnB)
+	if err != nil {
+		return nil, err
+	}
+
+	res := map[string][2]string{}
+	for _, key := range propsA.Keys() {
+		if valueA, valueB := propsA.Get(key), propsB.Get(key); valueA != valueB {
+			res[key] = [2]string{valueA, valueB}
+		}
+	}
+	for _, key := range propsB.Keys() {
+		if !propsA.ContainsKey(key) {
+			res[key] = [2]string{"", propsB.Get(key)}
+		}
+	}
+	return res, nil
+}
+
+// UnresolvedProperties resolves the given fqbn and returns the sorted keys of
+// the build properties whose value, once expanded, still contains a ${...}
+// placeholder. This usually points out a misconfigured platform that refers to
+// an undefined property or uses a shell-style variable.
+func (pme *Explorer) UnresolvedProperties(fqbnIn string) ([]string, error) {
+	fqbn, err := pme.ParseFQBN(fqbnIn)
+	if err != nil {
+		return nil, fmt.Errorf(tr("parsing fqbn: %s"), err)
+	}
+	_, _, _, buildProperties, _, err := pme.ResolveFQBN(fqbn)
+	if err != nil {
+		return nil, err
+	}
+
+	res := []string{}
+	for _, key := range buildProperties.Keys() {
+		if strings.Contains(buildProperties.ExpandPropsInString(buildProperties.Get(key)), "${") {
+			res = append(res, key)
+		}
+	}
+	sort.Strings(res)
+	return res, nil
+}
+
+// PlatformRecipes returns the recipe keys (recipe.* and recipe.hooks.*) defined
+// in the given build properties, usually obtained with ResolveFQBN, mapped to
+// their (not expanded) patterns.
+func PlatformRecipes(buildProperties *properties.Map) map[string]string {
+	res := map[string]string{}
+	for _, key := range buildProperties.Keys() {
+		if strings.HasPrefix(key, "recipe.") {
+			res[key] = buildProperties.Get(key)
+		}
+	}
+	return res
+}
+
 // ResolveFQBN returns, in order:
 //
 // - the Package pointed by the fqbn
@@ -274,7 +495,9 @@ func (pme *Explorer) FindBoardWithFQBN(fqbnIn string) (*cores.Board, error) {
 // - an error if any of the above is not found
 //
 // In case of error the partial results found in the meantime are
-// returned together with the error.
+// returned together with the error. The error is a *FQBNResolutionError,
+// its Kind (one of ErrPackageNotFound, ErrPlatformNotInstalled,
+// ErrBoardNotFound, etc.) can be checked with errors.Is.
 func (pme *Explorer) ResolveFQBN(fqbn *cores.FQBN) (
 	*cores.Package, *cores.PlatformRelease, *cores.Board,
 	*properties.Map, *cores.PlatformRelease, error) {
@@ -282,40 +505,62 @@ func (pme *Explorer) ResolveFQBN(fqbn *cores.FQBN) (
 	// Find package
 	targetPackage := pme.packages[fqbn.Package]
 	if targetPackage == nil {
-		return nil, nil, nil, nil, nil,
-			fmt.Errorf(tr("unknown package %s"), fqbn.Package)
+		return nil, nil, nil, nil, nil, &FQBNResolutionError{
+			Kind:    ErrPackageNotFound,
+			FQBN:    fqbn,
+			message: fmt.Sprintf(tr("unknown package %s"), fqbn.Package),
+		}
 	}
 
 	// Find platform
 	platform := targetPackage.Platforms[fqbn.PlatformArch]
 	if platform == nil {
-		return targetPackage, nil, nil, nil, nil,
-			fmt.Errorf(tr("unknown platform %s:%s"), targetPackage, fqbn.PlatformArch)
+		return targetPackage, nil, nil, nil, nil, &FQBNResolutionError{
+			Kind:    ErrPlatformNotFound,
+			FQBN:    fqbn,
+			Package: targetPackage,
+			message: fmt.Sprintf(tr("unknown platform %s:%s"), targetPackage, fqbn.PlatformArch),
+		}
 	}
 	boardPlatformRelease := pme.GetInstalledPlatformRelease(platform)
 	if boardPlatformRelease == nil {
-		return targetPackage, nil, nil, nil, nil,
-			fmt.Errorf(tr("platform %s is not installed"), platform)
+		return targetPackage, nil, nil, nil, nil, &FQBNResolutionError{
+			Kind:     ErrPlatformNotInstalled,
+			FQBN:     fqbn,
+			Package:  targetPackage,
+			Platform: platform,
+			message:  fmt.Sprintf(tr("platform %s is not installed"), platform),
+		}
 	}
 
 	// Find board
 	board := boardPlatformRelease.Boards[fqbn.BoardID]
 	if board == nil {
+		err := &FQBNResolutionError{
+			Kind:    ErrBoardNotFound,
+			message: fmt.Sprintf(tr("board %s not found"), fqbn.StringWithoutConfig()),
+		}
 		return targetPackage, boardPlatformRelease, nil, nil, nil,
-			fmt.Errorf(tr("board %s not found"), fqbn.StringWithoutConfig())
+			err.fillPartialResolution(fqbn, targetPackage, boardPlatformRelease, nil)
 	}
 
 	boardBuildProperties, err := board.GetBuildProperties(fqbn)
 	if err != nil {
+		err := &FQBNResolutionError{
+			Kind:    ErrInvalidBoardConfiguration,
+			Cause:   err,
+			message: fmt.Sprintf(tr("getting build properties for board %[1]s: %[2]s"), board, err),
+		}
 		return targetPackage, boardPlatformRelease, board, nil, nil,
-			fmt.Errorf(tr("getting build properties for board %[1]s: %[2]s"), board, err)
+			err.fillPartialResolution(fqbn, targetPackage, boardPlatformRelease, board)
 	}
 
 	// Determine the platform used for the build and the variant (in case the board refers
 	// to a core contained in another platform)
-	core, corePlatformRelease, variant, variantPlatformRelease, err := pme.determineReferencedPlatformRelease(boardBuildProperties, boardPlatformRelease, fqbn)
-	if err != nil {
-		return targetPackage, boardPlatformRelease, board, nil, nil, err
+	core, corePlatformRelease, variant, variantPlatformRelease, refErr := pme.determineReferencedPlatformRelease(boardBuildProperties, boardPlatformRelease, fqbn)
+	if refErr != nil {
+		return targetPackage, boardPlatformRelease, board, nil, nil,
+			refErr.fillPartialResolution(fqbn, targetPackage, boardPlatformRelease, board)
 	}
 
 	// Create the build properties map by overlaying the properties of the
@@ -339,9 +584,15 @@ func (pme *Explorer) ResolveFQBN(fqbn *cores.FQBN) (
 	for _, tool := range pme.GetAllInstalledToolsReleases() {
 		buildProperties.Merge(tool.RuntimeProperties())
 	}
-	requiredTools, err := pme.FindToolsRequiredForBuild(boardPlatformRelease, corePlatformRelease)
-	if err != nil {
-		return targetPackage, boardPlatformRelease, board, buildProperties, corePlatformRelease, err
+	requiredTools, toolsErr := pme.FindToolsRequiredForBuild(boardPlatformRelease, corePlatformRelease)
+	if toolsErr != nil {
+		err := &FQBNResolutionError{
+			Kind:    ErrRequiredToolNotFound,
+			Cause:   toolsErr,
+			message: toolsErr.Error(),
+		}
+		return targetPackage, boardPlatformRelease, board, buildProperties, corePlatformRelease,
+			err.fillPartialResolution(fqbn, targetPackage, boardPlatformRelease, board)
 	}
 	for _, tool := range requiredTools {
 		buildProperties.Merge(tool.RuntimeProperties())
@@ -381,7 +632,7 @@ func (pme *Explorer) ResolveFQBN(fqbn *cores.FQBN) (
 	return targetPackage, boardPlatformRelease, board, buildProperties, corePlatformRelease, nil
 }
 
-func (pme *Explorer) determineReferencedPlatformRelease(boardBuildProperties *properties.Map, boardPlatformRelease *cores.PlatformRelease, fqbn *cores.FQBN) (string, *cores.PlatformRelease, string, *cores.PlatformRelease, error) {
+func (pme *Explorer) determineReferencedPlatformRelease(boardBuildProperties *properties.Map, boardPlatformRelease *cores.PlatformRelease, fqbn *cores.FQBN) (string, *cores.PlatformRelease, string, *cores.PlatformRelease, *FQBNResolutionError) {
 	core := boardBuildProperties.ExpandPropsInString(boardBuildProperties.Get("build.core"))
 	referredCore := ""
 	if split := strings.Split(core, ":"); len(split) > 1 {
@@ -396,8 +647,10 @@ func (pme *Explorer) determineReferencedPlatformRelease(boardBuildProperties *pr
 
 	// core and variant cannot refer to two different platforms
 	if referredCore != "" && referredVariant != "" && referredCore != referredVariant {
-		return "", nil, "", nil,
-			fmt.Errorf(tr("'build.core' and 'build.variant' refer to different platforms: %[1]s and %[2]s"), referredCore+":"+core, referredVariant+":"+variant)
+		return "", nil, "", nil, &FQBNResolutionError{
+			Kind:    ErrInvalidReferencedPlatform,
+			message: fmt.Sprintf(tr("'build.core' and 'build.variant' refer to different platforms: %[1]s and %[2]s"), referredCore+":"+core, referredVariant+":"+variant),
+		}
 	}
 
 	// extract the referred platform
@@ -407,20 +660,30 @@ func (pme *Explorer) determineReferencedPlatformRelease(boardBuildProperties *pr
 		referredPackageName = referredVariant
 	}
 	if referredPackageName != "" {
+		referredPlatformID := referredPackageName + ":" + fqbn.PlatformArch
 		referredPackage := pme.packages[referredPackageName]
 		if referredPackage == nil {
-			return "", nil, "", nil,
-				fmt.Errorf(tr("missing package %[1]s referenced by board %[2]s"), referredPackageName, fqbn)
+			return "", nil, "", nil, &FQBNResolutionError{
+				Kind:               ErrReferencedPlatformNotFound,
+				ReferencedPlatform: referredPlatformID,
+				message:            fmt.Sprintf(tr("missing package %[1]s referenced by board %[2]s"), referredPackageName, fqbn),
+			}
 		}
 		referredPlatform := referredPackage.Platforms[fqbn.PlatformArch]
 		if referredPlatform == nil {
-			return "", nil, "", nil,
-				fmt.Errorf(tr("missing platform %[1]s:%[2]s referenced by board %[3]s"), referredPackageName, fqbn.PlatformArch, fqbn)
+			return "", nil, "", nil, &FQBNResolutionError{
+				Kind:               ErrReferencedPlatformNotFound,
+				ReferencedPlatform: referredPlatformID,
+				message:            fmt.Sprintf(tr("missing platform %[1]s:%[2]s referenced by board %[3]s"), referredPackageName, fqbn.PlatformArch, fqbn),
+			}
 		}
 		referredPlatformRelease = pme.GetInstalledPlatformRelease(referredPlatform)
 		if referredPlatformRelease == nil {
-			return "", nil, "", nil,
-				fmt.Errorf(tr("missing platform release %[1]s:%[2]s referenced by board %[3]s"), referredPackageName, fqbn.PlatformArch, fqbn)
+			return "", nil, "", nil, &FQBNResolutionError{
+				Kind:               ErrReferencedPlatformNotInstalled,
+				ReferencedPlatform: referredPlatformID,
+				message:            fmt.Sprintf(tr("missing platform release %[1]s:%[2]s referenced by board %[3]s"), referredPackageName, fqbn.PlatformArch, fqbn),
+			}
 		}
 	}
 
@@ -437,38 +700,150 @@ func (pme *Explorer) determineReferencedPlatformRelease(boardBuildProperties *pr
 	return core, corePlatformRelease, variant, variantPlatformRelease, nil
 }
 
+// SetIndexSignatureVerification sets how the detached signatures of the
+// package indexes are verified while loading them.
+func (pmb *Builder) SetIndexSignatureVerification(settings packageindex.SignatureVerification) {
+	pmb.indexSignatures = settings
+}
+
+// SetMaxIndexSize sets the maximum size, in bytes, of the package index files
+// that can be loaded. Bigger files fail to load with an arduino.LimitExceededError.
+// A size of 0 or less means packageindex.DefaultMaxIndexSize.
+func (pmb *Builder) SetMaxIndexSize(size int64) {
+	pmb.maxIndexSize = size
+}
+
+// SetArchiveSignatureVerification sets how the detached signatures of the
+// downloaded platform and tool archives are verified.
+func (pmb *Builder) SetArchiveSignatureVerification(settings resources.ArchiveSignatureVerification) {
+	pmb.archiveSignatures = settings
+}
+
+// ArchiveSignatureVerification returns the settings used to verify the
+// detached signatures of the downloaded platform and tool archives.
+func (pme *Explorer) ArchiveSignatureVerification() resources.ArchiveSignatureVerification {
+	return pme.archiveSignatures
+}
+
 // LoadPackageIndex loads a package index by looking up the local cached file from the specified URL
 func (pmb *Builder) LoadPackageIndex(URL *url.URL) error {
+	indexPath, err := pmb.packageIndexPath(URL)
+	if err != nil {
+		return err
+	}
+	index, err := packageindex.LoadIndex(indexPath, pmb.indexSignatures, pmb.maxIndexSize)
+	if err != nil {
+		return fmt.Errorf("%s: %w", tr("loading json index file %s", indexPath), err)
+	}
+	pmb.mergePackageIndex(URL, index, indexPath)
+	return nil
+}
+
+// packageIndexPath returns the local cached file of the package index with the given URL
+func (pmb *Builder) packageIndexPath(URL *url.URL) (*paths.Path, error) {
+	return localPackageIndexPath(pmb.IndexDir, URL)
+}
+
+// localPackageIndexPath returns the file in indexDir where the package index
+// with the given URL is cached
+func localPackageIndexPath(indexDir *paths.Path, URL *url.URL) (*paths.Path, error) {
 	indexFileName := path.Base(URL.Path)
 	if indexFileName == "." || indexFileName == "" {
-		return &arduino.InvalidURLError{Cause: errors.New(URL.String())}
+		return nil, &arduino.InvalidURLError{Cause: errors.New(URL.String())}
 	}
 	if strings.HasSuffix(indexFileName, ".tar.bz2") {
 		indexFileName = strings.TrimSuffix(indexFileName, ".tar.bz2") + ".json"
 	}
-	indexPath := pmb.IndexDir.Join(indexFileName)
-	index, err := packageindex.LoadIndex(indexPath)
-	if err != nil {
-		return fmt.Errorf(tr("loading json index file %[1]s: %[2]s"), indexPath, err)
-	}
+	return indexDir.Join(indexFileName), nil
+}
 
+// mergePackageIndex merges the package index loaded from the given URL into the packages
+func (pmb *Builder) mergePackageIndex(URL *url.URL, index *packageindex.Index, indexPath *paths.Path) {
 	for _, p := range index.Packages {
 		p.URL = URL.String()
 	}
 
 	index.MergeIntoPackages(pmb.packages)
-	return nil
+	pmb.recordIndexProvenance(index, URL.String())
+	pmb.recordLoadedIndex(index, URL.String(), indexPath)
+	pmb.eventBus.emit(&Event{Kind: EventIndexLoaded, Subject: URL.String()})
+}
+
+// LoadPackageIndexes loads the package indexes with the given URLs, using
+// LoadPackageIndexFromFile for the file:// URLs and LoadPackageIndex for the
+// others. The index files are read and parsed concurrently, at most jobs at the
+// same time (if jobs is 0 or less the number of available CPUs is used), then
+// they are merged in the given order, so the result is the same as loading
+// them one by one. The returned slice contains, for each URL, the error that
+// prevented its loading or nil.
+func (pmb *Builder) LoadPackageIndexes(URLs []*url.URL, jobs int) []error {
+	type parsedIndex struct {
+		path  *paths.Path
+		index *packageindex.Index
+	}
+	parsed := make([]*parsedIndex, len(URLs))
+	errs := make([]error, len(URLs))
+
+	if jobs <= 0 {
+		jobs = runtime.NumCPU()
+	}
+	var wg sync.WaitGroup
+	semaphore := make(chan struct{}, jobs)
+	for i, URL := range URLs {
+		indexPath := paths.New(URL.Path)
+		if URL.Scheme != "file" {
+			var err error
+			if indexPath, err = pmb.packageIndexPath(URL); err != nil {
+				errs[i] = err
+				continue
+			}
+		}
+		wg.Add(1)
+		semaphore <- struct{}{}
+		go func(i int, indexPath *paths.Path) {
+			defer func() {
+				<-semaphore
+				wg.Done()
+			}()
+			index, err := packageindex.LoadIndex(indexPath, pmb.indexSignatures, pmb.maxIndexSize)
+			if err != nil {
+				errs[i] = fmt.Errorf("%s: %w", tr("loading json index file %s", indexPath), err)
+				return
+			}
+			parsed[i] = &parsedIndex{path: indexPath, index: index}
+		}(i, indexPath)
+	}
+	wg.Wait()
+
+	for i, URL := range URLs {
+		if parsed[i] == nil {
+			continue
+		}
+		if URL.Scheme == "file" {
+			pmb.mergePackageIndexFile(parsed[i].index, parsed[i].path)
+		} else {
+			pmb.mergePackageIndex(URL, parsed[i].index, parsed[i].path)
+		}
+	}
+	return errs
 }
 
 // LoadPackageIndexFromFile load a package index from the specified file
 func (pmb *Builder) LoadPackageIndexFromFile(indexPath *paths.Path) (*packageindex.Index, error) {
-	index, err := packageindex.LoadIndex(indexPath)
+	index, err := packageindex.LoadIndex(indexPath, pmb.indexSignatures, pmb.maxIndexSize)
 	if err != nil {
-		return nil, fmt.Errorf(tr("loading json index file %[1]s: %[2]s"), indexPath, err)
+		return nil, fmt.Errorf("%s: %w", tr("loading json index file %s", indexPath), err)
 	}
+	pmb.mergePackageIndexFile(index, indexPath)
+	return index, nil
+}
 
+// mergePackageIndexFile merges the package index loaded from the given file into the packages
+func (pmb *Builder) mergePackageIndexFile(index *packageindex.Index, indexPath *paths.Path) {
 	index.MergeIntoPackages(pmb.packages)
-	return index, nil
+	pmb.recordIndexProvenance(index, indexPath.String())
+	pmb.recordLoadedIndex(index, indexPath.String(), indexPath)
+	pmb.eventBus.emit(&Event{Kind: EventIndexLoaded, Subject: indexPath.String()})
 }
 
 // Package looks for the Package with the given name, returning a structure
@@ -513,6 +888,25 @@ func (pa *PackageActions) Tool(name string) *ToolActions {
 	}
 }
 
+// InstalledPlatforms returns the installed releases of all the platforms of
+// the Package, sorted by architecture and version
+func (pa *PackageActions) InstalledPlatforms() ([]*cores.PlatformRelease, error) {
+	if pa.forwardError != nil {
+		return nil, pa.forwardError
+	}
+	res := []*cores.PlatformRelease{}
+	for _, platform := range pa.aPackage.Platforms {
+		res = append(res, platform.GetAllInstalled()...)
+	}
+	sort.Slice(res, func(i, j int) bool {
+		if res[i].Platform.Architecture != res[j].Platform.Architecture {
+			return res[i].Platform.Architecture < res[j].Platform.Architecture
+		}
+		return res[i].Version.LessThan(res[j].Version)
+	})
+	return res, nil
+}
+
 // END -- Actions that can be done on a Package
 
 // Actions that can be done on a Tool
@@ -807,8 +1201,15 @@ func (pme *Explorer) FindToolsRequiredForBuild(platform, buildPlatform *cores.Pl
 }
 
 // FindToolDependency returns the ToolRelease referenced by the ToolDependency or nil if
-// the referenced tool doesn't exists.
+// the referenced tool doesn't exists. If the dependency has a version constraint the newest
+// installed release satisfying the constraint is returned.
 func (pme *Explorer) FindToolDependency(dep *cores.ToolDependency) *cores.ToolRelease {
+	if dep.ToolVersionConstraint != nil {
+		if candidates := pme.FindToolDependencyCandidates(dep); len(candidates) > 0 {
+			return candidates[0]
+		}
+		return nil
+	}
 	toolRelease, err := pme.Package(dep.ToolPackager).Tool(dep.ToolName).Release(dep.ToolVersion).Get()
 	if err != nil {
 		return nil
@@ -816,6 +1217,29 @@ func (pme *Explorer) FindToolDependency(dep *cores.ToolDependency) *cores.ToolRe
 	return toolRelease
 }
 
+// FindToolDependencyCandidates returns all the installed ToolReleases that satisfy
+// the given ToolDependency, sorted from the newest to the oldest.
+func (pme *Explorer) FindToolDependencyCandidates(dep *cores.ToolDependency) []*cores.ToolRelease {
+	res := []*cores.ToolRelease{}
+	targetPackage, ok := pme.packages[dep.ToolPackager]
+	if !ok {
+		return res
+	}
+	tool, ok := targetPackage.Tools[dep.ToolName]
+	if !ok {
+		return res
+	}
+	for _, release := range tool.Releases {
+		if release.IsInstalled() && dep.IsSatisfiedBy(release.Version) {
+			res = append(res, release)
+		}
+	}
+	sort.Slice(res, func(i, j int) bool {
+		return res[i].Version.GreaterThan(res[j].Version)
+	})
+	return res
+}
+
 // FindDiscoveryDependency returns the ToolRelease referenced by the DiscoveryDepenency or nil if
 // the referenced discovery doesn't exists.
 func (pme *Explorer) FindDiscoveryDependency(discovery *cores.DiscoveryDependency) *cores.ToolRelease {
@@ -54,6 +54,148 @@ func TestFindBoardWithFQBN(t *testing.T) {
 	require.Equal(t, board.Name(), "Arduino/Genuino Mega or Mega 2560")
 }
 
+func TestFQBNAliases(t *testing.T) {
+	pmb := NewBuilder(customHardware, customHardware, customHardware, customHardware, "test")
+	pmb.LoadHardwareFromDirectory(customHardware)
+	pmb.AddFQBNAlias("MyMega", "arduino:avr:mega:cpu=atmega1280")
+	pmb.AddFQBNAlias("broken", "arduino:avr")
+	pm := pmb.Build()
+	pme, release := pm.NewExplorer()
+	defer release()
+
+	fqbn, err := pme.ParseFQBN("mymega")
+	require.NoError(t, err)
+	require.Equal(t, "arduino:avr:mega:cpu=atmega1280", fqbn.String())
+
+	board, err := pme.FindBoardWithFQBN("MyMega")
+	require.NoError(t, err)
+	require.Equal(t, "arduino:avr:mega", board.FQBN())
+	_, _, _, buildProperties, _, err := pme.ResolveFQBN(fqbn)
+	require.NoError(t, err)
+	require.Equal(t, "atmega1280", buildProperties.Get("build.mcu"))
+
+	// Valid FQBNs are not expanded
+	fqbn, err = pme.ParseFQBN("arduino:avr:uno")
+	require.NoError(t, err)
+	require.Equal(t, "arduino:avr:uno", fqbn.String())
+
+	_, err = pme.ParseFQBN("broken")
+	require.ErrorContains(t, err, "alias broken points to an invalid fqbn arduino:avr")
+
+	_, err = pme.ParseFQBN("unknown")
+	require.Error(t, err)
+}
+
+func TestDiffBuildProperties(t *testing.T) {
+	pmb := NewBuilder(customHardware, customHardware, customHardware, customHardware, "test")
+	pmb.LoadHardwareFromDirectory(customHardware)
+	pm := pmb.Build()
+	pme, release := pm.NewExplorer()
+	defer release()
+
+	diff, err := pme.DiffBuildProperties("arduino:avr:mega:cpu=atmega2560", "arduino:avr:mega:cpu=atmega1280")
+	require.NoError(t, err)
+	require.Equal(t, map[string][2]string{
+		"upload.protocol":           {"wiring", "arduino"},
+		"upload.maximum_size":       {"253952", "126976"},
+		"upload.speed":              {"115200", "57600"},
+		"bootloader.high_fuses":     {"0xD8", "0xDA"},
+		"bootloader.extended_fuses": {"0xFD", "0xF5"},
+		"bootloader.file":           {"stk500v2/stk500boot_v2_mega2560.hex", "atmega/ATmegaBOOT_168_atmega1280.hex"},
+		"build.mcu":                 {"atmega2560", "atmega1280"},
+		"build.board":               {"AVR_MEGA2560", "AVR_MEGA"},
+		"build.fqbn":                {"arduino:avr:mega:cpu=atmega2560", "arduino:avr:mega:cpu=atmega1280"},
+	}, diff)
+
+	// Explicitly selecting the default option changes only the fqbn
+	diff, err = pme.DiffBuildProperties("arduino:avr:mega", "arduino:avr:mega:cpu=atmega2560")
+	require.NoError(t, err)
+	require.Equal(t, map[string][2]string{
+		"build.fqbn": {"arduino:avr:mega", "arduino:avr:mega:cpu=atmega2560"},
+	}, diff)
+
+	_, err = pme.DiffBuildProperties("arduino:avr:mega", "arduino:avr:nonexistent")
+	require.Error(t, err)
+}
+
+func TestBoardName(t *testing.T) {
+	pmb := NewBuilder(customHardware, customHardware, customHardware, customHardware, "test")
+	pmb.LoadHardwareFromDirectory(customHardware)
+	pmb.AddFQBNAlias("due", "arduino:sam:arduino_due_x")
+	pm := pmb.Build()
+	pme, release := pm.NewExplorer()
+	defer release()
+
+	name, err := pme.BoardName("arduino:sam:arduino_due_x")
+	require.NoError(t, err)
+	require.Equal(t, "Arduino Due (Native USB Port)", name)
+
+	fullName, err := pme.BoardFullName("due")
+	require.NoError(t, err)
+	require.Equal(t, "Arduino ARM (32-bits) Boards / Arduino Due (Native USB Port)", fullName)
+
+	// The platform name is generated if not available
+	fullName, err = pme.BoardFullName("arduino:avr:uno")
+	require.NoError(t, err)
+	require.Equal(t, "arduino-avr / Arduino/Genuino Uno", fullName)
+
+	_, err = pme.BoardName("arduino:sam:missing")
+	require.ErrorContains(t, err, "board arduino:sam:missing not found")
+	_, err = pme.BoardFullName("arduino:sam")
+	require.Error(t, err)
+}
+
+func TestUnresolvedProperties(t *testing.T) {
+	hardwareDir := paths.New(t.TempDir())
+	platformDir := hardwareDir.Join("test", "avr")
+	require.NoError(t, platformDir.MkdirAll())
+	require.NoError(t, platformDir.Join("boards.txt").WriteFile([]byte(
+		"uno.name=Test Uno\n"+
+			"uno.build.mcu=atmega328p\n"+
+			"uno.build.board=TEST_UNO\n")))
+	require.NoError(t, platformDir.Join("platform.txt").WriteFile([]byte(
+		"name=Test\n"+
+			"version=1.0.0\n"+
+			"compiler.path=${runtime.tools.gcc.path}/bin/\n"+
+			"recipe.c.o.pattern=\"{compiler.path}gcc\" -mmcu={build.mcu} -c \"{source_file}\"\n"+
+			"recipe.size.pattern=\"{runtime.tools.size.path}/size\"\n")))
+
+	pmb := NewBuilder(nil, nil, nil, nil, "test")
+	pmb.LoadHardwareFromDirectory(hardwareDir)
+	pm := pmb.Build()
+	pme, release := pm.NewExplorer()
+	defer release()
+
+	unresolved, err := pme.UnresolvedProperties("test:avr:uno")
+	require.NoError(t, err)
+	require.Equal(t, []string{"compiler.path", "recipe.c.o.pattern"}, unresolved)
+
+	_, err = pme.UnresolvedProperties("test:avr:nonexistent")
+	require.Error(t, err)
+}
+
+func TestPlatformRecipes(t *testing.T) {
+	pmb := NewBuilder(nil, nil, nil, nil, "test")
+	pmb.LoadHardwareFromDirectory(dataDir1.Join("packages"))
+	pm := pmb.Build()
+	pme, release := pm.NewExplorer()
+	defer release()
+
+	fqbn, err := cores.ParseFQBN("esp8266:esp8266:generic")
+	require.NoError(t, err)
+	_, _, _, buildProperties, _, err := pme.ResolveFQBN(fqbn)
+	require.NoError(t, err)
+
+	recipes := PlatformRecipes(buildProperties)
+	require.Equal(t, buildProperties.Get("recipe.c.o.pattern"), recipes["recipe.c.o.pattern"])
+	require.Contains(t, recipes, "recipe.c.combine.pattern")
+	require.Contains(t, recipes["recipe.hooks.linking.prelink.1.pattern"], "{runtime.tools.mkdir}")
+	for key := range recipes {
+		require.True(t, strings.HasPrefix(key, "recipe."), key)
+	}
+	require.NotContains(t, recipes, "build.mcu")
+}
+
 func TestResolveFQBN(t *testing.T) {
 	// Pass nil, since these paths are only used for installing
 	pmb := NewBuilder(nil, nil, nil, nil, "test")
@@ -340,6 +482,59 @@ func TestResolveFQBN(t *testing.T) {
 	})
 }
 
+func TestResolveFQBNErrors(t *testing.T) {
+	pmb := NewBuilder(nil, nil, nil, nil, "test")
+	pmb.LoadHardwareFromDirectory(customHardware)
+	pmb.LoadHardwareFromDirectory(extraHardware)
+	// A platform known from the package indexes but not installed
+	pmb.GetOrCreatePackage("indexed").GetOrCreatePlatform("avr").GetOrCreateRelease(semver.MustParse("1.0.0"))
+	pme, release := pmb.Build().NewExplorer()
+	defer release()
+
+	resolve := func(fqbn string) *FQBNResolutionError {
+		parsed, err := cores.ParseFQBN(fqbn)
+		require.NoError(t, err)
+		_, _, _, _, _, err = pme.ResolveFQBN(parsed)
+		var resolutionErr *FQBNResolutionError
+		require.ErrorAs(t, err, &resolutionErr)
+		require.Equal(t, fqbn, resolutionErr.FQBN.String())
+		return resolutionErr
+	}
+
+	err := resolve("unknown:avr:uno")
+	require.ErrorIs(t, err, ErrPackageNotFound)
+	require.Nil(t, err.Package)
+	require.EqualError(t, err, "unknown package unknown")
+
+	err = resolve("arduino:unknown:uno")
+	require.ErrorIs(t, err, ErrPlatformNotFound)
+	require.Equal(t, "arduino", err.Package.Name)
+	require.Nil(t, err.Platform)
+
+	err = resolve("indexed:avr:uno")
+	require.ErrorIs(t, err, ErrPlatformNotInstalled)
+	require.NotErrorIs(t, err, ErrBoardNotFound)
+	require.Equal(t, "indexed:avr", err.Platform.String())
+	require.Nil(t, err.PlatformRelease)
+
+	err = resolve("arduino:avr:unknown")
+	require.ErrorIs(t, err, ErrBoardNotFound)
+	require.Equal(t, "arduino:avr", err.Platform.String())
+	require.NotNil(t, err.PlatformRelease)
+	require.Nil(t, err.Board)
+	require.EqualError(t, err, "board arduino:avr:unknown not found")
+
+	err = resolve("arduino:avr:mega:cpu=nonexistent")
+	require.ErrorIs(t, err, ErrInvalidBoardConfiguration)
+	require.Equal(t, "mega", err.Board.BoardID)
+	require.NotNil(t, err.Cause)
+
+	err = resolve("referenced:avr:dummy_invalid_platform")
+	require.ErrorIs(t, err, ErrReferencedPlatformNotFound)
+	require.Equal(t, "adafruit:avr", err.ReferencedPlatform)
+	require.Equal(t, "dummy_invalid_platform", err.Board.BoardID)
+}
+
 func TestBoardOptionsFunctions(t *testing.T) {
 	pmb := NewBuilder(customHardware, customHardware, customHardware, customHardware, "test")
 	pmb.LoadHardwareFromDirectory(customHardware)
@@ -430,6 +625,26 @@ func TestBoardOrdering(t *testing.T) {
 	require.Equal(t, expected, res)
 }
 
+func TestPlatformMenus(t *testing.T) {
+	pmb := NewBuilder(dataDir1, dataDir1.Join("packages"), nil, nil, "")
+	_ = pmb.LoadHardwareFromDirectories(paths.NewPathList(dataDir1.Join("packages").String()))
+	pm := pmb.Build()
+	pme, release := pm.NewExplorer()
+	defer release()
+
+	pl := pme.FindPlatform(&PlatformReference{
+		Package:              "test2",
+		PlatformArchitecture: "avr",
+	})
+	require.NotNil(t, pl)
+	plReleases := pl.GetAllInstalled()
+	require.NotEmpty(t, plReleases)
+	require.Equal(t, []cores.MenuDefinition{
+		{ID: "core", Label: "Core"},
+		{ID: "variant", Label: "Variant"},
+	}, cores.PlatformMenus(plReleases[0]))
+}
+
 func TestFindToolsRequiredForBoard(t *testing.T) {
 	t.Setenv("ARDUINO_DATA_DIR", dataDir1.String())
 	configuration.Settings = configuration.Init("")
@@ -741,6 +956,70 @@ func TestFindToolsRequiredFromPlatformRelease(t *testing.T) {
 	require.Len(t, tools, 6)
 }
 
+func TestFindToolDependencyCandidates(t *testing.T) {
+	pmb := NewBuilder(nil, nil, nil, nil, "test")
+	tool := pmb.GetOrCreatePackage("arduino").GetOrCreateTool("bossac")
+	for _, version := range []string{"1.6.1", "1.7.0", "1.8.1", "1.9.0"} {
+		release := tool.GetOrCreateRelease(semver.ParseRelaxed(version))
+		release.InstallDir = paths.New(t.TempDir())
+	}
+	// Not installed
+	tool.GetOrCreateRelease(semver.ParseRelaxed("1.8.5"))
+	pm := pmb.Build()
+	pme, release := pm.NewExplorer()
+	defer release()
+
+	constraint, err := semver.ParseConstraint(">=1.7.0 && <1.9.0")
+	require.NoError(t, err)
+	dep := &cores.ToolDependency{
+		ToolPackager:          "arduino",
+		ToolName:              "bossac",
+		ToolVersionConstraint: constraint,
+	}
+	candidates := pme.FindToolDependencyCandidates(dep)
+	require.Len(t, candidates, 2)
+	require.Equal(t, "arduino:bossac@1.8.1", candidates[0].String())
+	require.Equal(t, "arduino:bossac@1.7.0", candidates[1].String())
+	require.Equal(t, candidates[0], pme.FindToolDependency(dep))
+
+	// Exact version dependency
+	dep = &cores.ToolDependency{
+		ToolPackager: "arduino",
+		ToolName:     "bossac",
+		ToolVersion:  semver.ParseRelaxed("1.6.1"),
+	}
+	candidates = pme.FindToolDependencyCandidates(dep)
+	require.Len(t, candidates, 1)
+	require.Equal(t, "arduino:bossac@1.6.1", candidates[0].String())
+
+	// Unknown tool
+	require.Empty(t, pme.FindToolDependencyCandidates(&cores.ToolDependency{ToolPackager: "arduino", ToolName: "nonexistent"}))
+}
+
+func TestPackageInstalledPlatforms(t *testing.T) {
+	pmb := NewBuilder(nil, nil, nil, nil, "test")
+	pack := pmb.GetOrCreatePackage("arduino")
+	for _, arch := range []string{"samd", "avr"} {
+		release := pack.GetOrCreatePlatform(arch).GetOrCreateRelease(semver.MustParse("1.0.0"))
+		release.InstallDir = paths.New(t.TempDir())
+	}
+	// Not installed
+	pack.GetOrCreatePlatform("avr").GetOrCreateRelease(semver.MustParse("1.1.0"))
+	pack.GetOrCreatePlatform("mbed").GetOrCreateRelease(semver.MustParse("1.0.0"))
+	pm := pmb.Build()
+	pme, release := pm.NewExplorer()
+	defer release()
+
+	installed, err := pme.Package("arduino").InstalledPlatforms()
+	require.NoError(t, err)
+	require.Len(t, installed, 2)
+	require.Equal(t, "arduino:avr@1.0.0", installed[0].String())
+	require.Equal(t, "arduino:samd@1.0.0", installed[1].String())
+
+	_, err = pme.Package("nonexistent").InstalledPlatforms()
+	require.Error(t, err)
+}
+
 func TestFindPlatformReleaseDependencies(t *testing.T) {
 	pmb := NewBuilder(nil, nil, nil, nil, "test")
 	pmb.LoadPackageIndexFromFile(paths.New("testdata", "package_tooltest_index.json"))
@@ -921,6 +1200,47 @@ func TestVariantAndCoreSelection(t *testing.T) {
 	})
 }
 
+func TestResolveFQBNWithCrossPlatformVariant(t *testing.T) {
+	hardwareDir := paths.New(t.TempDir())
+	vendorDir := hardwareDir.Join("vendor", "avr")
+	require.NoError(t, vendorDir.Join("variants", "special").MkdirAll())
+	require.NoError(t, vendorDir.Join("cores", "vendorcore").MkdirAll())
+	require.NoError(t, vendorDir.Join("boards.txt").WriteFile([]byte("")))
+	require.NoError(t, vendorDir.Join("platform.txt").WriteFile([]byte("name=Vendor\nversion=1.0.0\nvendor.prop=vendor\n")))
+	thirdDir := hardwareDir.Join("third", "avr")
+	require.NoError(t, thirdDir.Join("cores", "thirdcore").MkdirAll())
+	require.NoError(t, thirdDir.Join("boards.txt").WriteFile([]byte(
+		"board.name=Board with vendor variant\n"+
+			"board.build.core=thirdcore\n"+
+			"board.build.variant=vendor:special\n"+
+			"missing.name=Board with missing variant platform\n"+
+			"missing.build.core=thirdcore\n"+
+			"missing.build.variant=nonexistent:special\n")))
+	require.NoError(t, thirdDir.Join("platform.txt").WriteFile([]byte("name=Third\nversion=1.0.0\n")))
+
+	pmb := NewBuilder(nil, nil, nil, nil, "test")
+	pmb.LoadHardwareFromDirectory(hardwareDir)
+	pm := pmb.Build()
+	pme, release := pm.NewExplorer()
+	defer release()
+
+	fqbn, err := cores.ParseFQBN("third:avr:board")
+	require.NoError(t, err)
+	_, boardPlatform, _, buildProps, buildPlatform, err := pme.ResolveFQBN(fqbn)
+	require.NoError(t, err)
+	// The core is taken from the board platform, only the variant is referenced
+	require.Equal(t, boardPlatform, buildPlatform)
+	require.True(t, buildProps.GetPath("build.core.path").EquivalentTo(thirdDir.Join("cores", "thirdcore")))
+	require.True(t, buildProps.GetPath("build.variant.path").EquivalentTo(vendorDir.Join("variants", "special")))
+	// The properties of the referenced platform are inherited too
+	require.Equal(t, "vendor", buildProps.Get("vendor.prop"))
+
+	fqbn, err = cores.ParseFQBN("third:avr:missing")
+	require.NoError(t, err)
+	_, _, _, _, _, err = pme.ResolveFQBN(fqbn)
+	require.EqualError(t, err, "missing package nonexistent referenced by board third:avr:missing")
+}
+
 func TestRunScript(t *testing.T) {
 	pmb := NewBuilder(nil, nil, nil, nil, "test")
 	pm := pmb.Build()
@@ -0,0 +1,90 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2023 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package packagemanager
+
+import (
+	"time"
+
+	"github.com/arduino/arduino-cli/arduino/cores"
+)
+
+// PlatformDeprecation is a warning about a platform that has been deprecated
+// or that has, or will reach, its end of life
+type PlatformDeprecation struct {
+	Platform *cores.Platform
+	// Deprecated is true if the platform is marked as deprecated in the
+	// package index
+	Deprecated bool
+	// EndOfLife is when the platform stops being supported, zero if not
+	// planned
+	EndOfLife time.Time
+	// EndOfLifeReached is true if the EndOfLife date has already passed
+	EndOfLifeReached bool
+	// Message is the reason of the deprecation or the migration instructions
+	Message string
+	// ReplacedBy is the platform (PACKAGER:ARCH) that should be used instead
+	ReplacedBy string
+}
+
+func (d *PlatformDeprecation) String() string {
+	endOfLife := d.EndOfLife.Format("2006-01-02")
+	var res string
+	switch {
+	case d.EndOfLifeReached:
+		res = tr("Platform %[1]s reached its end of life on %[2]s", d.Platform, endOfLife)
+	case d.Deprecated && !d.EndOfLife.IsZero():
+		res = tr("Platform %[1]s is deprecated and will reach its end of life on %[2]s", d.Platform, endOfLife)
+	case d.Deprecated:
+		res = tr("Platform %s is deprecated", d.Platform)
+	default:
+		res = tr("Platform %[1]s will reach its end of life on %[2]s", d.Platform, endOfLife)
+	}
+	if d.ReplacedBy != "" {
+		res += ", " + tr("use %s instead", d.ReplacedBy)
+	}
+	if d.Message != "" {
+		res += ": " + d.Message
+	}
+	return res
+}
+
+// FindPlatformDeprecations checks the given platform releases, for example the
+// board and build platforms returned by ResolveFQBN, and returns a warning for
+// each platform that is deprecated or has an end of life date. Each platform
+// is reported once, nil releases are ignored.
+func FindPlatformDeprecations(platformReleases ...*cores.PlatformRelease) []*PlatformDeprecation {
+	res := []*PlatformDeprecation{}
+	seen := map[*cores.Platform]bool{}
+	for _, platformRelease := range platformReleases {
+		if platformRelease == nil || seen[platformRelease.Platform] {
+			continue
+		}
+		platform := platformRelease.Platform
+		seen[platform] = true
+		if !platform.Deprecated && platform.EndOfLife.IsZero() {
+			continue
+		}
+		res = append(res, &PlatformDeprecation{
+			Platform:         platform,
+			Deprecated:       platform.Deprecated,
+			EndOfLife:        platform.EndOfLife,
+			EndOfLifeReached: platform.EndOfLifeReached(),
+			Message:          platform.DeprecationMessage,
+			ReplacedBy:       platform.ReplacedBy,
+		})
+	}
+	return res
+}
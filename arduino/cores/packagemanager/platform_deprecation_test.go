@@ -0,0 +1,63 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2023 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package packagemanager
+
+import (
+	"testing"
+	"time"
+
+	"github.com/arduino/arduino-cli/arduino/cores"
+	"github.com/stretchr/testify/require"
+	semver "go.bug.st/relaxed-semver"
+)
+
+func TestFindPlatformDeprecations(t *testing.T) {
+	packages := cores.NewPackages()
+	pack := packages.GetOrCreatePackage("test")
+	release := func(arch string) *cores.PlatformRelease {
+		return pack.GetOrCreatePlatform(arch).GetOrCreateRelease(semver.MustParse("1.0.0"))
+	}
+	avr := release("avr")
+	old := release("old")
+	old.Platform.Deprecated = true
+	old.Platform.ReplacedBy = "test:avr"
+	old.Platform.DeprecationMessage = "see the migration guide"
+	eol := release("eol")
+	eol.Platform.EndOfLife = time.Date(2020, 1, 31, 0, 0, 0, 0, time.UTC)
+	future := release("future")
+	future.Platform.Deprecated = true
+	future.Platform.EndOfLife = time.Date(2999, 12, 31, 0, 0, 0, 0, time.UTC)
+	planned := release("planned")
+	planned.Platform.EndOfLife = future.Platform.EndOfLife
+
+	require.Empty(t, FindPlatformDeprecations(avr, nil, avr))
+
+	res := FindPlatformDeprecations(avr, old, old)
+	require.Len(t, res, 1)
+	require.Equal(t, old.Platform, res[0].Platform)
+	require.True(t, res[0].Deprecated)
+	require.False(t, res[0].EndOfLifeReached)
+	require.Equal(t, "Platform test:old is deprecated, use test:avr instead: see the migration guide", res[0].String())
+
+	res = FindPlatformDeprecations(eol, future, planned)
+	require.Len(t, res, 3)
+	require.False(t, res[0].Deprecated)
+	require.True(t, res[0].EndOfLifeReached)
+	require.Equal(t, "Platform test:eol reached its end of life on 2020-01-31", res[0].String())
+	require.False(t, res[1].EndOfLifeReached)
+	require.Equal(t, "Platform test:future is deprecated and will reach its end of life on 2999-12-31", res[1].String())
+	require.Equal(t, "Platform test:planned will reach its end of life on 2999-12-31", res[2].String())
+}
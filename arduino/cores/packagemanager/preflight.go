@@ -0,0 +1,155 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2023 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package packagemanager
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/arduino/arduino-cli/arduino/cores"
+	properties "github.com/arduino/go-properties-orderedmap"
+)
+
+// PreflightIssueKind is the kind of check that failed during a preflight
+type PreflightIssueKind string
+
+const (
+	// PreflightIssueFQBN means that the FQBN is malformed or has invalid config options
+	PreflightIssueFQBN PreflightIssueKind = "fqbn"
+	// PreflightIssuePlatform means that the platform or the board can't be found
+	PreflightIssuePlatform PreflightIssueKind = "platform"
+	// PreflightIssueTool means that a required tool is missing or not usable
+	PreflightIssueTool PreflightIssueKind = "tool"
+	// PreflightIssueRecipe means that a recipe required to build is missing
+	PreflightIssueRecipe PreflightIssueKind = "recipe"
+	// PreflightIssueConfig means that the build properties reference something
+	// that is not available
+	PreflightIssueConfig PreflightIssueKind = "config"
+)
+
+// PreflightIssue is a problem that prevents building for a board
+type PreflightIssue struct {
+	Kind    PreflightIssueKind
+	Message string
+}
+
+func (i *PreflightIssue) String() string {
+	return fmt.Sprintf("%s: %s", i.Kind, i.Message)
+}
+
+// preflightRequiredRecipes are the recipes that every platform must provide
+// to build a sketch
+var preflightRequiredRecipes = []string{
+	"recipe.c.o.pattern",
+	"recipe.cpp.o.pattern",
+	"recipe.S.o.pattern",
+	"recipe.ar.pattern",
+	"recipe.c.combine.pattern",
+}
+
+// PreflightBuild checks, without compiling anything, that everything needed to
+// build for the given FQBN is available: the platform and the board are
+// installed, the config options are valid, all the tools required to build are
+// installed with binaries usable on the running host, the required recipes are
+// defined and every referenced tool is available. An empty slice is returned
+// if the board is ready to build.
+func (pme *Explorer) PreflightBuild(fqbnIn string) []*PreflightIssue {
+	issues := []*PreflightIssue{}
+	report := func(kind PreflightIssueKind, format string, args ...interface{}) {
+		issues = append(issues, &PreflightIssue{Kind: kind, Message: fmt.Sprintf(format, args...)})
+	}
+
+	fqbn, err := cores.ParseFQBN(fqbnIn)
+	if err != nil {
+		report(PreflightIssueFQBN, "%s", err)
+		return issues
+	}
+	_, boardPlatform, board, buildProperties, buildPlatform, err := pme.ResolveFQBN(fqbn)
+	if boardPlatform == nil || board == nil {
+		report(PreflightIssuePlatform, "%s", err)
+		return issues
+	}
+	if buildProperties == nil {
+		// The board has been found, so the config or the referenced core are wrong
+		report(PreflightIssueFQBN, "%s", err)
+		return issues
+	}
+	// Any other error comes from a missing tool, that is reported below in detail
+
+	platforms := []*cores.PlatformRelease{boardPlatform}
+	if buildPlatform != nil && buildPlatform != boardPlatform {
+		platforms = append(platforms, buildPlatform)
+	}
+	for _, platform := range platforms {
+		depsRoles := ToolDependenciesRoles(platform)
+		platform.ToolDependencies.Sort()
+		for _, toolDep := range platform.ToolDependencies {
+			isBuildTool := false
+			for _, role := range depsRoles[toolDep] {
+				isBuildTool = isBuildTool || role == ToolRoleBuild
+			}
+			if !isBuildTool {
+				continue
+			}
+			tool := pme.FindToolDependency(toolDep)
+			if tool == nil || !tool.IsInstalled() {
+				report(PreflightIssueTool, tr("tool %[1]s required by %[2]s is not installed"), toolDep, platform)
+				continue
+			}
+			if !tool.InstallDir.IsDir() {
+				report(PreflightIssueTool, tr("installation directory of tool %[1]s is missing: %[2]s"), tool, tool.InstallDir)
+				continue
+			}
+			if len(tool.Flavors) > 0 && tool.GetCompatibleFlavour() == nil {
+				report(PreflightIssueTool, tr("tool %s is not available for this operating system"), tool)
+			}
+		}
+	}
+
+	for _, recipe := range preflightRequiredRecipes {
+		if buildProperties.Get(recipe) == "" {
+			report(PreflightIssueRecipe, tr("recipe %[1]s is not defined by %[2]s"), recipe, buildPlatform)
+		}
+	}
+
+	for _, missing := range unresolvedRuntimeTools(buildProperties) {
+		report(PreflightIssueConfig, tr("%[1]s references the tool %[2]s that is not available"), missing.key, missing.tool)
+	}
+	return issues
+}
+
+type unresolvedRuntimeTool struct {
+	key  string
+	tool string
+}
+
+// unresolvedRuntimeTools returns the recipes and the compiler settings that
+// reference, through a {runtime.tools.TOOL.path} placeholder, a tool that is
+// not installed.
+func unresolvedRuntimeTools(buildProperties *properties.Map) []unresolvedRuntimeTool {
+	res := []unresolvedRuntimeTool{}
+	for _, key := range buildProperties.Keys() {
+		if !strings.HasPrefix(key, "recipe.") && !strings.HasPrefix(key, "compiler.") {
+			continue
+		}
+		for _, match := range runtimeToolPathRegexp.FindAllStringSubmatch(buildProperties.Get(key), -1) {
+			if !buildProperties.ContainsKey("runtime.tools." + match[1] + ".path") {
+				res = append(res, unresolvedRuntimeTool{key: key, tool: match[1]})
+			}
+		}
+	}
+	return res
+}
@@ -0,0 +1,79 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2023 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package packagemanager
+
+import (
+	"testing"
+
+	"github.com/arduino/arduino-cli/arduino/cores"
+	"github.com/arduino/go-paths-helper"
+	properties "github.com/arduino/go-properties-orderedmap"
+	"github.com/stretchr/testify/require"
+	semver "go.bug.st/relaxed-semver"
+)
+
+func TestPreflightBuild(t *testing.T) {
+	pmb := NewBuilder(nil, nil, nil, nil, "test")
+	pack := pmb.GetOrCreatePackage("test")
+	gcc := pack.GetOrCreateTool("gcc").GetOrCreateRelease(semver.ParseRelaxed("1.0.0"))
+	gcc.InstallDir = paths.New(t.TempDir())
+	// Available in the index but not installed
+	pack.GetOrCreateTool("other-gcc").GetOrCreateRelease(semver.ParseRelaxed("2.0.0"))
+
+	addPlatform := func(arch, toolName, toolVersion string) {
+		platform := pack.GetOrCreatePlatform(arch).GetOrCreateRelease(semver.MustParse("1.0.0"))
+		platform.InstallDir = paths.New(t.TempDir())
+		platform.ToolDependencies = cores.ToolDependencies{
+			{ToolPackager: "test", ToolName: toolName, ToolVersion: semver.ParseRelaxed(toolVersion)},
+		}
+		platform.Properties = properties.NewFromHashmap(map[string]string{
+			"compiler.path":            "{runtime.tools." + toolName + ".path}/bin/",
+			"recipe.c.o.pattern":       `"{compiler.path}gcc" -c`,
+			"recipe.cpp.o.pattern":     `"{compiler.path}g++" -c`,
+			"recipe.S.o.pattern":       `"{compiler.path}gcc" -c -x assembler-with-cpp`,
+			"recipe.ar.pattern":        `"{compiler.path}ar" rcs`,
+			"recipe.c.combine.pattern": `"{compiler.path}gcc" -o`,
+		})
+		platform.Menus = properties.NewMap()
+		board := platform.GetOrCreateBoard("board")
+		board.Properties = properties.NewFromHashmap(map[string]string{
+			"name":       "Board",
+			"build.core": "arduino",
+		})
+	}
+	addPlatform("ready", "gcc", "1.0.0")
+	addPlatform("broken", "other-gcc", "2.0.0")
+	pm := pmb.Build()
+	pme, release := pm.NewExplorer()
+	defer release()
+
+	require.Empty(t, pme.PreflightBuild("test:ready:board"))
+
+	issues := pme.PreflightBuild("test:broken:board")
+	require.Len(t, issues, 2)
+	require.Equal(t, PreflightIssueTool, issues[0].Kind)
+	require.Contains(t, issues[0].Message, "test:other-gcc@2.0.0")
+	require.Equal(t, PreflightIssueConfig, issues[1].Kind)
+	require.Contains(t, issues[1].Message, "compiler.path")
+
+	issues = pme.PreflightBuild("test:ready:board:opt=val")
+	require.Len(t, issues, 1)
+	require.Equal(t, PreflightIssueFQBN, issues[0].Kind)
+
+	issues = pme.PreflightBuild("test:missing:board")
+	require.Len(t, issues, 1)
+	require.Equal(t, PreflightIssuePlatform, issues[0].Kind)
+}
@@ -92,6 +92,9 @@ func (pmb *Builder) installMissingProfilePlatform(platformRef *sketch.ProfilePla
 		return fmt.Errorf("installing missing platform: could not create temp dir %s", err)
 	}
 	tmpPmb := NewBuilder(tmp, tmp, pmb.DownloadDir, tmp, pmb.userAgent)
+	tmpPmb.maxIndexSize = pmb.maxIndexSize
+	tmpPmb.indexSignatures = pmb.indexSignatures
+	tmpPmb.archiveSignatures = pmb.archiveSignatures
 	defer tmp.RemoveAll()
 
 	// Download the main index and parse it
@@ -145,6 +148,20 @@ func (pmb *Builder) loadProfileTool(toolRef *cores.ToolDependency, indexURL *url
 	targetPackage := pmb.packages.GetOrCreatePackage(toolRef.ToolPackager)
 	tool := targetPackage.GetOrCreateTool(toolRef.ToolName)
 
+	if toolRef.ToolVersionConstraint != nil {
+		// A version range must be resolved to a release, the installed ones
+		// are preferred
+		toolRelease := tool.FindReleaseForDependency(toolRef)
+		if toolRelease == nil {
+			return &arduino.InvalidVersionError{Cause: fmt.Errorf(tr("no release satisfies %s", toolRef))}
+		}
+		toolRef = &cores.ToolDependency{
+			ToolPackager: toolRef.ToolPackager,
+			ToolName:     toolRef.ToolName,
+			ToolVersion:  toolRelease.Version,
+		}
+	}
+
 	uid := toolRef.InternalUniqueIdentifier(indexURL)
 	destDir := configuration.ProfilesCacheDir(configuration.Settings).Join(uid)
 
@@ -176,7 +193,7 @@ func (pmb *Builder) installMissingProfileTool(toolRelease *cores.ToolRelease, de
 		return &arduino.InvalidVersionError{Cause: fmt.Errorf(tr("version %s not available for this operating system", toolRelease))}
 	}
 	taskCB(&rpc.TaskProgress{Name: tr("Downloading tool %s", toolRelease)})
-	if err := toolResource.Download(pmb.DownloadDir, nil, toolRelease.String(), downloadCB, ""); err != nil {
+	if err := toolResource.Download(pmb.DownloadDir, nil, toolRelease.String(), downloadCB, "", pmb.archiveSignatures); err != nil {
 		taskCB(&rpc.TaskProgress{Name: tr("Error downloading tool %s", toolRelease)})
 		return &arduino.FailedInstallError{Message: tr("Error installing tool %s", toolRelease), Cause: err}
 	}
@@ -184,7 +201,7 @@ func (pmb *Builder) installMissingProfileTool(toolRelease *cores.ToolRelease, de
 
 	// Install tool
 	taskCB(&rpc.TaskProgress{Name: tr("Installing tool %s", toolRelease)})
-	if err := toolResource.Install(pmb.DownloadDir, tmp, destDir); err != nil {
+	if err := toolResource.Install(pmb.DownloadDir, tmp, destDir, pmb.archiveSignatures); err != nil {
 		taskCB(&rpc.TaskProgress{Name: tr("Error installing tool %s", toolRelease)})
 		return &arduino.FailedInstallError{Message: tr("Error installing tool %s", toolRelease), Cause: err}
 	}
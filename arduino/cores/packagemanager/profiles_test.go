@@ -0,0 +1,85 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2023 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package packagemanager
+
+import (
+	"testing"
+
+	"github.com/arduino/arduino-cli/arduino/cores"
+	"github.com/arduino/arduino-cli/arduino/sketch"
+	"github.com/arduino/arduino-cli/configuration"
+	rpc "github.com/arduino/arduino-cli/rpc/cc/arduino/cli/commands/v1"
+	"github.com/arduino/go-paths-helper"
+	"github.com/stretchr/testify/require"
+	semver "go.bug.st/relaxed-semver"
+)
+
+func TestLoadHardwareForProfileWithToolVersionRange(t *testing.T) {
+	dataDir := paths.New(t.TempDir())
+	t.Setenv("ARDUINO_DATA_DIR", dataDir.String())
+	configuration.Settings = configuration.Init("")
+	cacheDir := configuration.ProfilesCacheDir(configuration.Settings)
+
+	platformRef := &sketch.ProfilePlatformReference{Packager: "test", Architecture: "esp", Version: semver.MustParse("1.0.0")}
+	platformDir := cacheDir.Join(platformRef.InternalUniqueIdentifier())
+	require.NoError(t, platformDir.MkdirAll())
+	require.NoError(t, platformDir.Join("platform.txt").WriteFile([]byte("name=Test\nversion=1.0.0\n")))
+	require.NoError(t, platformDir.Join("boards.txt").WriteFile([]byte("dev.name=Dev Board\n")))
+	require.NoError(t, platformDir.Join("installed.json").WriteFile([]byte(`{"packages": [{
+		"name": "test",
+		"platforms": [{"name": "Test", "architecture": "esp", "version": "1.0.0", "url": "http://example.com/esp.zip",
+			"archiveFileName": "esp.zip", "checksum": "SHA-256:0000", "size": "7", "boards": [],
+			"toolsDependencies": [{"packager": "test", "name": "esptool", "version": ">=1.2.0 <2.0.0"}]}],
+		"tools": [
+			{"name": "esptool", "version": "1.1.0", "systems": []},
+			{"name": "esptool", "version": "1.5.0", "systems": []},
+			{"name": "esptool", "version": "2.0.0", "systems": []}
+		]}]}`)))
+
+	// The range is resolved to the newest matching release
+	resolved := &cores.ToolDependency{ToolPackager: "test", ToolName: "esptool", ToolVersion: semver.ParseRelaxed("1.5.0")}
+	toolDir := cacheDir.Join(resolved.InternalUniqueIdentifier(nil))
+	require.NoError(t, toolDir.MkdirAll())
+
+	pmb := NewBuilder(dataDir, dataDir.Join("packages"), dataDir.Join("staging"), dataDir, "test")
+	errs := pmb.LoadHardwareForProfile(
+		&sketch.Profile{Name: "test", FQBN: "test:esp:dev", Platforms: sketch.ProfileRequiredPlatforms{platformRef}},
+		false, nil, func(*rpc.TaskProgress) {})
+	require.Empty(t, errs)
+
+	pme, release := pmb.Build().NewExplorer()
+	defer release()
+	tool := pme.GetTool("test:esptool")
+	require.NotNil(t, tool)
+	installed := tool.GetLatestInstalled()
+	require.NotNil(t, installed)
+	require.Equal(t, "1.5.0", installed.Version.String())
+	require.Equal(t, toolDir.String(), installed.InstallDir.String())
+	require.ElementsMatch(t, []string{"1.1.0", "1.5.0", "2.0.0"}, versions(tool.GetAllReleasesVersions()))
+
+	// The build resolves the range to the same release
+	platformRelease := pme.FindPlatformRelease(&PlatformReference{Package: "test", PlatformArchitecture: "esp", PlatformVersion: semver.MustParse("1.0.0")})
+	require.NotNil(t, platformRelease)
+	require.Equal(t, installed, pme.FindToolDependency(platformRelease.ToolDependencies[0]))
+}
+
+func versions(list []*semver.RelaxedVersion) []string {
+	res := []string{}
+	for _, v := range list {
+		res = append(res, v.String())
+	}
+	return res
+}
@@ -0,0 +1,142 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2023 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package packagemanager
+
+import (
+	"time"
+
+	"github.com/arduino/arduino-cli/arduino/cores"
+	"github.com/arduino/arduino-cli/arduino/cores/packageindex"
+	"github.com/arduino/go-paths-helper"
+	semver "go.bug.st/relaxed-semver"
+)
+
+// IndexProvenance describes a package index that contributed a platform or a tool
+type IndexProvenance struct {
+	// URL is the URL of the package index (or the path of the index file if
+	// it has been loaded directly from a file)
+	URL string
+	// LoadedAt is the time when the index has been loaded
+	LoadedAt time.Time
+}
+
+// IndexStatus describes a package index loaded in the PackageManager
+type IndexStatus struct {
+	// URL is the URL of the package index (or the path of the index file if
+	// it has been loaded directly from a file)
+	URL string
+	// Path is the local file the index has been loaded from
+	Path *paths.Path
+	// LoadedAt is the time when the index has been loaded
+	LoadedAt time.Time
+	// Checksum is the SHA-256 of the index file, in the same format used by
+	// the package indexes ("SHA-256:..."), empty if it could not be computed
+	Checksum string
+	// Trusted is true if the signature of the index has been verified
+	Trusted bool
+	// Packages, Platforms and Tools are the number of packages, platform
+	// releases and tool releases contained in the index
+	Packages  int
+	Platforms int
+	Tools     int
+}
+
+func platformProvenanceKey(packager, architecture string, version semver.NormalizedString) string {
+	return "platform:" + packager + ":" + architecture + "@" + string(version)
+}
+
+func toolProvenanceKey(packager, name string, version semver.NormalizedString) string {
+	return "tool:" + packager + ":" + name + "@" + string(version)
+}
+
+// recordIndexProvenance records the given source as the origin of all the
+// platforms and tools contained in the index.
+func (pmb *Builder) recordIndexProvenance(index *packageindex.Index, source string) {
+	provenance := &IndexProvenance{URL: source, LoadedAt: time.Now()}
+	add := func(key string) {
+		for _, p := range pmb.indexProvenance[key] {
+			if p.URL == source {
+				p.LoadedAt = provenance.LoadedAt
+				return
+			}
+		}
+		pmb.indexProvenance[key] = append(pmb.indexProvenance[key], provenance)
+	}
+	for _, indexPackage := range index.Packages {
+		for _, platform := range indexPackage.Platforms {
+			if platform.Version != nil {
+				add(platformProvenanceKey(indexPackage.Name, platform.Architecture, platform.Version.NormalizedString()))
+			}
+		}
+		for _, tool := range indexPackage.Tools {
+			if tool.Version != nil {
+				add(toolProvenanceKey(indexPackage.Name, tool.Name, tool.Version.NormalizedString()))
+			}
+		}
+	}
+}
+
+// recordLoadedIndex records the status of a package index loaded from the
+// given source, replacing the previous status of the same source.
+func (pmb *Builder) recordLoadedIndex(index *packageindex.Index, source string, indexPath *paths.Path) {
+	status := &IndexStatus{
+		URL:      source,
+		Path:     indexPath,
+		LoadedAt: time.Now(),
+		Trusted:  index.IsTrusted,
+		Packages: len(index.Packages),
+		Checksum: index.Checksum(),
+	}
+	for _, indexPackage := range index.Packages {
+		status.Platforms += len(indexPackage.Platforms)
+		status.Tools += len(indexPackage.Tools)
+	}
+
+	loadedIndexes := []*IndexStatus{}
+	for _, loaded := range pmb.loadedIndexes {
+		if loaded.URL != source {
+			loadedIndexes = append(loadedIndexes, loaded)
+		}
+	}
+	pmb.loadedIndexes = append(loadedIndexes, status)
+}
+
+// LoadedIndexes returns the status of the package indexes loaded in the
+// PackageManager, in loading order.
+func (pme *Explorer) LoadedIndexes() []*IndexStatus {
+	return append([]*IndexStatus{}, pme.loadedIndexes...)
+}
+
+// PlatformReleaseProvenance returns the package indexes that contributed the given
+// platform release, in loading order. An empty list is returned if the platform
+// release doesn't come from a package index (for example if manually installed).
+func (pme *Explorer) PlatformReleaseProvenance(release *cores.PlatformRelease) []*IndexProvenance {
+	key := platformProvenanceKey(release.Platform.Package.Name, release.Platform.Architecture, release.Version.NormalizedString())
+	return append([]*IndexProvenance{}, pme.indexProvenance[key]...)
+}
+
+// ToolReleaseProvenance returns the package indexes that contributed the given
+// tool release, in loading order.
+func (pme *Explorer) ToolReleaseProvenance(release *cores.ToolRelease) []*IndexProvenance {
+	key := toolProvenanceKey(release.Tool.Package.Name, release.Tool.Name, release.Version.NormalizedString())
+	return append([]*IndexProvenance{}, pme.indexProvenance[key]...)
+}
+
+// BoardProvenance returns the package indexes that contributed the platform
+// release of the given board, in loading order.
+func (pme *Explorer) BoardProvenance(board *cores.Board) []*IndexProvenance {
+	return pme.PlatformReleaseProvenance(board.PlatformRelease)
+}
@@ -0,0 +1,175 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2023 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package packagemanager
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"net/url"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/arduino/arduino-cli/arduino"
+	"github.com/arduino/arduino-cli/arduino/cores"
+	"github.com/stretchr/testify/require"
+	semver "go.bug.st/relaxed-semver"
+)
+
+func TestIndexProvenance(t *testing.T) {
+	start := time.Now()
+	pmb := NewBuilder(dataDir1, dataDir1.Join("packages"), nil, nil, "test")
+	adafruitURL, err := url.Parse("https://adafruit.github.io/arduino-board-index/package_adafruit_index.json")
+	require.NoError(t, err)
+	testURL, err := url.Parse("https://example.com/package_test_index.json")
+	require.NoError(t, err)
+	require.NoError(t, pmb.LoadPackageIndex(adafruitURL))
+	require.NoError(t, pmb.LoadPackageIndex(testURL))
+	pmb.LoadHardwareFromDirectory(dataDir1.Join("packages"))
+	pm := pmb.Build()
+	pme, release := pm.NewExplorer()
+	defer release()
+
+	adafruitAvr := pme.FindPlatformRelease(&PlatformReference{
+		Package:              "adafruit",
+		PlatformArchitecture: "avr",
+		PlatformVersion:      semver.MustParse("1.3.0"),
+	})
+	require.NotNil(t, adafruitAvr)
+	provenance := pme.PlatformReleaseProvenance(adafruitAvr)
+	require.Len(t, provenance, 1)
+	require.Equal(t, adafruitURL.String(), provenance[0].URL)
+	require.False(t, provenance[0].LoadedAt.Before(start))
+
+	board := adafruitAvr.GetOrCreateBoard("flora8")
+	boardProvenance := pme.BoardProvenance(board)
+	require.Len(t, boardProvenance, 1)
+	require.Equal(t, adafruitURL.String(), boardProvenance[0].URL)
+
+	bossac := pme.FindToolDependency(&cores.ToolDependency{
+		ToolPackager: "test",
+		ToolName:     "bossac",
+		ToolVersion:  semver.ParseRelaxed("1.7.5"),
+	})
+	require.NotNil(t, bossac)
+	toolProvenance := pme.ToolReleaseProvenance(bossac)
+	require.Len(t, toolProvenance, 1)
+	require.Equal(t, testURL.String(), toolProvenance[0].URL)
+
+	// Platforms not coming from an index have no provenance
+	pmb = NewBuilder(nil, nil, nil, nil, "test")
+	pmb.LoadHardwareFromDirectory(customHardware)
+	pm = pmb.Build()
+	pme2, release2 := pm.NewExplorer()
+	defer release2()
+	board, err = pme2.FindBoardWithFQBN("arduino:avr:uno")
+	require.NoError(t, err)
+	require.Empty(t, pme2.BoardProvenance(board))
+}
+
+func TestLoadedIndexes(t *testing.T) {
+	start := time.Now()
+	pmb := NewBuilder(dataDir1, dataDir1.Join("packages"), nil, nil, "test")
+	adafruitURL, err := url.Parse("https://adafruit.github.io/arduino-board-index/package_adafruit_index.json")
+	require.NoError(t, err)
+	testURL, err := url.Parse("https://example.com/package_test_index.json")
+	require.NoError(t, err)
+	require.NoError(t, pmb.LoadPackageIndex(adafruitURL))
+	require.NoError(t, pmb.LoadPackageIndex(testURL))
+	// Loading again the same index replaces its status
+	require.NoError(t, pmb.LoadPackageIndex(adafruitURL))
+	pm := pmb.Build()
+	pme, release := pm.NewExplorer()
+	defer release()
+
+	loaded := pme.LoadedIndexes()
+	require.Len(t, loaded, 2)
+
+	require.Equal(t, testURL.String(), loaded[0].URL)
+	require.Equal(t, dataDir1.Join("package_test_index.json").String(), loaded[0].Path.String())
+	require.Equal(t, 1, loaded[0].Packages)
+	require.Equal(t, 1, loaded[0].Platforms)
+	require.Equal(t, 1, loaded[0].Tools)
+	require.False(t, loaded[0].Trusted)
+	require.False(t, loaded[0].LoadedAt.Before(start))
+	data, err := dataDir1.Join("package_test_index.json").ReadFile()
+	require.NoError(t, err)
+	sum := sha256.Sum256(data)
+	require.Equal(t, "SHA-256:"+hex.EncodeToString(sum[:]), loaded[0].Checksum)
+
+	require.Equal(t, adafruitURL.String(), loaded[1].URL)
+	require.Equal(t, 3, loaded[1].Packages)
+	require.Equal(t, 88, loaded[1].Platforms)
+	require.Equal(t, 3, loaded[1].Tools)
+	require.NotEqual(t, loaded[0].Checksum, loaded[1].Checksum)
+}
+
+func TestLoadPackageIndexes(t *testing.T) {
+	parseURL := func(u string) *url.URL {
+		res, err := url.Parse(u)
+		require.NoError(t, err)
+		return res
+	}
+	absDataDir, err := dataDir1.Abs()
+	require.NoError(t, err)
+	URLs := []*url.URL{
+		parseURL("https://adafruit.github.io/arduino-board-index/package_adafruit_index.json"),
+		parseURL("https://example.com/package_missing_index.json"),
+		parseURL("file://" + filepath.ToSlash(absDataDir.Join("package_test_index.json").String())),
+		parseURL("https://example.com/package_esp8266com_index.json"),
+	}
+	pmb := NewBuilder(dataDir1, dataDir1.Join("packages"), nil, nil, "test")
+	errs := pmb.LoadPackageIndexes(URLs, 2)
+	require.Len(t, errs, 4)
+	require.NoError(t, errs[0])
+	require.ErrorContains(t, errs[1], "package_missing_index.json")
+	require.NoError(t, errs[2])
+	require.NoError(t, errs[3])
+	pm := pmb.Build()
+	pme, release := pm.NewExplorer()
+	defer release()
+
+	// The indexes are merged in the given order
+	loaded := pme.LoadedIndexes()
+	require.Len(t, loaded, 3)
+	require.Equal(t, URLs[0].String(), loaded[0].URL)
+	require.Equal(t, absDataDir.Join("package_test_index.json").String(), loaded[1].URL)
+	require.Equal(t, URLs[3].String(), loaded[2].URL)
+
+	require.NotNil(t, pme.GetPackages()["adafruit"])
+	require.Equal(t, URLs[0].String(), pme.GetPackages()["adafruit"].URL)
+	require.NotNil(t, pme.GetPackages()["test"])
+	require.NotNil(t, pme.GetPackages()["esp8266"])
+}
+
+func TestLoadPackageIndexKeepsTheCause(t *testing.T) {
+	testURL, err := url.Parse("https://example.com/package_test_index.json")
+	require.NoError(t, err)
+	pmb := NewBuilder(dataDir1, dataDir1.Join("packages"), nil, nil, "test")
+	pmb.SetMaxIndexSize(10)
+
+	var limitErr *arduino.LimitExceededError
+	err = pmb.LoadPackageIndex(testURL)
+	require.ErrorContains(t, err, "package_test_index.json")
+	require.ErrorAs(t, err, &limitErr)
+
+	errs := pmb.LoadPackageIndexes([]*url.URL{testURL}, 1)
+	require.Len(t, errs, 1)
+	require.ErrorAs(t, errs[0], &limitErr)
+
+	_, err = pmb.LoadPackageIndexFromFile(dataDir1.Join("package_test_index.json"))
+	require.ErrorAs(t, err, &limitErr)
+}
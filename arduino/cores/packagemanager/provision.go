@@ -0,0 +1,166 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2023 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package packagemanager
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/arduino/arduino-cli/arduino"
+	"github.com/arduino/arduino-cli/arduino/cores"
+)
+
+// ProvisionPlan contains the platform releases, and their tools, that must be
+// installed to build for a set of FQBN.
+type ProvisionPlan struct {
+	Platforms []*cores.PlatformRelease
+	Tools     []*cores.ToolRelease
+	// Unresolved maps the FQBN that can't be resolved to the reason why
+	Unresolved map[string]error
+}
+
+// PlanForFQBNs computes the minimal set of platform releases and tools needed
+// to build for all the given FQBN. Each FQBN is resolved against the loaded
+// package indexes: if its platform is installed the installed release is used
+// (together with the platform referenced by the board, if any), otherwise the
+// latest available release. Tools shared by more platforms, or the same tool
+// archive published by different packagers, appear only once in the plan.
+// The FQBN that can't be resolved are reported in the Unresolved field of the
+// plan and in the returned error, together with the partial plan.
+func (pme *Explorer) PlanForFQBNs(fqbns []string) (*ProvisionPlan, error) {
+	plan := &ProvisionPlan{
+		Platforms:  []*cores.PlatformRelease{},
+		Tools:      []*cores.ToolRelease{},
+		Unresolved: map[string]error{},
+	}
+	addedPlatforms := map[*cores.PlatformRelease]bool{}
+	addedTools := map[*cores.ToolRelease]bool{}
+	addedChecksums := map[string]bool{}
+	addTool := func(tool *cores.ToolRelease) {
+		if addedTools[tool] {
+			return
+		}
+		addedTools[tool] = true
+		if resource := tool.GetCompatibleFlavour(); resource != nil && resource.Checksum != "" {
+			if addedChecksums[resource.Checksum] {
+				return
+			}
+			addedChecksums[resource.Checksum] = true
+		}
+		plan.Tools = append(plan.Tools, tool)
+	}
+	addPlatform := func(release *cores.PlatformRelease) error {
+		if addedPlatforms[release] {
+			return nil
+		}
+		_, tools, err := pme.FindPlatformReleaseDependencies(&PlatformReference{
+			Package:              release.Platform.Package.Name,
+			PlatformArchitecture: release.Platform.Architecture,
+			PlatformVersion:      release.Version,
+		})
+		if err != nil {
+			return err
+		}
+		addedPlatforms[release] = true
+		plan.Platforms = append(plan.Platforms, release)
+		for _, tool := range tools {
+			addTool(tool)
+		}
+		return nil
+	}
+
+	for _, fqbnIn := range fqbns {
+		releases, err := pme.platformReleasesForFQBN(fqbnIn)
+		if err == nil {
+			for _, release := range releases {
+				if err = addPlatform(release); err != nil {
+					break
+				}
+			}
+		}
+		if err != nil {
+			plan.Unresolved[fqbnIn] = err
+		}
+	}
+
+	if len(plan.Unresolved) > 0 {
+		unresolved := []string{}
+		for _, fqbn := range fqbns {
+			if err, ok := plan.Unresolved[fqbn]; ok {
+				unresolved = append(unresolved, fmt.Sprintf("%s (%s)", fqbn, err))
+			}
+		}
+		return plan, &arduino.UnknownFQBNError{Cause: errors.New(strings.Join(unresolved, ", "))}
+	}
+	return plan, nil
+}
+
+// platformReleasesForFQBN returns the platform releases needed to build for
+// the given FQBN: the release providing the board and, if installed, the
+// release providing the core or the variant referenced by the board.
+func (pme *Explorer) platformReleasesForFQBN(fqbnIn string) ([]*cores.PlatformRelease, error) {
+	boardPlatformRelease, corePlatformRelease, variantPlatformRelease, err := pme.resolveFQBNPlatformReleases(fqbnIn)
+	if err != nil {
+		return nil, err
+	}
+	res := []*cores.PlatformRelease{boardPlatformRelease}
+	for _, release := range []*cores.PlatformRelease{corePlatformRelease, variantPlatformRelease} {
+		if !slices.Contains(res, release) {
+			res = append(res, release)
+		}
+	}
+	return res, nil
+}
+
+// resolveFQBNPlatformReleases returns the platform releases providing the board,
+// the core and the variant for the given FQBN. If the platform of the board is
+// not installed its latest release is returned for all of them.
+func (pme *Explorer) resolveFQBNPlatformReleases(fqbnIn string) (board, core, variant *cores.PlatformRelease, err error) {
+	fqbn, err := pme.ParseFQBN(fqbnIn)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	platform := pme.FindPlatform(&PlatformReference{Package: fqbn.Package, PlatformArchitecture: fqbn.PlatformArch})
+	if platform == nil {
+		return nil, nil, nil, &arduino.PlatformNotFoundError{Platform: fqbn.Package + ":" + fqbn.PlatformArch}
+	}
+	boardPlatformRelease := pme.GetInstalledPlatformRelease(platform)
+	if boardPlatformRelease == nil {
+		// The boards IDs are not available in the package indexes, so the
+		// board can't be checked until the platform is installed.
+		release := platform.GetLatestRelease()
+		if release == nil {
+			return nil, nil, nil, &arduino.PlatformNotFoundError{Platform: platform.String(), Cause: errors.New(tr("no releases available"))}
+		}
+		return release, release, release, nil
+	}
+
+	boardDefinition := boardPlatformRelease.Boards[fqbn.BoardID]
+	if boardDefinition == nil {
+		return nil, nil, nil, fmt.Errorf(tr("board %s not found"), fqbn.StringWithoutConfig())
+	}
+	boardBuildProperties, err := boardDefinition.GetBuildProperties(fqbn)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	_, corePlatformRelease, _, variantPlatformRelease, refErr := pme.determineReferencedPlatformRelease(boardBuildProperties, boardPlatformRelease, fqbn)
+	if refErr != nil {
+		return nil, nil, nil, refErr
+	}
+	return boardPlatformRelease, corePlatformRelease, variantPlatformRelease, nil
+}
@@ -0,0 +1,49 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2023 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package packagemanager
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/arduino/go-paths-helper"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPlanForFQBNs(t *testing.T) {
+	pmb := NewBuilder(nil, nil, nil, nil, "test")
+	_, err := pmb.LoadPackageIndexFromFile(paths.New("testdata", "package_provision_index.json"))
+	require.NoError(t, err)
+	pm := pmb.Build()
+	pme, release := pm.NewExplorer()
+	defer release()
+
+	plan, err := pme.PlanForFQBNs([]string{"test:avr:uno", "test:samd:zero", "test:avr:uno:cpu=fast"})
+	require.NoError(t, err)
+	require.Empty(t, plan.Unresolved)
+	require.Equal(t, "[test:avr@1.1.0 test:samd@1.0.0]", fmt.Sprint(plan.Platforms))
+	// gcc is required by both platforms, and the uploader archive published
+	// by the "other" packager is the same of the "test" packager
+	require.Equal(t, "[test:gcc@1.0.0 test:uploader@2.0.0]", fmt.Sprint(plan.Tools))
+
+	plan, err = pme.PlanForFQBNs([]string{"test:samd:zero", "test:unknown:board", "invalid"})
+	require.Error(t, err)
+	require.Len(t, plan.Unresolved, 2)
+	require.Contains(t, plan.Unresolved, "test:unknown:board")
+	require.Contains(t, plan.Unresolved, "invalid")
+	require.Equal(t, "[test:samd@1.0.0]", fmt.Sprint(plan.Platforms))
+	require.Equal(t, "[test:gcc@1.0.0 other:uploader@2.0.0]", fmt.Sprint(plan.Tools))
+}
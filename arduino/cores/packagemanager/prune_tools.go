@@ -0,0 +1,69 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2023 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package packagemanager
+
+import (
+	"sort"
+
+	"github.com/arduino/arduino-cli/arduino/cores"
+	rpc "github.com/arduino/arduino-cli/rpc/cc/arduino/cli/commands/v1"
+)
+
+// ToolReferences returns, for each installed tool release, the installed
+// platform releases requiring it. Tool releases not required by any platform
+// are included with no references.
+func (pme *Explorer) ToolReferences() map[*cores.ToolRelease][]*cores.PlatformRelease {
+	references := map[*cores.ToolRelease][]*cores.PlatformRelease{}
+	for _, toolRelease := range pme.GetAllInstalledToolsReleases() {
+		references[toolRelease] = pme.platformReleasesRequiringTool(toolRelease)
+	}
+	return references
+}
+
+// FindOrphanTools returns the installed tool releases, managed by the
+// PackageManager, that are not required by any installed platform. The
+// builtin tools, required by the CLI itself, are never orphans.
+func (pme *Explorer) FindOrphanTools() []*cores.ToolRelease {
+	orphans := []*cores.ToolRelease{}
+	for _, toolRelease := range pme.GetAllInstalledToolsReleases() {
+		if toolRelease.Tool.Package.Name == "builtin" || pme.IsToolRequired(toolRelease) {
+			continue
+		}
+		if !pme.IsManagedToolRelease(toolRelease) {
+			continue
+		}
+		orphans = append(orphans, toolRelease)
+	}
+	sort.Slice(orphans, func(i, j int) bool {
+		return orphans[i].String() < orphans[j].String()
+	})
+	return orphans
+}
+
+// PruneTools uninstalls all the tool releases returned by FindOrphanTools and
+// returns them. If an uninstall fails the pruning stops and the tools already
+// removed are returned together with the error.
+func (pme *Explorer) PruneTools(taskCB rpc.TaskProgressCB, skipPreUninstall bool) ([]*cores.ToolRelease, error) {
+	pruned := []*cores.ToolRelease{}
+	for _, toolRelease := range pme.FindOrphanTools() {
+		taskCB(&rpc.TaskProgress{Name: tr("Uninstalling %s, tool is no more required", toolRelease)})
+		if err := pme.UninstallTool(toolRelease, taskCB, skipPreUninstall); err != nil {
+			return pruned, err
+		}
+		pruned = append(pruned, toolRelease)
+	}
+	return pruned, nil
+}
@@ -0,0 +1,67 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2023 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package packagemanager
+
+import (
+	"testing"
+
+	"github.com/arduino/arduino-cli/arduino/cores"
+	rpc "github.com/arduino/arduino-cli/rpc/cc/arduino/cli/commands/v1"
+	"github.com/arduino/go-paths-helper"
+	"github.com/stretchr/testify/require"
+	semver "go.bug.st/relaxed-semver"
+)
+
+func TestPruneTools(t *testing.T) {
+	tmp := paths.New(t.TempDir())
+	packagesDir := tmp.Join("packages")
+	pmb := NewBuilder(tmp, packagesDir, nil, nil, "test")
+
+	installTool := func(packager, name, version string, installDir *paths.Path) *cores.ToolRelease {
+		toolRelease := pmb.GetOrCreatePackage(packager).GetOrCreateTool(name).GetOrCreateRelease(semver.ParseRelaxed(version))
+		require.NoError(t, installDir.MkdirAll())
+		toolRelease.InstallDir = installDir
+		return toolRelease
+	}
+	required := installTool("test", "gcc", "1.0.0", packagesDir.Join("test", "tools", "gcc", "1.0.0"))
+	orphan := installTool("test", "gcc", "0.9.0", packagesDir.Join("test", "tools", "gcc", "0.9.0"))
+	builtin := installTool("builtin", "ctags", "1.0.0", packagesDir.Join("builtin", "tools", "ctags", "1.0.0"))
+	unmanaged := installTool("test", "uploader", "1.0.0", tmp.Join("unmanaged", "uploader"))
+
+	platformRelease := pmb.GetOrCreatePackage("test").GetOrCreatePlatform("avr").GetOrCreateRelease(semver.MustParse("1.0.0"))
+	platformRelease.InstallDir = packagesDir.Join("test", "hardware", "avr", "1.0.0")
+	platformRelease.ToolDependencies = cores.ToolDependencies{
+		{ToolPackager: "test", ToolName: "gcc", ToolVersion: semver.ParseRelaxed("1.0.0")},
+	}
+
+	pme, release := pmb.Build().NewExplorer()
+	defer release()
+
+	references := pme.ToolReferences()
+	require.Equal(t, []*cores.PlatformRelease{platformRelease}, references[required])
+	require.Empty(t, references[orphan])
+	require.Empty(t, references[builtin])
+	require.Empty(t, references[unmanaged])
+
+	require.Equal(t, []*cores.ToolRelease{orphan}, pme.FindOrphanTools())
+
+	pruned, err := pme.PruneTools(func(*rpc.TaskProgress) {}, true)
+	require.NoError(t, err)
+	require.Equal(t, []*cores.ToolRelease{orphan}, pruned)
+	require.False(t, packagesDir.Join("test", "tools", "gcc", "0.9.0").Exist())
+	require.True(t, packagesDir.Join("test", "tools", "gcc", "1.0.0").Exist())
+	require.Empty(t, pme.FindOrphanTools())
+}
@@ -0,0 +1,54 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2023 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package packagemanager
+
+import (
+	"errors"
+
+	"github.com/arduino/arduino-cli/arduino"
+	semver "go.bug.st/relaxed-semver"
+)
+
+// ErrNoReleaseNotes is returned by PlatformReleaseNotes when the package index
+// doesn't provide any release notes for the platform release
+var ErrNoReleaseNotes = errors.New("no release notes available")
+
+// PlatformReleaseNotes returns the release notes of the given platform release
+// as declared in the package index. The notes embedded in the index entry
+// (releaseNotes) are preferred, otherwise the URL of the changelog
+// (help.changelog) or of the online help (help.online) is returned.
+// ErrNoReleaseNotes is returned if none of them is available.
+func (pme *Explorer) PlatformReleaseNotes(packager, arch string, version *semver.Version) (string, error) {
+	ref := &PlatformReference{
+		Package:              packager,
+		PlatformArchitecture: arch,
+		PlatformVersion:      version,
+	}
+	platformRelease := pme.FindPlatformRelease(ref)
+	if platformRelease == nil {
+		return "", &arduino.PlatformNotFoundError{Platform: ref.String()}
+	}
+	if platformRelease.ReleaseNotes != "" {
+		return platformRelease.ReleaseNotes, nil
+	}
+	if platformRelease.Help.Changelog != "" {
+		return platformRelease.Help.Changelog, nil
+	}
+	if platformRelease.Help.Online != "" {
+		return platformRelease.Help.Online, nil
+	}
+	return "", &kindError{kind: ErrNoReleaseNotes, message: tr("no release notes available for %s", platformRelease)}
+}
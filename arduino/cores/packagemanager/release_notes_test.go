@@ -0,0 +1,88 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2023 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package packagemanager
+
+import (
+	"testing"
+
+	"github.com/arduino/arduino-cli/arduino"
+	"github.com/arduino/go-paths-helper"
+	"github.com/stretchr/testify/require"
+	semver "go.bug.st/relaxed-semver"
+)
+
+func TestPlatformReleaseNotes(t *testing.T) {
+	indexFile := paths.New(t.TempDir()).Join("package_notes_index.json")
+	require.NoError(t, indexFile.WriteFile([]byte(`{
+  "packages": [{
+    "name": "notes",
+    "maintainer": "Notes",
+    "help": { "online": "https://example.com/package" },
+    "platforms": [
+      {
+        "name": "Notes Boards", "architecture": "avr", "version": "1.0.0",
+        "url": "https://example.com/notes-1.0.0.zip", "archiveFileName": "notes-1.0.0.zip", "size": "100",
+        "help": { "online": "https://example.com/help" },
+        "boards": [], "toolsDependencies": []
+      },
+      {
+        "name": "Notes Boards", "architecture": "avr", "version": "1.1.0",
+        "url": "https://example.com/notes-1.1.0.zip", "archiveFileName": "notes-1.1.0.zip", "size": "100",
+        "help": { "online": "https://example.com/help", "changelog": "https://example.com/changelog/1.1.0" },
+        "boards": [], "toolsDependencies": []
+      },
+      {
+        "name": "Notes Boards", "architecture": "avr", "version": "1.2.0",
+        "url": "https://example.com/notes-1.2.0.zip", "archiveFileName": "notes-1.2.0.zip", "size": "100",
+        "help": { "changelog": "https://example.com/changelog/1.2.0" },
+        "releaseNotes": "Added support for the new board",
+        "boards": [], "toolsDependencies": []
+      },
+      {
+        "name": "Notes Boards", "architecture": "avr", "version": "1.3.0",
+        "url": "https://example.com/notes-1.3.0.zip", "archiveFileName": "notes-1.3.0.zip", "size": "100",
+        "boards": [], "toolsDependencies": []
+      }
+    ],
+    "tools": []
+  }]
+}`)))
+	pmb := NewBuilder(nil, nil, nil, nil, "test")
+	_, err := pmb.LoadPackageIndexFromFile(indexFile)
+	require.NoError(t, err)
+	pm := pmb.Build()
+	pme, release := pm.NewExplorer()
+	defer release()
+
+	notes, err := pme.PlatformReleaseNotes("notes", "avr", semver.MustParse("1.0.0"))
+	require.NoError(t, err)
+	require.Equal(t, "https://example.com/help", notes)
+
+	notes, err = pme.PlatformReleaseNotes("notes", "avr", semver.MustParse("1.1.0"))
+	require.NoError(t, err)
+	require.Equal(t, "https://example.com/changelog/1.1.0", notes)
+
+	notes, err = pme.PlatformReleaseNotes("notes", "avr", semver.MustParse("1.2.0"))
+	require.NoError(t, err)
+	require.Equal(t, "Added support for the new board", notes)
+
+	// The help of the package is not used for the platform releases
+	_, err = pme.PlatformReleaseNotes("notes", "avr", semver.MustParse("1.3.0"))
+	require.ErrorIs(t, err, ErrNoReleaseNotes)
+
+	_, err = pme.PlatformReleaseNotes("notes", "avr", semver.MustParse("2.0.0"))
+	require.ErrorAs(t, err, new(*arduino.PlatformNotFoundError))
+}
@@ -0,0 +1,292 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2023 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package packagemanager
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/arduino/arduino-cli/arduino"
+	"github.com/arduino/arduino-cli/arduino/cores"
+	rpc "github.com/arduino/arduino-cli/rpc/cc/arduino/cli/commands/v1"
+	"github.com/arduino/go-paths-helper"
+	semver "go.bug.st/relaxed-semver"
+)
+
+// The previous releases of a platform are kept in a hidden directory, ignored
+// by the loader, next to the installed release:
+//
+//	PACKAGER/hardware/ARCHITECTURE/VERSION/...
+//	PACKAGER/hardware/ARCHITECTURE/.previous/OLD-VERSION/...
+//	PACKAGER/hardware/ARCHITECTURE/.pinned
+const previousReleasesDirName = ".previous"
+const pinnedVersionFileName = ".pinned"
+
+// SetKeepPreviousReleases sets how many previously installed releases of each
+// platform are kept on disk, to allow a rollback, when a platform is upgraded
+// or downgraded. With 0 (the default) the replaced releases are uninstalled.
+func (pmb *Builder) SetKeepPreviousReleases(n int) {
+	pmb.keepPreviousReleases = n
+}
+
+// platformDir returns the directory containing the installed releases of the platform
+func (pme *Explorer) platformDir(platform *cores.Platform) *paths.Path {
+	return pme.PackagesDir.Join(platform.Package.Name, "hardware", platform.Architecture)
+}
+
+// PinnedPlatformVersion returns the version the platform is pinned to, or nil
+// if the platform is not pinned.
+func (pme *Explorer) PinnedPlatformVersion(platform *cores.Platform) *semver.Version {
+	if pme.PackagesDir == nil {
+		return nil
+	}
+	data, err := pme.platformDir(platform).Join(pinnedVersionFileName).ReadFile()
+	if err != nil {
+		return nil
+	}
+	version, err := semver.Parse(strings.TrimSpace(string(data)))
+	if err != nil {
+		return nil
+	}
+	return version
+}
+
+// PinPlatform pins the platform to the given version: a pinned platform is
+// not upgraded by DownloadAndInstallPlatformUpgrades.
+func (pme *Explorer) PinPlatform(platform *cores.Platform, version *semver.Version) error {
+	platformDir := pme.platformDir(platform)
+	if err := platformDir.MkdirAll(); err != nil {
+		return err
+	}
+	return platformDir.Join(pinnedVersionFileName).WriteFile([]byte(version.String() + "\n"))
+}
+
+// UnpinPlatform removes the pin of the platform, if any.
+func (pme *Explorer) UnpinPlatform(platform *cores.Platform) error {
+	pinFile := pme.platformDir(platform).Join(pinnedVersionFileName)
+	if pinFile.NotExist() {
+		return nil
+	}
+	return pinFile.Remove()
+}
+
+// PreviousPlatformReleases returns the versions of the previous releases of the
+// platform kept on disk, from the most recently replaced to the oldest.
+func (pme *Explorer) PreviousPlatformReleases(platform *cores.Platform) []*semver.Version {
+	if pme.PackagesDir == nil {
+		return nil
+	}
+	dirs, err := pme.platformDir(platform).Join(previousReleasesDirName).ReadDir()
+	if err != nil {
+		return nil
+	}
+	dirs.FilterDirs()
+	sortByModTimeNewestFirst(dirs)
+	res := []*semver.Version{}
+	for _, dir := range dirs {
+		if version, err := semver.Parse(dir.Base()); err == nil {
+			res = append(res, version)
+		}
+	}
+	return res
+}
+
+// UninstallPreviousPlatformReleases uninstalls all the previous releases of
+// the platform kept on disk (their pre_uninstall script is run as usual).
+func (pme *Explorer) UninstallPreviousPlatformReleases(platform *cores.Platform, taskCB rpc.TaskProgressCB, skipPreUninstall bool) error {
+	previousDir := pme.platformDir(platform).Join(previousReleasesDirName)
+	if previousDir.NotExist() {
+		return nil
+	}
+	dirs, err := previousDir.ReadDir()
+	if err != nil {
+		return err
+	}
+	dirs.FilterDirs()
+	for _, dir := range dirs {
+		version, err := semver.Parse(dir.Base())
+		if err != nil {
+			// Not a platform release
+			continue
+		}
+		kept := &cores.PlatformRelease{
+			Platform:   platform,
+			Version:    version,
+			InstallDir: dir,
+		}
+		if err := pme.UninstallPlatform(kept, taskCB, skipPreUninstall); err != nil {
+			return err
+		}
+	}
+	return previousDir.RemoveAll()
+}
+
+func sortByModTimeNewestFirst(dirs paths.PathList) {
+	modTime := map[*paths.Path]time.Time{}
+	for _, dir := range dirs {
+		if info, err := dir.Stat(); err == nil {
+			modTime[dir] = info.ModTime()
+		}
+	}
+	sort.SliceStable(dirs, func(i, j int) bool {
+		return modTime[dirs[i]].After(modTime[dirs[j]])
+	})
+}
+
+// keepPreviousRelease moves the given installed release among the previous
+// releases of the platform, uninstalling the oldest ones exceeding the given
+// number of releases to keep (their pre_uninstall script is run as usual).
+func (pme *Explorer) keepPreviousRelease(platformRelease *cores.PlatformRelease, keep int, taskCB rpc.TaskProgressCB, skipPreUninstall bool) error {
+	// Safety measure
+	if !pme.IsManagedPlatformRelease(platformRelease) {
+		return fmt.Errorf(tr("%s is not managed by package manager"), platformRelease)
+	}
+	previousDir := pme.platformDir(platformRelease.Platform).Join(previousReleasesDirName)
+	if err := previousDir.MkdirAll(); err != nil {
+		return err
+	}
+	keptDir := previousDir.Join(platformRelease.Version.String())
+	if err := keptDir.RemoveAll(); err != nil {
+		return err
+	}
+	if err := platformRelease.InstallDir.Rename(keptDir); err != nil {
+		return err
+	}
+	// The modification time marks when the release has been replaced
+	now := time.Now()
+	_ = os.Chtimes(keptDir.String(), now, now)
+	platformRelease.InstallDir = nil
+	pme.eventBus.emit(&Event{Kind: EventUninstalled, Subject: platformRelease.String()})
+
+	dirs, err := previousDir.ReadDir()
+	if err != nil {
+		return err
+	}
+	dirs.FilterDirs()
+	sortByModTimeNewestFirst(dirs)
+	for i := keep; i < len(dirs); i++ {
+		version, err := semver.Parse(dirs[i].Base())
+		if err != nil {
+			// Not a platform release
+			continue
+		}
+		evicted := &cores.PlatformRelease{
+			Platform:   platformRelease.Platform,
+			Version:    version,
+			InstallDir: dirs[i],
+		}
+		if err := pme.UninstallPlatform(evicted, taskCB, skipPreUninstall); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// RollbackPlatform switches the installed release of the platform with one of
+// its previous releases kept on disk: the given version or, if nil, the most
+// recently replaced one. The currently installed release becomes a previous
+// release. The missing tools required by the restored release are installed
+// (their archives are usually still in the download cache) and the platform
+// is pinned to the restored version, so it's not upgraded again.
+func (pme *Explorer) RollbackPlatform(platform *cores.Platform, version *semver.Version, downloadCB rpc.DownloadProgressCB, taskCB rpc.TaskProgressCB) (*semver.Version, error) {
+	previous := pme.PreviousPlatformReleases(platform)
+	if len(previous) == 0 {
+		return nil, &arduino.NotFoundError{Message: tr("No previous release of platform %s to rollback to", platform)}
+	}
+	if version == nil {
+		version = previous[0]
+	} else if !containsVersion(previous, version) {
+		return nil, &arduino.NotFoundError{Message: tr("No previous release %[1]s of platform %[2]s to rollback to", version, platform)}
+	}
+	platformDir := pme.platformDir(platform)
+	keptDir := platformDir.Join(previousReleasesDirName, version.String())
+	restoredDir := platformDir.Join(version.String())
+	restoredID := fmt.Sprintf("%s@%s", platform, version)
+
+	installed := pme.GetInstalledPlatformRelease(platform)
+	if installed != nil && installed.Version.Equal(version) {
+		return nil, &arduino.InvalidArgumentError{Message: tr("Platform %s is already installed", restoredID)}
+	}
+
+	// Install the tools required by the restored release, if it's still in the index
+	if release := platform.FindReleaseWithVersion(version); release != nil {
+		tools, err := pme.packages.GetPlatformReleaseToolDependencies(release)
+		if err != nil {
+			return nil, &arduino.NotFoundError{Message: tr("Can't find dependencies for platform %s", release), Cause: err}
+		}
+		for _, tool := range tools {
+			if tool.IsInstalled() {
+				continue
+			}
+			if err := pme.DownloadToolRelease(tool, nil, downloadCB); err != nil {
+				return nil, err
+			}
+			if err := pme.InstallTool(tool, taskCB, false); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	taskCB(&rpc.TaskProgress{Name: tr("Rolling back platform %s", restoredID)})
+	// Put the installed release aside, to make room for the restored one...
+	var asideDir *paths.Path
+	if installed != nil {
+		var err error
+		if asideDir, err = paths.MkTempDir(platformDir.String(), ".rollback"); err != nil {
+			return nil, &arduino.TempDirCreationFailedError{Cause: err}
+		}
+		defer asideDir.RemoveAll()
+		if err := installed.InstallDir.Rename(asideDir.Join("release")); err != nil {
+			return nil, &arduino.FailedInstallError{Message: tr("Cannot rollback platform"), Cause: err}
+		}
+	}
+
+	// ...restore the previous release, putting back the installed one on failure...
+	if err := keptDir.Rename(restoredDir); err != nil {
+		if installed != nil {
+			_ = asideDir.Join("release").Rename(installed.InstallDir)
+		}
+		return nil, &arduino.FailedInstallError{Message: tr("Cannot rollback platform"), Cause: err}
+	}
+
+	// ...and keep the replaced release as a previous release
+	if installed != nil {
+		installed.InstallDir = asideDir.Join("release")
+		// Keep at least the replaced release, so the rollback can be undone
+		if err := pme.keepPreviousRelease(installed, max(pme.keepPreviousReleases, 1), taskCB, false); err != nil {
+			taskCB(&rpc.TaskProgress{Message: tr("WARNING cannot keep the replaced platform release: %s", err)})
+		}
+	}
+
+	if err := pme.PinPlatform(platform, version); err != nil {
+		taskCB(&rpc.TaskProgress{Message: tr("WARNING cannot pin platform: %s", err)})
+	}
+	pme.eventBus.emit(&Event{Kind: EventInstalled, Subject: restoredID})
+	taskCB(&rpc.TaskProgress{Message: tr("Platform %s restored", restoredID), Completed: true})
+	return version, nil
+}
+
+func containsVersion(versions []*semver.Version, version *semver.Version) bool {
+	for _, v := range versions {
+		if v.Equal(version) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,121 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2023 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package packagemanager
+
+import (
+	"os"
+	"runtime"
+	"testing"
+
+	rpc "github.com/arduino/arduino-cli/rpc/cc/arduino/cli/commands/v1"
+	"github.com/arduino/go-paths-helper"
+	"github.com/stretchr/testify/require"
+	semver "go.bug.st/relaxed-semver"
+)
+
+func TestPlatformPin(t *testing.T) {
+	tmp := paths.New(t.TempDir())
+	pmb := NewBuilder(tmp, tmp.Join("packages"), nil, nil, "test")
+	platform := pmb.GetOrCreatePackage("test").GetOrCreatePlatform("avr")
+	pme, release := pmb.Build().NewExplorer()
+	defer release()
+
+	require.Nil(t, pme.PinnedPlatformVersion(platform))
+	require.NoError(t, pme.PinPlatform(platform, semver.MustParse("1.2.3")))
+	require.Equal(t, "1.2.3", pme.PinnedPlatformVersion(platform).String())
+	require.NoError(t, pme.UnpinPlatform(platform))
+	require.Nil(t, pme.PinnedPlatformVersion(platform))
+	require.NoError(t, pme.UnpinPlatform(platform))
+}
+
+func TestPlatformRollback(t *testing.T) {
+	tmp := paths.New(t.TempDir())
+	packagesDir := tmp.Join("packages")
+	platformDir := packagesDir.Join("test", "hardware", "avr")
+	noTaskCB := func(*rpc.TaskProgress) {}
+
+	build := func() (*Explorer, func()) {
+		pmb := NewBuilder(tmp, packagesDir, nil, nil, "test")
+		pmb.SetKeepPreviousReleases(1)
+		require.Empty(t, pmb.LoadHardwareFromDirectory(packagesDir))
+		return pmb.Build().NewExplorer()
+	}
+	installRelease := func(version string) {
+		dir := platformDir.Join(version)
+		require.NoError(t, dir.MkdirAll())
+		require.NoError(t, dir.Join("platform.txt").WriteFile([]byte("name=Test\nversion="+version+"\n")))
+		require.NoError(t, dir.Join("boards.txt").WriteFile([]byte("uno.name=Uno\n")))
+	}
+
+	// Install 1.0.0, then replace it with 2.0.0 keeping the previous release
+	installRelease("1.0.0")
+	pme, release := build()
+	platform := pme.packages["test"].Platforms["avr"]
+	_, err := pme.RollbackPlatform(platform, nil, nil, noTaskCB)
+	require.Error(t, err)
+	require.NoError(t, pme.keepPreviousRelease(pme.GetInstalledPlatformRelease(platform), 1, noTaskCB, false))
+	release()
+	require.NoDirExists(t, platformDir.Join("1.0.0").String())
+	require.DirExists(t, platformDir.Join(".previous", "1.0.0").String())
+	installRelease("2.0.0")
+
+	pme, release = build()
+	platform = pme.packages["test"].Platforms["avr"]
+	require.Equal(t, "2.0.0", pme.GetInstalledPlatformRelease(platform).Version.String())
+	require.Equal(t, []*semver.Version{semver.MustParse("1.0.0")}, pme.PreviousPlatformReleases(platform))
+	_, err = pme.RollbackPlatform(platform, semver.MustParse("1.5.0"), nil, noTaskCB)
+	require.Error(t, err)
+
+	// Rollback to 1.0.0: 2.0.0 becomes the previous release and 1.0.0 is pinned
+	restored, err := pme.RollbackPlatform(platform, nil, nil, noTaskCB)
+	require.NoError(t, err)
+	require.Equal(t, "1.0.0", restored.String())
+	require.Equal(t, "1.0.0", pme.PinnedPlatformVersion(platform).String())
+	release()
+	require.DirExists(t, platformDir.Join("1.0.0").String())
+	require.NoDirExists(t, platformDir.Join("2.0.0").String())
+	require.DirExists(t, platformDir.Join(".previous", "2.0.0").String())
+	require.NoDirExists(t, platformDir.Join(".previous", "1.0.0").String())
+
+	pme, release = build()
+	defer release()
+	platform = pme.packages["test"].Platforms["avr"]
+	require.Equal(t, "1.0.0", pme.GetInstalledPlatformRelease(platform).Version.String())
+	require.Equal(t, []*semver.Version{semver.MustParse("2.0.0")}, pme.PreviousPlatformReleases(platform))
+
+	// Only the given number of previous releases is kept, the evicted ones are
+	// uninstalled running their pre_uninstall script
+	marker := tmp.Join("pre_uninstall_ran")
+	if runtime.GOOS != "windows" {
+		script := platformDir.Join(".previous", "2.0.0", "pre_uninstall.sh")
+		require.NoError(t, script.WriteFile([]byte("#!/bin/sh\ntouch "+marker.String()+"\n")))
+		require.NoError(t, os.Chmod(script.String(), 0755))
+	}
+	installRelease("3.0.0")
+	require.NoError(t, pme.keepPreviousRelease(pme.GetInstalledPlatformRelease(platform), 1, noTaskCB, false))
+	require.Equal(t, []*semver.Version{semver.MustParse("1.0.0")}, pme.PreviousPlatformReleases(platform))
+	require.NoDirExists(t, platformDir.Join(".previous", "2.0.0").String())
+	if runtime.GOOS != "windows" {
+		require.FileExists(t, marker.String())
+	}
+
+	// Uninstalling the platform removes all its previous releases too
+	require.NoError(t, pme.UninstallPreviousPlatformReleases(platform, noTaskCB, false))
+	require.Empty(t, pme.PreviousPlatformReleases(platform))
+	require.NoDirExists(t, platformDir.Join(".previous").String())
+	require.DirExists(t, platformDir.Join("3.0.0").String())
+	require.NoError(t, pme.UninstallPreviousPlatformReleases(platform, noTaskCB, false))
+}
@@ -0,0 +1,80 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2023 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package packagemanager
+
+import (
+	"errors"
+	"time"
+
+	rpc "github.com/arduino/arduino-cli/rpc/cc/arduino/cli/commands/v1"
+)
+
+// ErrScriptsDisabled is returned by RunPreOrPostScript when the execution of
+// the scripts is disabled by the ScriptsPolicy.
+var ErrScriptsDisabled = errors.New("the execution of the install scripts is disabled")
+
+// scriptsWaitDelay is how long the output of a script is still read after the
+// script has been killed for running past the timeout.
+const scriptsWaitDelay = time.Second
+
+// ScriptsPolicy controls the execution of the post_install and pre_uninstall
+// scripts of platforms and tools.
+type ScriptsPolicy struct {
+	// Disabled prevents the execution of all the scripts, for example in
+	// untrusted CI environments
+	Disabled bool
+	// Timeout is how long a script may run before being killed, with 0 or less
+	// the scripts run without a time limit
+	Timeout time.Duration
+	// SkippedPlatforms are the platforms (PACKAGER:ARCH) whose scripts are
+	// never run
+	SkippedPlatforms []string
+}
+
+// SetScriptsPolicy sets the policy for the execution of the post_install and
+// pre_uninstall scripts.
+func (pmb *Builder) SetScriptsPolicy(policy ScriptsPolicy) {
+	pmb.scriptsPolicy = policy
+}
+
+// SkipScripts returns true if the scripts of the given platform (PACKAGER:ARCH)
+// must not be run, because skip is true or because they are not allowed by the
+// ScriptsPolicy. In the latter case the reason is notified to taskCB (that may
+// be nil). For the scripts of tools platformID is empty.
+func (pme *Explorer) SkipScripts(skip bool, platformID string, taskCB rpc.TaskProgressCB) bool {
+	if skip {
+		return true
+	}
+	reason := ""
+	if pme.scriptsPolicy.Disabled {
+		reason = tr("the execution of the install scripts is disabled")
+	} else if platformID != "" {
+		for _, skipped := range pme.scriptsPolicy.SkippedPlatforms {
+			if skipped == platformID {
+				reason = tr("the execution of the install scripts of %s is disabled", platformID)
+				break
+			}
+		}
+	}
+	if reason == "" {
+		return false
+	}
+	pme.log.WithField("platform", platformID).Info("Scripts not allowed by the policy")
+	if taskCB != nil {
+		taskCB(&rpc.TaskProgress{Message: tr("Skipping script: %s", reason)})
+	}
+	return true
+}
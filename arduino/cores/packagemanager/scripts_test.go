@@ -0,0 +1,83 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2023 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package packagemanager
+
+import (
+	"os"
+	"runtime"
+	"testing"
+	"time"
+
+	rpc "github.com/arduino/arduino-cli/rpc/cc/arduino/cli/commands/v1"
+	"github.com/arduino/go-paths-helper"
+	"github.com/stretchr/testify/require"
+)
+
+func TestScriptsPolicy(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("the test scripts are shell scripts")
+	}
+	tmp := paths.New(t.TempDir())
+	require.NoError(t, tmp.Join("post_install.sh").WriteFile([]byte("#!/bin/sh\necho configured\nsleep 10\n")))
+	require.NoError(t, os.Chmod(tmp.Join("post_install.sh").String(), 0755))
+
+	newExplorer := func(policy ScriptsPolicy) (*Explorer, func()) {
+		pmb := NewBuilder(tmp, tmp.Join("packages"), nil, nil, "test")
+		pmb.SetScriptsPolicy(policy)
+		return pmb.Build().NewExplorer()
+	}
+
+	t.Run("Timeout", func(t *testing.T) {
+		pme, release := newExplorer(ScriptsPolicy{Timeout: 200 * time.Millisecond})
+		defer release()
+		start := time.Now()
+		stdout, _, err := pme.RunPreOrPostScript(tmp, "post_install")
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "post_install.sh killed after running for 200ms")
+		require.Equal(t, "configured\n", string(stdout))
+		require.Less(t, time.Since(start), 5*time.Second)
+	})
+
+	t.Run("Disabled", func(t *testing.T) {
+		pme, release := newExplorer(ScriptsPolicy{Disabled: true})
+		defer release()
+		_, _, err := pme.RunPreOrPostScript(tmp, "post_install")
+		require.ErrorIs(t, err, ErrScriptsDisabled)
+
+		// A missing script is not an error
+		_, _, err = pme.RunPreOrPostScript(tmp, "pre_uninstall")
+		require.NoError(t, err)
+
+		messages := []string{}
+		taskCB := func(msg *rpc.TaskProgress) { messages = append(messages, msg.Message) }
+		require.True(t, pme.SkipScripts(false, "arduino:avr", taskCB))
+		require.True(t, pme.SkipScripts(false, "", taskCB))
+		require.Len(t, messages, 2)
+	})
+
+	t.Run("SkippedPlatforms", func(t *testing.T) {
+		pme, release := newExplorer(ScriptsPolicy{SkippedPlatforms: []string{"esp32:esp32"}})
+		defer release()
+		messages := []string{}
+		taskCB := func(msg *rpc.TaskProgress) { messages = append(messages, msg.Message) }
+		require.True(t, pme.SkipScripts(false, "esp32:esp32", taskCB))
+		require.Equal(t, []string{"Skipping script: the execution of the install scripts of esp32:esp32 is disabled"}, messages)
+		require.False(t, pme.SkipScripts(false, "arduino:avr", taskCB))
+		require.False(t, pme.SkipScripts(false, "", taskCB))
+		require.True(t, pme.SkipScripts(true, "arduino:avr", taskCB))
+		require.Len(t, messages, 1)
+	})
+}
@@ -0,0 +1,79 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2023 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package packagemanager
+
+import (
+	"sort"
+
+	"github.com/arduino/arduino-cli/arduino/cores"
+	"github.com/arduino/go-paths-helper"
+)
+
+// ToolInstallDirCollision describes a set of installed tool releases sharing
+// the same installation directory, or installed one inside the other.
+type ToolInstallDirCollision struct {
+	// InstallDir is the outermost installation directory involved
+	InstallDir *paths.Path
+	// Tools are the tool releases installed in InstallDir or in one of its
+	// subdirectories
+	Tools []*cores.ToolRelease
+}
+
+// FindToolInstallDirCollisions checks all the installed tool releases and
+// returns the groups of releases whose installation directories are the same
+// or overlap. Such releases overwrite each other when installed or removed,
+// this usually happens when a package index declares wrong tool names or
+// versions.
+func (pme *Explorer) FindToolInstallDirCollisions() []*ToolInstallDirCollision {
+	tools := pme.GetAllInstalledToolsReleases()
+	dirs := make([]*paths.Path, len(tools))
+	for i, tool := range tools {
+		dirs[i] = tool.InstallDir.Canonical()
+	}
+
+	// Group every release under the outermost installation directory that
+	// contains it
+	groups := map[string]*ToolInstallDirCollision{}
+	for i, tool := range tools {
+		root := dirs[i]
+		for _, dir := range dirs {
+			if root.IsInsideDir(dir) {
+				root = dir
+			}
+		}
+		group, ok := groups[root.String()]
+		if !ok {
+			group = &ToolInstallDirCollision{InstallDir: root}
+			groups[root.String()] = group
+		}
+		group.Tools = append(group.Tools, tool)
+	}
+
+	res := []*ToolInstallDirCollision{}
+	for _, group := range groups {
+		if len(group.Tools) < 2 {
+			continue
+		}
+		sort.Slice(group.Tools, func(i, j int) bool {
+			return group.Tools[i].String() < group.Tools[j].String()
+		})
+		res = append(res, group)
+	}
+	sort.Slice(res, func(i, j int) bool {
+		return res[i].InstallDir.String() < res[j].InstallDir.String()
+	})
+	return res
+}
@@ -0,0 +1,55 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2023 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package packagemanager
+
+import (
+	"testing"
+
+	"github.com/arduino/go-paths-helper"
+	"github.com/stretchr/testify/require"
+	semver "go.bug.st/relaxed-semver"
+)
+
+func TestFindToolInstallDirCollisions(t *testing.T) {
+	pmb := NewBuilder(nil, nil, nil, nil, "test")
+	pack := pmb.GetOrCreatePackage("test")
+	installTool := func(name, version string, installDir *paths.Path) {
+		release := pack.GetOrCreateTool(name).GetOrCreateRelease(semver.ParseRelaxed(version))
+		release.InstallDir = installDir
+		require.NoError(t, installDir.MkdirAll())
+	}
+	tmp := paths.New(t.TempDir())
+	installTool("gcc", "1.0.0", tmp.Join("gcc", "1.0.0"))
+	installTool("gcc", "2.0.0", tmp.Join("gcc", "1.0.0"))
+	installTool("openocd", "1.0.0", tmp.Join("openocd"))
+	installTool("openocd-scripts", "1.0.0", tmp.Join("openocd", "scripts"))
+	installTool("openocd-extra", "1.0.0", tmp.Join("openocd-extra"))
+	installTool("bossac", "1.0.0", tmp.Join("bossac", "1.0.0"))
+	pm := pmb.Build()
+	pme, release := pm.NewExplorer()
+	defer release()
+
+	collisions := pme.FindToolInstallDirCollisions()
+	require.Len(t, collisions, 2)
+	require.True(t, collisions[0].InstallDir.EquivalentTo(tmp.Join("gcc", "1.0.0")))
+	require.Len(t, collisions[0].Tools, 2)
+	require.Equal(t, "test:gcc@1.0.0", collisions[0].Tools[0].String())
+	require.Equal(t, "test:gcc@2.0.0", collisions[0].Tools[1].String())
+	require.True(t, collisions[1].InstallDir.EquivalentTo(tmp.Join("openocd")))
+	require.Len(t, collisions[1].Tools, 2)
+	require.Equal(t, "test:openocd-scripts@1.0.0", collisions[1].Tools[0].String())
+	require.Equal(t, "test:openocd@1.0.0", collisions[1].Tools[1].String())
+}
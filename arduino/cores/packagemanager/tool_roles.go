@@ -0,0 +1,245 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2023 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package packagemanager
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/arduino/arduino-cli/arduino/cores"
+	properties "github.com/arduino/go-properties-orderedmap"
+)
+
+// ToolRole is the purpose a tool is required for by a platform
+type ToolRole string
+
+const (
+	// ToolRoleBuild is the role of the tools used to compile a sketch (compilers, preprocessors, ...)
+	ToolRoleBuild ToolRole = "build"
+	// ToolRoleUpload is the role of the tools used to upload a sketch, burn a bootloader or erase a board
+	ToolRoleUpload ToolRole = "upload"
+	// ToolRoleDiscovery is the role of the pluggable discoveries
+	ToolRoleDiscovery ToolRole = "discovery"
+	// ToolRoleMonitor is the role of the pluggable monitors
+	ToolRoleMonitor ToolRole = "monitor"
+	// ToolRoleDebug is the role of the tools used to debug a sketch
+	ToolRoleDebug ToolRole = "debug"
+)
+
+// ToolRoles is the list of all the known ToolRole
+var ToolRoles = []ToolRole{ToolRoleBuild, ToolRoleUpload, ToolRoleDiscovery, ToolRoleMonitor, ToolRoleDebug}
+
+// ParseToolRole returns the ToolRole with the given name
+func ParseToolRole(name string) (ToolRole, error) {
+	for _, role := range ToolRoles {
+		if string(role) == name {
+			return role, nil
+		}
+	}
+	return "", fmt.Errorf(tr("invalid tool role: %s"), name)
+}
+
+// toolActionsRoles maps the actions that may be performed by a tool recipe
+// (tools.RECIPE.ACTION.pattern) or selected for a board (ACTION.tool) to their role
+var toolActionsRoles = map[string]ToolRole{
+	"upload":     ToolRoleUpload,
+	"program":    ToolRoleUpload,
+	"erase":      ToolRoleUpload,
+	"bootloader": ToolRoleUpload,
+	"debug":      ToolRoleDebug,
+}
+
+var runtimeToolPathRegexp = regexp.MustCompile(`\{runtime\.tools\.([^{}]+)\.path\}`)
+
+// ToolDependenciesRoles categorizes the tool dependencies of the given platform
+// by role. The role of a tool is deduced from the properties of the platform and
+// of its boards: a tool referenced (as recipe name or through its
+// {runtime.tools.TOOL.path} property) only by upload, debug, pluggable discovery
+// or pluggable monitor recipes has only the corresponding role, a tool referenced
+// elsewhere has the build role. Tools not referenced at all have the build role,
+// unless they are also declared as discovery or monitor dependencies. A tool
+// may have more than one role.
+func ToolDependenciesRoles(platform *cores.PlatformRelease) map[*cores.ToolDependency][]ToolRole {
+	propsList := []*properties.Map{}
+	if platform.Properties != nil {
+		propsList = append(propsList, platform.Properties)
+	}
+	for _, board := range platform.Boards {
+		if board.Properties != nil {
+			propsList = append(propsList, board.Properties)
+		}
+	}
+
+	// First find the role of each tool recipe...
+	recipesRoles := map[string]map[ToolRole]bool{}
+	addRole := func(roles map[string]map[ToolRole]bool, name string, role ToolRole) {
+		if roles[name] == nil {
+			roles[name] = map[ToolRole]bool{}
+		}
+		roles[name][role] = true
+	}
+	for _, props := range propsList {
+		for _, key := range props.Keys() {
+			parts := strings.Split(stripMenuPrefix(key), ".")
+			if len(parts) >= 4 && parts[0] == "tools" && parts[len(parts)-1] == "pattern" {
+				if role, ok := toolActionsRoles[parts[2]]; ok {
+					addRole(recipesRoles, parts[1], role)
+				}
+			} else if len(parts) >= 2 && parts[1] == "tool" {
+				if role, ok := toolActionsRoles[parts[0]]; ok {
+					addRole(recipesRoles, props.Get(key), role)
+				}
+			}
+		}
+	}
+
+	// ...then the role of each tool referenced through {runtime.tools.TOOL.path}
+	referencedRoles := map[string]map[ToolRole]bool{}
+	for _, props := range propsList {
+		for _, key := range props.Keys() {
+			keyRoles := map[ToolRole]bool{ToolRoleBuild: true}
+			parts := strings.Split(stripMenuPrefix(key), ".")
+			if len(parts) >= 2 && parts[0] == "tools" {
+				if roles, ok := recipesRoles[parts[1]]; ok {
+					keyRoles = roles
+				}
+			} else if role, ok := toolActionsRoles[parts[0]]; ok {
+				keyRoles = map[ToolRole]bool{role: true}
+			} else if parts[0] == "pluggable_discovery" {
+				keyRoles = map[ToolRole]bool{ToolRoleDiscovery: true}
+			} else if parts[0] == "pluggable_monitor" {
+				keyRoles = map[ToolRole]bool{ToolRoleMonitor: true}
+			}
+			for _, match := range runtimeToolPathRegexp.FindAllStringSubmatch(props.Get(key), -1) {
+				for role := range keyRoles {
+					addRole(referencedRoles, match[1], role)
+				}
+			}
+		}
+	}
+
+	res := map[*cores.ToolDependency][]ToolRole{}
+	for _, dep := range platform.ToolDependencies {
+		depRoles := map[ToolRole]bool{}
+		names := []string{dep.ToolName}
+		if dep.ToolVersion != nil {
+			names = append(names, dep.ToolName+"-"+dep.ToolVersion.String())
+		}
+		for _, name := range names {
+			for role := range referencedRoles[name] {
+				depRoles[role] = true
+			}
+		}
+		for role := range recipesRoles[dep.ToolName] {
+			depRoles[role] = true
+		}
+		// Discoveries and monitors are often listed also as tool dependencies
+		for _, discoveryDep := range platform.DiscoveryDependencies {
+			if discoveryDep.Packager == dep.ToolPackager && discoveryDep.Name == dep.ToolName {
+				depRoles[ToolRoleDiscovery] = true
+			}
+		}
+		for _, monitorDep := range platform.MonitorDependencies {
+			if monitorDep.Packager == dep.ToolPackager && monitorDep.Name == dep.ToolName {
+				depRoles[ToolRoleMonitor] = true
+			}
+		}
+		if len(depRoles) == 0 {
+			depRoles[ToolRoleBuild] = true
+		}
+		roles := []ToolRole{}
+		for _, role := range ToolRoles {
+			if depRoles[role] {
+				roles = append(roles, role)
+			}
+		}
+		res[dep] = roles
+	}
+	return res
+}
+
+// stripMenuPrefix removes the "menu.MENU.OPTION." prefix from a board property key
+func stripMenuPrefix(key string) string {
+	if !strings.HasPrefix(key, "menu.") {
+		return key
+	}
+	parts := strings.SplitN(key, ".", 4)
+	if len(parts) < 4 {
+		return key
+	}
+	return parts[3]
+}
+
+// FindToolsRequiredForRole returns the ToolReleases required by the given platform
+// for the specified roles. Unlike FindToolsRequiredFromPlatformRelease only the
+// tools declared as dependencies by the platform are returned, so a caller that
+// needs, for example, only the discoveries doesn't have to install the debuggers.
+func (pme *Explorer) FindToolsRequiredForRole(platform *cores.PlatformRelease, roles ...ToolRole) ([]*cores.ToolRelease, error) {
+	wanted := map[ToolRole]bool{}
+	for _, role := range roles {
+		wanted[role] = true
+	}
+
+	requiredTools := []*cores.ToolRelease{}
+	added := map[*cores.ToolRelease]bool{}
+	add := func(tool *cores.ToolRelease) {
+		if !added[tool] {
+			added[tool] = true
+			requiredTools = append(requiredTools, tool)
+		}
+	}
+
+	depsRoles := ToolDependenciesRoles(platform)
+	platform.ToolDependencies.Sort()
+	for _, toolDep := range platform.ToolDependencies {
+		required := false
+		for _, role := range depsRoles[toolDep] {
+			required = required || wanted[role]
+		}
+		if !required {
+			continue
+		}
+		tool := pme.FindToolDependency(toolDep)
+		if tool == nil {
+			return nil, fmt.Errorf(tr("tool release not found: %s"), toolDep)
+		}
+		add(tool)
+	}
+
+	if wanted[ToolRoleDiscovery] {
+		platform.DiscoveryDependencies.Sort()
+		for _, discoveryDep := range platform.DiscoveryDependencies {
+			tool := pme.FindDiscoveryDependency(discoveryDep)
+			if tool == nil {
+				return nil, fmt.Errorf(tr("discovery release not found: %s"), discoveryDep)
+			}
+			add(tool)
+		}
+	}
+
+	if wanted[ToolRoleMonitor] {
+		platform.MonitorDependencies.Sort()
+		for _, monitorDep := range platform.MonitorDependencies {
+			tool := pme.FindMonitorDependency(monitorDep)
+			if tool == nil {
+				return nil, fmt.Errorf(tr("monitor release not found: %s"), monitorDep)
+			}
+			add(tool)
+		}
+	}
+	return requiredTools, nil
+}
@@ -0,0 +1,110 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2023 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package packagemanager
+
+import (
+	"testing"
+
+	"github.com/arduino/arduino-cli/arduino/cores"
+	"github.com/arduino/go-paths-helper"
+	properties "github.com/arduino/go-properties-orderedmap"
+	"github.com/stretchr/testify/require"
+	semver "go.bug.st/relaxed-semver"
+)
+
+func TestFindToolsRequiredForRole(t *testing.T) {
+	pmb := NewBuilder(nil, nil, nil, nil, "test")
+	pack := pmb.GetOrCreatePackage("test")
+	deps := cores.ToolDependencies{}
+	installTool := func(name, version string) *cores.ToolRelease {
+		release := pack.GetOrCreateTool(name).GetOrCreateRelease(semver.ParseRelaxed(version))
+		release.InstallDir = paths.New(t.TempDir())
+		deps = append(deps, &cores.ToolDependency{ToolPackager: "test", ToolName: name, ToolVersion: release.Version})
+		return release
+	}
+	gcc := installTool("arm-gcc", "7.2.1")
+	bossac := installTool("bossac", "1.9.1")
+	openocd := installTool("openocd", "0.11.0")
+	gdbServer := installTool("gdb-server", "1.0.0")
+	ctags := installTool("ctags", "5.8")
+	serialDiscovery := installTool("serial-discovery", "1.4.0")
+	serialMonitor := installTool("serial-monitor", "0.13.0")
+
+	platform := pack.GetOrCreatePlatform("arm").GetOrCreateRelease(semver.MustParse("1.0.0"))
+	platform.ToolDependencies = deps
+	platform.DiscoveryDependencies = cores.DiscoveryDependencies{{Packager: "test", Name: "serial-discovery"}}
+	platform.MonitorDependencies = cores.MonitorDependencies{{Packager: "test", Name: "serial-monitor"}}
+	platform.Properties = properties.NewFromHashmap(map[string]string{
+		"compiler.path":                     "{runtime.tools.arm-gcc.path}/bin/",
+		"tools.ctags.pattern":               `"{path}/ctags" "{source_file}"`,
+		"tools.ctags.path":                  "{runtime.tools.ctags.path}",
+		"tools.bossac.path":                 "{runtime.tools.bossac-1.9.1.path}",
+		"tools.bossac.upload.pattern":       `"{path}/bossac" "{build.path}/{build.project_name}.bin"`,
+		"tools.openocd.path":                "{runtime.tools.openocd.path}",
+		"tools.openocd.program.pattern":     `"{path}/bin/openocd" -c "program"`,
+		"tools.openocd.debug.pattern":       `"{path}/bin/openocd" -c "debug"`,
+		"debug.toolchain.path":              "{runtime.tools.arm-gcc.path}/bin/",
+		"debug.server.path":                 "{runtime.tools.gdb-server.path}/gdb-server",
+		"pluggable_discovery.required.0":    "test:serial-discovery",
+		"pluggable_monitor.required.serial": "test:serial-monitor",
+	})
+	board := platform.GetOrCreateBoard("board")
+	board.Properties = properties.NewFromHashmap(map[string]string{
+		"upload.tool.default": "bossac",
+	})
+	pm := pmb.Build()
+	pme, release := pm.NewExplorer()
+	defer release()
+
+	roles := ToolDependenciesRoles(platform)
+	rolesOf := func(tool *cores.ToolRelease) []ToolRole {
+		for dep, roles := range roles {
+			if dep.ToolName == tool.Tool.Name {
+				return roles
+			}
+		}
+		return nil
+	}
+	require.Equal(t, []ToolRole{ToolRoleBuild, ToolRoleDebug}, rolesOf(gcc))
+	require.Equal(t, []ToolRole{ToolRoleBuild}, rolesOf(ctags))
+	require.Equal(t, []ToolRole{ToolRoleUpload}, rolesOf(bossac))
+	require.Equal(t, []ToolRole{ToolRoleUpload, ToolRoleDebug}, rolesOf(openocd))
+	require.Equal(t, []ToolRole{ToolRoleDebug}, rolesOf(gdbServer))
+	require.Equal(t, []ToolRole{ToolRoleDiscovery}, rolesOf(serialDiscovery))
+	require.Equal(t, []ToolRole{ToolRoleMonitor}, rolesOf(serialMonitor))
+
+	tools, err := pme.FindToolsRequiredForRole(platform, ToolRoleDiscovery)
+	require.NoError(t, err)
+	require.Equal(t, []*cores.ToolRelease{serialDiscovery}, tools)
+
+	tools, err = pme.FindToolsRequiredForRole(platform, ToolRoleBuild)
+	require.NoError(t, err)
+	require.ElementsMatch(t, []*cores.ToolRelease{gcc, ctags}, tools)
+
+	tools, err = pme.FindToolsRequiredForRole(platform, ToolRoleUpload, ToolRoleMonitor)
+	require.NoError(t, err)
+	require.ElementsMatch(t, []*cores.ToolRelease{bossac, openocd, serialMonitor}, tools)
+
+	tools, err = pme.FindToolsRequiredForRole(platform, ToolRoleDebug)
+	require.NoError(t, err)
+	require.ElementsMatch(t, []*cores.ToolRelease{gcc, openocd, gdbServer}, tools)
+
+	_, err = ParseToolRole("flash")
+	require.Error(t, err)
+	role, err := ParseToolRole("monitor")
+	require.NoError(t, err)
+	require.Equal(t, ToolRoleMonitor, role)
+}
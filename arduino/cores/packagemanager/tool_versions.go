@@ -0,0 +1,78 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2023 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package packagemanager
+
+import (
+	"strings"
+
+	"github.com/arduino/arduino-cli/arduino/cores"
+	"github.com/arduino/go-paths-helper"
+	semver "go.bug.st/relaxed-semver"
+)
+
+// toolVersionMarkers are the files, relative to the tool installation
+// directory, that may contain the version of the tool actually installed.
+var toolVersionMarkers = []string{"version.txt", "VERSION"}
+
+// ToolVersionMismatch describes an installed tool release whose on-disk
+// version marker doesn't agree with the declared version.
+type ToolVersionMismatch struct {
+	// Tool is the installed tool release
+	Tool *cores.ToolRelease
+	// MarkerFile is the file containing the on-disk version
+	MarkerFile *paths.Path
+	// OnDiskVersion is the version found in the MarkerFile
+	OnDiskVersion string
+}
+
+// FindToolVersionMismatches checks all the installed tool releases and returns
+// those whose version marker (a version.txt or VERSION file in the root of the
+// installation directory) reports a version different from the declared one.
+// This usually means that the tool files have been replaced manually.
+// Tools without a version marker can't be checked and are not reported.
+func (pme *Explorer) FindToolVersionMismatches() []*ToolVersionMismatch {
+	res := []*ToolVersionMismatch{}
+	for _, toolRelease := range pme.GetAllInstalledToolsReleases() {
+		if mismatch := checkToolVersionMarker(toolRelease); mismatch != nil {
+			res = append(res, mismatch)
+		}
+	}
+	return res
+}
+
+func checkToolVersionMarker(toolRelease *cores.ToolRelease) *ToolVersionMismatch {
+	for _, marker := range toolVersionMarkers {
+		markerFile := toolRelease.InstallDir.Join(marker)
+		data, err := markerFile.ReadFile()
+		if err != nil {
+			continue
+		}
+		onDiskVersion, _, _ := strings.Cut(strings.TrimSpace(string(data)), "\n")
+		onDiskVersion = strings.TrimSpace(onDiskVersion)
+		if onDiskVersion == "" {
+			continue
+		}
+		if semver.ParseRelaxed(strings.TrimPrefix(onDiskVersion, "v")).Equal(toolRelease.Version) {
+			return nil
+		}
+		return &ToolVersionMismatch{
+			Tool:          toolRelease,
+			MarkerFile:    markerFile,
+			OnDiskVersion: onDiskVersion,
+		}
+	}
+	return nil
+}
@@ -0,0 +1,49 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2023 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package packagemanager
+
+import (
+	"testing"
+
+	"github.com/arduino/go-paths-helper"
+	"github.com/stretchr/testify/require"
+	semver "go.bug.st/relaxed-semver"
+)
+
+func TestFindToolVersionMismatches(t *testing.T) {
+	pmb := NewBuilder(nil, nil, nil, nil, "test")
+	pack := pmb.GetOrCreatePackage("test")
+	installTool := func(name, version, marker, markerContent string) {
+		release := pack.GetOrCreateTool(name).GetOrCreateRelease(semver.ParseRelaxed(version))
+		release.InstallDir = paths.New(t.TempDir())
+		if marker != "" {
+			require.NoError(t, release.InstallDir.Join(marker).WriteFile([]byte(markerContent)))
+		}
+	}
+	installTool("matching", "1.2.0", "version.txt", "1.2.0\n")
+	installTool("prefixed", "2.0.0", "VERSION", "v2.0.0\n")
+	installTool("unmarked", "3.0.0", "", "")
+	installTool("swapped", "7.3.0-atmel3.6.1-arduino7", "version.txt", "7.3.0-atmel3.6.1-arduino5\nbuilt by someone\n")
+	pm := pmb.Build()
+	pme, release := pm.NewExplorer()
+	defer release()
+
+	mismatches := pme.FindToolVersionMismatches()
+	require.Len(t, mismatches, 1)
+	require.Equal(t, "test:swapped@7.3.0-atmel3.6.1-arduino7", mismatches[0].Tool.String())
+	require.Equal(t, "7.3.0-atmel3.6.1-arduino5", mismatches[0].OnDiskVersion)
+	require.Equal(t, "version.txt", mismatches[0].MarkerFile.Base())
+}
@@ -0,0 +1,83 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2023 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package packagemanager
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	properties "github.com/arduino/go-properties-orderedmap"
+)
+
+// MissingOption is a board config option required for upload that is not
+// set in the FQBN
+type MissingOption struct {
+	// Option is the id of the config option (for example "PartitionScheme")
+	Option string
+	// OptionLabel is the name of the config option as shown in the menu
+	OptionLabel string
+	// Values are the allowed values of the option, mapped to their labels
+	Values *properties.Map
+}
+
+// ValidateForUpload returns the config options of the board that must be
+// explicitly set in the FQBN before uploading but are missing. A board lists
+// such options, usually those whose default value may not match the hardware
+// (like the flash partitioning), in the upload.required_menus property as a
+// comma separated list of menu ids:
+//
+//	myboard.upload.required_menus=PartitionScheme,FlashSize
+//
+// The options not defined in the board menus are ignored. The result follows
+// the order of the menus of the platform. The options are checked even if a
+// tool required by the platform is missing.
+func (pme *Explorer) ValidateForUpload(fqbnIn string) ([]*MissingOption, error) {
+	fqbn, err := pme.ParseFQBN(fqbnIn)
+	if err != nil {
+		return nil, fmt.Errorf(tr("parsing fqbn: %s"), err)
+	}
+	_, _, board, _, _, err := pme.ResolveFQBN(fqbn)
+	var resolutionErr *FQBNResolutionError
+	if errors.Is(err, ErrRequiredToolNotFound) && errors.As(err, &resolutionErr) && resolutionErr.Board != nil {
+		// A missing tool doesn't prevent checking the board options: the
+		// board has been resolved anyway
+		board = resolutionErr.Board
+	} else if err != nil {
+		return nil, err
+	}
+
+	required := map[string]bool{}
+	for _, option := range strings.Split(board.Properties.Get("upload.required_menus"), ",") {
+		if option = strings.TrimSpace(option); option != "" {
+			required[option] = true
+		}
+	}
+
+	res := []*MissingOption{}
+	configOptions := board.GetConfigOptions()
+	for _, option := range configOptions.Keys() {
+		if !required[option] || fqbn.Configs.ContainsKey(option) {
+			continue
+		}
+		res = append(res, &MissingOption{
+			Option:      option,
+			OptionLabel: configOptions.Get(option),
+			Values:      board.GetConfigOptionValues(option),
+		})
+	}
+	return res, nil
+}
@@ -0,0 +1,93 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2023 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package packagemanager
+
+import (
+	"testing"
+
+	"github.com/arduino/arduino-cli/arduino/cores"
+	"github.com/arduino/go-paths-helper"
+	"github.com/stretchr/testify/require"
+	semver "go.bug.st/relaxed-semver"
+)
+
+func TestValidateForUpload(t *testing.T) {
+	hardwareDir := paths.New(t.TempDir())
+	platformDir := hardwareDir.Join("test", "esp")
+	require.NoError(t, platformDir.MkdirAll())
+	require.NoError(t, platformDir.Join("boards.txt").WriteFile([]byte(
+		"menu.UploadSpeed=Upload Speed\n"+
+			"menu.PartitionScheme=Partition Scheme\n"+
+			"menu.FlashSize=Flash Size\n"+
+			"dev.name=Dev Board\n"+
+			"dev.upload.required_menus=PartitionScheme, FlashSize, Missing\n"+
+			"dev.menu.UploadSpeed.921600=921600\n"+
+			"dev.menu.UploadSpeed.115200=115200\n"+
+			"dev.menu.FlashSize.4M=4MB\n"+
+			"dev.menu.FlashSize.8M=8MB\n"+
+			"dev.menu.PartitionScheme.default=Default\n"+
+			"dev.menu.PartitionScheme.default.upload.extra_flags=--default\n"+
+			"dev.menu.PartitionScheme.huge_app=Huge APP\n"+
+			"dev.menu.PartitionScheme.huge_app.upload.extra_flags=--huge\n"+
+			"simple.name=Simple Board\n"+
+			"simple.menu.UploadSpeed.921600=921600\n")))
+	require.NoError(t, platformDir.Join("platform.txt").WriteFile([]byte("name=Test\nversion=1.0.0\n")))
+
+	pmb := NewBuilder(nil, nil, nil, nil, "test")
+	pmb.LoadHardwareFromDirectory(hardwareDir)
+	pm := pmb.Build()
+	pme, release := pm.NewExplorer()
+	defer release()
+
+	missing, err := pme.ValidateForUpload("test:esp:dev")
+	require.NoError(t, err)
+	require.Len(t, missing, 2)
+	require.Equal(t, "PartitionScheme", missing[0].Option)
+	require.Equal(t, "Partition Scheme", missing[0].OptionLabel)
+	require.Equal(t, []string{"default", "huge_app"}, missing[0].Values.Keys())
+	require.Equal(t, "FlashSize", missing[1].Option)
+
+	missing, err = pme.ValidateForUpload("test:esp:dev:PartitionScheme=huge_app,UploadSpeed=115200")
+	require.NoError(t, err)
+	require.Len(t, missing, 1)
+	require.Equal(t, "FlashSize", missing[0].Option)
+
+	missing, err = pme.ValidateForUpload("test:esp:dev:PartitionScheme=default,FlashSize=4M")
+	require.NoError(t, err)
+	require.Empty(t, missing)
+
+	missing, err = pme.ValidateForUpload("test:esp:simple")
+	require.NoError(t, err)
+	require.Empty(t, missing)
+
+	_, err = pme.ValidateForUpload("test:esp:dev:PartitionScheme=wrong")
+	require.Error(t, err)
+
+	// A missing tool doesn't prevent the validation
+	platformRelease := pme.GetInstalledPlatformRelease(pme.FindPlatform(&PlatformReference{Package: "test", PlatformArchitecture: "esp"}))
+	require.NotNil(t, platformRelease)
+	platformRelease.ToolDependencies = cores.ToolDependencies{
+		{ToolPackager: "test", ToolName: "esptool", ToolVersion: semver.ParseRelaxed("1.0.0")},
+	}
+	fqbn, err := cores.ParseFQBN("test:esp:dev")
+	require.NoError(t, err)
+	_, _, _, _, _, err = pme.ResolveFQBN(fqbn)
+	require.ErrorIs(t, err, ErrRequiredToolNotFound)
+	missing, err = pme.ValidateForUpload("test:esp:dev:FlashSize=4M")
+	require.NoError(t, err)
+	require.Len(t, missing, 1)
+	require.Equal(t, "PartitionScheme", missing[0].Option)
+}
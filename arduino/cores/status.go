@@ -92,6 +92,14 @@ func (packages Packages) GetPlatformReleaseToolDependencies(release *PlatformRel
 		if !exists {
 			return nil, fmt.Errorf(tr("tool %s not found"), dep.ToolName)
 		}
+		if dep.ToolVersionConstraint != nil {
+			toolRelease := tool.FindReleaseForDependency(dep)
+			if toolRelease == nil {
+				return nil, fmt.Errorf(tr("tool version %s not found"), dep.ToolVersion)
+			}
+			ret = append(ret, toolRelease)
+			continue
+		}
 		toolRelease, exists := tool.Releases[dep.ToolVersion.NormalizedString()]
 		if !exists {
 			return nil, fmt.Errorf(tr("tool version %s not found"), dep.ToolVersion)
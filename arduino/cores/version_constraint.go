@@ -0,0 +1,119 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2023 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package cores
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	semver "go.bug.st/relaxed-semver"
+)
+
+// IsToolVersionConstraint returns true if the given tool version, as written
+// in a package index, is a version range (ex: ">=1.2.0", "^7.3.0", "~1.4")
+// instead of a single version.
+func IsToolVersionConstraint(version string) bool {
+	version = strings.TrimSpace(version)
+	return version != "" && strings.ContainsRune("<>=!^~(", rune(version[0]))
+}
+
+// ParseToolVersionConstraint parses a tool version range. In addition to the
+// syntax of semver.ParseConstraint (=, <, <=, >, >=, ^, !, &&, ||, parenthesis)
+// the following forms are accepted:
+//   - "~1.2.3" or "~1.2" meaning ">=1.2.3 <1.3.0" (patch releases allowed)
+//   - "~1" meaning ">=1.0.0 <2.0.0" (minor releases allowed)
+//   - space or comma separated terms, meaning that all the terms must match
+//     (ex: ">=1.2.0 <2.0.0")
+func ParseToolVersionConstraint(in string) (semver.Constraint, error) {
+	in = strings.TrimSpace(in)
+	if in == "" {
+		return nil, fmt.Errorf(tr("empty version constraint"))
+	}
+	if strings.ContainsAny(in, "&(") {
+		// Already in semver.ParseConstraint syntax
+		return semver.ParseConstraint(in)
+	}
+
+	alternatives := []string{}
+	for _, alternative := range strings.Split(in, "||") {
+		terms := []string{}
+		for _, term := range strings.FieldsFunc(alternative, func(r rune) bool { return r == ' ' || r == ',' }) {
+			if strings.HasPrefix(term, "~") {
+				expanded, err := expandTildeConstraint(term)
+				if err != nil {
+					return nil, err
+				}
+				term = expanded
+			}
+			terms = append(terms, term)
+		}
+		if len(terms) == 0 {
+			return nil, fmt.Errorf(tr("invalid version constraint: %s"), in)
+		}
+		alternatives = append(alternatives, "("+strings.Join(terms, " && ")+")")
+	}
+	return semver.ParseConstraint(strings.Join(alternatives, " || "))
+}
+
+// expandTildeConstraint converts a "~VERSION" constraint into the equivalent
+// ">=VERSION && <NEXT" constraint
+func expandTildeConstraint(term string) (string, error) {
+	versionString := strings.TrimPrefix(strings.TrimPrefix(term, "~"), ">")
+	version, err := semver.Parse(versionString)
+	if err != nil {
+		return "", fmt.Errorf(tr("invalid version constraint: %s"), term)
+	}
+	versionCore, _, _ := strings.Cut(versionString, "-")
+	versionCore, _, _ = strings.Cut(versionCore, "+")
+	parts := strings.Split(versionCore, ".")
+	major, err := strconv.ParseUint(parts[0], 10, 64)
+	if err != nil {
+		return "", fmt.Errorf(tr("invalid version constraint: %s"), term)
+	}
+	// ~1 allows minor releases, ~1.2 and ~1.2.3 allow patch releases
+	next := fmt.Sprintf("%d.0.0", major+1)
+	if len(parts) > 1 {
+		minor, err := strconv.ParseUint(parts[1], 10, 64)
+		if err != nil {
+			return "", fmt.Errorf(tr("invalid version constraint: %s"), term)
+		}
+		next = fmt.Sprintf("%d.%d.0", major, minor+1)
+	}
+	return "(>=" + version.String() + " && <" + next + ")", nil
+}
+
+// FindReleaseForDependency returns the release of the tool satisfying the given
+// dependency: the newest installed one or, if none is installed, the newest one
+// available. Returns nil if no release satisfies the dependency.
+func (tool *Tool) FindReleaseForDependency(dep *ToolDependency) *ToolRelease {
+	var installed, available *ToolRelease
+	for _, release := range tool.Releases {
+		if !dep.IsSatisfiedBy(release.Version) {
+			continue
+		}
+		if available == nil || release.Version.GreaterThan(available.Version) {
+			available = release
+		}
+		if release.IsInstalled() && (installed == nil || release.Version.GreaterThan(installed.Version)) {
+			installed = release
+		}
+	}
+	if installed != nil {
+		return installed
+	}
+	return available
+}
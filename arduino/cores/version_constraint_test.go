@@ -0,0 +1,85 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2023 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package cores
+
+import (
+	"testing"
+
+	"github.com/arduino/go-paths-helper"
+	"github.com/stretchr/testify/require"
+	semver "go.bug.st/relaxed-semver"
+)
+
+func TestParseToolVersionConstraint(t *testing.T) {
+	match := func(constraint string, version string) bool {
+		c, err := ParseToolVersionConstraint(constraint)
+		require.NoError(t, err, constraint)
+		return c.Match(semver.MustParse(version))
+	}
+	require.True(t, match(">=1.2.0", "1.2.0"))
+	require.True(t, match("~1.2", "1.2.9"))
+	require.False(t, match("~1.2", "1.3.0"))
+	require.True(t, match("~1", "1.9.0"))
+	require.False(t, match("~1", "2.0.0"))
+	require.True(t, match("~1.2.3", "1.2.5"))
+	require.False(t, match("~1.2.3", "1.2.2"))
+	require.True(t, match("^7.3.0", "7.9.1"))
+	require.False(t, match("^7.3.0", "8.0.0"))
+	require.True(t, match(">=1.0.0 <2.0.0", "1.5.0"))
+	require.False(t, match(">=1.0.0, <2.0.0", "2.0.0"))
+	require.True(t, match("<1.0.0 || >=3.0.0", "3.1.0"))
+	require.False(t, match("<1.0.0 || >=3.0.0", "2.0.0"))
+	require.True(t, match(">=1.0.0 && <2.0.0", "1.0.0"))
+}
+
+func TestFindReleaseForDependency(t *testing.T) {
+	tool := NewPackages().GetOrCreatePackage("arduino").GetOrCreateTool("bossac")
+	for _, version := range []string{"1.6.1", "1.7.0", "1.8.1", "2.0.0"} {
+		tool.GetOrCreateRelease(semver.ParseRelaxed(version))
+	}
+	constraint, err := ParseToolVersionConstraint("^1.7.0")
+	require.NoError(t, err)
+	dep := &ToolDependency{ToolPackager: "arduino", ToolName: "bossac", ToolVersion: semver.ParseRelaxed("^1.7.0"), ToolVersionConstraint: constraint}
+
+	// The newest available release is selected...
+	require.Equal(t, "arduino:bossac@1.8.1", tool.FindReleaseForDependency(dep).String())
+
+	// ...unless a compatible release is installed
+	tool.Releases["1.7.0"].InstallDir = paths.New(t.TempDir())
+	require.Equal(t, "arduino:bossac@1.7.0", tool.FindReleaseForDependency(dep).String())
+
+	constraint, err = ParseToolVersionConstraint(">=3.0.0")
+	require.NoError(t, err)
+	require.Nil(t, tool.FindReleaseForDependency(&ToolDependency{ToolVersionConstraint: constraint}))
+}
+
+func TestToolDependencyIsSatisfiedBy(t *testing.T) {
+	constraint, err := ParseToolVersionConstraint("^1.7.0")
+	require.NoError(t, err)
+	dep := &ToolDependency{ToolPackager: "arduino", ToolName: "bossac", ToolVersion: semver.ParseRelaxed("^1.7.0"), ToolVersionConstraint: constraint}
+	require.True(t, dep.IsSatisfiedBy(semver.ParseRelaxed("1.8.1")))
+	require.False(t, dep.IsSatisfiedBy(semver.ParseRelaxed("2.0.0")))
+	require.False(t, dep.IsSatisfiedBy(nil))
+
+	dep = &ToolDependency{ToolPackager: "arduino", ToolName: "bossac", ToolVersion: semver.ParseRelaxed("1.7.0")}
+	require.True(t, dep.IsSatisfiedBy(semver.ParseRelaxed("1.7.0")))
+	require.False(t, dep.IsSatisfiedBy(semver.ParseRelaxed("1.8.1")))
+	require.False(t, dep.IsSatisfiedBy(nil))
+
+	// A dependency without version is never satisfied
+	dep = &ToolDependency{ToolPackager: "arduino", ToolName: "bossac"}
+	require.False(t, dep.IsSatisfiedBy(semver.ParseRelaxed("1.7.0")))
+}
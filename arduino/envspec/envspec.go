@@ -0,0 +1,230 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2023 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+// Package envspec allows to export the platforms, tools and libraries
+// installed in an environment into a spec file, and to compute the plan needed
+// to reproduce the same environment on another machine.
+package envspec
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/arduino/arduino-cli/arduino"
+	"github.com/arduino/arduino-cli/arduino/cores"
+	"github.com/arduino/arduino-cli/arduino/cores/packagemanager"
+	"github.com/arduino/arduino-cli/arduino/libraries"
+	"github.com/arduino/arduino-cli/arduino/libraries/librariesindex"
+	"github.com/arduino/arduino-cli/arduino/libraries/librariesmanager"
+	"github.com/arduino/arduino-cli/i18n"
+	"github.com/arduino/go-paths-helper"
+	semver "go.bug.st/relaxed-semver"
+	"gopkg.in/yaml.v2"
+)
+
+var tr = i18n.Tr
+
+// Spec is the exact set of platforms, tools and libraries installed in an
+// environment.
+type Spec struct {
+	Platforms []*PlatformSpec `yaml:"platforms"`
+	Tools     []*ToolSpec     `yaml:"tools"`
+	Libraries []*LibrarySpec  `yaml:"libraries"`
+}
+
+// PlatformSpec is an installed platform release
+type PlatformSpec struct {
+	Packager     string `yaml:"packager"`
+	Architecture string `yaml:"architecture"`
+	Version      string `yaml:"version"`
+	// IndexURL is the URL of the package index providing the platform, if any
+	IndexURL string `yaml:"index_url,omitempty"`
+	// URL is the download URL of the platform archive, if any
+	URL string `yaml:"url,omitempty"`
+}
+
+func (p *PlatformSpec) String() string {
+	return p.Packager + ":" + p.Architecture + "@" + p.Version
+}
+
+// ToolSpec is an installed tool release
+type ToolSpec struct {
+	Packager string `yaml:"packager"`
+	Name     string `yaml:"name"`
+	Version  string `yaml:"version"`
+	// URL is the download URL of the tool archive for the current host, if any
+	URL string `yaml:"url,omitempty"`
+}
+
+func (t *ToolSpec) String() string {
+	return t.Packager + ":" + t.Name + "@" + t.Version
+}
+
+// LibrarySpec is a library installed in the user libraries directory
+type LibrarySpec struct {
+	Name    string `yaml:"name"`
+	Version string `yaml:"version"`
+	// URL is the download URL of the library archive, if any
+	URL string `yaml:"url,omitempty"`
+}
+
+func (l *LibrarySpec) String() string {
+	return l.Name + "@" + l.Version
+}
+
+// NewSpec returns the Spec of the platforms and tools installed in pme and of
+// the libraries installed in the user directory of lm. Libraries without a
+// version (legacy libraries) are not part of the spec since they can't be
+// installed from the index.
+func NewSpec(pme *packagemanager.Explorer, lm *librariesmanager.LibrariesManager) *Spec {
+	spec := &Spec{
+		Platforms: []*PlatformSpec{},
+		Tools:     []*ToolSpec{},
+		Libraries: []*LibrarySpec{},
+	}
+	for _, release := range pme.InstalledPlatformReleases() {
+		platformSpec := &PlatformSpec{
+			Packager:     release.Platform.Package.Name,
+			Architecture: release.Platform.Architecture,
+			Version:      release.Version.String(),
+		}
+		if provenance := pme.PlatformReleaseProvenance(release); len(provenance) > 0 {
+			platformSpec.IndexURL = provenance[len(provenance)-1].URL
+		}
+		if release.Resource != nil {
+			platformSpec.URL = release.Resource.URL
+		}
+		spec.Platforms = append(spec.Platforms, platformSpec)
+	}
+	for _, release := range pme.GetAllInstalledToolsReleases() {
+		toolSpec := &ToolSpec{
+			Packager: release.Tool.Package.Name,
+			Name:     release.Tool.Name,
+			Version:  release.Version.String(),
+		}
+		if flavour := release.GetCompatibleFlavour(); flavour != nil {
+			toolSpec.URL = flavour.URL
+		}
+		spec.Tools = append(spec.Tools, toolSpec)
+	}
+	for _, lib := range lm.InstalledLibraries() {
+		if lib.Location != libraries.User || lib.IsLegacy || lib.Version == nil || lib.Version.String() == "" {
+			continue
+		}
+		libSpec := &LibrarySpec{
+			Name:    lib.Name,
+			Version: lib.Version.String(),
+		}
+		if lm.Index != nil {
+			if release := lm.Index.FindRelease(&librariesindex.Reference{Name: lib.Name, Version: lib.Version}); release != nil && release.Resource != nil {
+				libSpec.URL = release.Resource.URL
+			}
+		}
+		spec.Libraries = append(spec.Libraries, libSpec)
+	}
+
+	sort.Slice(spec.Platforms, func(i, j int) bool { return spec.Platforms[i].String() < spec.Platforms[j].String() })
+	sort.Slice(spec.Tools, func(i, j int) bool { return spec.Tools[i].String() < spec.Tools[j].String() })
+	sort.Slice(spec.Libraries, func(i, j int) bool { return spec.Libraries[i].String() < spec.Libraries[j].String() })
+	return spec
+}
+
+// ExportSpec writes the Spec of the environment to the given file, see NewSpec.
+func ExportSpec(pme *packagemanager.Explorer, lm *librariesmanager.LibrariesManager, path *paths.Path) error {
+	return NewSpec(pme, lm).Save(path)
+}
+
+// Save writes the Spec to the given file as YAML
+func (s *Spec) Save(path *paths.Path) error {
+	data, err := yaml.Marshal(s)
+	if err != nil {
+		return err
+	}
+	return path.WriteFile(data)
+}
+
+// LoadSpec reads a Spec from the given file
+func LoadSpec(path *paths.Path) (*Spec, error) {
+	data, err := path.ReadFile()
+	if err != nil {
+		return nil, err
+	}
+	spec := &Spec{}
+	if err := yaml.Unmarshal(data, spec); err != nil {
+		return nil, fmt.Errorf("%s: %w", tr("invalid spec file"), err)
+	}
+	return spec, nil
+}
+
+// InstallPlan contains the releases needed to reproduce a Spec. All the
+// releases listed in the spec are included, even if already installed: use
+// the IsInstalled method of platforms and tools, or the
+// LibrariesManager.InstallPrerequisiteCheck for libraries, to skip them.
+type InstallPlan struct {
+	Platforms []*cores.PlatformRelease
+	Tools     []*cores.ToolRelease
+	Libraries []*librariesindex.Release
+}
+
+// Resolve returns the InstallPlan needed to reach the Spec using the package
+// indexes loaded in pme and the library index loaded in lm. An error is
+// returned if any of the releases in the spec is not available.
+func (s *Spec) Resolve(pme *packagemanager.Explorer, lm *librariesmanager.LibrariesManager) (*InstallPlan, error) {
+	plan := &InstallPlan{
+		Platforms: []*cores.PlatformRelease{},
+		Tools:     []*cores.ToolRelease{},
+		Libraries: []*librariesindex.Release{},
+	}
+	for _, platformSpec := range s.Platforms {
+		version, err := semver.Parse(platformSpec.Version)
+		if err != nil {
+			return nil, &arduino.InvalidVersionError{Cause: err}
+		}
+		release := pme.FindPlatformRelease(&packagemanager.PlatformReference{
+			Package:              platformSpec.Packager,
+			PlatformArchitecture: platformSpec.Architecture,
+			PlatformVersion:      version,
+		})
+		if release == nil {
+			return nil, &arduino.PlatformNotFoundError{Platform: platformSpec.String()}
+		}
+		plan.Platforms = append(plan.Platforms, release)
+	}
+	for _, toolSpec := range s.Tools {
+		var release *cores.ToolRelease
+		if tool := pme.GetTool(toolSpec.Packager + ":" + toolSpec.Name); tool != nil {
+			release = tool.FindReleaseWithRelaxedVersion(semver.ParseRelaxed(toolSpec.Version))
+		}
+		if release == nil {
+			return nil, &arduino.NotFoundError{Message: tr("Tool '%s' not found", toolSpec.String())}
+		}
+		plan.Tools = append(plan.Tools, release)
+	}
+	for _, libSpec := range s.Libraries {
+		version, err := semver.Parse(libSpec.Version)
+		if err != nil {
+			return nil, &arduino.InvalidVersionError{Cause: err}
+		}
+		var release *librariesindex.Release
+		if lm.Index != nil {
+			release = lm.Index.FindRelease(&librariesindex.Reference{Name: libSpec.Name, Version: version})
+		}
+		if release == nil {
+			return nil, &arduino.LibraryNotFoundError{Library: libSpec.String()}
+		}
+		plan.Libraries = append(plan.Libraries, release)
+	}
+	return plan, nil
+}
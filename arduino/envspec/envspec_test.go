@@ -0,0 +1,102 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2023 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package envspec
+
+import (
+	"testing"
+
+	"github.com/arduino/arduino-cli/arduino/cores/packagemanager"
+	"github.com/arduino/arduino-cli/arduino/libraries"
+	"github.com/arduino/arduino-cli/arduino/libraries/librariesindex"
+	"github.com/arduino/arduino-cli/arduino/libraries/librariesmanager"
+	"github.com/arduino/arduino-cli/arduino/resources"
+	"github.com/arduino/go-paths-helper"
+	"github.com/stretchr/testify/require"
+	semver "go.bug.st/relaxed-semver"
+)
+
+func TestExportSpecAndResolve(t *testing.T) {
+	indexPath := paths.New("..", "cores", "packagemanager", "testdata", "package_tooltest_index.json")
+	pmb := packagemanager.NewBuilder(nil, nil, nil, nil, "test")
+	_, err := pmb.LoadPackageIndexFromFile(indexPath)
+	require.NoError(t, err)
+	pm := pmb.Build()
+	pme, release := pm.NewExplorer()
+	defer release()
+
+	// Fake the installation of the platform and of one of its tools
+	platformRelease := pme.FindPlatformRelease(&packagemanager.PlatformReference{
+		Package:              "test",
+		PlatformArchitecture: "avr",
+		PlatformVersion:      semver.MustParse("1.2.3"),
+	})
+	require.NotNil(t, platformRelease)
+	platformRelease.InstallDir = paths.New(t.TempDir())
+	toolRelease := pme.GetTool("test:some-tool").FindReleaseWithRelaxedVersion(semver.ParseRelaxed("0.42.0"))
+	require.NotNil(t, toolRelease)
+	toolRelease.InstallDir = paths.New(t.TempDir())
+
+	userDir := paths.New(t.TempDir())
+	require.NoError(t, paths.New("..", "libraries", "testdata", "TestLib").CopyDirTo(userDir.Join("TestLib")))
+	require.NoError(t, paths.New("..", "libraries", "testdata", "LegacyLib").CopyDirTo(userDir.Join("LegacyLib")))
+	lm := librariesmanager.NewLibraryManager(nil, nil)
+	lm.AddLibrariesDir(userDir, libraries.User)
+	indexedLib := &librariesindex.Library{Name: "TestLib", Releases: map[semver.NormalizedString]*librariesindex.Release{}}
+	for _, v := range []string{"1.0.3", "1.1.0"} {
+		libRelease := &librariesindex.Release{
+			Version:  semver.MustParse(v),
+			Library:  indexedLib,
+			Resource: &resources.DownloadResource{URL: "https://example.com/TestLib-" + v + ".zip"},
+		}
+		indexedLib.Releases[libRelease.Version.NormalizedString()] = libRelease
+		indexedLib.Latest = libRelease
+	}
+	lm.Index = &librariesindex.Index{Libraries: map[string]*librariesindex.Library{"TestLib": indexedLib}}
+
+	specFile := paths.New(t.TempDir()).Join("spec.yaml")
+	require.NoError(t, ExportSpec(pme, lm, specFile))
+
+	spec, err := LoadSpec(specFile)
+	require.NoError(t, err)
+	require.Equal(t, []*PlatformSpec{{
+		Packager:     "test",
+		Architecture: "avr",
+		Version:      "1.2.3",
+		IndexURL:     indexPath.String(),
+		URL:          "http://downloads.arduino.cc/cores/avr-1.8.3.tar.bz2",
+	}}, spec.Platforms)
+	require.Len(t, spec.Tools, 1)
+	require.Equal(t, "test:some-tool@0.42.0", spec.Tools[0].String())
+	require.Equal(t, []*LibrarySpec{{
+		Name:    "TestLib",
+		Version: "1.0.3",
+		URL:     "https://example.com/TestLib-1.0.3.zip",
+	}}, spec.Libraries)
+
+	plan, err := spec.Resolve(pme, lm)
+	require.NoError(t, err)
+	require.Len(t, plan.Platforms, 1)
+	require.Equal(t, platformRelease, plan.Platforms[0])
+	require.Len(t, plan.Tools, 1)
+	require.Equal(t, toolRelease, plan.Tools[0])
+	require.Len(t, plan.Libraries, 1)
+	require.Equal(t, "TestLib@1.0.3", plan.Libraries[0].String())
+
+	// A release missing from the indexes can't be resolved
+	spec.Libraries[0].Version = "2.0.0"
+	_, err = spec.Resolve(pme, lm)
+	require.Error(t, err)
+}
@@ -0,0 +1,339 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2023 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package envspec
+
+import (
+	"errors"
+	"runtime"
+	"sync"
+
+	"github.com/arduino/arduino-cli/arduino"
+	"github.com/arduino/arduino-cli/arduino/cores/packagemanager"
+	"github.com/arduino/arduino-cli/arduino/libraries"
+	"github.com/arduino/arduino-cli/arduino/libraries/librariesmanager"
+	"github.com/arduino/arduino-cli/arduino/resources"
+	rpc "github.com/arduino/arduino-cli/rpc/cc/arduino/cli/commands/v1"
+	"github.com/arduino/go-paths-helper"
+	"go.bug.st/downloader/v2"
+)
+
+// ExecuteOptions are the options of ExecuteInstallPlan
+type ExecuteOptions struct {
+	// Jobs is the maximum number of concurrent downloads, if 0 or less the
+	// number of available CPUs is used
+	Jobs int
+	// DownloaderConfig is used for the downloads, the default configuration
+	// is used if nil
+	DownloaderConfig *downloader.Config
+	// DownloadCB and TaskCB receive the progress of each item of the plan,
+	// they may be nil
+	DownloadCB rpc.DownloadProgressCB
+	TaskCB     rpc.TaskProgressCB
+	// SkipPostInstall disables the post_install scripts of platforms and tools
+	SkipPostInstall bool
+	// SkipPreUninstall disables the pre_uninstall scripts of the platforms
+	// replaced by the plan
+	SkipPreUninstall bool
+}
+
+// planItem is a platform, tool or library release of an InstallPlan that must
+// be installed
+type planItem struct {
+	label       string
+	resource    *resources.DownloadResource
+	downloadDir *paths.Path
+	signatures  resources.ArchiveSignatureVerification
+	// install installs the item and returns the functions to undo the
+	// installation or, if all the plan succeeds, to make it final
+	install func() (rollback func(), commit func(), err error)
+}
+
+// ExecuteInstallPlan installs all the releases of the plan that are not
+// already installed. The archives are downloaded first, up to opts.Jobs at
+// the same time: archives with the same checksum are downloaded only once,
+// and every archive is verified before installing anything. Then the tools,
+// the platforms and the libraries are installed in this order. The platforms
+// and libraries replaced by a different version are removed only after the
+// whole plan is installed: if any step fails, the releases installed so far
+// are removed and the replaced ones are restored. The libraries are installed
+// in the user directory.
+func ExecuteInstallPlan(pme *packagemanager.Explorer, lm *librariesmanager.LibrariesManager, plan *InstallPlan, opts *ExecuteOptions) error {
+	if opts == nil {
+		opts = &ExecuteOptions{}
+	}
+	taskCB := opts.TaskCB
+	if taskCB == nil {
+		taskCB = func(*rpc.TaskProgress) {}
+	}
+	var downloadCBMux sync.Mutex
+	downloadCB := func(progress *rpc.DownloadProgress) {
+		if opts.DownloadCB == nil {
+			return
+		}
+		downloadCBMux.Lock()
+		defer downloadCBMux.Unlock()
+		opts.DownloadCB(progress)
+	}
+
+	items, err := planItems(pme, lm, plan, opts, taskCB)
+	if err != nil {
+		return err
+	}
+	if len(items) == 0 {
+		return nil
+	}
+
+	taskCB(&rpc.TaskProgress{Name: tr("Downloading packages")})
+	if err := downloadPlanItems(items, opts, downloadCB); err != nil {
+		return err
+	}
+	taskCB(&rpc.TaskProgress{Completed: true})
+
+	rollbacks := []func(){}
+	commits := []func(){}
+	for _, item := range items {
+		rollback, commit, err := item.install()
+		if err != nil {
+			taskCB(&rpc.TaskProgress{Message: tr("Error installing %[1]s: %[2]s", item.label, err)})
+			for i := len(rollbacks) - 1; i >= 0; i-- {
+				rollbacks[i]()
+			}
+			return &arduino.FailedInstallError{Message: tr("Cannot install %s", item.label), Cause: err}
+		}
+		rollbacks = append(rollbacks, rollback)
+		commits = append(commits, commit)
+	}
+	for _, commit := range commits {
+		commit()
+	}
+	return nil
+}
+
+// planItems returns the items of the plan that must be installed, in
+// installation order
+func planItems(pme *packagemanager.Explorer, lm *librariesmanager.LibrariesManager, plan *InstallPlan, opts *ExecuteOptions, taskCB rpc.TaskProgressCB) ([]*planItem, error) {
+	if lm == nil && len(plan.Libraries) > 0 {
+		return nil, &arduino.InvalidArgumentError{Message: tr("A libraries manager is required to install the libraries of the plan")}
+	}
+
+	items := []*planItem{}
+	for _, tool := range plan.Tools {
+		tool := tool
+		if tool.IsInstalled() {
+			taskCB(&rpc.TaskProgress{Name: tr("Tool %s already installed", tool), Completed: true})
+			continue
+		}
+		resource := tool.GetCompatibleFlavour()
+		if resource == nil {
+			return nil, &arduino.FailedDownloadError{
+				Message: tr("Error downloading tool %s", tool),
+				Cause:   errors.New(tr("no versions available for the current OS, try contacting %s", tool.Tool.Package.Email))}
+		}
+		items = append(items, &planItem{
+			label:       tool.String(),
+			resource:    resource,
+			downloadDir: pme.DownloadDir,
+			signatures:  pme.ArchiveSignatureVerification(),
+			install: func() (func(), func(), error) {
+				if err := pme.InstallTool(tool, taskCB, opts.SkipPostInstall); err != nil {
+					return nil, nil, err
+				}
+				rollback := func() { _ = pme.UninstallTool(tool, taskCB, true) }
+				return rollback, func() {}, nil
+			},
+		})
+	}
+
+	for _, platformRelease := range plan.Platforms {
+		platformRelease := platformRelease
+		if platformRelease.IsInstalled() {
+			taskCB(&rpc.TaskProgress{Name: tr("Platform %s already installed", platformRelease), Completed: true})
+			continue
+		}
+		if platformRelease.Resource == nil {
+			return nil, &arduino.PlatformNotFoundError{Platform: platformRelease.String()}
+		}
+		items = append(items, &planItem{
+			label:       platformRelease.String(),
+			resource:    platformRelease.Resource,
+			downloadDir: pme.DownloadDir,
+			signatures:  pme.ArchiveSignatureVerification(),
+			install: func() (func(), func(), error) {
+				replaced := pme.GetInstalledPlatformRelease(platformRelease.Platform)
+				taskCB(&rpc.TaskProgress{Name: tr("Installing platform %s", platformRelease)})
+				if err := pme.InstallPlatform(platformRelease); err != nil {
+					return nil, nil, err
+				}
+				if !pme.SkipScripts(opts.SkipPostInstall, platformRelease.Platform.String(), taskCB) {
+					stdout, stderr, err := pme.RunPreOrPostScript(platformRelease.InstallDir, "post_install")
+					if len(stdout) > 0 {
+						taskCB(&rpc.TaskProgress{Message: string(stdout)})
+					}
+					if len(stderr) > 0 {
+						taskCB(&rpc.TaskProgress{Message: string(stderr)})
+					}
+					if err != nil {
+						taskCB(&rpc.TaskProgress{Message: tr("WARNING cannot configure platform: %s", err)})
+					}
+				}
+				taskCB(&rpc.TaskProgress{Message: tr("Platform %s installed", platformRelease), Completed: true})
+				rollback := func() { _ = pme.UninstallPlatform(platformRelease, taskCB, true) }
+				commit := func() {
+					if replaced != nil {
+						_ = pme.UninstallPlatform(replaced, taskCB, opts.SkipPreUninstall)
+					}
+				}
+				return rollback, commit, nil
+			},
+		})
+	}
+
+	for _, libRelease := range plan.Libraries {
+		libRelease := libRelease
+		libInstallPlan, err := lm.InstallPrerequisiteCheck(libRelease.Library.Name, libRelease.Version, libraries.User)
+		if err != nil {
+			return nil, err
+		}
+		if libInstallPlan.UpToDate {
+			taskCB(&rpc.TaskProgress{Name: tr("Library %s is already installed", libRelease), Completed: true})
+			continue
+		}
+		items = append(items, &planItem{
+			label:       libRelease.String(),
+			resource:    libRelease.Resource,
+			downloadDir: lm.DownloadsDir,
+			signatures:  lm.ArchiveSignatureVerification(),
+			install: func() (func(), func(), error) {
+				taskCB(&rpc.TaskProgress{Name: tr("Installing %s", libRelease)})
+				// The replaced library is moved aside, to be restored if the plan fails
+				var backup *paths.Path
+				if replaced := libInstallPlan.ReplacedLib; replaced != nil {
+					backup = replaced.InstallDir.Parent().Join("." + replaced.InstallDir.Base() + ".replaced")
+					if err := backup.RemoveAll(); err != nil {
+						return nil, nil, err
+					}
+					if err := replaced.InstallDir.Rename(backup); err != nil {
+						return nil, nil, err
+					}
+				}
+				restore := func() {
+					if backup != nil {
+						_ = libInstallPlan.TargetPath.RemoveAll()
+						_ = backup.Rename(libInstallPlan.ReplacedLib.InstallDir)
+					}
+				}
+				if err := lm.Install(libRelease, libInstallPlan.TargetPath); err != nil {
+					restore()
+					return nil, nil, &arduino.FailedLibraryInstallError{Cause: err}
+				}
+				taskCB(&rpc.TaskProgress{Message: tr("Installed %s", libRelease), Completed: true})
+				rollback := func() {
+					_ = libInstallPlan.TargetPath.RemoveAll()
+					restore()
+				}
+				commit := func() {
+					if backup != nil {
+						_ = backup.RemoveAll()
+					}
+				}
+				return rollback, commit, nil
+			},
+		})
+	}
+	return items, nil
+}
+
+// downloadPlanItems downloads and verifies the archives of the items. The
+// items whose archives have the same checksum share a single download.
+func downloadPlanItems(items []*planItem, opts *ExecuteOptions, downloadCB rpc.DownloadProgressCB) error {
+	groups := [][]*planItem{}
+	groupsByChecksum := map[string]int{}
+	for _, item := range items {
+		if item.resource.Checksum != "" {
+			if i, ok := groupsByChecksum[item.resource.Checksum]; ok {
+				groups[i] = append(groups[i], item)
+				continue
+			}
+			groupsByChecksum[item.resource.Checksum] = len(groups)
+		}
+		groups = append(groups, []*planItem{item})
+	}
+
+	jobs := opts.Jobs
+	if jobs <= 0 {
+		jobs = runtime.NumCPU()
+	}
+	errs := make([]error, len(groups))
+	var wg sync.WaitGroup
+	semaphore := make(chan struct{}, jobs)
+	for i, group := range groups {
+		wg.Add(1)
+		semaphore <- struct{}{}
+		go func(i int, group []*planItem) {
+			defer func() {
+				<-semaphore
+				wg.Done()
+			}()
+			errs[i] = downloadPlanItemsGroup(group, opts.DownloaderConfig, downloadCB)
+		}(i, group)
+	}
+	wg.Wait()
+	return errors.Join(errs...)
+}
+
+// downloadPlanItemsGroup downloads the archive of the first item and copies
+// it in place of the archives of the others, that have the same content
+func downloadPlanItemsGroup(group []*planItem, config *downloader.Config, downloadCB rpc.DownloadProgressCB) error {
+	first := group[0]
+	if err := first.resource.Download(first.downloadDir, config, first.label, downloadCB, "", first.signatures); err != nil {
+		return &arduino.FailedDownloadError{Message: tr("Error downloading %s", first.label), Cause: err}
+	}
+	if err := verifyPlanItemArchive(first); err != nil {
+		return err
+	}
+	firstArchive, err := first.resource.ArchivePath(first.downloadDir)
+	if err != nil {
+		return err
+	}
+	for _, item := range group[1:] {
+		archive, err := item.resource.ArchivePath(item.downloadDir)
+		if err != nil {
+			return err
+		}
+		downloadCB.Start(item.resource.URL, item.label)
+		if !archive.EquivalentTo(firstArchive) {
+			if err := firstArchive.CopyTo(archive); err != nil {
+				downloadCB.End(false, err.Error())
+				return &arduino.FailedDownloadError{Message: tr("Error downloading %s", item.label), Cause: err}
+			}
+		}
+		downloadCB.End(true, tr("%[1]s has the same archive of %[2]s", item.label, first.label))
+		if err := verifyPlanItemArchive(item); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func verifyPlanItemArchive(item *planItem) error {
+	if ok, err := item.resource.TestLocalArchiveIntegrity(item.downloadDir); err != nil || !ok {
+		if err == nil {
+			err = errors.New(tr("archive is not valid"))
+		}
+		return &arduino.FailedDownloadError{Message: tr("Error downloading %s", item.label), Cause: err}
+	}
+	return nil
+}
@@ -0,0 +1,173 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2023 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package envspec
+
+import (
+	"archive/zip"
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/arduino/arduino-cli/arduino"
+	"github.com/arduino/arduino-cli/arduino/cores"
+	"github.com/arduino/arduino-cli/arduino/cores/packagemanager"
+	"github.com/arduino/arduino-cli/arduino/libraries/librariesindex"
+	"github.com/arduino/arduino-cli/arduino/resources"
+	rpc "github.com/arduino/arduino-cli/rpc/cc/arduino/cli/commands/v1"
+	"github.com/arduino/go-paths-helper"
+	"github.com/stretchr/testify/require"
+	semver "go.bug.st/relaxed-semver"
+)
+
+func makeTestZip(t *testing.T, root string) []byte {
+	var buf bytes.Buffer
+	w := zip.NewWriter(&buf)
+	f, err := w.Create(root + "/file.txt")
+	require.NoError(t, err)
+	_, err = f.Write([]byte("content of " + root))
+	require.NoError(t, err)
+	require.NoError(t, w.Close())
+	return buf.Bytes()
+}
+
+func TestExecuteInstallPlan(t *testing.T) {
+	archives := map[string][]byte{
+		"/tool.zip":     makeTestZip(t, "tool"),
+		"/platform.zip": makeTestZip(t, "platform"),
+		"/broken.zip":   []byte("this is not a zip file"),
+	}
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		data, ok := archives[r.URL.Path]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		w.Write(data)
+	}))
+	defer server.Close()
+	resource := func(name, archivePath string) *resources.DownloadResource {
+		data := archives[archivePath]
+		sum := sha256.Sum256(data)
+		return &resources.DownloadResource{
+			URL:             server.URL + archivePath,
+			ArchiveFileName: name,
+			Checksum:        "SHA-256:" + hex.EncodeToString(sum[:]),
+			Size:            int64(len(data)),
+			CachePath:       "packages",
+		}
+	}
+
+	dataDir := paths.New(t.TempDir())
+	pmb := packagemanager.NewBuilder(dataDir, dataDir.Join("packages"), dataDir.Join("staging"), dataDir.Join("tmp"), "test")
+	pack := pmb.GetOrCreatePackage("test")
+	newTool := func(name string, res *resources.DownloadResource) *cores.ToolRelease {
+		release := pack.GetOrCreateTool(name).GetOrCreateRelease(semver.ParseRelaxed("1.0.0"))
+		release.Flavors = []*cores.Flavor{{OS: "all", Resource: res}}
+		return release
+	}
+	// Two tools with different archive names but the same content
+	toolA := newTool("tool-a", resource("tool-a-1.0.0.zip", "/tool.zip"))
+	toolB := newTool("tool-b", resource("tool-b-1.0.0.zip", "/tool.zip"))
+	platformRelease := pack.GetOrCreatePlatform("avr").GetOrCreateRelease(semver.MustParse("1.0.0"))
+	platformRelease.Resource = resource("platform-1.0.0.zip", "/platform.zip")
+	brokenRelease := pack.GetOrCreatePlatform("broken").GetOrCreateRelease(semver.MustParse("1.0.0"))
+	brokenRelease.Resource = resource("broken-1.0.0.zip", "/broken.zip")
+	pm := pmb.Build()
+	pme, release := pm.NewExplorer()
+	defer release()
+
+	t.Run("FailureRollsBack", func(t *testing.T) {
+		plan := &InstallPlan{
+			Platforms: []*cores.PlatformRelease{brokenRelease},
+			Tools:     []*cores.ToolRelease{toolA},
+		}
+		err := ExecuteInstallPlan(pme, nil, plan, &ExecuteOptions{SkipPostInstall: true})
+		require.Error(t, err)
+		require.False(t, toolA.IsInstalled())
+		require.False(t, dataDir.Join("packages", "test", "tools", "tool-a", "1.0.0").Exist())
+		require.False(t, brokenRelease.IsInstalled())
+	})
+
+	requests.Store(0)
+	installed := []string{}
+	downloaded := []string{}
+	opts := &ExecuteOptions{
+		Jobs:            2,
+		SkipPostInstall: true,
+		TaskCB: func(progress *rpc.TaskProgress) {
+			if strings.HasPrefix(progress.Name, "Installing") {
+				installed = append(installed, progress.Name)
+			}
+		},
+		DownloadCB: func(progress *rpc.DownloadProgress) {
+			if start := progress.GetStart(); start != nil {
+				downloaded = append(downloaded, start.Label)
+			}
+		},
+	}
+	plan := &InstallPlan{
+		Platforms: []*cores.PlatformRelease{platformRelease},
+		Tools:     []*cores.ToolRelease{toolA, toolB},
+	}
+	require.NoError(t, ExecuteInstallPlan(pme, nil, plan, opts))
+
+	// The tool archive is downloaded once (it was already downloaded by the
+	// previous test) and the platform archive once
+	require.Equal(t, int32(1), requests.Load())
+	require.ElementsMatch(t, []string{"test:tool-a@1.0.0", "test:tool-b@1.0.0", "test:avr@1.0.0"}, downloaded)
+	require.True(t, dataDir.Join("staging", "packages", "tool-b-1.0.0.zip").Exist())
+
+	// The tools are installed before the platform
+	require.Equal(t, []string{
+		"Installing test:tool-a@1.0.0",
+		"Installing test:tool-b@1.0.0",
+		"Installing platform test:avr@1.0.0",
+	}, installed)
+	require.True(t, toolA.IsInstalled())
+	require.True(t, toolB.IsInstalled())
+	require.True(t, platformRelease.IsInstalled())
+	require.True(t, toolB.InstallDir.Join("file.txt").Exist())
+	require.True(t, platformRelease.InstallDir.Join("file.txt").Exist())
+
+	// Nothing to do if everything is already installed
+	requests.Store(0)
+	require.NoError(t, ExecuteInstallPlan(pme, nil, plan, nil))
+	require.Equal(t, int32(0), requests.Load())
+}
+
+func TestExecuteInstallPlanWithoutLibrariesManager(t *testing.T) {
+	dataDir := paths.New(t.TempDir())
+	pmb := packagemanager.NewBuilder(dataDir, dataDir.Join("packages"), dataDir.Join("staging"), dataDir.Join("tmp"), "test")
+	pme, release := pmb.Build().NewExplorer()
+	defer release()
+
+	lib := &librariesindex.Library{Name: "TestLib"}
+	libRelease := &librariesindex.Release{Library: lib, Version: semver.MustParse("1.0.0")}
+	plan := &InstallPlan{Libraries: []*librariesindex.Release{libRelease}}
+	err := ExecuteInstallPlan(pme, nil, plan, nil)
+	require.Error(t, err)
+	require.IsType(t, &arduino.InvalidArgumentError{}, err)
+
+	// A plan without libraries doesn't need the libraries manager
+	require.NoError(t, ExecuteInstallPlan(pme, nil, &InstallPlan{}, nil))
+}
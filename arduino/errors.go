@@ -491,6 +491,21 @@ func (e *PlatformAlreadyAtTheLatestVersionError) ToRPCStatus() *status.Status {
 	return st
 }
 
+// PlatformPinnedError is returned when trying to upgrade a platform pinned to a specific version
+type PlatformPinnedError struct {
+	Platform string
+	Version  string
+}
+
+func (e *PlatformPinnedError) Error() string {
+	return tr("Platform '%[1]s' is pinned to version %[2]s", e.Platform, e.Version)
+}
+
+// ToRPCStatus converts the error into a *status.Status
+func (e *PlatformPinnedError) ToRPCStatus() *status.Status {
+	return status.New(codes.FailedPrecondition, e.Error())
+}
+
 // MissingSketchPathError is returned when the sketch path is mandatory and not specified
 type MissingSketchPathError struct{}
 
@@ -773,6 +788,25 @@ func (e *UnavailableError) ToRPCStatus() *status.Status {
 	return status.New(codes.Unavailable, e.Error())
 }
 
+// PackagesLockedError is returned when the installed platforms can not be
+// changed because another process is changing them
+type PackagesLockedError struct {
+	LockFile *paths.Path
+	Owner    string
+}
+
+func (e *PackagesLockedError) Error() string {
+	if e.Owner != "" {
+		return tr("Another process (PID %[1]s) is installing or updating the platforms, lock file %[2]s", e.Owner, e.LockFile)
+	}
+	return tr("Another process is installing or updating the platforms, lock file %s", e.LockFile)
+}
+
+// ToRPCStatus converts the error into a *status.Status
+func (e *PackagesLockedError) ToRPCStatus() *status.Status {
+	return status.New(codes.Unavailable, e.Error())
+}
+
 // TempDirCreationFailedError is returned if a temp dir could not be created
 type TempDirCreationFailedError struct {
 	Cause error
@@ -868,3 +902,19 @@ func (e *MultipleLibraryInstallDetected) Error() string {
 func (e *MultipleLibraryInstallDetected) ToRPCStatus() *status.Status {
 	return status.New(codes.InvalidArgument, e.Error())
 }
+
+// LimitExceededError is returned when a resource exceeds the maximum size allowed
+type LimitExceededError struct {
+	Message string
+	// Limit is the maximum size allowed, in bytes
+	Limit int64
+}
+
+func (e *LimitExceededError) Error() string {
+	return tr("%[1]s: exceeds the limit of %[2]d bytes", e.Message, e.Limit)
+}
+
+// ToRPCStatus converts the error into a *status.Status
+func (e *LimitExceededError) ToRPCStatus() *status.Status {
+	return status.New(codes.ResourceExhausted, e.Error())
+}
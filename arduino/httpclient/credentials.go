@@ -0,0 +1,138 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2023 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package httpclient
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"os/exec"
+	"strings"
+	"sync"
+
+	"github.com/arduino/arduino-cli/configuration"
+)
+
+// findCredential returns the credential to use for the given URL, the one with
+// the longest matching URL prefix, or nil if none matches. The credentials are
+// used only for the same scheme and host of their URL, so they are never sent
+// in clear text over HTTP, when given for HTTPS, or to the hosts where the
+// requests are redirected.
+func findCredential(credentials []*configuration.NetworkCredential, target *url.URL) *configuration.NetworkCredential {
+	var res *configuration.NetworkCredential
+	matchLen := -1
+	for _, credential := range credentials {
+		u, err := url.Parse(credential.URL)
+		if err != nil {
+			continue
+		}
+		if !strings.EqualFold(u.Scheme, target.Scheme) || !strings.EqualFold(u.Host, target.Host) {
+			continue
+		}
+		if !pathHasPrefix(target.Path, u.Path) || len(u.Path) <= matchLen {
+			continue
+		}
+		res = credential
+		matchLen = len(u.Path)
+	}
+	return res
+}
+
+// pathHasPrefix returns true if path is prefix or is inside it: the prefix must
+// end at a path segment boundary, so /private matches /private/index.json but
+// not /private-other/index.json.
+func pathHasPrefix(path, prefix string) bool {
+	if !strings.HasPrefix(path, prefix) {
+		return false
+	}
+	return len(path) == len(prefix) || prefix == "" || strings.HasSuffix(prefix, "/") || path[len(prefix)] == '/'
+}
+
+// authenticate returns a copy of the request with the Authorization header of
+// the matching credential, or the request itself if no credential matches. A
+// request already carrying an Authorization header, for example from the user
+// info of the URL, is left untouched.
+func authenticate(req *http.Request, credentials []*configuration.NetworkCredential) (*http.Request, error) {
+	if req.Header.Get("Authorization") != "" {
+		return req, nil
+	}
+	credential := findCredential(credentials, req.URL)
+	if credential == nil {
+		return req, nil
+	}
+	secret := credential.Password
+	if credential.Token != "" {
+		secret = credential.Token
+	}
+	value, err := resolveSecret(secret)
+	if err != nil {
+		return nil, fmt.Errorf(tr("getting the credentials for %[1]s: %[2]s"), credential.URL, err)
+	}
+	req = req.Clone(req.Context())
+	if credential.Token != "" {
+		req.Header.Set("Authorization", "Bearer "+value)
+	} else {
+		req.SetBasicAuth(credential.Username, value)
+	}
+	return req, nil
+}
+
+var resolvedSecrets = map[string]string{}
+var resolvedSecretsMux sync.Mutex
+
+// resolveSecret returns the secret given in the form documented in
+// configuration.NetworkCredential. The output of the commands is cached, so
+// the following requests don't run them again. The lock is not held while a
+// command runs, so a slow helper doesn't block the requests using other
+// secrets.
+func resolveSecret(secret string) (string, error) {
+	if name, ok := strings.CutPrefix(secret, "env:"); ok {
+		value, ok := os.LookupEnv(name)
+		if !ok {
+			return "", fmt.Errorf(tr("environment variable %s not set"), name)
+		}
+		return value, nil
+	}
+	commandLine, ok := strings.CutPrefix(secret, "cmd:")
+	if !ok {
+		return secret, nil
+	}
+
+	resolvedSecretsMux.Lock()
+	value, ok := resolvedSecrets[commandLine]
+	resolvedSecretsMux.Unlock()
+	if ok {
+		return value, nil
+	}
+	args := strings.Fields(commandLine)
+	if len(args) == 0 {
+		return "", fmt.Errorf(tr("missing command"))
+	}
+	stderr := &bytes.Buffer{}
+	cmd := exec.Command(args[0], args[1:]...)
+	cmd.Stderr = stderr
+	output, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf(tr("running %[1]s: %[2]s %[3]s"), args[0], err, strings.TrimSpace(stderr.String()))
+	}
+	value = strings.TrimRight(string(output), "\r\n")
+	resolvedSecretsMux.Lock()
+	resolvedSecrets[commandLine] = value
+	resolvedSecretsMux.Unlock()
+	return value, nil
+}
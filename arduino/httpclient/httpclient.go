@@ -16,8 +16,13 @@
 package httpclient
 
 import (
+	"context"
+	"fmt"
+	"io"
 	"net/http"
 	"net/url"
+	"os"
+	"strings"
 	"time"
 
 	"github.com/arduino/arduino-cli/arduino"
@@ -77,10 +82,150 @@ func DownloadFile(path *paths.Path, URL string, queryParameter string, label str
 	return nil
 }
 
+// DownloadFileWithContext downloads a file from a URL into the specified path. The download
+// can be canceled through the given context. The data is first written into a partial file
+// (the target path with a ".part" suffix) that is renamed to the target path only once the
+// download has been completed: if a partial file is already present, for example from a
+// previously canceled download, the download is resumed using an HTTP Range request.
+// The resume is conditional (If-Range) on the ETag or Last-Modified validator received
+// when the partial file was started: if the resource has changed meanwhile the server
+// sends it whole and the download restarts from zero.
+// A DownloadProgressCB callback function must be passed to monitor download progress.
+// An optional config may be passed (or nil to use the defaults).
+func DownloadFileWithContext(ctx context.Context, path *paths.Path, URL string, label string, downloadCB rpc.DownloadProgressCB, config *downloader.Config) (returnedError error) {
+	logrus.WithField("url", URL).Info("Starting download")
+	downloadCB.Start(URL, label)
+	defer func() {
+		if returnedError == nil {
+			downloadCB.End(true, "")
+		} else {
+			downloadCB.End(false, returnedError.Error())
+		}
+	}()
+
+	if config == nil {
+		c, err := GetDownloaderConfig()
+		if err != nil {
+			return err
+		}
+		config = c
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "GET", URL, nil)
+	if err != nil {
+		return err
+	}
+	partialPath := paths.New(path.String() + ".part")
+	validatorPath := paths.New(path.String() + ".part.validator")
+	var completed int64
+	if info, err := partialPath.Stat(); err == nil && info.Size() > 0 {
+		// Without a validator there is no way to know if the partial file
+		// still matches the resource, so the download is restarted
+		if validator, err := validatorPath.ReadFile(); err == nil && len(validator) > 0 {
+			completed = info.Size()
+			req.Header.Set("Range", fmt.Sprintf("bytes=%d-", completed))
+			req.Header.Set("If-Range", string(validator))
+		}
+	}
+
+	resp, err := config.HttpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	flags := os.O_WRONLY | os.O_CREATE
+	switch {
+	case resp.StatusCode == http.StatusPartialContent:
+		logrus.WithField("url", URL).Infof("Resuming download from byte %d", completed)
+		flags |= os.O_APPEND
+	case resp.StatusCode == http.StatusRequestedRangeNotSatisfiable && completed > 0:
+		// The partial file already contains the whole resource
+		_ = validatorPath.Remove()
+		return partialPath.Rename(path)
+	case resp.StatusCode >= 400 && resp.StatusCode <= 599:
+		// The URL is not reachable for some reason
+		msg := tr("Server responded with: %s", resp.Status)
+		return &arduino.FailedDownloadError{Message: msg}
+	default:
+		// The server ignored the Range request, the resource changed since the
+		// partial file was started, or there was nothing to resume
+		completed = 0
+		flags |= os.O_TRUNC
+		if validator := resumeValidator(resp); validator != "" {
+			if err := validatorPath.WriteFile([]byte(validator)); err != nil {
+				return err
+			}
+		} else if validatorPath.Exist() {
+			if err := validatorPath.Remove(); err != nil {
+				return err
+			}
+		}
+	}
+
+	out, err := os.OpenFile(partialPath.String(), flags, 0644)
+	if err != nil {
+		return err
+	}
+
+	size := completed + resp.ContentLength
+	if resp.ContentLength < 0 {
+		size = 0
+	}
+	downloadCB.Update(completed, size)
+	lastUpdate := time.Now()
+	buff := make([]byte, 32*1024)
+	for {
+		n, readErr := resp.Body.Read(buff)
+		if n > 0 {
+			if _, err := out.Write(buff[:n]); err != nil {
+				out.Close()
+				return err
+			}
+			completed += int64(n)
+			if time.Since(lastUpdate) > 250*time.Millisecond {
+				downloadCB.Update(completed, size)
+				lastUpdate = time.Now()
+			}
+		}
+		if readErr == io.EOF {
+			break
+		}
+		if readErr != nil {
+			out.Close()
+			if ctxErr := ctx.Err(); ctxErr != nil {
+				return ctxErr
+			}
+			return readErr
+		}
+	}
+	downloadCB.Update(completed, size)
+	if err := out.Close(); err != nil {
+		return err
+	}
+	_ = validatorPath.Remove()
+	return partialPath.Rename(path)
+}
+
+// resumeValidator returns the value to send in the If-Range header to resume
+// the download of the given response: the ETag, if it's a strong one, or the
+// Last-Modified date. An empty string is returned if there is none.
+func resumeValidator(resp *http.Response) string {
+	if etag := resp.Header.Get("ETag"); etag != "" && !strings.HasPrefix(etag, "W/") {
+		return etag
+	}
+	return resp.Header.Get("Last-Modified")
+}
+
 // Config is the configuration of the http client
 type Config struct {
 	UserAgent string
 	Proxy     *url.URL
+	// RateLimiter, if not nil, limits the bandwidth used to read the
+	// responses. The same RateLimiter may be shared by many clients.
+	RateLimiter *RateLimiter
+	// Credentials are used to authenticate the requests to the matching URLs
+	Credentials []*configuration.NetworkCredential
 }
 
 // New returns a default http client for use in the arduino-cli
@@ -90,7 +235,12 @@ func New() (*http.Client, error) {
 	if err != nil {
 		return nil, err
 	}
-	return NewWithConfig(&Config{UserAgent: userAgent, Proxy: proxy}), nil
+	credentials, err := configuration.NetworkCredentials(configuration.Settings)
+	if err != nil {
+		return nil, err
+	}
+	rateLimiter := getSharedRateLimiter(configuration.NetworkDownloadRateLimit(configuration.Settings))
+	return NewWithConfig(&Config{UserAgent: userAgent, Proxy: proxy, RateLimiter: rateLimiter, Credentials: credentials}), nil
 }
 
 // NewWithConfig creates a http client for use in the arduino-cli, with a given configuration
@@ -100,7 +250,9 @@ func NewWithConfig(config *Config) *http.Client {
 			transport: &http.Transport{
 				Proxy: http.ProxyURL(config.Proxy),
 			},
-			userAgent: config.UserAgent,
+			userAgent:   config.UserAgent,
+			rateLimiter: config.RateLimiter,
+			credentials: config.Credentials,
 		},
 	}
 }
@@ -117,11 +269,24 @@ func GetDownloaderConfig() (*downloader.Config, error) {
 }
 
 type httpClientRoundTripper struct {
-	transport http.RoundTripper
-	userAgent string
+	transport   http.RoundTripper
+	userAgent   string
+	rateLimiter *RateLimiter
+	credentials []*configuration.NetworkCredential
 }
 
 func (h *httpClientRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
 	req.Header.Add("User-Agent", h.userAgent)
-	return h.transport.RoundTrip(req)
+	if len(h.credentials) > 0 {
+		authenticatedReq, err := authenticate(req, h.credentials)
+		if err != nil {
+			return nil, err
+		}
+		req = authenticatedReq
+	}
+	resp, err := h.transport.RoundTrip(req)
+	if err == nil && h.rateLimiter != nil {
+		resp.Body = h.rateLimiter.Reader(resp.Body)
+	}
+	return resp, err
 }
@@ -16,14 +16,23 @@
 package httpclient
 
 import (
+	"bytes"
+	"context"
 	"fmt"
 	"io"
 	"net/http"
 	"net/http/httptest"
 	"net/url"
+	"runtime"
+	"strings"
 	"testing"
+	"time"
 
+	"github.com/arduino/arduino-cli/configuration"
+	rpc "github.com/arduino/arduino-cli/rpc/cc/arduino/cli/commands/v1"
+	"github.com/arduino/go-paths-helper"
 	"github.com/stretchr/testify/require"
+	"go.bug.st/downloader/v2"
 )
 
 func TestUserAgentHeader(t *testing.T) {
@@ -68,3 +77,221 @@ func TestProxy(t *testing.T) {
 	require.NoError(t, err)
 	require.Equal(t, http.StatusNoContent, response.StatusCode)
 }
+
+func TestDownloadFileWithContext(t *testing.T) {
+	data := bytes.Repeat([]byte("0123456789abcdef"), 16*1024)
+	etag := `"v1"`
+	halfSent := make(chan bool)
+	interrupt := true
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("ETag", etag)
+		if interrupt {
+			// Send only half of the file and hang until the client gives up
+			w.Header().Set("Content-Length", fmt.Sprint(len(data)))
+			w.WriteHeader(http.StatusOK)
+			w.Write(data[:len(data)/2])
+			w.(http.Flusher).Flush()
+			close(halfSent)
+			<-r.Context().Done()
+			return
+		}
+		http.ServeContent(w, r, "index.json", time.Time{}, bytes.NewReader(data))
+	}))
+	defer ts.Close()
+
+	tmp, err := paths.MkTempDir("", "download_test")
+	require.NoError(t, err)
+	defer tmp.RemoveAll()
+	target := tmp.Join("index.json")
+	partial := tmp.Join("index.json.part")
+	config := &downloader.Config{HttpClient: http.Client{}}
+
+	var updates []*rpc.DownloadProgressUpdate
+	var end *rpc.DownloadProgressEnd
+	downloadCB := func(msg *rpc.DownloadProgress) {
+		if update := msg.GetUpdate(); update != nil {
+			updates = append(updates, update)
+		}
+		if msg.GetEnd() != nil {
+			end = msg.GetEnd()
+		}
+	}
+
+	// Cancel the download midway
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		<-halfSent
+		time.Sleep(100 * time.Millisecond)
+		cancel()
+	}()
+	err = DownloadFileWithContext(ctx, target, ts.URL, "index", downloadCB, config)
+	require.ErrorIs(t, err, context.Canceled)
+	require.False(t, end.GetSuccess())
+	require.False(t, target.Exist())
+	require.True(t, partial.Exist())
+	partialData, err := partial.ReadFile()
+	require.NoError(t, err)
+	require.NotEmpty(t, partialData)
+	require.Equal(t, data[:len(partialData)], partialData)
+
+	// Resume the download
+	interrupt = false
+	updates = nil
+	err = DownloadFileWithContext(context.Background(), target, ts.URL, "index", downloadCB, config)
+	require.NoError(t, err)
+	require.True(t, end.GetSuccess())
+	require.False(t, partial.Exist())
+	downloaded, err := target.ReadFile()
+	require.NoError(t, err)
+	require.Equal(t, data, downloaded)
+
+	// Progress starts from the already downloaded data and reaches the full size
+	require.NotEmpty(t, updates)
+	require.Equal(t, int64(len(partialData)), updates[0].GetDownloaded())
+	last := updates[len(updates)-1]
+	require.Equal(t, int64(len(data)), last.GetDownloaded())
+	require.Equal(t, int64(len(data)), last.GetTotalSize())
+
+	// A partial file of a resource that has changed meanwhile is downloaded again from zero
+	require.NoError(t, partial.WriteFile(data[:1024]))
+	require.NoError(t, tmp.Join("index.json.part.validator").WriteFile([]byte(etag)))
+	etag = `"v2"`
+	data = bytes.Repeat([]byte("fedcba9876543210"), 16*1024)
+	updates = nil
+	err = DownloadFileWithContext(context.Background(), target, ts.URL, "index", downloadCB, config)
+	require.NoError(t, err)
+	require.True(t, end.GetSuccess())
+	require.False(t, partial.Exist())
+	downloaded, err = target.ReadFile()
+	require.NoError(t, err)
+	require.Equal(t, data, downloaded)
+	require.Equal(t, int64(0), updates[0].GetDownloaded())
+}
+
+func TestRateLimit(t *testing.T) {
+	data := bytes.Repeat([]byte("0123456789abcdef"), 16*1024) // 256 KiB
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write(data)
+	}))
+	defer ts.Close()
+
+	// Two concurrent downloads share the same 1 MiB/s limit
+	const limit = 1024 * 1024
+	rateLimiter := NewRateLimiter(limit)
+	download := func(done chan<- int) {
+		client := NewWithConfig(&Config{RateLimiter: rateLimiter})
+		resp, err := client.Get(ts.URL)
+		if err != nil {
+			t.Error(err)
+			done <- 0
+			return
+		}
+		defer resp.Body.Close()
+		n, err := io.Copy(io.Discard, resp.Body)
+		if err != nil {
+			t.Error(err)
+		}
+		done <- int(n)
+	}
+	start := time.Now()
+	done := make(chan int)
+	go download(done)
+	go download(done)
+	total := <-done + <-done
+	elapsed := time.Since(start)
+	require.Equal(t, 2*len(data), total)
+
+	throughput := float64(total) / elapsed.Seconds()
+	require.LessOrEqual(t, throughput, limit*1.1)
+	require.Greater(t, throughput, limit*0.5)
+}
+
+func TestSharedRateLimiter(t *testing.T) {
+	require.Nil(t, getSharedRateLimiter(0))
+	require.Nil(t, getSharedRateLimiter(-1))
+	limiter := getSharedRateLimiter(1000)
+	require.Same(t, limiter, getSharedRateLimiter(1000))
+	require.Equal(t, int64(2000), getSharedRateLimiter(2000).BytesPerSecond())
+}
+
+func TestCredentials(t *testing.T) {
+	var ts *httptest.Server
+	ts = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/private/redirect" {
+			// Redirect to the same server through another host name
+			http.Redirect(w, r, strings.Replace(ts.URL, "127.0.0.1", "localhost", 1)+"/private/index.json", http.StatusFound)
+			return
+		}
+		fmt.Fprint(w, r.Header.Get("Authorization"))
+	}))
+	defer ts.Close()
+
+	t.Setenv("TEST_CORES_PASSWORD", "secret")
+	client := NewWithConfig(&Config{
+		Credentials: []*configuration.NetworkCredential{
+			{URL: ts.URL + "/private/", Username: "user", Password: "env:TEST_CORES_PASSWORD"},
+			{URL: ts.URL + "/private/token/", Token: "token"},
+			{URL: ts.URL + "/missing/", Token: "env:TEST_MISSING_TOKEN"},
+		},
+	})
+	get := func(path string) string {
+		response, err := client.Get(ts.URL + path)
+		require.NoError(t, err)
+		defer response.Body.Close()
+		b, err := io.ReadAll(response.Body)
+		require.NoError(t, err)
+		return string(b)
+	}
+
+	require.Equal(t, "Basic dXNlcjpzZWNyZXQ=", get("/private/index.json"))
+	require.Equal(t, "Bearer token", get("/private/token/core.tar.bz2"))
+	require.Equal(t, "", get("/public/index.json"))
+	// The URL of a credential matches only at a path segment boundary
+	require.Equal(t, "", get("/private-other/index.json"))
+	// The credentials are not sent to the host where the request is redirected
+	require.Equal(t, "", get("/private/redirect"))
+	// A secret that can't be resolved fails the request
+	_, err := client.Get(ts.URL + "/missing/index.json")
+	require.ErrorContains(t, err, "TEST_MISSING_TOKEN")
+}
+
+func TestFindCredential(t *testing.T) {
+	credentials := []*configuration.NetworkCredential{
+		{URL: "https://example.com/private", Token: "private"},
+		{URL: "https://example.com/private/token/", Token: "token"},
+	}
+	find := func(target string) string {
+		u, err := url.Parse(target)
+		require.NoError(t, err)
+		if credential := findCredential(credentials, u); credential != nil {
+			return credential.Token
+		}
+		return ""
+	}
+	require.Equal(t, "private", find("https://example.com/private"))
+	require.Equal(t, "private", find("https://example.com/private/index.json"))
+	require.Equal(t, "", find("https://example.com/privateer/index.json"))
+	require.Equal(t, "token", find("https://example.com/private/token/core.tar.bz2"))
+	require.Equal(t, "private", find("https://example.com/private/tokens/core.tar.bz2"))
+	require.Equal(t, "", find("http://example.com/private/index.json"))
+}
+
+func TestResolveSecret(t *testing.T) {
+	secret, err := resolveSecret("plain")
+	require.NoError(t, err)
+	require.Equal(t, "plain", secret)
+
+	t.Setenv("TEST_SECRET", "from-env")
+	secret, err = resolveSecret("env:TEST_SECRET")
+	require.NoError(t, err)
+	require.Equal(t, "from-env", secret)
+
+	if runtime.GOOS == "windows" {
+		t.Skip("echo is not an executable on Windows")
+	}
+	secret, err = resolveSecret("cmd:echo from-command")
+	require.NoError(t, err)
+	require.Equal(t, "from-command", secret)
+	_, err = resolveSecret("cmd:")
+	require.Error(t, err)
+}
@@ -0,0 +1,102 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2023 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package httpclient
+
+import (
+	"io"
+	"sync"
+	"time"
+)
+
+// RateLimiter is a token bucket limiting the total bandwidth of all the
+// readers wrapped with it.
+type RateLimiter struct {
+	mux            sync.Mutex
+	bytesPerSecond int64
+	tokens         float64
+	last           time.Time
+}
+
+// NewRateLimiter creates a RateLimiter allowing the given number of bytes per
+// second. The bucket starts empty and accumulates at most one second of credit
+// while idle.
+func NewRateLimiter(bytesPerSecond int64) *RateLimiter {
+	return &RateLimiter{bytesPerSecond: bytesPerSecond, last: time.Now()}
+}
+
+// BytesPerSecond returns the bandwidth allowed by the limiter
+func (l *RateLimiter) BytesPerSecond() int64 {
+	return l.bytesPerSecond
+}
+
+// wait blocks until n bytes may be transferred. The bytes are reserved
+// immediately, so concurrent callers are served in order and the total
+// bandwidth doesn't exceed the limit.
+func (l *RateLimiter) wait(n int) {
+	l.mux.Lock()
+	now := time.Now()
+	l.tokens += now.Sub(l.last).Seconds() * float64(l.bytesPerSecond)
+	if burst := float64(l.bytesPerSecond); l.tokens > burst {
+		l.tokens = burst
+	}
+	l.last = now
+	l.tokens -= float64(n)
+	var delay time.Duration
+	if l.tokens < 0 {
+		delay = time.Duration(-l.tokens / float64(l.bytesPerSecond) * float64(time.Second))
+	}
+	l.mux.Unlock()
+	time.Sleep(delay)
+}
+
+// Reader returns a reader that reads from r respecting the rate limit
+func (l *RateLimiter) Reader(r io.ReadCloser) io.ReadCloser {
+	return &rateLimitedReader{ReadCloser: r, limiter: l}
+}
+
+type rateLimitedReader struct {
+	io.ReadCloser
+	limiter *RateLimiter
+}
+
+func (r *rateLimitedReader) Read(p []byte) (int, error) {
+	// Read in small chunks to keep the transfer smooth
+	if chunk := int(r.limiter.bytesPerSecond / 10); chunk > 0 && len(p) > chunk {
+		p = p[:chunk]
+	}
+	n, err := r.ReadCloser.Read(p)
+	if n > 0 {
+		r.limiter.wait(n)
+	}
+	return n, err
+}
+
+var sharedRateLimiterMux sync.Mutex
+var sharedRateLimiter *RateLimiter
+
+// getSharedRateLimiter returns the RateLimiter shared by all the clients
+// created with the given limit, or nil if the limit is not positive.
+func getSharedRateLimiter(bytesPerSecond int64) *RateLimiter {
+	if bytesPerSecond <= 0 {
+		return nil
+	}
+	sharedRateLimiterMux.Lock()
+	defer sharedRateLimiterMux.Unlock()
+	if sharedRateLimiter == nil || sharedRateLimiter.bytesPerSecond != bytesPerSecond {
+		sharedRateLimiter = NewRateLimiter(bytesPerSecond)
+	}
+	return sharedRateLimiter
+}
@@ -0,0 +1,53 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2023 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package libraries
+
+import "strings"
+
+// ArchitectureFamilies maps the name of an architecture family to the
+// architectures that belong to it. A library compatible with a family is
+// considered compatible with all its members, unless the library explicitly
+// excludes a member by listing it prefixed with "!" in the `architectures`
+// field of library.properties (for example `architectures=esp32,!esp32h2`).
+type ArchitectureFamilies map[string][]string
+
+// isCompatibleWithFamilyMember returns true if the given architecture is a
+// member of a family declared by the library, and the library doesn't
+// explicitly exclude it.
+func (library *Library) isCompatibleWithFamilyMember(arch string, families ArchitectureFamilies) bool {
+	if len(families) == 0 || library.isArchitectureExcluded(arch) {
+		return false
+	}
+	for _, libArch := range library.Architectures {
+		for _, member := range families[libArch] {
+			if member == arch {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// isArchitectureExcluded returns true if the library lists the given
+// architecture prefixed with "!"
+func (library *Library) isArchitectureExcluded(arch string) bool {
+	for _, libArch := range library.Architectures {
+		if strings.HasPrefix(libArch, "!") && libArch[1:] == arch {
+			return true
+		}
+	}
+	return false
+}
@@ -148,14 +148,15 @@ func (library *Library) ToRPCLibrary() (*rpc.Library, error) {
 
 // SupportsAnyArchitectureIn returns true if any of the following is true:
 // - the library supports at least one of the given architectures
+// - one of the given architectures is a member of a family supported by the library
 // - the library is architecture independent
 // - the library doesn't specify any `architecture` field in library.properties
-func (library *Library) SupportsAnyArchitectureIn(archs ...string) bool {
+func (library *Library) SupportsAnyArchitectureIn(families ArchitectureFamilies, archs ...string) bool {
 	if library.IsArchitectureIndependent() {
 		return true
 	}
 	for _, arch := range archs {
-		if arch == "*" || library.IsOptimizedForArchitecture(arch) {
+		if arch == "*" || library.IsOptimizedForArchitecture(arch) || library.isCompatibleWithFamilyMember(arch, families) {
 			return true
 		}
 	}
@@ -182,10 +183,11 @@ func (library *Library) IsArchitectureIndependent() bool {
 }
 
 // IsCompatibleWith returns true if the library declares compatibility with
-// the given architecture. If this function returns false, the library may still
+// the given architecture, or with a family the architecture belongs to (see
+// ArchitectureFamilies). If this function returns false, the library may still
 // be compatible with the given architecture, but it's not explicitly declared.
-func (library *Library) IsCompatibleWith(arch string) bool {
-	return library.IsArchitectureIndependent() || library.IsOptimizedForArchitecture(arch)
+func (library *Library) IsCompatibleWith(arch string, families ArchitectureFamilies) bool {
+	return library.IsArchitectureIndependent() || library.IsOptimizedForArchitecture(arch) || library.isCompatibleWithFamilyMember(arch, families)
 }
 
 // SourceDir represents a source dir of a library
@@ -157,3 +157,24 @@ func TestLoadExamples(t *testing.T) {
 	require.Len(t, lib.Examples, 1)
 	require.True(t, lib.Examples.Contains(example))
 }
+
+func TestArchitectureFamilies(t *testing.T) {
+	lib := &Library{Name: "ESP32Lib", Architectures: []string{"esp32", "!esp32h2"}}
+
+	// Exact matching by default
+	require.True(t, lib.IsCompatibleWith("esp32", nil))
+	require.False(t, lib.IsCompatibleWith("esp32s3", nil))
+	require.False(t, lib.SupportsAnyArchitectureIn(nil, "esp32s3"))
+
+	families := ArchitectureFamilies{"esp32": {"esp32s3", "esp32c3", "esp32h2"}}
+	require.True(t, lib.IsCompatibleWith("esp32", families))
+	require.True(t, lib.IsCompatibleWith("esp32s3", families))
+	require.True(t, lib.SupportsAnyArchitectureIn(families, "avr", "esp32s3"))
+	// Explicitly excluded by the library
+	require.False(t, lib.IsCompatibleWith("esp32h2", families))
+	require.False(t, lib.SupportsAnyArchitectureIn(families, "esp32h2"))
+	// Not a member of the family
+	require.False(t, lib.IsCompatibleWith("avr", families))
+	// Exact architecture matches are still preferred when resolving libraries
+	require.False(t, lib.IsOptimizedForArchitecture("esp32s3"))
+}
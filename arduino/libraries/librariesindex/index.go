@@ -144,6 +144,25 @@ func (idx *Index) FindLibraryUpdate(lib *libraries.Library) *Release {
 	return nil
 }
 
+// FindLibrariesProvidingInclude returns the libraries whose latest release
+// provides the given include file, sorted by name.
+func (idx *Index) FindLibrariesProvidingInclude(include string) []*Library {
+	res := []*Library{}
+	for _, indexLib := range idx.Libraries {
+		if indexLib.Latest == nil {
+			continue
+		}
+		for _, provided := range indexLib.Latest.ProvidesIncludes {
+			if provided == include {
+				res = append(res, indexLib)
+				break
+			}
+		}
+	}
+	sort.Slice(res, func(i, j int) bool { return res[i].Name < res[j].Name })
+	return res
+}
+
 // ResolveDependencies returns the dependencies of a library release.
 func (idx *Index) ResolveDependencies(lib *Release) []*Release {
 	// Box lib index *Release to be digested by dep-resolver
@@ -0,0 +1,81 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2023 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package librariesindex
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+
+	semver "go.bug.st/relaxed-semver"
+)
+
+// StreamIndex decodes a library_index.json from r one library release at a
+// time and calls cb for each release, in the order they appear in the index,
+// without keeping the whole index in memory. Each release is delivered in its
+// own Library, containing only that release. If cb returns an error the
+// decoding is stopped and the error is returned.
+func StreamIndex(r io.Reader, cb func(*Release) error) error {
+	decoder := json.NewDecoder(r)
+	if err := expectDelim(decoder, '{'); err != nil {
+		return err
+	}
+	for decoder.More() {
+		key, err := decoder.Token()
+		if err != nil {
+			return fmt.Errorf(tr("parsing library_index.json: %s"), err)
+		}
+		if key != "libraries" {
+			var skip json.RawMessage
+			if err := decoder.Decode(&skip); err != nil {
+				return fmt.Errorf(tr("parsing library_index.json: %s"), err)
+			}
+			continue
+		}
+		if err := expectDelim(decoder, '['); err != nil {
+			return err
+		}
+		for decoder.More() {
+			var indexLib indexRelease
+			if err := decoder.Decode(&indexLib); err != nil {
+				return fmt.Errorf(tr("parsing library_index.json: %s"), err)
+			}
+			library := &Library{
+				Name:     indexLib.Name,
+				Releases: map[semver.NormalizedString]*Release{},
+			}
+			indexLib.extractReleaseIn(library)
+			if err := cb(library.Latest); err != nil {
+				return err
+			}
+		}
+		if err := expectDelim(decoder, ']'); err != nil {
+			return err
+		}
+	}
+	return expectDelim(decoder, '}')
+}
+
+func expectDelim(decoder *json.Decoder, delim json.Delim) error {
+	token, err := decoder.Token()
+	if err != nil {
+		return fmt.Errorf(tr("parsing library_index.json: %s"), err)
+	}
+	if token != delim {
+		return fmt.Errorf(tr("parsing library_index.json: expected %[1]s, found %[2]v"), delim, token)
+	}
+	return nil
+}
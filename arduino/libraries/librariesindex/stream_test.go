@@ -0,0 +1,75 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2023 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package librariesindex
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/arduino/go-paths-helper"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStreamIndex(t *testing.T) {
+	index, err := LoadIndex(paths.New("testdata/library_index.json"))
+	require.NoError(t, err)
+	expected := map[string]*Release{}
+	for _, lib := range index.Libraries {
+		for _, release := range lib.Releases {
+			expected[release.String()] = release
+		}
+	}
+
+	file, err := paths.New("testdata/library_index.json").Open()
+	require.NoError(t, err)
+	defer file.Close()
+	streamed := map[string]*Release{}
+	count := 0
+	err = StreamIndex(file, func(release *Release) error {
+		count++
+		streamed[release.String()] = release
+		require.Same(t, release, release.Library.Releases[release.Version.NormalizedString()])
+		return nil
+	})
+	require.NoError(t, err)
+	require.Equal(t, len(expected), count)
+	require.Equal(t, len(expected), len(streamed))
+	for id, release := range expected {
+		streamedRelease, ok := streamed[id]
+		require.True(t, ok, id)
+		require.Equal(t, release.Author, streamedRelease.Author)
+		require.Equal(t, release.Resource, streamedRelease.Resource)
+		require.Equal(t, release.Dependencies, streamedRelease.Dependencies)
+	}
+
+	// The callback can stop the parsing
+	stop := errors.New("stop")
+	count = 0
+	file2, err := paths.New("testdata/library_index.json").Open()
+	require.NoError(t, err)
+	defer file2.Close()
+	err = StreamIndex(file2, func(release *Release) error {
+		count++
+		return stop
+	})
+	require.ErrorIs(t, err, stop)
+	require.Equal(t, 1, count)
+
+	require.Error(t, StreamIndex(strings.NewReader(`{"libraries": [{"name": `), func(*Release) error { return nil }))
+	require.Error(t, StreamIndex(strings.NewReader(`[]`), func(*Release) error { return nil }))
+	require.NoError(t, StreamIndex(strings.NewReader(`{"other": {"a": [1]}, "libraries": []}`), func(*Release) error { return nil }))
+}
@@ -0,0 +1,55 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2023 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package librariesmanager
+
+import (
+	"errors"
+	"time"
+)
+
+// IndexAge returns the time elapsed since the last update of the library
+// index file, based on the modification time of the file.
+func (lm *LibrariesManager) IndexAge() (time.Duration, error) {
+	if lm.IndexFile == nil {
+		return 0, errors.New(tr("library index file not set"))
+	}
+	info, err := lm.IndexFile.Stat()
+	if err != nil {
+		return 0, err
+	}
+	age := time.Since(info.ModTime())
+	if age < 0 {
+		// The file is in the future, probably the clock has been adjusted
+		age = 0
+	}
+	return age, nil
+}
+
+// IndexStalenessWarning returns a message suggesting to update the library
+// index if the index is older than the given threshold, otherwise it returns
+// an empty string. A threshold of zero or less disables the check, a missing
+// index is not reported.
+func (lm *LibrariesManager) IndexStalenessWarning(threshold time.Duration) string {
+	if threshold <= 0 {
+		return ""
+	}
+	age, err := lm.IndexAge()
+	if err != nil || age <= threshold {
+		return ""
+	}
+	days := int(age / (24 * time.Hour))
+	return tr("The library index is %[1]d days old, run '%[2]s' to find the latest libraries.", days, "arduino-cli lib update-index")
+}
@@ -0,0 +1,54 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2023 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package librariesmanager
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	"github.com/arduino/go-paths-helper"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIndexAge(t *testing.T) {
+	indexDir := paths.New(t.TempDir())
+	lm := NewLibraryManager(indexDir, nil)
+
+	_, err := lm.IndexAge()
+	require.Error(t, err)
+	require.Empty(t, lm.IndexStalenessWarning(24*time.Hour))
+
+	require.NoError(t, lm.IndexFile.WriteFile([]byte("{}")))
+	age, err := lm.IndexAge()
+	require.NoError(t, err)
+	require.Less(t, age, time.Minute)
+	require.Empty(t, lm.IndexStalenessWarning(24*time.Hour))
+
+	// Backdate the index
+	modTime := time.Now().Add(-45 * 24 * time.Hour)
+	require.NoError(t, os.Chtimes(lm.IndexFile.String(), modTime, modTime))
+	age, err = lm.IndexAge()
+	require.NoError(t, err)
+	require.InDelta(t, 45*24*time.Hour, age, float64(time.Minute))
+	require.Empty(t, lm.IndexStalenessWarning(60*24*time.Hour))
+	require.Empty(t, lm.IndexStalenessWarning(0))
+	warning := lm.IndexStalenessWarning(30 * 24 * time.Hour)
+	require.Contains(t, warning, "45 days old")
+	require.Contains(t, warning, "lib update-index")
+
+	require.Empty(t, NewLibraryManager(nil, nil).IndexStalenessWarning(time.Hour))
+}
@@ -105,7 +105,7 @@ func (lm *LibrariesManager) InstallPrerequisiteCheck(name string, version *semve
 
 // Install installs a library on the specified path.
 func (lm *LibrariesManager) Install(indexLibrary *librariesindex.Release, installPath *paths.Path) error {
-	return indexLibrary.Resource.Install(lm.DownloadsDir, installPath.Parent(), installPath)
+	return indexLibrary.Resource.Install(lm.DownloadsDir, installPath.Parent(), installPath, lm.archiveSignatures)
 }
 
 // importLibraryFromDirectory installs a library by copying it from the given directory.
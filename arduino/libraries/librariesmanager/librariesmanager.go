@@ -23,10 +23,13 @@ import (
 	"github.com/arduino/arduino-cli/arduino/cores"
 	"github.com/arduino/arduino-cli/arduino/libraries"
 	"github.com/arduino/arduino-cli/arduino/libraries/librariesindex"
+	"github.com/arduino/arduino-cli/arduino/resources"
+	"github.com/arduino/arduino-cli/arduino/utils"
 	"github.com/arduino/arduino-cli/i18n"
 	paths "github.com/arduino/go-paths-helper"
 	"github.com/pmylund/sortutil"
 	"github.com/sirupsen/logrus"
+	semver "go.bug.st/relaxed-semver"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 )
@@ -41,6 +44,10 @@ type LibrariesManager struct {
 	IndexFile          *paths.Path
 	IndexFileSignature *paths.Path
 	DownloadsDir       *paths.Path
+
+	symlinkPolicy        utils.SymlinkPolicy
+	archiveSignatures    resources.ArchiveSignatureVerification
+	architectureFamilies libraries.ArchitectureFamilies
 }
 
 // LibrariesDir is a directory containing libraries
@@ -80,6 +87,43 @@ func NewLibraryManager(indexDir *paths.Path, downloadsDir *paths.Path) *Librarie
 	}
 }
 
+// SetSymlinkPolicy sets which symlinks are followed while scanning the
+// libraries directories.
+func (lm *LibrariesManager) SetSymlinkPolicy(policy utils.SymlinkPolicy) {
+	lm.symlinkPolicy = policy
+}
+
+// SymlinkPolicy returns which symlinks are followed while scanning the
+// libraries directories.
+func (lm *LibrariesManager) SymlinkPolicy() utils.SymlinkPolicy {
+	return lm.symlinkPolicy
+}
+
+// SetArchiveSignatureVerification sets how the detached signatures of the
+// downloaded library archives are verified.
+func (lm *LibrariesManager) SetArchiveSignatureVerification(settings resources.ArchiveSignatureVerification) {
+	lm.archiveSignatures = settings
+}
+
+// ArchiveSignatureVerification returns the settings used to verify the
+// detached signatures of the downloaded library archives.
+func (lm *LibrariesManager) ArchiveSignatureVerification() resources.ArchiveSignatureVerification {
+	return lm.archiveSignatures
+}
+
+// SetArchitectureFamilies sets the architecture families used to check the
+// compatibility of the libraries. By default no family is configured and the
+// architectures must match exactly.
+func (lm *LibrariesManager) SetArchitectureFamilies(families libraries.ArchitectureFamilies) {
+	lm.architectureFamilies = families
+}
+
+// ArchitectureFamilies returns the architecture families used to check the
+// compatibility of the libraries.
+func (lm *LibrariesManager) ArchitectureFamilies() libraries.ArchitectureFamilies {
+	return lm.architectureFamilies
+}
+
 // LoadIndex reads a library_index.json from a file and returns
 // the corresponding Index structure.
 func (lm *LibrariesManager) LoadIndex() error {
@@ -162,7 +206,7 @@ func (lm *LibrariesManager) getLibrariesDir(installLocation libraries.LibraryLoc
 // nil if the directory doesn't exists.
 func (lm *LibrariesManager) LoadLibrariesFromDir(librariesDir *LibrariesDir) []*status.Status {
 	statuses := []*status.Status{}
-	subDirs, err := librariesDir.Path.ReadDir()
+	subDirs, err := librariesDir.Path.ReadDir(lm.symlinkPolicy.FilterSymlinks(librariesDir.Path))
 	if os.IsNotExist(err) {
 		return statuses
 	}
@@ -189,6 +233,78 @@ func (lm *LibrariesManager) LoadLibrariesFromDir(librariesDir *LibrariesDir) []*
 	return statuses
 }
 
+// InstalledLibrary contains the metadata of a library installed in one of
+// the libraries directories.
+type InstalledLibrary struct {
+	Name          string
+	Version       *semver.Version
+	Author        string
+	Maintainer    string
+	Sentence      string
+	Category      string
+	Architectures []string
+	InstallDir    *paths.Path
+	Location      libraries.LibraryLocation
+	// IsLegacy is true if the library has no library.properties file, in this case
+	// the metadata is inferred from the library folder.
+	IsLegacy bool
+}
+
+// InstalledLibraries scans all the libraries directories and returns the metadata
+// of the libraries found. Directories that can not be loaded as a library are skipped.
+func (lm *LibrariesManager) InstalledLibraries() []*InstalledLibrary {
+	res := []*InstalledLibrary{}
+	for _, librariesDir := range lm.LibrariesDir {
+		res = append(res, installedLibrariesInDir(librariesDir.Path, librariesDir.Location, lm.symlinkPolicy)...)
+	}
+	return res
+}
+
+// PlatformBundledLibraries scans the libraries directory of the given platform
+// release and returns the metadata of the libraries bundled with the platform.
+// Directories that can not be loaded as a library are skipped.
+func PlatformBundledLibraries(platformRelease *cores.PlatformRelease) []*InstalledLibrary {
+	librariesDir := platformRelease.GetLibrariesDir()
+	if librariesDir == nil {
+		return []*InstalledLibrary{}
+	}
+	return installedLibrariesInDir(librariesDir, libraries.PlatformBuiltIn, utils.FollowSymlinksWithinRoot)
+}
+
+func installedLibrariesInDir(librariesDir *paths.Path, location libraries.LibraryLocation, symlinkPolicy utils.SymlinkPolicy) []*InstalledLibrary {
+	res := []*InstalledLibrary{}
+	subDirs, err := librariesDir.ReadDir(symlinkPolicy.FilterSymlinks(librariesDir))
+	if err != nil {
+		if !os.IsNotExist(err) {
+			logrus.WithError(err).Warnf("Reading libraries dir %s", librariesDir)
+		}
+		return res
+	}
+	subDirs.FilterDirs()
+	subDirs.FilterOutHiddenFiles()
+
+	for _, subDir := range subDirs {
+		library, err := libraries.Load(subDir, location)
+		if err != nil {
+			logrus.WithError(err).Warnf("Loading library from %s", subDir)
+			continue
+		}
+		res = append(res, &InstalledLibrary{
+			Name:          library.Name,
+			Version:       library.Version,
+			Author:        library.Author,
+			Maintainer:    library.Maintainer,
+			Sentence:      library.Sentence,
+			Category:      library.Category,
+			Architectures: library.Architectures,
+			InstallDir:    library.InstallDir,
+			Location:      library.Location,
+			IsLegacy:      library.IsLegacy,
+		})
+	}
+	return res
+}
+
 // LoadLibraryFromDir loads one single library from the libRootDir.
 // libRootDir must point to the root of a valid library.
 // An error is returned if the path doesn't exist or loading of the library fails.
@@ -17,6 +17,7 @@ package librariesmanager
 import (
 	"testing"
 
+	"github.com/arduino/arduino-cli/arduino/cores"
 	"github.com/arduino/arduino-cli/arduino/libraries"
 	"github.com/arduino/go-paths-helper"
 	"github.com/stretchr/testify/require"
@@ -30,3 +31,51 @@ func Test_RescanLibrariesCallClear(t *testing.T) {
 	lm.RescanLibraries()
 	require.Len(t, lm.Libraries, 0)
 }
+
+func TestInstalledLibraries(t *testing.T) {
+	userDir := paths.New(t.TempDir())
+	require.NoError(t, paths.New("..", "testdata", "TestLib").CopyDirTo(userDir.Join("TestLib")))
+	require.NoError(t, paths.New("..", "testdata", "LegacyLib").CopyDirTo(userDir.Join("LegacyLib")))
+	require.NoError(t, userDir.Join("NotALibrary").MkdirAll())
+
+	lm := NewLibraryManager(nil, nil)
+	lm.AddLibrariesDir(userDir, libraries.User)
+	lm.AddLibrariesDir(userDir.Join("nonexistent"), libraries.IDEBuiltIn)
+
+	libs := lm.InstalledLibraries()
+	require.Len(t, libs, 2)
+
+	legacy := libs[0]
+	require.Equal(t, "LegacyLib", legacy.Name)
+	require.Equal(t, "", legacy.Version.String())
+	require.Equal(t, []string{"*"}, legacy.Architectures)
+	require.Equal(t, libraries.User, legacy.Location)
+	require.True(t, legacy.InstallDir.EquivalentTo(userDir.Join("LegacyLib")))
+	require.True(t, legacy.IsLegacy)
+
+	lib := libs[1]
+	require.Equal(t, "TestLib", lib.Name)
+	require.Equal(t, "1.0.3", lib.Version.String())
+	require.Equal(t, "Arduino", lib.Author)
+	require.Equal(t, "Device Control", lib.Category)
+	require.Equal(t, []string{"avr"}, lib.Architectures)
+	require.Equal(t, libraries.User, lib.Location)
+	require.True(t, lib.InstallDir.EquivalentTo(userDir.Join("TestLib")))
+	require.False(t, lib.IsLegacy)
+}
+
+func TestPlatformBundledLibraries(t *testing.T) {
+	platformDir := paths.New(t.TempDir())
+	release := &cores.PlatformRelease{InstallDir: platformDir}
+	require.Empty(t, PlatformBundledLibraries(release))
+
+	require.NoError(t, paths.New("..", "testdata", "TestLib").CopyDirTo(platformDir.Join("libraries", "TestLib")))
+	libs := PlatformBundledLibraries(release)
+	require.Len(t, libs, 1)
+	require.Equal(t, "TestLib", libs[0].Name)
+	require.Equal(t, "1.0.3", libs[0].Version.String())
+	require.Equal(t, []string{"avr"}, libs[0].Architectures)
+	require.Equal(t, libraries.PlatformBuiltIn, libs[0].Location)
+	require.True(t, libs[0].InstallDir.EquivalentTo(platformDir.Join("libraries", "TestLib")))
+	require.False(t, libs[0].IsLegacy)
+}
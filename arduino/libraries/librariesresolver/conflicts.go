@@ -0,0 +1,70 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2023 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package librariesresolver
+
+import (
+	"sort"
+
+	"github.com/arduino/arduino-cli/arduino/libraries"
+	"github.com/arduino/arduino-cli/arduino/libraries/librariesmanager"
+)
+
+// LibraryVersionConflict describes a library installed in more than one location
+// with different versions.
+type LibraryVersionConflict struct {
+	// Name is the name of the library
+	Name string
+	// Libraries are all the installed copies of the library
+	Libraries libraries.List
+	// Chosen is the copy that the library detection would use
+	Chosen *libraries.Library
+}
+
+// FindLibraryVersionConflicts returns the libraries loaded in the LibrariesManager
+// that are installed in multiple locations with differing versions, together with
+// the copy that would be chosen when compiling for the given architecture.
+// The result is sorted by library name.
+func FindLibraryVersionConflicts(lm *librariesmanager.LibrariesManager, architecture string) []*LibraryVersionConflict {
+	res := []*LibraryVersionConflict{}
+	for name, alternatives := range lm.Libraries {
+		if len(alternatives) < 2 {
+			continue
+		}
+		versions := map[string]bool{}
+		for _, lib := range alternatives {
+			versions[lib.Version.String()] = true
+		}
+		if len(versions) < 2 {
+			continue
+		}
+		res = append(res, &LibraryVersionConflict{
+			Name:      name,
+			Libraries: alternatives,
+			Chosen:    chooseBetweenAlternatives(alternatives, architecture),
+		})
+	}
+	sort.Slice(res, func(i, j int) bool { return res[i].Name < res[j].Name })
+	return res
+}
+
+// chooseBetweenAlternatives returns the library that ResolveFor would choose among
+// copies of the same library when resolving its main header.
+func chooseBetweenAlternatives(alternatives libraries.List, architecture string) *libraries.Library {
+	resolver := NewCppResolver()
+	header := alternatives[0].Name + ".h"
+	resolver.headers[header] = alternatives
+	return resolver.ResolveFor(header, architecture)
+}
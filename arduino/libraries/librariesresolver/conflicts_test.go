@@ -0,0 +1,55 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2023 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package librariesresolver
+
+import (
+	"testing"
+
+	"github.com/arduino/arduino-cli/arduino/libraries"
+	"github.com/arduino/arduino-cli/arduino/libraries/librariesmanager"
+	"github.com/arduino/go-paths-helper"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFindLibraryVersionConflicts(t *testing.T) {
+	tmp := paths.New(t.TempDir())
+	installLib := func(dir *paths.Path, name, version string) {
+		libDir := dir.Join(name)
+		require.NoError(t, libDir.Join("src").MkdirAll())
+		require.NoError(t, libDir.Join("library.properties").WriteFile([]byte("name="+name+"\nversion="+version+"\narchitectures=*\n")))
+		require.NoError(t, libDir.Join("src", name+".h").WriteFile([]byte{}))
+	}
+	userDir := tmp.Join("user")
+	builtinDir := tmp.Join("builtin")
+	installLib(userDir, "Servo", "1.2.0")
+	installLib(builtinDir, "Servo", "1.1.0")
+	installLib(userDir, "Wire", "1.0.0")
+	installLib(builtinDir, "Wire", "1.0.0")
+	installLib(builtinDir, "SPI", "1.0.0")
+
+	lm := librariesmanager.NewLibraryManager(nil, nil)
+	lm.AddLibrariesDir(userDir, libraries.User)
+	lm.AddLibrariesDir(builtinDir, libraries.IDEBuiltIn)
+	require.Empty(t, lm.RescanLibraries())
+
+	conflicts := FindLibraryVersionConflicts(lm, "avr")
+	require.Len(t, conflicts, 1)
+	conflict := conflicts[0]
+	require.Equal(t, "Servo", conflict.Name)
+	require.Len(t, conflict.Libraries, 2)
+	require.Equal(t, libraries.User, conflict.Chosen.Location)
+	require.Equal(t, "1.2.0", conflict.Chosen.Version.String())
+}
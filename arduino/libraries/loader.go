@@ -20,6 +20,7 @@ import (
 	"strings"
 
 	"github.com/arduino/arduino-cli/arduino/globals"
+	"github.com/arduino/arduino-cli/arduino/utils"
 	"github.com/arduino/go-paths-helper"
 	properties "github.com/arduino/go-properties-orderedmap"
 	"github.com/pkg/errors"
@@ -182,7 +183,7 @@ func addExamples(lib *Library) error {
 }
 
 func addExamplesToPathList(examplesPath *paths.Path, list *paths.PathList) error {
-	files, err := examplesPath.ReadDirRecursiveFiltered(nil, paths.AndFilter(paths.FilterDirectories(), filterExamplesDirs))
+	files, err := utils.FollowAllSymlinks.ReadDirRecursiveFiltered(examplesPath, examplesPath, nil, paths.AndFilter(paths.FilterDirectories(), filterExamplesDirs))
 	if err != nil {
 		return err
 	}
@@ -0,0 +1,223 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2023 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package resources
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bufio"
+	"bytes"
+	"compress/bzip2"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"strings"
+
+	paths "github.com/arduino/go-paths-helper"
+	"github.com/klauspost/compress/zstd"
+	"github.com/ulikunitz/xz"
+)
+
+// ArchiveEntry is an entry of an archive, as it would be installed
+type ArchiveEntry struct {
+	// Name is the path of the entry inside the archive
+	Name string
+	// Destination is the path where the entry would be installed
+	Destination *paths.Path
+	// IsDir is true if the entry is a directory
+	IsDir bool
+	// Size is the uncompressed size of the entry
+	Size int64
+	// LinkTarget is the target of the entry if it's a symbolic or hard link
+	LinkTarget string
+	// OutsideInstallDir is true if the entry (or the target of the symbolic
+	// link) would be placed outside the installation directory
+	OutsideInstallDir bool
+
+	// hardLink is true if LinkTarget is relative to the archive root
+	hardLink bool
+}
+
+// ListArchiveContents returns the entries of the already downloaded archive
+// of the resource, together with the path where each entry would be placed if
+// the archive is installed in destDir. The entries that would be written
+// outside destDir are flagged.
+func (r *DownloadResource) ListArchiveContents(downloadDir, destDir *paths.Path) ([]*ArchiveEntry, error) {
+	archivePath, err := r.ArchivePath(downloadDir)
+	if err != nil {
+		return nil, fmt.Errorf(tr("getting archive path: %s", err))
+	}
+	file, err := os.Open(archivePath.String())
+	if err != nil {
+		return nil, fmt.Errorf(tr("opening archive file: %s", err))
+	}
+	defer file.Close()
+
+	entries, err := readArchiveEntries(file)
+	if err != nil {
+		return nil, fmt.Errorf(tr("reading archive: %s", err))
+	}
+
+	res := []*ArchiveEntry{}
+	for _, entry := range entries {
+		// The root folder of the archive is renamed as destDir and the
+		// __MACOSX folders are not installed.
+		if strings.HasPrefix(entry.Name, "__MACOSX") {
+			continue
+		}
+		name := strings.ReplaceAll(entry.Name, "\\", "/")
+		entry.Destination = destDir.Join(stripArchiveRoot(name)).Clean()
+		entry.OutsideInstallDir = isAbsArchivePath(name) || !isInsideDir(entry.Destination, destDir)
+		if entry.LinkTarget != "" && !entry.OutsideInstallDir {
+			target := strings.ReplaceAll(entry.LinkTarget, "\\", "/")
+			var targetPath *paths.Path
+			if entry.hardLink {
+				// Hard links are relative to the archive root
+				targetPath = destDir.Join(stripArchiveRoot(target)).Clean()
+			} else {
+				// Symbolic links are relative to the directory containing the link
+				targetPath = entry.Destination.Parent().Join(target).Clean()
+			}
+			entry.OutsideInstallDir = isAbsArchivePath(target) || !isInsideDir(targetPath, destDir)
+		}
+		res = append(res, entry)
+	}
+	return res, nil
+}
+
+// stripArchiveRoot returns the given archive path without the first element,
+// the root folder of the archive that is renamed as the installation directory.
+// The path is not cleaned, so that the ".." elements are kept.
+func stripArchiveRoot(name string) string {
+	name = strings.TrimLeft(name, "/")
+	if i := strings.Index(name, "/"); i != -1 {
+		return name[i+1:]
+	}
+	return ""
+}
+
+// isAbsArchivePath returns true if the given archive path is absolute, either
+// as a unix path or as a windows path with a drive letter.
+func isAbsArchivePath(name string) bool {
+	return path.IsAbs(name) || (len(name) > 1 && name[1] == ':')
+}
+
+// isInsideDir returns true if p is dir or a path inside dir.
+func isInsideDir(p, dir *paths.Path) bool {
+	dir = dir.Clean()
+	return p.EqualsTo(dir) || p.IsInsideDir(dir)
+}
+
+func readArchiveEntries(file *os.File) ([]*ArchiveEntry, error) {
+	header := make([]byte, 6)
+	if n, err := io.ReadFull(file, header); errors.Is(err, io.ErrUnexpectedEOF) {
+		header = header[:n]
+	} else if err != nil {
+		return nil, err
+	}
+	if _, err := file.Seek(0, io.SeekStart); err != nil {
+		return nil, err
+	}
+
+	switch {
+	case bytes.HasPrefix(header, []byte("PK\x03\x04")):
+		stat, err := file.Stat()
+		if err != nil {
+			return nil, err
+		}
+		return readZipEntries(file, stat.Size())
+	case bytes.HasPrefix(header, []byte{0x1f, 0x8b}):
+		gz, err := gzip.NewReader(file)
+		if err != nil {
+			return nil, err
+		}
+		defer gz.Close()
+		return readTarEntries(gz)
+	case bytes.HasPrefix(header, []byte("BZh")):
+		return readTarEntries(bzip2.NewReader(bufio.NewReader(file)))
+	case bytes.HasPrefix(header, []byte{0xfd, '7', 'z', 'X', 'Z', 0x00}):
+		xzReader, err := xz.NewReader(bufio.NewReader(file))
+		if err != nil {
+			return nil, err
+		}
+		return readTarEntries(xzReader)
+	case bytes.HasPrefix(header, []byte{0x28, 0xb5, 0x2f, 0xfd}):
+		zstdReader, err := zstd.NewReader(file)
+		if err != nil {
+			return nil, err
+		}
+		defer zstdReader.Close()
+		return readTarEntries(zstdReader)
+	default:
+		return readTarEntries(file)
+	}
+}
+
+func readTarEntries(in io.Reader) ([]*ArchiveEntry, error) {
+	res := []*ArchiveEntry{}
+	tarReader := tar.NewReader(in)
+	for {
+		header, err := tarReader.Next()
+		if errors.Is(err, io.EOF) {
+			return res, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		entry := &ArchiveEntry{
+			Name:  header.Name,
+			IsDir: header.Typeflag == tar.TypeDir,
+			Size:  header.Size,
+		}
+		if header.Typeflag == tar.TypeSymlink || header.Typeflag == tar.TypeLink {
+			entry.LinkTarget = header.Linkname
+			entry.hardLink = header.Typeflag == tar.TypeLink
+		}
+		res = append(res, entry)
+	}
+}
+
+func readZipEntries(in io.ReaderAt, size int64) ([]*ArchiveEntry, error) {
+	zr, err := zip.NewReader(in, size)
+	if err != nil {
+		return nil, err
+	}
+	res := []*ArchiveEntry{}
+	for _, f := range zr.File {
+		entry := &ArchiveEntry{
+			Name:  f.Name,
+			IsDir: f.FileInfo().IsDir(),
+			Size:  int64(f.UncompressedSize64),
+		}
+		if f.Mode()&os.ModeSymlink != 0 {
+			r, err := f.Open()
+			if err != nil {
+				return nil, err
+			}
+			target, err := io.ReadAll(r)
+			r.Close()
+			if err != nil {
+				return nil, err
+			}
+			entry.LinkTarget = string(target)
+		}
+		res = append(res, entry)
+	}
+	return res, nil
+}
@@ -0,0 +1,146 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2023 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package resources
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"io"
+	"testing"
+
+	paths "github.com/arduino/go-paths-helper"
+	"github.com/klauspost/compress/zstd"
+	"github.com/stretchr/testify/require"
+	"github.com/ulikunitz/xz"
+)
+
+// writeCompressedPlatformArchive writes in dir a small platform archive,
+// a tarball compressed with the given format ("xz" or "zst").
+func writeCompressedPlatformArchive(t *testing.T, dir *paths.Path, format string) string {
+	archive := &bytes.Buffer{}
+	var compressor io.WriteCloser
+	var err error
+	switch format {
+	case "xz":
+		compressor, err = xz.NewWriter(archive)
+	case "zst":
+		compressor, err = zstd.NewWriter(archive)
+	}
+	require.NoError(t, err)
+	tw := tar.NewWriter(compressor)
+	require.NoError(t, tw.WriteHeader(&tar.Header{Name: "platform/", Typeflag: tar.TypeDir, Mode: 0755}))
+	content := []byte("uno.name=Uno\n")
+	require.NoError(t, tw.WriteHeader(&tar.Header{Name: "platform/boards.txt", Typeflag: tar.TypeReg, Mode: 0644, Size: int64(len(content))}))
+	_, err = tw.Write(content)
+	require.NoError(t, err)
+	require.NoError(t, tw.Close())
+	require.NoError(t, compressor.Close())
+
+	fileName := "platform.tar." + format
+	require.NoError(t, dir.Join(fileName).WriteFile(archive.Bytes()))
+	return fileName
+}
+
+func TestListArchiveContents(t *testing.T) {
+	destDir := paths.New("/packages", "test", "hardware", "avr", "1.0.0")
+
+	t.Run("SampleArchive", func(t *testing.T) {
+		r := &DownloadResource{ArchiveFileName: "platform_with_root_and__MACOSX_folder.tar.bz2"}
+		entries, err := r.ListArchiveContents(paths.New("testdata", "valid"), destDir)
+		require.NoError(t, err)
+		require.NotEmpty(t, entries)
+		for _, entry := range entries {
+			require.NotContains(t, entry.Name, "__MACOSX")
+			require.False(t, entry.OutsideInstallDir, entry.Name)
+			require.True(t, entry.Destination.EqualsTo(destDir) || entry.Destination.IsInsideDir(destDir), entry.Destination.String())
+		}
+	})
+
+	t.Run("EscapingEntries", func(t *testing.T) {
+		archive := &bytes.Buffer{}
+		gz := gzip.NewWriter(archive)
+		tw := tar.NewWriter(gz)
+		add := func(header *tar.Header, content string) {
+			header.Size = int64(len(content))
+			if header.Mode == 0 {
+				header.Mode = 0644
+			}
+			require.NoError(t, tw.WriteHeader(header))
+			_, err := tw.Write([]byte(content))
+			require.NoError(t, err)
+		}
+		add(&tar.Header{Name: "platform/", Typeflag: tar.TypeDir, Mode: 0755}, "")
+		add(&tar.Header{Name: "platform/boards.txt", Typeflag: tar.TypeReg}, "uno.name=Uno\n")
+		add(&tar.Header{Name: "platform/../../../evil.sh", Typeflag: tar.TypeReg}, "#!/bin/sh\n")
+		add(&tar.Header{Name: "platform/link", Typeflag: tar.TypeSymlink, Linkname: "../../../../etc/passwd"}, "")
+		add(&tar.Header{Name: "platform/variants", Typeflag: tar.TypeSymlink, Linkname: "boards.txt"}, "")
+		add(&tar.Header{Name: "platform/../evil.sh", Typeflag: tar.TypeReg}, "#!/bin/sh\n")
+		add(&tar.Header{Name: "platform/sibling", Typeflag: tar.TypeSymlink, Linkname: "../sibling"}, "")
+		add(&tar.Header{Name: "platform/cores/arduino", Typeflag: tar.TypeSymlink, Linkname: "../variants/standard"}, "")
+		add(&tar.Header{Name: "platform/hardlink", Typeflag: tar.TypeLink, Linkname: "platform/../../sibling"}, "")
+		require.NoError(t, tw.Close())
+		require.NoError(t, gz.Close())
+
+		downloadDir := paths.New(t.TempDir())
+		require.NoError(t, downloadDir.Join("platform.tar.gz").WriteFile(archive.Bytes()))
+		r := &DownloadResource{ArchiveFileName: "platform.tar.gz"}
+		entries, err := r.ListArchiveContents(downloadDir, destDir)
+		require.NoError(t, err)
+		require.Len(t, entries, 9)
+
+		require.Equal(t, "platform/", entries[0].Name)
+		require.True(t, entries[0].IsDir)
+		require.Equal(t, destDir.String(), entries[0].Destination.String())
+		require.False(t, entries[0].OutsideInstallDir)
+
+		require.Equal(t, destDir.Join("boards.txt").String(), entries[1].Destination.String())
+		require.Equal(t, int64(13), entries[1].Size)
+		require.False(t, entries[1].OutsideInstallDir)
+
+		require.Equal(t, "platform/../../../evil.sh", entries[2].Name)
+		require.True(t, entries[2].OutsideInstallDir)
+
+		require.Equal(t, "../../../../etc/passwd", entries[3].LinkTarget)
+		require.True(t, entries[3].OutsideInstallDir)
+
+		require.False(t, entries[4].OutsideInstallDir)
+
+		// The ".." elements are resolved after the root folder is stripped
+		require.Equal(t, "platform/../evil.sh", entries[5].Name)
+		require.Equal(t, destDir.Parent().Join("evil.sh").String(), entries[5].Destination.String())
+		require.True(t, entries[5].OutsideInstallDir)
+
+		// Symbolic links are resolved against their directory in the installation directory
+		require.Equal(t, destDir.Join("sibling").String(), entries[6].Destination.String())
+		require.True(t, entries[6].OutsideInstallDir)
+		require.False(t, entries[7].OutsideInstallDir)
+
+		// Hard links are resolved against the archive root
+		require.True(t, entries[8].OutsideInstallDir)
+	})
+	for _, format := range []string{"xz", "zst"} {
+		format := format
+		t.Run("CompressedWith"+format, func(t *testing.T) {
+			downloadDir := paths.New(t.TempDir())
+			r := &DownloadResource{ArchiveFileName: writeCompressedPlatformArchive(t, downloadDir, format)}
+			entries, err := r.ListArchiveContents(downloadDir, destDir)
+			require.NoError(t, err)
+			require.Len(t, entries, 2)
+			require.Equal(t, destDir.Join("boards.txt").String(), entries[1].Destination.String())
+		})
+	}
+}
@@ -16,23 +16,36 @@
 package resources
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"net/http"
+	"net/url"
 	"os"
+	"slices"
+	"strings"
 
+	"github.com/arduino/arduino-cli/arduino"
 	"github.com/arduino/arduino-cli/arduino/httpclient"
 	rpc "github.com/arduino/arduino-cli/rpc/cc/arduino/cli/commands/v1"
 	paths "github.com/arduino/go-paths-helper"
+	"github.com/sirupsen/logrus"
 	"go.bug.st/downloader/v2"
 )
 
 // Download performs a download loop using the provided downloader.Config.
 // Messages are passed back to the DownloadProgressCB using label as text for the File field.
-// queryParameter is passed for analysis purposes.
-func (r *DownloadResource) Download(downloadDir *paths.Path, config *downloader.Config, label string, downloadCB rpc.DownloadProgressCB, queryParameter string) error {
+// queryParameter is passed for analysis purposes. If the signature verification is
+// enabled in signatures the detached signature of the archive is downloaded too.
+func (r *DownloadResource) Download(downloadDir *paths.Path, config *downloader.Config, label string, downloadCB rpc.DownloadProgressCB, queryParameter string, signatures ArchiveSignatureVerification) error {
 	path, err := r.ArchivePath(downloadDir)
 	if err != nil {
 		return fmt.Errorf(tr("getting archive path: %s"), err)
 	}
+	config, err = r.restrictRedirects(config)
+	if err != nil {
+		return err
+	}
 
 	if _, err := path.Stat(); os.IsNotExist(err) {
 		// normal download
@@ -47,10 +60,155 @@ func (r *DownloadResource) Download(downloadDir *paths.Path, config *downloader.
 			// File is cached, nothing to do here
 			downloadCB.Start(r.URL, label)
 			downloadCB.End(true, tr("%s already downloaded", label))
-			return nil
+			return r.downloadSignature(downloadDir, config, signatures)
 		}
 	} else {
 		return fmt.Errorf(tr("getting archive file info: %s"), err)
 	}
-	return httpclient.DownloadFile(path, r.URL, queryParameter, label, downloadCB, config)
+	if err := r.downloadFromMirrors(downloadDir, path, config, label, downloadCB, queryParameter); err != nil {
+		return err
+	}
+	return r.downloadSignature(downloadDir, config, signatures)
+}
+
+// downloadFromMirrors downloads the archive from the resource URL or, if the
+// download fails or the downloaded archive doesn't match the size and checksum
+// declared in the index, from the Mirrors in the given order. The data is kept
+// in a partial file until the download is completed, so an interrupted download
+// is resumed, from the same or from another mirror, instead of restarted.
+// Since a corrupted archive may be caused by a stale partial file, the first
+// corrupted download is retried once from scratch before moving to the next
+// mirror.
+func (r *DownloadResource) downloadFromMirrors(downloadDir, path *paths.Path, config *downloader.Config, label string, downloadCB rpc.DownloadProgressCB, queryParameter string) error {
+	var downloadErr error
+	retried := false
+	for _, u := range append([]string{r.URL}, r.Mirrors...) {
+		if queryParameter != "" {
+			u = u + "?query=" + queryParameter
+		}
+		corrupted, err := r.downloadAndVerify(downloadDir, path, u, config, label, downloadCB)
+		if corrupted && !retried {
+			retried = true
+			logrus.WithField("url", u).WithError(err).Warn("Downloaded archive is corrupted, downloading it again")
+			_, err = r.downloadAndVerify(downloadDir, path, u, config, label, downloadCB)
+		}
+		if err == nil {
+			return nil
+		}
+		downloadErr = err
+		logrus.WithField("url", u).WithError(downloadErr).Warn("Download failed")
+	}
+	return downloadErr
+}
+
+// downloadAndVerify downloads the archive from the given URL and checks it
+// against the size and checksum declared for the resource. A corrupted archive
+// is removed, together with any partial file, and corrupted is set to true.
+func (r *DownloadResource) downloadAndVerify(downloadDir, path *paths.Path, url string, config *downloader.Config, label string, downloadCB rpc.DownloadProgressCB) (corrupted bool, err error) {
+	if err := httpclient.DownloadFileWithContext(context.Background(), path, url, label, downloadCB, config); err != nil {
+		return false, err
+	}
+	if err := r.verifyDownloadedArchive(downloadDir, url); err != nil {
+		// A corrupted archive can't be resumed, the next download starts from scratch
+		for _, p := range []string{path.String(), path.String() + ".part", path.String() + ".part.validator"} {
+			if err := os.Remove(p); err != nil && !os.IsNotExist(err) {
+				return false, fmt.Errorf(tr("removing corrupted archive file: %s"), err)
+			}
+		}
+		return true, err
+	}
+	return false, nil
+}
+
+// verifyDownloadedArchive checks the downloaded archive against the size and
+// checksum declared for the resource, if any. The archive is hashed once.
+func (r *DownloadResource) verifyDownloadedArchive(downloadDir *paths.Path, url string) error {
+	var err error
+	if r.Size > 0 {
+		_, err = r.TestLocalArchiveSize(downloadDir)
+	}
+	if err == nil && r.Checksum != "" {
+		_, err = r.TestLocalArchiveChecksum(downloadDir)
+	}
+	if err != nil {
+		return &arduino.FailedDownloadError{Message: tr("Archive downloaded from %s is corrupted", url), Cause: err}
+	}
+	return nil
+}
+
+// CheckReachable checks if the archive of the resource can be downloaded,
+// without downloading it, by issuing an HTTP HEAD request (or a GET request if
+// the server doesn't support HEAD). The trusted redirect hosts are honored. It
+// returns the HTTP status code of the response, or an error if the server can't
+// be reached.
+func (r *DownloadResource) CheckReachable(ctx context.Context, config *downloader.Config) (int, error) {
+	config, err := r.restrictRedirects(config)
+	if err != nil {
+		return 0, err
+	}
+	if config == nil {
+		if config, err = httpclient.GetDownloaderConfig(); err != nil {
+			return 0, err
+		}
+	}
+	statusCode := 0
+	for _, method := range []string{http.MethodHead, http.MethodGet} {
+		req, err := http.NewRequestWithContext(ctx, method, r.URL, nil)
+		if err != nil {
+			return 0, err
+		}
+		resp, err := config.HttpClient.Do(req)
+		if err != nil {
+			return 0, err
+		}
+		resp.Body.Close()
+		statusCode = resp.StatusCode
+		if statusCode != http.StatusMethodNotAllowed && statusCode != http.StatusNotImplemented {
+			break
+		}
+	}
+	return statusCode, nil
+}
+
+// restrictRedirects returns a copy of the given config that rejects the
+// redirects to hosts other than the ones of the resource URL, of the Mirrors
+// and the TrustedRedirectHosts. If no trusted hosts are set the config is
+// returned as is.
+func (r *DownloadResource) restrictRedirects(config *downloader.Config) (*downloader.Config, error) {
+	if len(r.TrustedRedirectHosts) == 0 {
+		return config, nil
+	}
+	if config == nil {
+		c, err := httpclient.GetDownloaderConfig()
+		if err != nil {
+			return nil, err
+		}
+		config = c
+	}
+	trustedHosts := []string{}
+	for _, resourceURL := range append([]string{r.URL}, r.Mirrors...) {
+		if u, err := url.Parse(resourceURL); err == nil {
+			trustedHosts = append(trustedHosts, strings.ToLower(u.Host), strings.ToLower(u.Hostname()))
+		}
+	}
+	for _, host := range r.TrustedRedirectHosts {
+		trustedHosts = append(trustedHosts, strings.ToLower(host))
+	}
+
+	restricted := *config
+	checkRedirect := config.HttpClient.CheckRedirect
+	restricted.HttpClient.CheckRedirect = func(req *http.Request, via []*http.Request) error {
+		if !slices.Contains(trustedHosts, strings.ToLower(req.URL.Host)) && !slices.Contains(trustedHosts, strings.ToLower(req.URL.Hostname())) {
+			return &arduino.FailedDownloadError{Message: tr("Download of %[1]s redirected to untrusted host %[2]s", r.URL, req.URL.Host)}
+		}
+		if checkRedirect != nil {
+			return checkRedirect(req, via)
+		}
+		// Same limit of the default http.Client policy
+		if len(via) >= 10 {
+			return errors.New(tr("stopped after 10 redirects"))
+		}
+		return nil
+	}
+	return &restricted, nil
 }
@@ -16,11 +16,15 @@
 package resources
 
 import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
 	"net/http"
 	"net/http/httptest"
 	"os"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/arduino/arduino-cli/arduino/httpclient"
 	rpc "github.com/arduino/arduino-cli/rpc/cc/arduino/cli/commands/v1"
@@ -56,7 +60,7 @@ func TestDownloadApplyUserAgentHeaderUsingConfig(t *testing.T) {
 
 	httpClient := httpclient.NewWithConfig(&httpclient.Config{UserAgent: goldUserAgentValue})
 
-	err = r.Download(tmp, &downloader.Config{HttpClient: *httpClient}, "", func(progress *rpc.DownloadProgress) {}, "")
+	err = r.Download(tmp, &downloader.Config{HttpClient: *httpClient}, "", func(progress *rpc.DownloadProgress) {}, "", ArchiveSignatureVerification{})
 	require.NoError(t, err)
 
 	// leverage the download helper to download the echo for the request made by the downloader itself
@@ -80,3 +84,78 @@ func TestDownloadApplyUserAgentHeaderUsingConfig(t *testing.T) {
 	require.Equal(t, goldUserAgentString, userAgentHeaderString)
 
 }
+
+func TestDownloadFromMirrorsWithResume(t *testing.T) {
+	content := bytes.Repeat([]byte("0123456789"), 1000)
+	checksum := sha256.Sum256(content)
+
+	ranges := []string{}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/broken/archive.zip", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	})
+	mux.HandleFunc("/corrupted/archive.zip", func(w http.ResponseWriter, r *http.Request) {
+		w.Write(bytes.Repeat([]byte("x"), len(content)))
+	})
+	mux.HandleFunc("/good/archive.zip", func(w http.ResponseWriter, r *http.Request) {
+		ranges = append(ranges, r.Header.Get("Range"))
+		w.Header().Set("ETag", `"good"`)
+		http.ServeContent(w, r, "archive.zip", time.Time{}, bytes.NewReader(content))
+	})
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	tmp := paths.New(t.TempDir())
+	r := &DownloadResource{
+		ArchiveFileName: "archive.zip",
+		CachePath:       "cache",
+		Checksum:        "SHA-256:" + hex.EncodeToString(checksum[:]),
+		Size:            int64(len(content)),
+		URL:             srv.URL + "/broken/archive.zip",
+		Mirrors:         []string{srv.URL + "/good/archive.zip"},
+	}
+	noProgress := func(progress *rpc.DownloadProgress) {}
+	config := &downloader.Config{HttpClient: *httpclient.NewWithConfig(&httpclient.Config{})}
+
+	// A previously interrupted download is resumed from the mirror providing the right archive
+	archivePath := tmp.Join("cache", "archive.zip")
+	require.NoError(t, archivePath.Parent().MkdirAll())
+	require.NoError(t, paths.New(archivePath.String()+".part").WriteFile(content[:4000]))
+	require.NoError(t, paths.New(archivePath.String()+".part.validator").WriteFile([]byte(`"good"`)))
+	require.NoError(t, r.Download(tmp, config, "", noProgress, "", ArchiveSignatureVerification{}))
+	data, err := archivePath.ReadFile()
+	require.NoError(t, err)
+	require.Equal(t, content, data)
+	require.Equal(t, []string{"bytes=4000-"}, ranges)
+	require.NoFileExists(t, archivePath.String()+".part")
+
+	// A corrupted archive is discarded and downloaded again from the next mirror
+	require.NoError(t, archivePath.Remove())
+	r.URL = srv.URL + "/corrupted/archive.zip"
+	require.NoError(t, r.Download(tmp, config, "", noProgress, "", ArchiveSignatureVerification{}))
+	data, err = archivePath.ReadFile()
+	require.NoError(t, err)
+	require.Equal(t, content, data)
+	require.Equal(t, []string{"bytes=4000-", ""}, ranges)
+
+	// An archive corrupted by a stale partial file is downloaded again from
+	// scratch, even without other mirrors
+	require.NoError(t, archivePath.Remove())
+	r.URL = srv.URL + "/good/archive.zip"
+	r.Mirrors = nil
+	ranges = nil
+	require.NoError(t, paths.New(archivePath.String()+".part").WriteFile(bytes.Repeat([]byte("x"), 4000)))
+	require.NoError(t, paths.New(archivePath.String()+".part.validator").WriteFile([]byte(`"good"`)))
+	require.NoError(t, r.Download(tmp, config, "", noProgress, "", ArchiveSignatureVerification{}))
+	data, err = archivePath.ReadFile()
+	require.NoError(t, err)
+	require.Equal(t, content, data)
+	require.Equal(t, []string{"bytes=4000-", ""}, ranges)
+
+	// The download fails if no mirror provides the right archive
+	require.NoError(t, archivePath.Remove())
+	r.URL = srv.URL + "/corrupted/archive.zip"
+	r.Mirrors = []string{srv.URL + "/broken/archive.zip"}
+	require.Error(t, r.Download(tmp, config, "", noProgress, "", ArchiveSignatureVerification{}))
+	require.NoFileExists(t, archivePath.String())
+}
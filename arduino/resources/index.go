@@ -37,6 +37,10 @@ type IndexResource struct {
 	URL                          *url.URL
 	SignatureURL                 *url.URL
 	EnforceSignatureVerification bool
+	// SignatureOptional makes the signature at SignatureURL optional: if it can't
+	// be downloaded the index is saved without a signature. The signature is not
+	// checked against the Arduino key, it's verified when the index is loaded.
+	SignatureOptional bool
 }
 
 // IndexFileName returns the index file name as it is saved in data dir (package_xxx_index.json).
@@ -59,6 +63,13 @@ func (res *IndexResource) IndexFileName() (string, error) {
 // Download will download the index and possibly check the signature using the Arduino's public key.
 // If the file is in .gz format it will be unpacked first.
 func (res *IndexResource) Download(destDir *paths.Path, downloadCB rpc.DownloadProgressCB) error {
+	return res.DownloadWithContext(context.Background(), destDir, downloadCB)
+}
+
+// DownloadWithContext works like Download but the download can be canceled through the given
+// context. The index is downloaded into a partial file inside destDir, so an interrupted
+// download is resumed on the next call instead of being restarted from scratch.
+func (res *IndexResource) DownloadWithContext(ctx context.Context, destDir *paths.Path, downloadCB rpc.DownloadProgressCB) error {
 	// Create destination directory
 	if err := destDir.MkdirAll(); err != nil {
 		return &arduino.PermissionDeniedError{Message: tr("Can't create data directory %s", destDir), Cause: err}
@@ -77,10 +88,11 @@ func (res *IndexResource) Download(destDir *paths.Path, downloadCB rpc.DownloadP
 	if err != nil {
 		return err
 	}
-	tmpIndexPath := tmp.Join(downloadFileName)
-	if err := httpclient.DownloadFile(tmpIndexPath, res.URL.String(), "", tr("Downloading index: %s", downloadFileName), downloadCB, nil, downloader.NoResume); err != nil {
+	tmpIndexPath := destDir.Join("." + downloadFileName)
+	if err := httpclient.DownloadFileWithContext(ctx, tmpIndexPath, res.URL.String(), tr("Downloading index: %s", downloadFileName), downloadCB, nil); err != nil {
 		return &arduino.FailedDownloadError{Message: tr("Error downloading index '%s'", res.URL), Cause: err}
 	}
+	defer tmpIndexPath.Remove()
 
 	var signaturePath, tmpSignaturePath *paths.Path
 	hasSignature := false
@@ -133,24 +145,24 @@ func (res *IndexResource) Download(destDir *paths.Path, downloadCB rpc.DownloadP
 		// Download signature
 		signaturePath = destDir.Join(signatureFileName)
 		tmpSignaturePath = tmp.Join(signatureFileName)
-		if err := httpclient.DownloadFile(tmpSignaturePath, res.SignatureURL.String(), "", tr("Downloading index signature: %s", signatureFileName), downloadCB, nil, downloader.NoResume); err != nil {
+		if err := httpclient.DownloadFile(tmpSignaturePath, res.SignatureURL.String(), "", tr("Downloading index signature: %s", signatureFileName), downloadCB, nil, downloader.NoResume); err == nil {
+			hasSignature = true
+		} else if res.SignatureOptional {
+			logrus.WithError(err).Infof("No signature found for index %s", res.URL)
+		} else {
 			return &arduino.FailedDownloadError{Message: tr("Error downloading index signature '%s'", res.SignatureURL), Cause: err}
 		}
-
-		hasSignature = true
 	}
 
-	if hasSignature {
-		// Check signature...
+	if hasSignature && !res.SignatureOptional {
+		// Check signature... (the optional signatures are verified when the index is loaded)
 		if valid, _, err := security.VerifyArduinoDetachedSignature(tmpIndexPath, tmpSignaturePath); err != nil {
 			return &arduino.PermissionDeniedError{Message: tr("Error verifying signature"), Cause: err}
 		} else if !valid {
 			return &arduino.SignatureVerificationFailedError{File: res.URL.String()}
 		}
-	} else {
-		if res.EnforceSignatureVerification {
-			return &arduino.PermissionDeniedError{Message: tr("Error verifying signature"), Cause: errors.New(tr("missing signature"))}
-		}
+	} else if !hasSignature && res.EnforceSignatureVerification {
+		return &arduino.PermissionDeniedError{Message: tr("Error verifying signature"), Cause: errors.New(tr("missing signature"))}
 	}
 
 	// TODO: Implement a ResourceValidator
@@ -179,6 +191,9 @@ func (res *IndexResource) Download(destDir *paths.Path, downloadCB rpc.DownloadP
 		if err := tmpSignaturePath.CopyTo(signaturePath); err != nil {
 			return &arduino.PermissionDeniedError{Message: tr("Error saving downloaded index signature"), Cause: err}
 		}
+	} else if res.SignatureOptional {
+		// Remove the signature of the previous version of the index, if any
+		_ = signaturePath.Remove()
 	}
 	_ = oldIndex.Remove()
 	_ = oldSignature.Remove()
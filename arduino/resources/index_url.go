@@ -0,0 +1,105 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2023 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package resources
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/arduino/arduino-cli/arduino"
+	"github.com/arduino/arduino-cli/arduino/httpclient"
+	"github.com/arduino/arduino-cli/arduino/utils"
+)
+
+// IndexURLWarning is a non-fatal problem found while validating an index URL
+type IndexURLWarning string
+
+// IndexURLProbe checks if the given index URL is reachable
+type IndexURLProbe func(URL *url.URL) error
+
+// ProbeIndexURL is an IndexURLProbe that sends a HEAD request to the given URL
+// using the default http client.
+func ProbeIndexURL(URL *url.URL) error {
+	client, err := httpclient.New()
+	if err != nil {
+		return err
+	}
+	resp, err := client.Head(URL.String())
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("%s", resp.Status)
+	}
+	return nil
+}
+
+// ValidateIndexURL normalizes and validates a user-supplied package index URL.
+// An error is returned if the URL can not be used at all, otherwise the
+// normalized URL is returned together with a list of warnings about possible
+// mistakes. If probe is not nil it is used to check if the URL is reachable
+// and if an http URL is also available via https (in that case the https URL
+// is returned).
+func ValidateIndexURL(raw string, probe IndexURLProbe) (*url.URL, []IndexURLWarning, error) {
+	warnings := []IndexURLWarning{}
+	raw = strings.TrimSpace(raw)
+	if raw == "" {
+		return nil, nil, &arduino.InvalidURLError{Cause: errors.New(tr("empty URL"))}
+	}
+	if !strings.Contains(raw, "://") {
+		raw = "https://" + raw
+		warnings = append(warnings, IndexURLWarning(tr("URL scheme is missing, assuming %s", "https")))
+	}
+
+	URL, err := utils.URLParse(raw)
+	if err != nil {
+		return nil, nil, &arduino.InvalidURLError{Cause: err}
+	}
+	URL.Scheme = strings.ToLower(URL.Scheme)
+	URL.Host = strings.ToLower(URL.Host)
+	switch URL.Scheme {
+	case "http", "https":
+		if URL.Host == "" {
+			return nil, nil, &arduino.InvalidURLError{Cause: errors.New(tr("missing host in URL"))}
+		}
+	case "file":
+	default:
+		return nil, nil, &arduino.InvalidURLError{Cause: errors.New(tr("unsupported URL scheme: %s", URL.Scheme))}
+	}
+
+	if fileName, err := (&IndexResource{URL: URL}).IndexFileName(); err != nil || !strings.HasSuffix(fileName, "_index.json") {
+		warnings = append(warnings, IndexURLWarning(tr("URL doesn't point to a package index file, the file name should end with %s", "_index.json")))
+	}
+
+	if probe == nil || URL.Scheme == "file" {
+		return URL, warnings, nil
+	}
+	if URL.Scheme == "http" {
+		httpsURL := *URL
+		httpsURL.Scheme = "https"
+		if probe(&httpsURL) == nil {
+			warnings = append(warnings, IndexURLWarning(tr("The index is also available via https, using %s", httpsURL.String())))
+			return &httpsURL, warnings, nil
+		}
+	}
+	if err := probe(URL); err != nil {
+		warnings = append(warnings, IndexURLWarning(tr("URL is not reachable: %s", err)))
+	}
+	return URL, warnings, nil
+}
@@ -0,0 +1,93 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2023 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package resources
+
+import (
+	"errors"
+	"net/url"
+	"testing"
+
+	"github.com/arduino/arduino-cli/arduino"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateIndexURL(t *testing.T) {
+	// reachable simulates a server that serves the index only on the given URLs
+	reachable := func(urls ...string) IndexURLProbe {
+		return func(URL *url.URL) error {
+			for _, u := range urls {
+				if URL.String() == u {
+					return nil
+				}
+			}
+			return errors.New("404 Not Found")
+		}
+	}
+
+	t.Run("GoodURL", func(t *testing.T) {
+		URL, warnings, err := ValidateIndexURL("  https://Example.com/package_example_index.json ",
+			reachable("https://example.com/package_example_index.json"))
+		require.NoError(t, err)
+		require.Equal(t, "https://example.com/package_example_index.json", URL.String())
+		require.Empty(t, warnings)
+	})
+
+	t.Run("DirectoryURL", func(t *testing.T) {
+		URL, warnings, err := ValidateIndexURL("https://example.com/boards/", nil)
+		require.NoError(t, err)
+		require.Equal(t, "https://example.com/boards/", URL.String())
+		require.Len(t, warnings, 1)
+		require.Contains(t, string(warnings[0]), "_index.json")
+	})
+
+	t.Run("HTTPWithHTTPSAvailable", func(t *testing.T) {
+		URL, warnings, err := ValidateIndexURL("http://example.com/package_example_index.json",
+			reachable("http://example.com/package_example_index.json", "https://example.com/package_example_index.json"))
+		require.NoError(t, err)
+		require.Equal(t, "https://example.com/package_example_index.json", URL.String())
+		require.Len(t, warnings, 1)
+		require.Contains(t, string(warnings[0]), "https")
+	})
+
+	t.Run("HTTPOnly", func(t *testing.T) {
+		URL, warnings, err := ValidateIndexURL("http://example.com/package_example_index.json",
+			reachable("http://example.com/package_example_index.json"))
+		require.NoError(t, err)
+		require.Equal(t, "http://example.com/package_example_index.json", URL.String())
+		require.Empty(t, warnings)
+	})
+
+	t.Run("UnreachableURL", func(t *testing.T) {
+		_, warnings, err := ValidateIndexURL("https://example.com/package_example_index.json", reachable())
+		require.NoError(t, err)
+		require.Len(t, warnings, 1)
+		require.Contains(t, string(warnings[0]), "not reachable")
+	})
+
+	t.Run("MissingScheme", func(t *testing.T) {
+		URL, warnings, err := ValidateIndexURL("example.com/package_example_index.json", nil)
+		require.NoError(t, err)
+		require.Equal(t, "https://example.com/package_example_index.json", URL.String())
+		require.Len(t, warnings, 1)
+	})
+
+	t.Run("InvalidURLs", func(t *testing.T) {
+		for _, raw := range []string{"", "ftp://example.com/package_example_index.json", "https:///package_example_index.json"} {
+			_, _, err := ValidateIndexURL(raw, nil)
+			require.ErrorAs(t, err, new(*arduino.InvalidURLError), raw)
+		}
+	})
+}
@@ -26,12 +26,13 @@ import (
 )
 
 // Install installs the resource in three steps:
-// - the archive is unpacked in a temporary subdir of tempPath
+// - the archive is verified and unpacked in a temporary subdir of tempPath
 // - there should be only one root dir in the unpacked content
 // - the only root dir is moved/renamed to/as the destination directory
 // Note that tempPath and destDir must be on the same filesystem partition
-// otherwise the last step will fail.
-func (release *DownloadResource) Install(downloadDir, tempPath, destDir *paths.Path) error {
+// otherwise the last step will fail. The signature of the archive is verified
+// according to the given settings.
+func (release *DownloadResource) Install(downloadDir, tempPath, destDir *paths.Path, signatures ArchiveSignatureVerification) error {
 	// Check the integrity of the package
 	if ok, err := release.TestLocalArchiveIntegrity(downloadDir); err != nil {
 		return fmt.Errorf(tr("testing local archive integrity: %s", err))
@@ -39,6 +40,11 @@ func (release *DownloadResource) Install(downloadDir, tempPath, destDir *paths.P
 		return fmt.Errorf(tr("checking local archive integrity"))
 	}
 
+	// Check the signature of the package
+	if err := release.VerifySignature(downloadDir, signatures); err != nil {
+		return err
+	}
+
 	// Create a temporary dir to extract package
 	if err := tempPath.MkdirAll(); err != nil {
 		return fmt.Errorf(tr("creating temp dir for extraction: %s", err))
@@ -16,6 +16,8 @@
 package resources
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
 	"os"
 	"path"
 	"path/filepath"
@@ -44,9 +46,25 @@ func TestInstallPlatform(t *testing.T) {
 			Size:            157,
 		}
 
-		require.NoError(t, r.Install(downloadDir, tempPath, destDir))
+		require.NoError(t, r.Install(downloadDir, tempPath, destDir, ArchiveSignatureVerification{}))
 	})
 
+	for _, format := range []string{"xz", "zst"} {
+		format := format
+		t.Run("tarball compressed with "+format, func(t *testing.T) {
+			downloadDir, tempPath, destDir := paths.New(t.TempDir()), paths.New(t.TempDir()), paths.New(t.TempDir())
+			r := &DownloadResource{ArchiveFileName: writeCompressedPlatformArchive(t, downloadDir, format)}
+			archive, err := downloadDir.Join(r.ArchiveFileName).ReadFile()
+			require.NoError(t, err)
+			checksum := sha256.Sum256(archive)
+			r.Checksum = "SHA-256:" + hex.EncodeToString(checksum[:])
+			r.Size = int64(len(archive))
+
+			require.NoError(t, r.Install(downloadDir, tempPath, destDir.Join("platform"), ArchiveSignatureVerification{}))
+			require.FileExists(t, destDir.Join("platform", "boards.txt").String())
+		})
+	}
+
 	tests := []struct {
 		testName         string
 		downloadResource *DownloadResource
@@ -82,7 +100,7 @@ func TestInstallPlatform(t *testing.T) {
 			require.NoError(t, err)
 			require.NoError(t, os.WriteFile(path.Join(downloadDir.String(), testFileName), origin, 0644))
 
-			err = test.downloadResource.Install(downloadDir, tempPath, destDir)
+			err = test.downloadResource.Install(downloadDir, tempPath, destDir, ArchiveSignatureVerification{})
 			require.Error(t, err)
 			require.Contains(t, err.Error(), test.error)
 		})
@@ -49,7 +49,7 @@ func TestDownloadAndChecksums(t *testing.T) {
 	require.NoError(t, err)
 
 	downloadAndTestChecksum := func() {
-		err := r.Download(tmp, &downloader.Config{}, "", func(*rpc.DownloadProgress) {}, "")
+		err := r.Download(tmp, &downloader.Config{}, "", func(*rpc.DownloadProgress) {}, "", ArchiveSignatureVerification{})
 		require.NoError(t, err)
 
 		data, err := testFile.ReadFile()
@@ -63,7 +63,7 @@ func TestDownloadAndChecksums(t *testing.T) {
 	downloadAndTestChecksum()
 
 	// Download with cached file
-	err = r.Download(tmp, &downloader.Config{}, "", func(*rpc.DownloadProgress) {}, "")
+	err = r.Download(tmp, &downloader.Config{}, "", func(*rpc.DownloadProgress) {}, "", ArchiveSignatureVerification{})
 	require.NoError(t, err)
 
 	// Download if cached file has data in excess (redownload)
@@ -0,0 +1,103 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2023 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package resources
+
+import (
+	"errors"
+
+	"github.com/arduino/arduino-cli/arduino"
+	"github.com/arduino/arduino-cli/arduino/httpclient"
+	"github.com/arduino/arduino-cli/arduino/security"
+	rpc "github.com/arduino/arduino-cli/rpc/cc/arduino/cli/commands/v1"
+	"github.com/arduino/go-paths-helper"
+	"github.com/sirupsen/logrus"
+	"go.bug.st/downloader/v2"
+)
+
+// ArchiveSignatureVerification contains the settings used to verify the detached
+// signatures (.sig files published next to the archives) of the downloaded archives.
+// The zero value disables the verification.
+type ArchiveSignatureVerification struct {
+	// Keyring is the path to the keyring containing the trusted public keys,
+	// if nil the signatures of the archives are not verified.
+	Keyring *paths.Path
+	// Strict makes the installation of archives without a signature fail,
+	// otherwise unsigned archives are installed with a warning.
+	Strict bool
+}
+
+// SignaturePath returns the path of the detached signature of the Archive of the
+// specified DownloadResource relative to the specified downloadDir
+func (r *DownloadResource) SignaturePath(downloadDir *paths.Path) (*paths.Path, error) {
+	archivePath, err := r.ArchivePath(downloadDir)
+	if err != nil {
+		return nil, err
+	}
+	return paths.New(archivePath.String() + ".sig"), nil
+}
+
+// downloadSignature downloads the detached signature of the archive, if the signature
+// verification is enabled. A missing signature is not an error: it will be reported
+// when the archive is verified.
+func (r *DownloadResource) downloadSignature(downloadDir *paths.Path, config *downloader.Config, signatures ArchiveSignatureVerification) error {
+	if signatures.Keyring == nil {
+		return nil
+	}
+	signaturePath, err := r.SignaturePath(downloadDir)
+	if err != nil {
+		return err
+	}
+	_ = signaturePath.Remove()
+	noProgress := func(*rpc.DownloadProgress) {}
+	if err := httpclient.DownloadFile(signaturePath, r.URL+".sig", "", "", noProgress, config); err != nil {
+		logrus.WithError(err).Warnf("Could not download signature for %s", r.URL)
+		_ = signaturePath.Remove()
+	}
+	return nil
+}
+
+// VerifySignature checks the detached signature of the archive against the
+// keyring of the given settings. If the signature verification is not enabled
+// this function does nothing.
+func (r *DownloadResource) VerifySignature(downloadDir *paths.Path, settings ArchiveSignatureVerification) error {
+	if settings.Keyring == nil {
+		return nil
+	}
+	archivePath, err := r.ArchivePath(downloadDir)
+	if err != nil {
+		return err
+	}
+	signaturePath, err := r.SignaturePath(downloadDir)
+	if err != nil {
+		return err
+	}
+	if settings.Keyring.NotExist() {
+		return &arduino.PermissionDeniedError{Message: tr("Error verifying signature"), Cause: errors.New(tr("keyring %s not found", settings.Keyring))}
+	}
+	if signaturePath.NotExist() {
+		if settings.Strict {
+			return &arduino.PermissionDeniedError{Message: tr("Error verifying signature"), Cause: errors.New(tr("missing signature"))}
+		}
+		logrus.Warnf("Archive %s is not signed", archivePath)
+		return nil
+	}
+	if valid, _, err := security.VerifyDetachedSignature(archivePath, signaturePath, settings.Keyring); err != nil {
+		return &arduino.SignatureVerificationFailedError{File: r.ArchiveFileName, Cause: err}
+	} else if !valid {
+		return &arduino.SignatureVerificationFailedError{File: r.ArchiveFileName}
+	}
+	return nil
+}
@@ -0,0 +1,84 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2023 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package resources
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/ProtonMail/go-crypto/openpgp"
+	"github.com/arduino/arduino-cli/arduino"
+	"github.com/arduino/go-paths-helper"
+	"github.com/stretchr/testify/require"
+)
+
+func TestArchiveSignatureVerification(t *testing.T) {
+	// Create a signing key and the corresponding keyring
+	signer, err := openpgp.NewEntity("test", "", "test@example.com", nil)
+	require.NoError(t, err)
+	keyring := paths.New(t.TempDir()).Join("keyring.gpg")
+	keyringData := &bytes.Buffer{}
+	require.NoError(t, signer.Serialize(keyringData))
+	require.NoError(t, keyring.WriteFile(keyringData.Bytes()))
+
+	archiveName := "platform_with_root_and__MACOSX_folder.tar.bz2"
+	archive, err := paths.New("testdata", "valid", archiveName).ReadFile()
+	require.NoError(t, err)
+	r := &DownloadResource{
+		ArchiveFileName: archiveName,
+		Checksum:        "SHA-256:600ad56b6260352e0b2cee786f60749e778e179252a0594ba542f0bd1f8adee5",
+		Size:            157,
+	}
+
+	sign := func(data []byte) []byte {
+		signature := &bytes.Buffer{}
+		require.NoError(t, openpgp.DetachSign(signature, signer, bytes.NewReader(data), nil))
+		return signature.Bytes()
+	}
+	install := func(signature []byte, settings ArchiveSignatureVerification) error {
+		downloadDir, tempPath, destDir := paths.New(t.TempDir()), paths.New(t.TempDir()), paths.New(t.TempDir())
+		require.NoError(t, downloadDir.Join(archiveName).WriteFile(archive))
+		if signature != nil {
+			require.NoError(t, downloadDir.Join(archiveName+".sig").WriteFile(signature))
+		}
+		return r.Install(downloadDir, tempPath, destDir.Join("platform"), settings)
+	}
+
+	verify := ArchiveSignatureVerification{Keyring: keyring}
+	t.Run("ValidSignature", func(t *testing.T) {
+		require.NoError(t, install(sign(archive), verify))
+	})
+	t.Run("InvalidSignature", func(t *testing.T) {
+		err := install(sign([]byte("tampered content")), verify)
+		require.ErrorAs(t, err, new(*arduino.SignatureVerificationFailedError))
+	})
+	t.Run("UnsignedArchive", func(t *testing.T) {
+		require.NoError(t, install(nil, verify))
+	})
+	t.Run("VerificationDisabled", func(t *testing.T) {
+		require.NoError(t, install(sign([]byte("tampered content")), ArchiveSignatureVerification{}))
+	})
+
+	strict := ArchiveSignatureVerification{Keyring: keyring, Strict: true}
+	t.Run("StrictValidSignature", func(t *testing.T) {
+		require.NoError(t, install(sign(archive), strict))
+	})
+	t.Run("StrictUnsignedArchive", func(t *testing.T) {
+		err := install(nil, strict)
+		require.ErrorAs(t, err, new(*arduino.PermissionDeniedError))
+		require.ErrorContains(t, err, "missing signature")
+	})
+}
@@ -22,6 +22,13 @@ type DownloadResource struct {
 	Checksum        string
 	Size            int64
 	CachePath       string
+	// Mirrors are alternative URLs of the same archive, tried in order when
+	// the download from URL fails or the downloaded archive is corrupted.
+	Mirrors []string
+	// TrustedRedirectHosts are the hosts, declared by the package index providing
+	// the resource, where the download may be redirected to. If empty the
+	// redirects are not restricted.
+	TrustedRedirectHosts []string
 }
 
 // DownloadResult contains the result of a download
@@ -36,6 +36,8 @@ type Project struct {
 	DefaultFqbn     string   `yaml:"default_fqbn"`
 	DefaultPort     string   `yaml:"default_port,omitempty"`
 	DefaultProtocol string   `yaml:"default_protocol,omitempty"`
+	// SketchFilesOrder is the order used to merge the secondary .ino files of the sketch
+	SketchFilesOrder []string `yaml:"sketch_files_order,omitempty"`
 }
 
 // AsYaml outputs the sketch project file as YAML
@@ -58,6 +60,12 @@ func (p *Project) AsYaml() string {
 	if p.DefaultProtocol != "" {
 		res += fmt.Sprintf("default_protocol: %s\n", p.DefaultProtocol)
 	}
+	if len(p.SketchFilesOrder) > 0 {
+		res += "sketch_files_order:\n"
+		for _, file := range p.SketchFilesOrder {
+			res += fmt.Sprintf("  - %s\n", file)
+		}
+	}
 	return res
 }
 
@@ -152,9 +152,87 @@ func New(path *paths.Path) (*Sketch, error) {
 	sort.Sort(&sketch.OtherSketchFiles)
 	sort.Sort(&sketch.RootFolderFiles)
 
+	if len(sketch.Project.SketchFilesOrder) > 0 {
+		if err := sketch.SetOtherSketchFilesOrder(sketch.Project.SketchFilesOrder); err != nil {
+			return nil, err
+		}
+	}
+
 	return sketch, nil
 }
 
+// SetOtherSketchFilesOrder changes the order of the secondary sketch files (the .ino
+// files other than the main file), that is the order used to merge them together with
+// the main file. The files listed in order (by file name) are moved in front, in the
+// given order, the remaining files follow in alphabetical order.
+// A file name that matches no file exactly is matched case-insensitively, an error is
+// returned if it's ambiguous (for example "b.ino" and "B.ino" both exist and "b.INO" is
+// requested) or if it doesn't match any file.
+func (s *Sketch) SetOtherSketchFilesOrder(order []string) error {
+	remaining := s.OtherSketchFiles.Clone()
+	sort.Sort(&remaining)
+	ordered := paths.PathList{}
+	for _, name := range order {
+		idx := -1
+		for i, file := range remaining {
+			if file.Base() == name {
+				idx = i
+				break
+			}
+		}
+		if idx == -1 {
+			for i, file := range remaining {
+				if strings.EqualFold(file.Base(), name) {
+					if idx != -1 {
+						return errors.Errorf(tr("sketch file %s is ambiguous"), name)
+					}
+					idx = i
+				}
+			}
+		}
+		if idx == -1 {
+			return errors.Errorf(tr("sketch file %s not found"), name)
+		}
+		ordered.Add(remaining[idx])
+		remaining = append(remaining[:idx], remaining[idx+1:]...)
+	}
+	s.OtherSketchFiles = append(ordered, remaining...)
+	return nil
+}
+
+// SetMainFile makes the given .ino file the main file of the sketch, in place of
+// the one detected by convention (the file named after the sketch folder). The
+// file may be given with a path relative to the sketch folder and must be one of
+// the sketch files in the root of the sketch, the previous main file becomes
+// the first of the other sketch files.
+func (s *Sketch) SetMainFile(file *paths.Path) error {
+	if !file.IsAbs() {
+		file = s.FullPath.JoinPath(file)
+	}
+	if file.EquivalentTo(s.MainFile) {
+		return nil
+	}
+	idx := -1
+	for i, other := range s.OtherSketchFiles {
+		if other.EquivalentTo(file) {
+			idx = i
+			break
+		}
+	}
+	if idx == -1 {
+		return errors.Errorf(tr("main file %s is not a sketch file"), file)
+	}
+	// The previous main file is merged right after the new one, so the merge
+	// order of the other files doesn't change
+	newMainFile := s.OtherSketchFiles[idx]
+	others := paths.PathList{s.MainFile}
+	others = append(others, s.OtherSketchFiles[:idx]...)
+	others = append(others, s.OtherSketchFiles[idx+1:]...)
+	s.OtherSketchFiles = others
+	s.MainFile = newMainFile
+	return nil
+}
+
 // supportedFiles reads all files recursively contained in Sketch and
 // filter out unneded or unsupported ones and returns them
 func (s *Sketch) supportedFiles() (*paths.PathList, error) {
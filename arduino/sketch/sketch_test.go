@@ -18,6 +18,7 @@ package sketch
 import (
 	"fmt"
 	"os"
+	"runtime"
 	"testing"
 	"time"
 
@@ -422,3 +423,74 @@ func TestSketchWithMultipleSymlinkLoops(t *testing.T) {
 	require.Error(t, err)
 	require.Nil(t, sketch)
 }
+
+func TestOtherSketchFilesOrder(t *testing.T) {
+	sketchPath := paths.New(t.TempDir()).Join("SketchOrder")
+	require.NoError(t, sketchPath.MkdirAll())
+	for _, name := range []string{"SketchOrder.ino", "c.ino", "a.ino", "b.ino"} {
+		require.NoError(t, sketchPath.Join(name).WriteFile([]byte{}))
+	}
+	names := func(files paths.PathList) []string {
+		res := []string{}
+		for _, f := range files {
+			res = append(res, f.Base())
+		}
+		return res
+	}
+
+	// Default order is alphabetical
+	sk, err := New(sketchPath)
+	require.NoError(t, err)
+	require.Equal(t, "SketchOrder.ino", sk.MainFile.Base())
+	require.Equal(t, []string{"a.ino", "b.ino", "c.ino"}, names(sk.OtherSketchFiles))
+
+	// Order from the sketch project file
+	require.NoError(t, sketchPath.Join("sketch.yaml").WriteFile([]byte("sketch_files_order:\n  - c.ino\n  - b.ino\n")))
+	sk, err = New(sketchPath)
+	require.NoError(t, err)
+	require.Equal(t, []string{"c.ino", "b.ino", "a.ino"}, names(sk.OtherSketchFiles))
+
+	require.NoError(t, sk.SetOtherSketchFilesOrder([]string{"B.INO"}))
+	require.Equal(t, []string{"b.ino", "a.ino", "c.ino"}, names(sk.OtherSketchFiles))
+
+	require.Error(t, sk.SetOtherSketchFilesOrder([]string{"missing.ino"}))
+
+	require.NoError(t, sketchPath.Join("sketch.yaml").WriteFile([]byte("sketch_files_order:\n  - missing.ino\n")))
+	_, err = New(sketchPath)
+	require.Error(t, err)
+	require.NoError(t, sketchPath.Join("sketch.yaml").Remove())
+
+	if runtime.GOOS != "linux" {
+		// The following checks require a case sensitive filesystem
+		return
+	}
+	require.NoError(t, sketchPath.Join("B.ino").WriteFile([]byte{}))
+	sk, err = New(sketchPath)
+	require.NoError(t, err)
+	require.Equal(t, []string{"B.ino", "a.ino", "b.ino", "c.ino"}, names(sk.OtherSketchFiles))
+	require.NoError(t, sk.SetOtherSketchFilesOrder([]string{"b.ino", "B.ino"}))
+	require.Equal(t, []string{"b.ino", "B.ino", "a.ino", "c.ino"}, names(sk.OtherSketchFiles))
+	require.ErrorContains(t, sk.SetOtherSketchFilesOrder([]string{"b.INO"}), "ambiguous")
+}
+
+func TestSetMainFile(t *testing.T) {
+	sketchPath := paths.New(t.TempDir()).Join("SketchMain")
+	require.NoError(t, sketchPath.MkdirAll())
+	for _, name := range []string{"SketchMain.ino", "a.ino", "generated.ino", "z.ino", "other.cpp"} {
+		require.NoError(t, sketchPath.Join(name).WriteFile([]byte{}))
+	}
+	sk, err := New(sketchPath)
+	require.NoError(t, err)
+
+	require.Error(t, sk.SetMainFile(paths.New("other.cpp")))
+	require.Error(t, sk.SetMainFile(paths.New("missing.ino")))
+	require.Error(t, sk.SetMainFile(paths.New(t.TempDir()).Join("generated.ino")))
+	require.Equal(t, sketchPath.Join("SketchMain.ino"), sk.MainFile)
+
+	require.NoError(t, sk.SetMainFile(paths.New("generated.ino")))
+	require.Equal(t, sketchPath.Join("generated.ino"), sk.MainFile)
+	require.Equal(t, paths.PathList{sketchPath.Join("SketchMain.ino"), sketchPath.Join("a.ino"), sketchPath.Join("z.ino")}, sk.OtherSketchFiles)
+
+	require.NoError(t, sk.SetMainFile(sketchPath.Join("generated.ino")))
+	require.Equal(t, sketchPath.Join("generated.ino"), sk.MainFile)
+}
@@ -53,28 +53,84 @@ func SearchTermsFromQueryString(query string) []string {
 	})
 }
 
-// Match returns true if all substrings are contained in str.
-// Both str and substrings are transforms to lower case and have their
-// accents and other unicode diatrics removed.
-// If strings transformation fails an error is returned.
-func Match(str string, substrings []string) bool {
-	clean := func(s string) string {
-		s = strings.ToLower(s)
-		if s2, err := removeDiatrics(s); err == nil {
-			return s2
-		}
-		return s
+// normalizeSearchString transforms the string to lower case and removes
+// accents and other unicode diatrics.
+func normalizeSearchString(s string) string {
+	s = strings.ToLower(s)
+	if s2, err := removeDiatrics(s); err == nil {
+		return s2
+	}
+	return s
+}
+
+// Matcher matches strings against a set of search terms. The terms are
+// normalized only once when the Matcher is created, so the same Matcher
+// should be reused to test many strings against the same query.
+type Matcher struct {
+	terms []string
+}
+
+// NewMatcher creates a Matcher for the terms contained in the query string,
+// see SearchTermsFromQueryString.
+func NewMatcher(query string) *Matcher {
+	return NewMatcherFromTerms(SearchTermsFromQueryString(query))
+}
+
+// NewMatcherFromTerms creates a Matcher for the given search terms.
+func NewMatcherFromTerms(terms []string) *Matcher {
+	normalized := make([]string, len(terms))
+	for i, term := range terms {
+		normalized[i] = normalizeSearchString(term)
 	}
+	return &Matcher{terms: normalized}
+}
 
-	str = clean(str)
-	for _, sub := range substrings {
-		if !strings.Contains(str, clean(sub)) {
+// Match returns true if all the search terms are contained in str.
+func (m *Matcher) Match(str string) bool {
+	str = normalizeSearchString(str)
+	for _, term := range m.terms {
+		if !strings.Contains(str, term) {
 			return false
 		}
 	}
 	return true
 }
 
+// Score returns how well str matches the search terms, or 0 if str doesn't
+// match. Each term adds 1 if it's contained in str, 2 if it's the beginning
+// of a word of str, or 3 if it's a whole word of str.
+func (m *Matcher) Score(str string) int {
+	str = normalizeSearchString(str)
+	words := SearchTermsFromQueryString(str)
+	score := 0
+	for _, term := range m.terms {
+		if !strings.Contains(str, term) {
+			return 0
+		}
+		termScore := 1
+		for _, word := range words {
+			if word == term {
+				termScore = 3
+				break
+			}
+			if strings.HasPrefix(word, term) {
+				termScore = 2
+			}
+		}
+		score += termScore
+	}
+	return score
+}
+
+// Match returns true if all substrings are contained in str.
+// Both str and substrings are transforms to lower case and have their
+// accents and other unicode diatrics removed.
+// If many strings must be matched against the same substrings, use a
+// Matcher instead.
+func Match(str string, substrings []string) bool {
+	return NewMatcherFromTerms(substrings).Match(str)
+}
+
 // MatchAny checks if query matches at least one of the
 // string in arrayToMatch using the utils.Match function.
 func MatchAny(query string, arrayToMatch []string) bool {
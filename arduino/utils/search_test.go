@@ -0,0 +1,75 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2023 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package utils
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+var searchCandidates = []string{
+	"Arduino AVR Boards arduino:avr Arduino Uno Arduino Mega",
+	"Servo Allows Arduino boards to control a variety of servo motors. Michael Margolis",
+	"Ärduino Ümlaut library with accents",
+	"ESP32 Arduino esp32:esp32 Espressif Systems",
+	"",
+}
+
+func TestMatcherIsEquivalentToMatch(t *testing.T) {
+	queries := []string{"", "arduino", "ARDUINO avr", "servo motor", "umlaut", "Ümlaut", "esp32:esp32", "nonexistent", "arduino nonexistent"}
+	for _, query := range queries {
+		terms := SearchTermsFromQueryString(query)
+		matcher := NewMatcher(query)
+		for _, candidate := range searchCandidates {
+			require.Equal(t, Match(candidate, terms), matcher.Match(candidate), fmt.Sprintf("query %q candidate %q", query, candidate))
+		}
+	}
+}
+
+func TestMatcherScore(t *testing.T) {
+	m := NewMatcher("servo")
+	require.Equal(t, 0, m.Score("Stepper motors"))
+	require.Equal(t, 3, m.Score("Servo library"))
+	require.Equal(t, 2, m.Score("ServoEasing library"))
+	require.Equal(t, 1, m.Score("MyServo library"))
+
+	m = NewMatcher("arduino avr")
+	require.Equal(t, 6, m.Score("Arduino AVR Boards"))
+	require.Equal(t, 0, m.Score("Arduino SAMD Boards"))
+	require.Greater(t, m.Score("Arduino AVR Boards"), m.Score("Arduino avrdude"))
+}
+
+func BenchmarkMatch(b *testing.B) {
+	terms := SearchTermsFromQueryString("arduino boards")
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		for _, candidate := range searchCandidates {
+			Match(candidate, terms)
+		}
+	}
+}
+
+func BenchmarkMatcher(b *testing.B) {
+	matcher := NewMatcher("arduino boards")
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		for _, candidate := range searchCandidates {
+			matcher.Match(candidate)
+		}
+	}
+}
@@ -0,0 +1,190 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2023 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package utils
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/arduino/go-paths-helper"
+	"github.com/sirupsen/logrus"
+)
+
+// SymlinkPolicy defines how symlinks are handled while scanning directories
+type SymlinkPolicy int32
+
+const (
+	// FollowSymlinksWithinRoot follows only the symlinks that point inside the
+	// root of the directory being scanned
+	FollowSymlinksWithinRoot SymlinkPolicy = iota
+	// NeverFollowSymlinks ignores all the symlinks
+	NeverFollowSymlinks
+	// FollowAllSymlinks follows all the symlinks, wherever they point
+	FollowAllSymlinks
+)
+
+var symlinkPolicyNames = map[SymlinkPolicy]string{
+	FollowSymlinksWithinRoot: "within_root",
+	NeverFollowSymlinks:      "never",
+	FollowAllSymlinks:        "all",
+}
+
+func (p SymlinkPolicy) String() string {
+	if name, ok := symlinkPolicyNames[p]; ok {
+		return name
+	}
+	return fmt.Sprintf("SymlinkPolicy(%d)", int32(p))
+}
+
+// ParseSymlinkPolicy returns the SymlinkPolicy with the given name, the
+// accepted values are "within_root", "never" and "all".
+func ParseSymlinkPolicy(name string) (SymlinkPolicy, error) {
+	for policy, policyName := range symlinkPolicyNames {
+		if policyName == name {
+			return policy, nil
+		}
+	}
+	return FollowSymlinksWithinRoot, fmt.Errorf("invalid symlink policy: %s", name)
+}
+
+// FilterSymlinks returns a paths.ReadDirFilter that rejects the symlinks that
+// must not be followed, according to the policy, while scanning root.
+// Broken or looping symlinks are always rejected. Each rejected symlink is
+// logged.
+func (p SymlinkPolicy) FilterSymlinks(root *paths.Path) paths.ReadDirFilter {
+	realRoot, rootErr := evalSymlinks(root)
+	return func(path *paths.Path) bool {
+		if !isSymlink(path) {
+			return true
+		}
+		log := logrus.WithField("path", path).WithField("symlinks_policy", p.String())
+		if p == NeverFollowSymlinks {
+			log.Info("Skipping symlink")
+			return false
+		}
+		target, err := evalSymlinks(path)
+		if err != nil {
+			log.WithError(err).Warn("Skipping broken symlink")
+			return false
+		}
+		if p == FollowAllSymlinks {
+			return true
+		}
+		if rootErr != nil || !isWithinDir(target, realRoot) {
+			log.WithField("target", target).Info("Skipping symlink pointing outside of the scanned directory")
+			return false
+		}
+		return true
+	}
+}
+
+// ReadDirRecursiveFiltered works like paths.Path.ReadDirRecursiveFiltered but
+// follows the symlinks found in dir according to the policy, root is the
+// directory used to check if a symlink points outside (usually dir itself or
+// one of its parents). The resolved paths of the directories being scanned are
+// tracked, so a symlink pointing to one of them (for example dir/back -> ..)
+// creates a loop and is ignored instead of causing an infinite recursion.
+// Unless the policy is NeverFollowSymlinks, a broken symlink is an error.
+func (p SymlinkPolicy) ReadDirRecursiveFiltered(root, dir *paths.Path, recursionFilter paths.ReadDirFilter, filters ...paths.ReadDirFilter) (paths.PathList, error) {
+	realDir, err := evalSymlinks(dir)
+	if err != nil {
+		return nil, err
+	}
+	ancestors := map[string]bool{realDir: true}
+	return p.readDirRecursiveFiltered(dir, realDir, ancestors, p.FilterSymlinks(root), recursionFilter, filters)
+}
+
+func (p SymlinkPolicy) readDirRecursiveFiltered(dir *paths.Path, realDir string, ancestors map[string]bool, symlinksFilter, recursionFilter paths.ReadDirFilter, filters []paths.ReadDirFilter) (paths.PathList, error) {
+	infos, err := os.ReadDir(dir.String())
+	if err != nil {
+		return nil, err
+	}
+
+	accept := func(p *paths.Path) bool {
+		for _, filter := range filters {
+			if !filter(p) {
+				return false
+			}
+		}
+		return true
+	}
+
+	res := paths.PathList{}
+	for _, info := range infos {
+		path := dir.Join(info.Name())
+		realPath := filepath.Join(realDir, info.Name())
+		if info.Type()&os.ModeSymlink != 0 {
+			if p != NeverFollowSymlinks {
+				// Like in paths.Path.ReadDirRecursiveFiltered a broken symlink is an error
+				if realPath, err = evalSymlinks(path); err != nil {
+					return nil, err
+				}
+			}
+			if !symlinksFilter(path) {
+				continue
+			}
+			if ancestors[realPath] {
+				logrus.WithField("path", path).WithField("target", realPath).Info("Skipping symlink creating a loop")
+				continue
+			}
+		}
+
+		if accept(path) {
+			res.Add(path)
+		}
+
+		if recursionFilter == nil || recursionFilter(path) {
+			if isDir, err := path.IsDirCheck(); err != nil {
+				return nil, err
+			} else if isDir {
+				ancestors[realPath] = true
+				subPaths, err := p.readDirRecursiveFiltered(path, realPath, ancestors, symlinksFilter, recursionFilter, filters)
+				delete(ancestors, realPath)
+				if err != nil {
+					return nil, err
+				}
+				res.AddAll(subPaths)
+			}
+		}
+	}
+	return res, nil
+}
+
+func isSymlink(path *paths.Path) bool {
+	info, err := os.Lstat(path.String())
+	return err == nil && info.Mode()&os.ModeSymlink != 0
+}
+
+func evalSymlinks(path *paths.Path) (string, error) {
+	abs, err := path.Abs()
+	if err != nil {
+		return "", err
+	}
+	return filepath.EvalSymlinks(abs.String())
+}
+
+func isWithinDir(path, dir string) bool {
+	rel, err := filepath.Rel(dir, path)
+	if err != nil {
+		return false
+	}
+	return rel == "." || (rel != ".." && !filepath.IsAbs(rel) && !startsWithParentDir(rel))
+}
+
+func startsWithParentDir(rel string) bool {
+	return len(rel) >= 3 && rel[:2] == ".." && os.IsPathSeparator(rel[2])
+}
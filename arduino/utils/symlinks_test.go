@@ -0,0 +1,150 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2023 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package utils
+
+import (
+	"os"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/arduino/go-paths-helper"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseSymlinkPolicy(t *testing.T) {
+	for _, policy := range []SymlinkPolicy{FollowSymlinksWithinRoot, NeverFollowSymlinks, FollowAllSymlinks} {
+		p, err := ParseSymlinkPolicy(policy.String())
+		require.NoError(t, err)
+		require.Equal(t, policy, p)
+	}
+	_, err := ParseSymlinkPolicy("sometimes")
+	require.Error(t, err)
+	var defaultPolicy SymlinkPolicy
+	require.Equal(t, FollowSymlinksWithinRoot, defaultPolicy)
+}
+
+func TestSymlinkPolicy(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("symlinks are not supported on windows")
+	}
+
+	// root/
+	//   dir/file.txt
+	//   alias -> dir
+	//   escape -> outside
+	// outside/outside.txt
+	tmp, err := paths.MkTempDir("", "symlinks")
+	require.NoError(t, err)
+	defer tmp.RemoveAll()
+	root := tmp.Join("root")
+	require.NoError(t, root.Join("dir").MkdirAll())
+	require.NoError(t, root.Join("dir", "file.txt").WriteFile([]byte{}))
+	require.NoError(t, os.Symlink("dir", root.Join("alias").String()))
+	outside := tmp.Join("outside")
+	require.NoError(t, outside.MkdirAll())
+	require.NoError(t, outside.Join("outside.txt").WriteFile([]byte{}))
+	require.NoError(t, os.Symlink(outside.String(), root.Join("escape").String()))
+
+	relative := func(list paths.PathList) []string {
+		res := []string{}
+		for _, p := range list {
+			rel, err := p.RelFrom(root)
+			require.NoError(t, err)
+			res = append(res, rel.String())
+		}
+		return res
+	}
+
+	t.Run("WithinRoot", func(t *testing.T) {
+		dirs, err := root.ReadDir(FollowSymlinksWithinRoot.FilterSymlinks(root))
+		require.NoError(t, err)
+		require.ElementsMatch(t, []string{"alias", "dir"}, relative(dirs))
+	})
+
+	t.Run("Never", func(t *testing.T) {
+		dirs, err := root.ReadDir(NeverFollowSymlinks.FilterSymlinks(root))
+		require.NoError(t, err)
+		require.ElementsMatch(t, []string{"dir"}, relative(dirs))
+	})
+
+	t.Run("All", func(t *testing.T) {
+		dirs, err := root.ReadDir(FollowAllSymlinks.FilterSymlinks(root))
+		require.NoError(t, err)
+		require.ElementsMatch(t, []string{"alias", "dir", "escape"}, relative(dirs))
+	})
+
+	t.Run("Recursive", func(t *testing.T) {
+		files, err := FollowSymlinksWithinRoot.ReadDirRecursiveFiltered(root, root, nil)
+		require.NoError(t, err)
+		require.ElementsMatch(t, []string{"dir", "dir/file.txt", "alias", "alias/file.txt"}, relative(files))
+
+		files, err = NeverFollowSymlinks.ReadDirRecursiveFiltered(root, root, nil)
+		require.NoError(t, err)
+		require.ElementsMatch(t, []string{"dir", "dir/file.txt"}, relative(files))
+
+		files, err = FollowAllSymlinks.ReadDirRecursiveFiltered(root, root, nil)
+		require.NoError(t, err)
+		require.ElementsMatch(t, []string{"dir", "dir/file.txt", "alias", "alias/file.txt", "escape", "escape/outside.txt"}, relative(files))
+	})
+
+	t.Run("DirectoryCycle", func(t *testing.T) {
+		// dir/back -> .. and dir/self -> . are followed once
+		back := root.Join("dir", "back")
+		require.NoError(t, os.Symlink("..", back.String()))
+		defer back.Remove()
+		self := root.Join("dir", "self")
+		require.NoError(t, os.Symlink(".", self.String()))
+		defer self.Remove()
+		for _, policy := range []SymlinkPolicy{FollowSymlinksWithinRoot, FollowAllSymlinks} {
+			done := make(chan struct{})
+			var files paths.PathList
+			var err error
+			go func() {
+				files, err = policy.ReadDirRecursiveFiltered(root, root, nil)
+				close(done)
+			}()
+			select {
+			case <-done:
+			case <-time.After(10 * time.Second):
+				require.FailNow(t, "the scan doesn't terminate", "policy %s", policy)
+			}
+			require.NoError(t, err)
+			require.NotContains(t, relative(files), "dir/back")
+			require.NotContains(t, relative(files), "dir/self")
+			require.Contains(t, relative(files), "alias/file.txt")
+			require.NotContains(t, relative(files), "alias/back")
+		}
+	})
+
+	t.Run("SelfLoop", func(t *testing.T) {
+		selfLoop := root.Join("self")
+		require.NoError(t, os.Symlink(selfLoop.String(), selfLoop.String()))
+		defer selfLoop.Remove()
+		for _, policy := range []SymlinkPolicy{FollowSymlinksWithinRoot, NeverFollowSymlinks, FollowAllSymlinks} {
+			dirs, err := root.ReadDir(policy.FilterSymlinks(root))
+			require.NoError(t, err)
+			require.NotContains(t, relative(dirs), "self")
+		}
+		_, err := FollowSymlinksWithinRoot.ReadDirRecursiveFiltered(root, root, nil)
+		require.Error(t, err)
+		_, err = FollowAllSymlinks.ReadDirRecursiveFiltered(root, root, nil)
+		require.Error(t, err)
+		files, err := NeverFollowSymlinks.ReadDirRecursiveFiltered(root, root, nil)
+		require.NoError(t, err)
+		require.NotContains(t, relative(files), "self")
+	})
+}
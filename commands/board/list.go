@@ -139,11 +139,13 @@ func identifyViaCloudAPI(props *properties.Map) ([]*rpc.BoardListItem, error) {
 	return cachedAPIByVidPid(props.Get("vid"), props.Get("pid"))
 }
 
-// identify returns a list of boards checking first the installed platforms or the Cloud API
-func identify(pme *packagemanager.Explorer, port *discovery.Port) ([]*rpc.BoardListItem, error) {
+// identify returns a list of boards checking first the installed platforms or the Cloud API,
+// falling back to the installed boards matching the USB VID/PID of the port with a wildcard.
+// It returns also the ranked list of the installed boards that may be the USB device.
+func identify(pme *packagemanager.Explorer, port *discovery.Port) ([]*rpc.BoardListItem, []*rpc.BoardCandidate, error) {
 	boards := []*rpc.BoardListItem{}
 	if port.Properties == nil {
-		return boards, nil
+		return boards, nil, nil
 	}
 
 	// first query installed cores through the Package Manager
@@ -151,7 +153,7 @@ func identify(pme *packagemanager.Explorer, port *discovery.Port) ([]*rpc.BoardL
 	for _, board := range pme.IdentifyBoard(port.Properties) {
 		fqbn, err := cores.ParseFQBN(board.FQBN())
 		if err != nil {
-			return nil, &arduino.InvalidFQBNError{Cause: err}
+			return nil, nil, &arduino.InvalidFQBNError{Cause: err}
 		}
 		fqbn.Configs = board.IdentifyBoardConfiguration(port.Properties)
 
@@ -178,6 +180,37 @@ func identify(pme *packagemanager.Explorer, port *discovery.Port) ([]*rpc.BoardL
 		boards = items
 	}
 
+	identified := len(boards) > 0
+	var candidates []*rpc.BoardCandidate
+	if port.Properties.ContainsKey("vid") && port.Properties.ContainsKey("pid") {
+		logrus.Debug("Querying installed cores for board candidates...")
+		candidates = []*rpc.BoardCandidate{}
+		for _, candidate := range pme.FindBoardCandidatesWithVidPid(port.Properties.Get("vid"), port.Properties.Get("pid")) {
+			candidates = append(candidates, &rpc.BoardCandidate{
+				Board: &rpc.BoardListItem{
+					Name:     candidate.Board.Name(),
+					Fqbn:     candidate.Board.FQBN(),
+					IsHidden: candidate.Board.IsHidden(),
+				},
+				Match:      candidate.Match.String(),
+				Confidence: candidate.Confidence,
+			})
+
+			// if the board is still unknown, use the boards declaring the VID/PID
+			// with a wildcard, but not the other boards of the same vendor
+			if !identified && candidate.Match != cores.UsbIDVendorMatch {
+				boards = append(boards, &rpc.BoardListItem{
+					Name:     candidate.Board.Name(),
+					Fqbn:     candidate.Board.FQBN(),
+					IsHidden: candidate.Board.IsHidden(),
+					Platform: &rpc.Platform{
+						Maintainer: candidate.Board.PlatformRelease.Platform.Package.Maintainer,
+					},
+				})
+			}
+		}
+	}
+
 	// Sort by FQBN alphabetically
 	sort.Slice(boards, func(i, j int) bool {
 		return strings.ToLower(boards[i].Fqbn) < strings.ToLower(boards[j].Fqbn)
@@ -196,7 +229,7 @@ func identify(pme *packagemanager.Explorer, port *discovery.Port) ([]*rpc.BoardL
 		board.Platform = nil
 	}
 
-	return boards, nil
+	return boards, candidates, nil
 }
 
 // List returns a list of boards found by the loaded discoveries.
@@ -212,7 +245,7 @@ func List(req *rpc.BoardListRequest) (r []*rpc.DetectedPort, discoveryStartError
 	var fqbnFilter *cores.FQBN
 	if f := req.GetFqbn(); f != "" {
 		var err error
-		fqbnFilter, err = cores.ParseFQBN(f)
+		fqbnFilter, err = pme.ParseFQBN(f)
 		if err != nil {
 			return nil, nil, &arduino.InvalidFQBNError{Cause: err}
 		}
@@ -224,7 +257,7 @@ func List(req *rpc.BoardListRequest) (r []*rpc.DetectedPort, discoveryStartError
 
 	retVal := []*rpc.DetectedPort{}
 	for _, port := range dm.List() {
-		boards, err := identify(pme, port)
+		boards, candidates, err := identify(pme, port)
 		if err != nil {
 			return nil, discoveryStartErrors, err
 		}
@@ -232,8 +265,9 @@ func List(req *rpc.BoardListRequest) (r []*rpc.DetectedPort, discoveryStartError
 		// boards slice can be empty at this point if neither the cores nor the
 		// API managed to recognize the connected board
 		b := &rpc.DetectedPort{
-			Port:           port.ToRPC(),
-			MatchingBoards: boards,
+			Port:            port.ToRPC(),
+			MatchingBoards:  boards,
+			BoardCandidates: candidates,
 		}
 
 		if fqbnFilter == nil || hasMatchingBoard(b, fqbnFilter) {
@@ -286,11 +320,12 @@ func Watch(ctx context.Context, req *rpc.BoardListWatchRequest) (<-chan *rpc.Boa
 
 			boardsError := ""
 			if event.Type == "add" {
-				boards, err := identify(pme, event.Port)
+				boards, candidates, err := identify(pme, event.Port)
 				if err != nil {
 					boardsError = err.Error()
 				}
 				port.MatchingBoards = boards
+				port.BoardCandidates = candidates
 			}
 			outChan <- &rpc.BoardListWatchResponse{
 				EventType: event.Type,
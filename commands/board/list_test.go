@@ -150,7 +150,7 @@ func TestBoardIdentifySorting(t *testing.T) {
 	pme, release := pm.NewExplorer()
 	defer release()
 
-	res, err := identify(pme, &discovery.Port{Properties: idPrefs})
+	res, _, err := identify(pme, &discovery.Port{Properties: idPrefs})
 	require.NoError(t, err)
 	require.NotNil(t, res)
 	require.Len(t, res, 4)
@@ -161,3 +161,67 @@ func TestBoardIdentifySorting(t *testing.T) {
 	require.Equal(t, res[2].Fqbn, "packager:platform:boardA")
 	require.Equal(t, res[3].Fqbn, "packager:platform:boardB")
 }
+
+func TestBoardIdentifyCandidates(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer ts.Close()
+	vidPidURL = ts.URL
+
+	dataDir := paths.New(t.TempDir())
+	pmb := packagemanager.NewBuilder(dataDir, dataDir, dataDir, dataDir, "test")
+	pack := pmb.GetOrCreatePackage("packager")
+	platformRelease := pack.GetOrCreatePlatform("platform").GetOrCreateRelease(semver.MustParse("0.0.0"))
+	platformRelease.InstallDir = dataDir
+	board := platformRelease.GetOrCreateBoard("exact")
+	board.Properties.Set("upload_port.vid", "0x1a86")
+	board.Properties.Set("upload_port.pid", "0x7523")
+	board = platformRelease.GetOrCreateBoard("family")
+	board.Properties.Set("upload_port.vid", "0x1a86")
+	board.Properties.Set("upload_port.pid", "0x80*")
+
+	pm := pmb.Build()
+	pme, release := pm.NewExplorer()
+	defer release()
+
+	type candidate struct {
+		Fqbn       string
+		Match      string
+		Confidence float64
+	}
+	identifyVidPid := func(vid, pid string) ([]string, []candidate) {
+		idPrefs := properties.NewMap()
+		idPrefs.Set("vid", vid)
+		idPrefs.Set("pid", pid)
+		res, candidates, err := identify(pme, &discovery.Port{Properties: idPrefs})
+		require.NoError(t, err)
+		fqbns := []string{}
+		for _, item := range res {
+			require.Nil(t, item.Platform)
+			fqbns = append(fqbns, item.Fqbn)
+		}
+		ranked := []candidate{}
+		for _, c := range candidates {
+			ranked = append(ranked, candidate{c.GetBoard().GetFqbn(), c.GetMatch(), c.GetConfidence()})
+		}
+		return fqbns, ranked
+	}
+
+	// Exact match
+	fqbns, candidates := identifyVidPid("0x1a86", "0x7523")
+	require.Equal(t, []string{"packager:platform:exact"}, fqbns)
+	require.Equal(t, []candidate{{"packager:platform:exact", "exact", 1}}, candidates)
+	// Wildcard PID declared by the board
+	fqbns, candidates = identifyVidPid("0x1a86", "0x8037")
+	require.Equal(t, []string{"packager:platform:family"}, fqbns)
+	require.Equal(t, []candidate{{"packager:platform:family", "wildcard", 0.75}}, candidates)
+	// Vendor only matches are not reported as matching boards
+	fqbns, candidates = identifyVidPid("0x1a86", "0x1234")
+	require.Empty(t, fqbns)
+	require.Equal(t, []candidate{{"packager:platform:exact", "vendor", 0.125}, {"packager:platform:family", "vendor", 0.125}}, candidates)
+	// Unknown vendor
+	fqbns, candidates = identifyVidPid("0x0000", "0x1234")
+	require.Empty(t, fqbns)
+	require.Empty(t, candidates)
+}
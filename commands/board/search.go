@@ -17,16 +17,15 @@ package board
 
 import (
 	"context"
-	"sort"
-	"strings"
 
 	"github.com/arduino/arduino-cli/arduino"
-	"github.com/arduino/arduino-cli/arduino/utils"
+	"github.com/arduino/arduino-cli/arduino/cores"
 	"github.com/arduino/arduino-cli/commands"
 	rpc "github.com/arduino/arduino-cli/rpc/cc/arduino/cli/commands/v1"
 )
 
-// Search returns all boards that match the search arg.
+// Search returns all boards that match the search arg, sorted by relevance
+// (see packagemanager.SearchBoards).
 // Boards are searched in all platforms, including those in the index that are not yet
 // installed. Note that platforms that are not installed don't include boards' FQBNs.
 // If no search argument is used all boards are returned.
@@ -37,78 +36,45 @@ func Search(ctx context.Context, req *rpc.BoardSearchRequest) (*rpc.BoardSearchR
 	}
 	defer release()
 
-	res := &rpc.BoardSearchResponse{Boards: []*rpc.BoardListItem{}}
-	for _, targetPackage := range pme.GetPackages() {
-		for _, platform := range targetPackage.Platforms {
-			latestPlatformRelease := platform.GetLatestRelease()
-			installedPlatformRelease := pme.GetInstalledPlatformRelease(platform)
-
-			if latestPlatformRelease == nil && installedPlatformRelease == nil {
-				continue
-			}
-
-			rpcPlatform := &rpc.Platform{
-				Id:                platform.String(),
-				Name:              platform.Name,
-				Maintainer:        platform.Package.Maintainer,
-				Website:           platform.Package.WebsiteURL,
-				Email:             platform.Package.Email,
-				ManuallyInstalled: platform.ManuallyInstalled,
-				Indexed:           platform.Indexed,
-			}
-
-			if latestPlatformRelease != nil {
-				rpcPlatform.Latest = latestPlatformRelease.Version.String()
-			}
-			if installedPlatformRelease != nil {
-				rpcPlatform.Installed = installedPlatformRelease.Version.String()
-				rpcPlatform.MissingMetadata = !installedPlatformRelease.HasMetadata()
-			}
-
-			// Platforms that are not installed don't have a list of boards
-			// generated from their boards.txt file so we need two different
-			// ways of reading board data.
-			// The only boards information for platforms that are not installed
-			// is that found in the index, usually that's only a board name.
-			if installedPlatformRelease != nil {
-				for _, board := range installedPlatformRelease.Boards {
-					if !req.GetIncludeHiddenBoards() && board.IsHidden() {
-						continue
-					}
-
-					toTest := append(strings.Split(board.Name(), " "), board.Name(), board.FQBN())
-					if !utils.MatchAny(req.GetSearchArgs(), toTest) {
-						continue
-					}
-
-					res.Boards = append(res.Boards, &rpc.BoardListItem{
-						Name:     board.Name(),
-						Fqbn:     board.FQBN(),
-						IsHidden: board.IsHidden(),
-						Platform: rpcPlatform,
-					})
-				}
-			} else if latestPlatformRelease != nil {
-				for _, board := range latestPlatformRelease.BoardsManifest {
-					toTest := append(strings.Split(board.Name, " "), board.Name)
-					if !utils.MatchAny(req.GetSearchArgs(), toTest) {
-						continue
-					}
-
-					res.Boards = append(res.Boards, &rpc.BoardListItem{
-						Name:     strings.Trim(board.Name, " \n"),
-						Platform: rpcPlatform,
-					})
-				}
-			}
+	rpcPlatforms := map[*cores.Platform]*rpc.Platform{}
+	toRPCPlatform := func(platform *cores.Platform) *rpc.Platform {
+		if rpcPlatform, ok := rpcPlatforms[platform]; ok {
+			return rpcPlatform
+		}
+		rpcPlatform := &rpc.Platform{
+			Id:                platform.String(),
+			Name:              platform.Name,
+			Maintainer:        platform.Package.Maintainer,
+			Website:           platform.Package.WebsiteURL,
+			Email:             platform.Package.Email,
+			ManuallyInstalled: platform.ManuallyInstalled,
+			Indexed:           platform.Indexed,
+		}
+		if latestPlatformRelease := platform.GetLatestRelease(); latestPlatformRelease != nil {
+			rpcPlatform.Latest = latestPlatformRelease.Version.String()
+		}
+		if installedPlatformRelease := pme.GetInstalledPlatformRelease(platform); installedPlatformRelease != nil {
+			rpcPlatform.Installed = installedPlatformRelease.Version.String()
+			rpcPlatform.MissingMetadata = !installedPlatformRelease.HasMetadata()
 		}
+		rpcPlatforms[platform] = rpcPlatform
+		return rpcPlatform
 	}
 
-	sort.Slice(res.Boards, func(i, j int) bool {
-		if res.Boards[i].Name != res.Boards[j].Name {
-			return res.Boards[i].Name < res.Boards[j].Name
+	res := &rpc.BoardSearchResponse{Boards: []*rpc.BoardListItem{}}
+	for _, found := range pme.SearchBoards(req.GetSearchArgs(), req.GetIncludeHiddenBoards()) {
+		item := &rpc.BoardListItem{
+			Name:     found.Name,
+			Platform: toRPCPlatform(found.PlatformRelease.Platform),
+		}
+		// Platforms that are not installed don't have a list of boards
+		// generated from their boards.txt file, the only boards information
+		// is that found in the index, usually that's only a board name.
+		if found.Board != nil {
+			item.Fqbn = found.Board.FQBN()
+			item.IsHidden = found.Board.IsHidden()
 		}
-		return res.Boards[i].Platform.Id < res.Boards[j].Platform.Id
-	})
+		res.Boards = append(res.Boards, item)
+	}
 	return res, nil
 }
@@ -25,7 +25,7 @@ import (
 
 	"github.com/arduino/arduino-cli/arduino"
 	"github.com/arduino/arduino-cli/arduino/builder"
-	"github.com/arduino/arduino-cli/arduino/cores"
+	"github.com/arduino/arduino-cli/arduino/cores/packagemanager"
 	"github.com/arduino/arduino-cli/arduino/libraries/librariesmanager"
 	"github.com/arduino/arduino-cli/arduino/sketch"
 	"github.com/arduino/arduino-cli/arduino/utils"
@@ -90,7 +90,7 @@ func Compile(ctx context.Context, req *rpc.CompileRequest, outStream, errStream
 		return nil, &arduino.MissingFQBNError{}
 	}
 
-	fqbn, err := cores.ParseFQBN(fqbnIn)
+	fqbn, err := pme.ParseFQBN(fqbnIn)
 	if err != nil {
 		return nil, &arduino.InvalidFQBNError{Cause: err}
 	}
@@ -102,6 +102,13 @@ func Compile(ctx context.Context, req *rpc.CompileRequest, outStream, errStream
 				Cause:    fmt.Errorf(tr("platform not installed")),
 			}
 		}
+		var resolutionErr *packagemanager.FQBNResolutionError
+		if errors.Is(err, packagemanager.ErrReferencedPlatformNotInstalled) && errors.As(err, &resolutionErr) {
+			return nil, &arduino.PlatformNotFoundError{
+				Platform: resolutionErr.ReferencedPlatform,
+				Cause:    err,
+			}
+		}
 		return nil, &arduino.InvalidFQBNError{Cause: err}
 	}
 
@@ -211,6 +218,9 @@ func Compile(ctx context.Context, req *rpc.CompileRequest, outStream, errStream
 		}
 		return r, &arduino.CompileFailedError{Message: err.Error()}
 	}
+	sketchBuilder.SetArchitectureFamilies(lm.ArchitectureFamilies())
+	sketchBuilder.SetSymlinkPolicy(lm.SymlinkPolicy())
+	sketchBuilder.SetMapFileParsing(req.GetParseMapFile())
 
 	defer func() {
 		if p := sketchBuilder.GetBuildPath(); p != nil {
@@ -286,6 +296,12 @@ func Compile(ctx context.Context, req *rpc.CompileRequest, outStream, errStream
 			tr("Warning: Board %[1]s doesn't define a %[2]s preference. Auto-set to: %[3]s",
 				targetBoard.String(), "'build.board'", sketchBuilder.GetBuildProperties().Get("build.board")) + "\n"))
 	}
+	for _, deprecation := range packagemanager.FindPlatformDeprecations(targetPlatform, buildPlatform) {
+		outStream.Write([]byte(tr("Warning: %s", deprecation) + "\n"))
+	}
+	for _, deprecated := range packagemanager.FindDeprecatedBuildProperties(sketchBuilder.GetBuildProperties()) {
+		outStream.Write([]byte(tr("Warning: %s", deprecated) + "\n"))
+	}
 
 	if err := sketchBuilder.Build(); err != nil {
 		return r, &arduino.CompileFailedError{Message: err.Error()}
@@ -344,6 +360,7 @@ func Compile(ctx context.Context, req *rpc.CompileRequest, outStream, errStream
 	}
 
 	r.ExecutableSectionsSize = sketchBuilder.ExecutableSectionsSize().ToRPCExecutableSectionSizeArray()
+	r.MapFile = sketchBuilder.MapFile().ToRPC()
 
 	logrus.Tracef("Compile %s for %s successful", sk.Name, fqbnIn)
 
@@ -0,0 +1,58 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2023 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package compile
+
+import (
+	"fmt"
+
+	"github.com/arduino/arduino-cli/arduino"
+	"github.com/arduino/arduino-cli/arduino/builder"
+	"github.com/arduino/arduino-cli/arduino/cores/packagemanager"
+	"github.com/arduino/arduino-cli/configuration"
+	paths "github.com/arduino/go-paths-helper"
+)
+
+// LibrariesSearchOrder returns the directories searched for libraries when
+// compiling for the given FQBN, in the same order used by Compile.
+// librariesDirs and libraryDirs are the values of the --libraries and
+// --library flags.
+func LibrariesSearchOrder(pme *packagemanager.Explorer, fqbnIn string, librariesDirs, libraryDirs []string) (paths.PathList, error) {
+	if fqbnIn == "" {
+		return nil, &arduino.MissingFQBNError{}
+	}
+	fqbn, err := pme.ParseFQBN(fqbnIn)
+	if err != nil {
+		return nil, &arduino.InvalidFQBNError{Cause: err}
+	}
+	_, targetPlatform, _, _, buildPlatform, err := pme.ResolveFQBN(fqbn)
+	if err != nil {
+		if targetPlatform == nil {
+			return nil, &arduino.PlatformNotFoundError{
+				Platform: fmt.Sprintf("%s:%s", fqbn.Package, fqbn.PlatformArch),
+				Cause:    fmt.Errorf(tr("platform not installed")),
+			}
+		}
+		return nil, &arduino.InvalidFQBNError{Cause: err}
+	}
+
+	otherLibrariesDirs := paths.NewPathList(librariesDirs...)
+	otherLibrariesDirs.Add(configuration.LibrariesDir(configuration.Settings))
+	return builder.LibrariesSearchOrder(
+		configuration.IDEBuiltinLibrariesDir(configuration.Settings),
+		paths.NewPathList(libraryDirs...),
+		otherLibrariesDirs,
+		buildPlatform, targetPlatform), nil
+}
@@ -61,6 +61,9 @@ func PlatformReleaseToRPC(platformRelease *cores.PlatformRelease) *rpc.Platform
 		Latest:            platformRelease.Version.String(),
 		ManuallyInstalled: platformRelease.Platform.ManuallyInstalled,
 		Deprecated:        platformRelease.Platform.Deprecated,
+		EndOfLife:         platformRelease.Platform.EndOfLifeDate(),
+		EndOfLifeReached:  platformRelease.Platform.EndOfLifeReached(),
+		ReplacedBy:        platformRelease.Platform.ReplacedBy,
 		Type:              []string{platformRelease.Platform.Category},
 		Indexed:           platformRelease.Platform.Indexed,
 		MissingMetadata:   !platformRelease.HasMetadata(),
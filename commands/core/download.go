@@ -50,15 +50,9 @@ func PlatformDownload(ctx context.Context, req *rpc.PlatformDownloadRequest, dow
 		return nil, &arduino.PlatformNotFoundError{Platform: ref.String(), Cause: err}
 	}
 
-	if err := pme.DownloadPlatformRelease(platform, nil, downloadCB); err != nil {
+	if err := pme.DownloadPlatformAndTools(platform, tools, nil, downloadCB); err != nil {
 		return nil, err
 	}
 
-	for _, tool := range tools {
-		if err := pme.DownloadToolRelease(tool, nil, downloadCB); err != nil {
-			return nil, err
-		}
-	}
-
 	return &rpc.PlatformDownloadResponse{}, nil
 }
@@ -20,9 +20,11 @@ import (
 	"fmt"
 
 	"github.com/arduino/arduino-cli/arduino"
+	"github.com/arduino/arduino-cli/arduino/cores"
 	"github.com/arduino/arduino-cli/arduino/cores/packagemanager"
 	"github.com/arduino/arduino-cli/commands"
 	rpc "github.com/arduino/arduino-cli/rpc/cc/arduino/cli/commands/v1"
+	"github.com/arduino/go-paths-helper"
 )
 
 // PlatformInstall FIXMEDOC
@@ -34,6 +36,12 @@ func PlatformInstall(ctx context.Context, req *rpc.PlatformInstallRequest, downl
 		}
 		defer release()
 
+		unlock, err := pme.LockPackages(taskCB)
+		if err != nil {
+			return err
+		}
+		defer unlock()
+
 		version, err := commands.ParseVersion(req)
 		if err != nil {
 			return &arduino.InvalidVersionError{Cause: err}
@@ -52,7 +60,7 @@ func PlatformInstall(ctx context.Context, req *rpc.PlatformInstallRequest, downl
 		// Prerequisite checks before install
 		if platformRelease.IsInstalled() {
 			taskCB(&rpc.TaskProgress{Name: tr("Platform %s already installed", platformRelease), Completed: true})
-			return nil
+			return updatePlatformPin(pme, platformRelease, version != nil)
 		}
 
 		if req.GetNoOverwrite() {
@@ -67,7 +75,7 @@ func PlatformInstall(ctx context.Context, req *rpc.PlatformInstallRequest, downl
 			return err
 		}
 
-		return nil
+		return updatePlatformPin(pme, platformRelease, version != nil)
 	}
 
 	if err := install(); err != nil {
@@ -78,3 +86,95 @@ func PlatformInstall(ctx context.Context, req *rpc.PlatformInstallRequest, downl
 	}
 	return &rpc.PlatformInstallResponse{}, nil
 }
+
+// PlatformInstallPlan resolves the platform release and the tools that
+// PlatformInstall would download and install for the given request, together
+// with the download size and the disk space required, without downloading or
+// installing anything.
+func PlatformInstallPlan(ctx context.Context, req *rpc.PlatformInstallRequest) (*packagemanager.InstallPlan, error) {
+	pme, release := commands.GetPackageManagerExplorer(req)
+	if pme == nil {
+		return nil, &arduino.InvalidInstanceError{}
+	}
+	defer release()
+
+	version, err := commands.ParseVersion(req)
+	if err != nil {
+		return nil, &arduino.InvalidVersionError{Cause: err}
+	}
+
+	ref := &packagemanager.PlatformReference{
+		Package:              req.PlatformPackage,
+		PlatformArchitecture: req.Architecture,
+		PlatformVersion:      version,
+	}
+	platformRelease, tools, err := pme.FindPlatformReleaseDependencies(ref)
+	if err != nil {
+		return nil, &arduino.PlatformNotFoundError{Platform: ref.String(), Cause: err}
+	}
+
+	if req.GetNoOverwrite() && !platformRelease.IsInstalled() {
+		if installed := pme.GetInstalledPlatformRelease(platformRelease.Platform); installed != nil {
+			return nil, fmt.Errorf("%s: %s",
+				tr("Platform %s already installed", installed),
+				tr("could not overwrite"))
+		}
+	}
+
+	return pme.PlanPlatformInstall(platformRelease, tools)
+}
+
+// updatePlatformPin pins the platform to the given release if a specific
+// version has been requested, otherwise it removes the pin so the platform
+// follows the upgrades again.
+func updatePlatformPin(pme *packagemanager.Explorer, platformRelease *cores.PlatformRelease, pin bool) error {
+	if pin {
+		return pme.PinPlatform(platformRelease.Platform, platformRelease.Version)
+	}
+	return pme.UnpinPlatform(platformRelease.Platform)
+}
+
+// PlatformInstallFromPath installs the platform contained in the given local
+// directory or archive as the platform specified in the request. The requested
+// version is ignored: the platform is registered with the synthetic version
+// computed by packagemanager.InstallLocalPlatform.
+func PlatformInstallFromPath(ctx context.Context, req *rpc.PlatformInstallRequest, source *paths.Path, taskCB rpc.TaskProgressCB) (*rpc.PlatformInstallResponse, error) {
+	return platformInstallWithExplorer(req, taskCB, func(pme *packagemanager.Explorer) error {
+		_, err := pme.InstallLocalPlatform(ctx, req.GetPlatformPackage(), req.GetArchitecture(), source, !req.GetNoOverwrite(), taskCB, req.GetSkipPostInstall())
+		return err
+	})
+}
+
+// PlatformInstallFromGit installs the platform hosted on the given git repository
+// as the platform specified in the request, see PlatformInstallFromPath. A branch,
+// tag or commit may be selected with the URL fragment (ex: https://github.com/vendor/core.git#v2.1.0).
+func PlatformInstallFromGit(ctx context.Context, req *rpc.PlatformInstallRequest, gitURL string, taskCB rpc.TaskProgressCB) (*rpc.PlatformInstallResponse, error) {
+	return platformInstallWithExplorer(req, taskCB, func(pme *packagemanager.Explorer) error {
+		_, err := pme.InstallGitPlatform(ctx, req.GetPlatformPackage(), req.GetArchitecture(), gitURL, !req.GetNoOverwrite(), taskCB, req.GetSkipPostInstall())
+		return err
+	})
+}
+
+// platformInstallWithExplorer runs the given install function, holding the
+// packages lock, and then reloads the instance
+func platformInstallWithExplorer(req *rpc.PlatformInstallRequest, taskCB rpc.TaskProgressCB, install func(pme *packagemanager.Explorer) error) (*rpc.PlatformInstallResponse, error) {
+	pme, release := commands.GetPackageManagerExplorer(req)
+	if pme == nil {
+		return nil, &arduino.InvalidInstanceError{}
+	}
+	unlock, err := pme.LockPackages(taskCB)
+	if err != nil {
+		release()
+		return nil, err
+	}
+	err = install(pme)
+	unlock()
+	release()
+	if err != nil {
+		return nil, err
+	}
+	if err := commands.Init(&rpc.InitRequest{Instance: req.Instance}, nil); err != nil {
+		return nil, err
+	}
+	return &rpc.PlatformInstallResponse{}, nil
+}
@@ -35,44 +35,37 @@ func PlatformList(req *rpc.PlatformListRequest) (*rpc.PlatformListResponse, erro
 	defer release()
 
 	res := []*rpc.Platform{}
-	for _, targetPackage := range pme.GetPackages() {
-		for _, platform := range targetPackage.Platforms {
-			platformRelease := pme.GetInstalledPlatformRelease(platform)
-
-			// The All flags adds to the list of installed platforms the installable platforms (from the indexes)
-			// If both All and UpdatableOnly are set All takes precedence
-			if req.All {
-				installedVersion := ""
-				if platformRelease == nil { // if the platform is not installed
-					platformRelease = platform.GetLatestRelease()
-				} else {
-					installedVersion = platformRelease.Version.String()
-				}
-				// it could happen, especially with indexes not perfectly compliant with specs that a platform do not contain a valid release
-				if platformRelease != nil {
-					rpcPlatform := commands.PlatformReleaseToRPC(platformRelease)
-					rpcPlatform.Installed = installedVersion
-					res = append(res, rpcPlatform)
-					continue
-				}
+	for _, entry := range pme.Inventory() {
+		// The All flags adds to the list of installed platforms the installable platforms (from the indexes)
+		// If both All and UpdatableOnly are set All takes precedence
+		if req.All {
+			installedVersion := ""
+			platformRelease := entry.Installed
+			if platformRelease == nil { // if the platform is not installed
+				platformRelease = entry.Latest
+			} else {
+				installedVersion = platformRelease.Version.String()
 			}
+			rpcPlatform := commands.PlatformReleaseToRPC(platformRelease)
+			rpcPlatform.Installed = installedVersion
+			res = append(res, rpcPlatform)
+			continue
+		}
 
-			if platformRelease != nil {
-				latest := platform.GetLatestRelease()
-				if latest == nil {
-					return nil, &arduino.PlatformNotFoundError{Platform: platform.String(), Cause: fmt.Errorf(tr("the platform has no releases"))}
-				}
-
-				// show only the updatable platforms
-				if req.UpdatableOnly && latest == platformRelease {
-					continue
-				}
+		if entry.Installed != nil {
+			if entry.Latest == nil {
+				return nil, &arduino.PlatformNotFoundError{Platform: entry.Platform.String(), Cause: fmt.Errorf(tr("the platform has no releases"))}
+			}
 
-				rpcPlatform := commands.PlatformReleaseToRPC(platformRelease)
-				rpcPlatform.Installed = platformRelease.Version.String()
-				rpcPlatform.Latest = latest.Version.String()
-				res = append(res, rpcPlatform)
+			// show only the updatable platforms
+			if req.UpdatableOnly && !entry.Updatable {
+				continue
 			}
+
+			rpcPlatform := commands.PlatformReleaseToRPC(entry.Installed)
+			rpcPlatform.Installed = entry.Installed.Version.String()
+			rpcPlatform.Latest = entry.Latest.Version.String()
+			res = append(res, rpcPlatform)
 		}
 	}
 	// Sort result alphabetically and put deprecated platforms at the bottom
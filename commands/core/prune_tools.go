@@ -0,0 +1,72 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2023 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package core
+
+import (
+	"context"
+
+	"github.com/arduino/arduino-cli/arduino"
+	"github.com/arduino/arduino-cli/arduino/cores"
+	"github.com/arduino/arduino-cli/commands"
+	rpc "github.com/arduino/arduino-cli/rpc/cc/arduino/cli/commands/v1"
+)
+
+// PruneTools uninstalls the tools that are no more required by any installed
+// platform and returns their ids (PACKAGER:TOOL@VERSION). If dryRun is true the
+// tools are only listed and nothing is uninstalled.
+func PruneTools(ctx context.Context, instance *rpc.Instance, dryRun bool, skipPreUninstall bool, taskCB rpc.TaskProgressCB) (_ []string, returnedErr error) {
+	req := &rpc.InitRequest{Instance: instance}
+	pme, release := commands.GetPackageManagerExplorer(req)
+	if pme == nil {
+		return nil, &arduino.InvalidInstanceError{}
+	}
+	if dryRun {
+		defer release()
+		return toolReleasesIDs(pme.FindOrphanTools()), nil
+	}
+
+	// Reload the installed tools once done, even if the pruning stopped
+	// midway, so the instance doesn't keep the removed tools in memory
+	var pruned []*cores.ToolRelease
+	defer func() {
+		if len(pruned) == 0 {
+			return
+		}
+		if err := commands.Init(req, nil); err != nil && returnedErr == nil {
+			returnedErr = err
+		}
+	}()
+	defer release()
+
+	unlock, err := pme.LockPackages(taskCB)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+	pruned, err = pme.PruneTools(taskCB, skipPreUninstall)
+	if err != nil {
+		return nil, err
+	}
+	return toolReleasesIDs(pruned), nil
+}
+
+func toolReleasesIDs(tools []*cores.ToolRelease) []string {
+	res := []string{}
+	for _, tool := range tools {
+		res = append(res, tool.String())
+	}
+	return res
+}
@@ -0,0 +1,73 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2023 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package core
+
+import (
+	"context"
+
+	"github.com/arduino/arduino-cli/arduino"
+	"github.com/arduino/arduino-cli/arduino/cores/packagemanager"
+	"github.com/arduino/arduino-cli/commands"
+	rpc "github.com/arduino/arduino-cli/rpc/cc/arduino/cli/commands/v1"
+	semver "go.bug.st/relaxed-semver"
+)
+
+// PlatformRollback restores one of the previous releases, kept on disk, of the
+// given platform: the given version or, if empty, the most recently replaced
+// one. The restored version is returned, the platform is pinned to it.
+func PlatformRollback(ctx context.Context, instance *rpc.Instance, packager, architecture, version string, downloadCB rpc.DownloadProgressCB, taskCB rpc.TaskProgressCB) (string, error) {
+	req := &rpc.InitRequest{Instance: instance}
+	rollback := func() (*semver.Version, error) {
+		pme, release := commands.GetPackageManagerExplorer(req)
+		if pme == nil {
+			return nil, &arduino.InvalidInstanceError{}
+		}
+		defer release()
+
+		unlock, err := pme.LockPackages(taskCB)
+		if err != nil {
+			return nil, err
+		}
+		defer unlock()
+
+		ref := &packagemanager.PlatformReference{
+			Package:              packager,
+			PlatformArchitecture: architecture,
+		}
+		platform := pme.FindPlatform(ref)
+		if platform == nil {
+			return nil, &arduino.PlatformNotFoundError{Platform: ref.String()}
+		}
+		var requestedVersion *semver.Version
+		if version != "" {
+			v, err := semver.Parse(version)
+			if err != nil {
+				return nil, &arduino.InvalidVersionError{Cause: err}
+			}
+			requestedVersion = v
+		}
+		return pme.RollbackPlatform(platform, requestedVersion, downloadCB, taskCB)
+	}
+
+	restored, err := rollback()
+	if err != nil {
+		return "", err
+	}
+	if err := commands.Init(req, nil); err != nil {
+		return "", err
+	}
+	return restored.String(), nil
+}
@@ -40,7 +40,7 @@ func PlatformSearch(req *rpc.PlatformSearchRequest) (*rpc.PlatformSearchResponse
 		vid, pid := req.SearchArgs[:4], req.SearchArgs[5:]
 		res = pme.FindPlatformReleaseProvidingBoardsWithVidPid(vid, pid)
 	} else {
-		searchArgs := utils.SearchTermsFromQueryString(req.SearchArgs)
+		matcher := utils.NewMatcher(req.SearchArgs)
 		allVersions := req.AllVersions
 		for _, targetPackage := range pme.GetPackages() {
 			for _, platform := range targetPackage.Platforms {
@@ -69,7 +69,7 @@ func PlatformSearch(req *rpc.PlatformSearchRequest) (*rpc.PlatformSearchResponse
 				}
 
 				// Search
-				if !utils.Match(toTest, searchArgs) {
+				if !matcher.Match(toTest) {
 					continue
 				}
 
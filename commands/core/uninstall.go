@@ -43,6 +43,12 @@ func platformUninstall(ctx context.Context, req *rpc.PlatformUninstallRequest, t
 	}
 	defer release()
 
+	unlock, err := pme.LockPackages(taskCB)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
 	ref := &packagemanager.PlatformReference{
 		Package:              req.PlatformPackage,
 		PlatformArchitecture: req.Architecture,
@@ -67,6 +73,12 @@ func platformUninstall(ctx context.Context, req *rpc.PlatformUninstallRequest, t
 	if err := pme.UninstallPlatform(platform, taskCB, req.GetSkipPreUninstall()); err != nil {
 		return err
 	}
+	if err := pme.UnpinPlatform(platform.Platform); err != nil {
+		return err
+	}
+	if err := pme.UninstallPreviousPlatformReleases(platform.Platform, taskCB, req.GetSkipPreUninstall()); err != nil {
+		return err
+	}
 
 	for _, tool := range tools {
 		if !pme.IsToolRequired(tool) {
@@ -35,6 +35,12 @@ func PlatformUpgrade(ctx context.Context, req *rpc.PlatformUpgradeRequest, downl
 		}
 		defer release()
 
+		unlock, err := pme.LockPackages(taskCB)
+		if err != nil {
+			return nil, err
+		}
+		defer unlock()
+
 		// Extract all PlatformReference to platforms that have updates
 		ref := &packagemanager.PlatformReference{
 			Package:              req.PlatformPackage,
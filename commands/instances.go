@@ -46,6 +46,14 @@ import (
 
 var tr = i18n.Tr
 
+// packageIndexesJobs is the maximum number of package indexes downloaded or
+// loaded at the same time
+const packageIndexesJobs = 8
+
+// defaultParallelDownloads is the maximum number of platform and tool archives
+// downloaded at the same time, if not set with network.parallel_downloads.
+const defaultParallelDownloads = 4
+
 // CoreInstance is an instance of the Arduino Core Services. The user can
 // instantiate as many as needed by providing a different configuration
 // for each one.
@@ -167,22 +175,35 @@ func Create(req *rpc.CreateRequest, extraUserAgent ...string) (*rpc.CreateRespon
 		}
 	}
 
+	// Setup how symlinks are followed while scanning the hardware and libraries directories
+	symlinksPolicy, err := symlinksPolicyFromSettings()
+	if err != nil {
+		return nil, err
+	}
+
 	// Create package manager
 	userAgent := "arduino-cli/" + version.VersionInfo.VersionString
 	for _, ua := range extraUserAgent {
 		userAgent += " " + ua
 	}
-	instance.pm = packagemanager.NewBuilder(
+	pmb := packagemanager.NewBuilder(
 		dataDir,
 		configuration.PackagesDir(configuration.Settings),
 		downloadsDir,
 		dataDir.Join("tmp"),
 		userAgent,
-	).Build()
+	)
+	// The lock timeout is needed by the first update of the indexes, done before Init
+	pmb.SetPackagesLockTimeout(configuration.Settings.GetDuration("board_manager.lock_timeout"))
+	pmb.SetSymlinkPolicy(symlinksPolicy)
+	instance.pm = pmb.Build()
 	instance.lm = librariesmanager.NewLibraryManager(
 		dataDir,
 		downloadsDir,
 	)
+	instance.lm.SetSymlinkPolicy(symlinksPolicy)
+	instance.lm.SetArchiveSignatureVerification(archiveSignatureVerificationFromSettings())
+	instance.lm.SetArchitectureFamilies(configuration.Settings.GetStringMapStringSlice("library.architecture_families"))
 
 	// Save instance
 	instanceID := instances.AddAndAssignID(instance)
@@ -192,6 +213,46 @@ func Create(req *rpc.CreateRequest, extraUserAgent ...string) (*rpc.CreateRespon
 	}, nil
 }
 
+// symlinksPolicyFromSettings returns the policy used to follow the symlinks
+// found while scanning the hardware and libraries directories.
+func symlinksPolicyFromSettings() (utils.SymlinkPolicy, error) {
+	policy := configuration.Settings.GetString("directories.symlinks_policy")
+	if policy == "" {
+		return utils.FollowSymlinksWithinRoot, nil
+	}
+	p, err := utils.ParseSymlinkPolicy(policy)
+	if err != nil {
+		return utils.FollowSymlinksWithinRoot, &arduino.InvalidArgumentError{Message: tr("Invalid directories.symlinks_policy setting"), Cause: err}
+	}
+	return p, nil
+}
+
+// archiveSignatureVerificationFromSettings returns the settings used to verify
+// the signatures of the downloaded archives.
+func archiveSignatureVerificationFromSettings() resources.ArchiveSignatureVerification {
+	settings := resources.ArchiveSignatureVerification{
+		Strict: configuration.Settings.GetBool("security.require_signed_archives"),
+	}
+	if keyring := configuration.Settings.GetString("security.archives_keyring"); keyring != "" {
+		settings.Keyring = paths.New(keyring)
+	}
+	return settings
+}
+
+// indexSignatureVerificationFromSettings returns the settings used to verify
+// the signatures of the package indexes. The signatures are required only if
+// some trusted keys are set.
+func indexSignatureVerificationFromSettings() packageindex.SignatureVerification {
+	settings := packageindex.SignatureVerification{
+		SkipCheck: configuration.Settings.GetBool("security.skip_index_signature_check"),
+	}
+	if trustedKeys := configuration.Settings.GetString("security.trusted_index_keys"); trustedKeys != "" {
+		settings.TrustedKeys = paths.New(trustedKeys)
+		settings.RequireSignature = configuration.Settings.GetBool("security.require_signed_indexes")
+	}
+	return settings
+}
+
 // Init loads installed libraries and Platforms in CoreInstance with specified ID,
 // a gRPC status error is returned if the CoreInstance doesn't exist.
 // All responses are sent through responseCallback, can be nil to ignore all responses.
@@ -240,6 +301,12 @@ func Init(req *rpc.InitRequest, responseCallback func(r *rpc.InitResponse)) erro
 		})
 	}
 
+	// The settings may have changed since the instance was created
+	symlinksPolicy, err := symlinksPolicyFromSettings()
+	if err != nil {
+		return err
+	}
+
 	// Try to extract profile if specified
 	var profile *sketch.Profile
 	if req.GetProfile() != "" {
@@ -297,22 +364,44 @@ func Init(req *rpc.InitRequest, responseCallback func(r *rpc.InitResponse)) erro
 		// even if it should not.
 		pmb, commitPackageManager := instance.pm.NewBuilder()
 
-		// Load packages index
-		for _, URL := range allPackageIndexUrls {
-			if URL.Scheme == "file" {
-				_, err := pmb.LoadPackageIndexFromFile(paths.New(URL.Path))
-				if err != nil {
-					e := &arduino.InitFailedError{
-						Code:   codes.FailedPrecondition,
-						Cause:  fmt.Errorf(tr("Loading index file: %v", err)),
-						Reason: rpc.FailedInstanceInitReason_FAILED_INSTANCE_INIT_REASON_INDEX_LOAD_ERROR,
-					}
-					responseError(e.ToRPCStatus())
-				}
-				continue
-			}
+		// Load FQBN aliases
+		for alias, fqbn := range configuration.Settings.GetStringMapString("board_manager.fqbn_aliases") {
+			pmb.AddFQBNAlias(alias, fqbn)
+		}
+
+		// Number of replaced platform releases kept for a rollback
+		pmb.SetKeepPreviousReleases(configuration.Settings.GetInt("board_manager.keep_previous_releases"))
+
+		// Number of archives downloaded at the same time
+		parallelDownloads := configuration.Settings.GetInt("network.parallel_downloads")
+		if parallelDownloads <= 0 {
+			parallelDownloads = defaultParallelDownloads
+		}
+		pmb.SetParallelDownloads(parallelDownloads)
+
+		// How long to wait for another process changing the installed platforms
+		pmb.SetPackagesLockTimeout(configuration.Settings.GetDuration("board_manager.lock_timeout"))
 
-			if err := pmb.LoadPackageIndex(URL); err != nil {
+		// Symlinks followed while scanning the hardware directories
+		pmb.SetSymlinkPolicy(symlinksPolicy)
+
+		// Signature verification of the package indexes and of the downloaded archives
+		pmb.SetIndexSignatureVerification(indexSignatureVerificationFromSettings())
+		pmb.SetArchiveSignatureVerification(archiveSignatureVerificationFromSettings())
+
+		// Maximum size of the package indexes
+		pmb.SetMaxIndexSize(configuration.Settings.GetInt64("board_manager.max_index_size"))
+
+		// Execution of the post_install and pre_uninstall scripts
+		pmb.SetScriptsPolicy(packagemanager.ScriptsPolicy{
+			Disabled:         !configuration.Settings.GetBool("board_manager.scripts.enabled"),
+			Timeout:          configuration.Settings.GetDuration("board_manager.scripts.timeout"),
+			SkippedPlatforms: configuration.Settings.GetStringSlice("board_manager.scripts.skip_platforms"),
+		})
+
+		// Load packages index
+		for _, err := range pmb.LoadPackageIndexes(allPackageIndexUrls, packageIndexesJobs) {
+			if err != nil {
 				e := &arduino.InitFailedError{
 					Code:   codes.FailedPrecondition,
 					Cause:  fmt.Errorf(tr("Loading index file: %v", err)),
@@ -403,6 +492,9 @@ func Init(req *rpc.InitRequest, responseCallback func(r *rpc.InitResponse)) erro
 		pme.IndexDir,
 		pme.DownloadDir,
 	)
+	lm.SetSymlinkPolicy(symlinksPolicy)
+	lm.SetArchiveSignatureVerification(archiveSignatureVerificationFromSettings())
+	lm.SetArchitectureFamilies(configuration.Settings.GetStringMapStringSlice("library.architecture_families"))
 	instance.lm = lm
 
 	// Load libraries
@@ -417,6 +509,8 @@ func Init(req *rpc.InitRequest, responseCallback func(r *rpc.InitResponse)) erro
 	if err := lm.LoadIndex(); err != nil {
 		s := status.Newf(codes.FailedPrecondition, tr("Loading index file: %v"), err)
 		responseError(s)
+	} else if warning := lm.IndexStalenessWarning(configuration.Settings.GetDuration("library.index_staleness_threshold")); warning != "" {
+		taskCallback(&rpc.TaskProgress{Message: warning, Completed: true})
 	}
 
 	if profile == nil {
@@ -447,7 +541,7 @@ func Init(req *rpc.InitRequest, responseCallback func(r *rpc.InitResponse)) erro
 					responseError(err.ToRPCStatus())
 					continue
 				}
-				if err := libRelease.Resource.Download(lm.DownloadsDir, nil, libRelease.String(), downloadCallback, ""); err != nil {
+				if err := libRelease.Resource.Download(lm.DownloadsDir, nil, libRelease.String(), downloadCallback, "", lm.ArchiveSignatureVerification()); err != nil {
 					taskCallback(&rpc.TaskProgress{Name: tr("Error downloading library %s", libraryRef)})
 					e := &arduino.FailedLibraryInstallError{Cause: err}
 					responseError(e.ToRPCStatus())
@@ -457,7 +551,7 @@ func Init(req *rpc.InitRequest, responseCallback func(r *rpc.InitResponse)) erro
 
 				// Install library
 				taskCallback(&rpc.TaskProgress{Name: tr("Installing library %s", libraryRef)})
-				if err := libRelease.Resource.Install(lm.DownloadsDir, libRoot, libDir); err != nil {
+				if err := libRelease.Resource.Install(lm.DownloadsDir, libRoot, libDir, lm.ArchiveSignatureVerification()); err != nil {
 					taskCallback(&rpc.TaskProgress{Name: tr("Error installing library %s", libraryRef)})
 					e := &arduino.FailedLibraryInstallError{Cause: err}
 					responseError(e.ToRPCStatus())
@@ -514,7 +608,7 @@ func UpdateLibrariesIndex(ctx context.Context, req *rpc.UpdateLibrariesIndexRequ
 		URL:                          librariesmanager.LibraryIndexWithSignatureArchiveURL,
 		EnforceSignatureVerification: true,
 	}
-	if err := indexResource.Download(lm.IndexFile.Parent(), downloadCB); err != nil {
+	if err := indexResource.DownloadWithContext(ctx, lm.IndexFile.Parent(), downloadCB); err != nil {
 		return err
 	}
 
@@ -523,58 +617,181 @@ func UpdateLibrariesIndex(ctx context.Context, req *rpc.UpdateLibrariesIndexRequ
 
 // UpdateIndex FIXMEDOC
 func UpdateIndex(ctx context.Context, req *rpc.UpdateIndexRequest, downloadCB rpc.DownloadProgressCB) error {
-	if instances.GetInstance(req.GetInstance().GetId()) == nil {
+	pme, release := GetPackageManagerExplorer(req)
+	if pme == nil {
 		return &arduino.InvalidInstanceError{}
 	}
+	unlock, err := pme.LockPackages(nil)
+	release()
+	if err != nil {
+		return err
+	}
+	defer unlock()
 
 	indexpath := configuration.DataDir(configuration.Settings)
+	urls := packageIndexURLs(req)
+
+	// The indexes are downloaded concurrently. The progress of the first
+	// download not yet completed, in the order of the URLs, is forwarded live
+	// to downloadCB, while the progress of the following ones is collected and
+	// forwarded when they reach the head of the queue, so the progress of
+	// different downloads is not interleaved. The URLs with the same index file
+	// name are downloaded one after the other since they would overwrite each
+	// other.
+	type indexUpdate struct {
+		mux    sync.Mutex
+		live   bool
+		events []*rpc.DownloadProgress
+		failed bool
+		done   chan bool
+	}
+	updates := make([]*indexUpdate, len(urls))
+	urlsByFileName := map[string][]int{}
+	fileNames := []string{}
+	for i, u := range urls {
+		updates[i] = &indexUpdate{done: make(chan bool)}
+		fileName := u
+		if URL, err := utils.URLParse(u); err == nil {
+			if name, err := (&resources.IndexResource{URL: URL}).IndexFileName(); err == nil {
+				fileName = name
+			}
+		}
+		if _, ok := urlsByFileName[fileName]; !ok {
+			fileNames = append(fileNames, fileName)
+		}
+		urlsByFileName[fileName] = append(urlsByFileName[fileName], i)
+	}
+
+	semaphore := make(chan struct{}, packageIndexesJobs)
+	go func() {
+		for _, fileName := range fileNames {
+			semaphore <- struct{}{}
+			go func(indexes []int) {
+				defer func() { <-semaphore }()
+				for _, i := range indexes {
+					update := updates[i]
+					progressCB := func(progress *rpc.DownloadProgress) {
+						update.mux.Lock()
+						defer update.mux.Unlock()
+						if update.live {
+							downloadCB(progress)
+							return
+						}
+						// Keep only the last update of the progress
+						if n := len(update.events); n > 0 && progress.GetUpdate() != nil && update.events[n-1].GetUpdate() != nil {
+							update.events[n-1] = progress
+							return
+						}
+						update.events = append(update.events, progress)
+					}
+					update.failed = !updateIndex(urls[i], indexpath, progressCB)
+					close(update.done)
+				}
+			}(urlsByFileName[fileName])
+		}
+	}()
+
+	failed := false
+	for _, update := range updates {
+		update.mux.Lock()
+		for _, event := range update.events {
+			downloadCB(event)
+		}
+		update.events = nil
+		update.live = true
+		update.mux.Unlock()
+		<-update.done
+		if update.failed {
+			failed = true
+		}
+	}
 
+	if failed {
+		return &arduino.FailedDownloadError{Message: tr("Some indexes could not be updated.")}
+	}
+	return nil
+}
+
+// packageIndexURLs returns the URLs of the package indexes to update
+func packageIndexURLs(req *rpc.UpdateIndexRequest) []string {
 	urls := []string{globals.DefaultIndexURL}
 	if !req.GetIgnoreCustomPackageIndexes() {
 		urls = append(urls, configuration.Settings.GetStringSlice("board_manager.additional_urls")...)
 	}
+	return urls
+}
 
-	failed := false
-	for _, u := range urls {
-		URL, err := utils.URLParse(u)
-		if err != nil {
-			logrus.Warnf("unable to parse additional URL: %s", u)
-			msg := fmt.Sprintf("%s: %v", tr("Unable to parse URL"), err)
-			downloadCB.Start(u, tr("Downloading index: %s", u))
-			downloadCB.End(false, msg)
-			failed = true
-			continue
+// UpdateIndexWithReport updates the package indexes as UpdateIndex and returns
+// the changes of their content: the new and removed platforms and releases and
+// the archives whose checksum has changed. The instance is not reloaded.
+func UpdateIndexWithReport(ctx context.Context, req *rpc.UpdateIndexRequest, downloadCB rpc.DownloadProgressCB) (*packagemanager.IndexDiff, error) {
+	urls := []*url.URL{}
+	for _, u := range packageIndexURLs(req) {
+		if URL, err := utils.URLParse(u); err == nil {
+			urls = append(urls, URL)
 		}
+	}
+	snapshot := func() (cores.Packages, error) {
+		pme, release := GetPackageManagerExplorer(req)
+		if pme == nil {
+			return nil, &arduino.InvalidInstanceError{}
+		}
+		defer release()
+		return pme.IndexSnapshot(urls), nil
+	}
 
-		logrus.WithField("url", URL).Print("Updating index")
+	before, err := snapshot()
+	if err != nil {
+		return nil, err
+	}
+	if err := UpdateIndex(ctx, req, downloadCB); err != nil {
+		return nil, err
+	}
+	after, err := snapshot()
+	if err != nil {
+		return nil, err
+	}
+	return packagemanager.DiffIndexSnapshots(before, after), nil
+}
 
-		if URL.Scheme == "file" {
-			downloadCB.Start(u, tr("Downloading index: %s", filepath.Base(URL.Path)))
-			path := paths.New(URL.Path)
-			if _, err := packageindex.LoadIndexNoSign(path); err != nil {
-				msg := fmt.Sprintf("%s: %v", tr("Invalid package index in %s", path), err)
-				downloadCB.End(false, msg)
-				failed = true
-			} else {
-				downloadCB.End(true, "")
-			}
-			continue
-		}
+// updateIndex downloads the package index with the given URL in indexpath, or
+// checks that the index file is valid for the file:// URLs. It returns false
+// if the update failed.
+func updateIndex(u string, indexpath *paths.Path, downloadCB rpc.DownloadProgressCB) bool {
+	URL, err := utils.URLParse(u)
+	if err != nil {
+		logrus.Warnf("unable to parse additional URL: %s", u)
+		msg := fmt.Sprintf("%s: %v", tr("Unable to parse URL"), err)
+		downloadCB.Start(u, tr("Downloading index: %s", u))
+		downloadCB.End(false, msg)
+		return false
+	}
 
-		indexResource := resources.IndexResource{URL: URL}
-		if strings.HasSuffix(URL.Host, "arduino.cc") && strings.HasSuffix(URL.Path, ".json") {
-			indexResource.SignatureURL, _ = url.Parse(u) // should not fail because we already parsed it
-			indexResource.SignatureURL.Path += ".sig"
-		}
-		if err := indexResource.Download(indexpath, downloadCB); err != nil {
-			failed = true
+	logrus.WithField("url", URL).Print("Updating index")
+
+	if URL.Scheme == "file" {
+		downloadCB.Start(u, tr("Downloading index: %s", filepath.Base(URL.Path)))
+		path := paths.New(URL.Path)
+		if _, err := packageindex.LoadIndexNoSign(path, configuration.Settings.GetInt64("board_manager.max_index_size")); err != nil {
+			msg := fmt.Sprintf("%s: %v", tr("Invalid package index in %s", path), err)
+			downloadCB.End(false, msg)
+			return false
 		}
+		downloadCB.End(true, "")
+		return true
 	}
 
-	if failed {
-		return &arduino.FailedDownloadError{Message: tr("Some indexes could not be updated.")}
+	indexResource := resources.IndexResource{URL: URL}
+	if strings.HasSuffix(URL.Host, "arduino.cc") && strings.HasSuffix(URL.Path, ".json") {
+		indexResource.SignatureURL, _ = url.Parse(u) // should not fail because we already parsed it
+		indexResource.SignatureURL.Path += ".sig"
+	} else if configuration.Settings.GetString("security.trusted_index_keys") != "" && !configuration.Settings.GetBool("security.skip_index_signature_check") {
+		// Third party indexes may be signed with one of the trusted keys
+		indexResource.SignatureURL, _ = url.Parse(u)
+		indexResource.SignatureURL.Path += ".sig"
+		indexResource.SignatureOptional = !configuration.Settings.GetBool("security.require_signed_indexes")
 	}
-	return nil
+	return indexResource.Download(indexpath, downloadCB) == nil
 }
 
 // firstUpdate downloads libraries and packages indexes if they don't exist.
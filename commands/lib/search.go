@@ -44,7 +44,7 @@ func searchLibrary(req *rpc.LibrarySearchRequest, lm *librariesmanager.Libraries
 	if query == "" {
 		query = req.GetQuery()
 	}
-	queryTerms := utils.SearchTermsFromQueryString(query)
+	matcher := utils.NewMatcher(query)
 
 	for _, lib := range lm.Index.Libraries {
 		toTest := lib.Name + " " +
@@ -55,7 +55,7 @@ func searchLibrary(req *rpc.LibrarySearchRequest, lm *librariesmanager.Libraries
 			toTest += include + " "
 		}
 
-		if utils.Match(toTest, queryTerms) {
+		if matcher.Match(toTest) {
 			res = append(res, indexLibraryToRPCSearchLibrary(lib, req.GetOmitReleasesDetails()))
 		}
 	}
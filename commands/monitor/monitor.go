@@ -123,7 +123,7 @@ func findMonitorAndSettingsForProtocolAndBoard(pme *packagemanager.Explorer, pro
 
 	// If a board is specified search the monitor in the board package first
 	if fqbn != "" {
-		fqbn, err := cores.ParseFQBN(fqbn)
+		fqbn, err := pme.ParseFQBN(fqbn)
 		if err != nil {
 			return nil, nil, &arduino.InvalidFQBNError{Cause: err}
 		}
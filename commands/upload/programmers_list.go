@@ -36,7 +36,7 @@ func ListProgrammersAvailableForUpload(ctx context.Context, req *rpc.ListProgram
 	if fqbnIn == "" {
 		return nil, &arduino.MissingFQBNError{}
 	}
-	fqbn, err := cores.ParseFQBN(fqbnIn)
+	fqbn, err := pme.ParseFQBN(fqbnIn)
 	if err != nil {
 		return nil, &arduino.InvalidFQBNError{Cause: err}
 	}
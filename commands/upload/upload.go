@@ -57,7 +57,7 @@ func SupportedUserFields(ctx context.Context, req *rpc.SupportedUserFieldsReques
 		return nil, &arduino.InvalidInstanceError{}
 	}
 
-	fqbn, err := cores.ParseFQBN(req.GetFqbn())
+	fqbn, err := pme.ParseFQBN(req.GetFqbn())
 	if err != nil {
 		return nil, &arduino.InvalidFQBNError{Cause: err}
 	}
@@ -195,134 +195,83 @@ func UsingProgrammer(ctx context.Context, req *rpc.UploadUsingProgrammerRequest,
 	return err
 }
 
-func runProgramAction(pme *packagemanager.Explorer,
-	sk *sketch.Sketch,
-	importFile, importDir, fqbnIn string, userPort *rpc.Port,
-	programmerID string,
-	verbose, verify, burnBootloader bool,
-	outStream, errStream io.Writer,
-	dryRun bool, userFields map[string]string,
-) (*rpc.Port, error) {
-	port := discovery.PortFromRPCPort(userPort)
+// ResolveUploadCommand returns the command line (argv) that uploads the given
+// build artifact to the board with the given FQBN, connected to the given port,
+// optionally using a programmer. The command is fully resolved but not executed,
+// and no board reset is performed. The artifactPath may be one of the files
+// produced by the build (for example "build/Blink.ino.hex") or the build
+// directory itself. An empty command is returned if the upload recipe is empty.
+func ResolveUploadCommand(pme *packagemanager.Explorer, fqbn string, port *discovery.Port, programmerID string, artifactPath *paths.Path) ([]string, error) {
+	if artifactPath == nil {
+		return nil, &arduino.InvalidArgumentError{Message: tr("Missing build artifact path")}
+	}
 	if port == nil || (port.Address == "" && port.Protocol == "") {
 		// For no-port uploads use "default" protocol
 		port = &discovery.Port{Protocol: "default"}
 	}
-	logrus.WithField("port", port).Tracef("Upload port")
-
-	if burnBootloader && programmerID == "" {
-		return nil, &arduino.MissingProgrammerError{}
-	}
-
-	fqbn, err := cores.ParseFQBN(fqbnIn)
+	parsedFqbn, programmer, uploadProperties, err := resolveUploadProperties(pme, fqbn, port, programmerID, false, nil)
 	if err != nil {
-		return nil, &arduino.InvalidFQBNError{Cause: err}
+		return nil, err
 	}
-	logrus.WithField("fqbn", fqbn).Tracef("Detected FQBN")
+	uploadProperties.Set("upload.verbose", uploadProperties.Get("upload.params.quiet"))
+	uploadProperties.Set("program.verbose", uploadProperties.Get("program.params.quiet"))
+	uploadProperties.Set("upload.verify", uploadProperties.Get("upload.params.noverify"))
+	uploadProperties.Set("program.verify", uploadProperties.Get("program.params.noverify"))
 
-	// Find target board and board properties
-	_, boardPlatform, board, boardProperties, buildPlatform, err := pme.ResolveFQBN(fqbn)
-	if boardPlatform == nil {
-		return nil, &arduino.PlatformNotFoundError{
-			Platform: fmt.Sprintf("%s:%s", fqbn.Package, fqbn.PlatformArch),
-			Cause:    err,
-		}
-	} else if err != nil {
-		return nil, &arduino.UnknownFQBNError{Cause: err}
+	importFile, importDir := "", ""
+	if artifactPath.IsDir() {
+		importDir = artifactPath.String()
+	} else {
+		importFile = artifactPath.String()
 	}
-	logrus.
-		WithField("boardPlatform", boardPlatform).
-		WithField("board", board).
-		WithField("buildPlatform", buildPlatform).
-		Tracef("Upload data")
-
-	// Extract programmer properties (when specified)
-	var programmer *cores.Programmer
-	if programmerID != "" {
-		programmer = boardPlatform.Programmers[programmerID]
-		if programmer == nil {
-			// Try to find the programmer in the referenced build platform
-			programmer = buildPlatform.Programmers[programmerID]
-		}
-		if programmer == nil {
-			return nil, &arduino.ProgrammerNotFoundError{Programmer: programmerID}
-		}
+	buildPath, sketchName, err := determineBuildPathAndSketchName(importFile, importDir, nil, parsedFqbn)
+	if err != nil {
+		return nil, &arduino.NotFoundError{Message: tr("Error finding build artifacts"), Cause: err}
 	}
+	uploadProperties.SetPath("build.path", buildPath)
+	uploadProperties.Set("build.project_name", sketchName)
+	setUploadPortProperties(uploadProperties, port, port)
 
-	// Determine upload tool
-	// create a temporary configuration only for the selection of upload tool
-	props := properties.NewMap()
-	props.Merge(boardPlatform.Properties)
-	props.Merge(boardPlatform.RuntimeProperties())
-	props.Merge(boardProperties)
+	recipeID := "upload.pattern"
 	if programmer != nil {
-		props.Merge(programmer.Properties)
+		recipeID = "program.pattern"
 	}
-	action := "upload"
-	if burnBootloader {
-		action = "bootloader"
-	} else if programmer != nil {
-		action = "program"
+	recipe, ok := uploadProperties.GetOk(recipeID)
+	if !ok {
+		return nil, fmt.Errorf(tr("recipe not found '%s'"), recipeID)
 	}
-	uploadToolID, err := getToolID(props, action, port.Protocol)
+	if strings.TrimSpace(recipe) == "" {
+		return []string{}, nil
+	}
+	_, cmdArgs, err := expandToolRecipe(recipe, uploadProperties)
 	if err != nil {
 		return nil, err
 	}
+	return cmdArgs, nil
+}
 
-	var uploadToolPlatform *cores.PlatformRelease
-	if programmer != nil {
-		uploadToolPlatform = programmer.PlatformRelease
-	} else {
-		uploadToolPlatform = boardPlatform
-	}
-	logrus.
-		WithField("uploadToolID", uploadToolID).
-		WithField("uploadToolPlatform", uploadToolPlatform).
-		Trace("Upload tool")
-
-	if split := strings.Split(uploadToolID, ":"); len(split) > 2 {
-		return nil, &arduino.InvalidPlatformPropertyError{
-			Property: fmt.Sprintf("%s.tool.%s", action, port.Protocol), // TODO: Can be done better, maybe inline getToolID(...)
-			Value:    uploadToolID}
-	} else if len(split) == 2 {
-		p := pme.FindPlatform(&packagemanager.PlatformReference{
-			Package:              split[0],
-			PlatformArchitecture: boardPlatform.Platform.Architecture,
-		})
-		if p == nil {
-			return nil, &arduino.PlatformNotFoundError{Platform: split[0] + ":" + boardPlatform.Platform.Architecture}
-		}
-		uploadToolID = split[1]
-		uploadToolPlatform = pme.GetInstalledPlatformRelease(p)
-		if uploadToolPlatform == nil {
-			return nil, &arduino.PlatformNotFoundError{Platform: split[0] + ":" + boardPlatform.Platform.Architecture}
-		}
-	}
-
-	// Build configuration for upload
-	uploadProperties := properties.NewMap()
-	if uploadToolPlatform != nil {
-		uploadProperties.Merge(uploadToolPlatform.Properties)
-	}
-	uploadProperties.Set("runtime.os", properties.GetOSSuffix())
-	uploadProperties.Merge(boardPlatform.Properties)
-	uploadProperties.Merge(boardPlatform.RuntimeProperties())
-	uploadProperties.Merge(overrideProtocolProperties(action, port.Protocol, boardProperties))
-	uploadProperties.Merge(uploadProperties.SubTree("tools." + uploadToolID))
-	if programmer != nil {
-		uploadProperties.Merge(programmer.Properties)
+func runProgramAction(pme *packagemanager.Explorer,
+	sk *sketch.Sketch,
+	importFile, importDir, fqbnIn string, userPort *rpc.Port,
+	programmerID string,
+	verbose, verify, burnBootloader bool,
+	outStream, errStream io.Writer,
+	dryRun bool, userFields map[string]string,
+) (*rpc.Port, error) {
+	port := discovery.PortFromRPCPort(userPort)
+	if port == nil || (port.Address == "" && port.Protocol == "") {
+		// For no-port uploads use "default" protocol
+		port = &discovery.Port{Protocol: "default"}
 	}
+	logrus.WithField("port", port).Tracef("Upload port")
 
-	// Certain tools require the user to provide custom fields at run time,
-	// if they've been provided set them
-	// For more info:
-	// https://arduino.github.io/arduino-cli/latest/platform-specification/#user-provided-fields
-	for name, value := range userFields {
-		uploadProperties.Set(fmt.Sprintf("%s.field.%s", action, name), value)
+	if burnBootloader && programmerID == "" {
+		return nil, &arduino.MissingProgrammerError{}
 	}
 
-	if !uploadProperties.ContainsKey("upload.protocol") && programmer == nil {
-		return nil, &arduino.ProgrammerRequiredForUploadError{}
+	fqbn, programmer, uploadProperties, err := resolveUploadProperties(pme, fqbnIn, port, programmerID, burnBootloader, userFields)
+	if err != nil {
+		return nil, err
 	}
 
 	// Set properties for verbose upload
@@ -474,26 +423,7 @@ func runProgramAction(pme *packagemanager.Explorer,
 		}
 	}
 
-	if actualPort.Address != "" {
-		// Set serial port property
-		uploadProperties.Set("serial.port", actualPort.Address)
-		if actualPort.Protocol == "serial" || actualPort.Protocol == "default" {
-			// This must be done only for serial ports
-			portFile := strings.TrimPrefix(actualPort.Address, "/dev/")
-			uploadProperties.Set("serial.port.file", portFile)
-		}
-	}
-
-	// Get Port properties gathered using pluggable discovery
-	uploadProperties.Set("upload.port.address", port.Address)
-	uploadProperties.Set("upload.port.label", port.AddressLabel)
-	uploadProperties.Set("upload.port.protocol", port.Protocol)
-	uploadProperties.Set("upload.port.protocolLabel", port.ProtocolLabel)
-	if actualPort.Properties != nil {
-		for prop, value := range actualPort.Properties.AsMap() {
-			uploadProperties.Set(fmt.Sprintf("upload.port.properties.%s", prop), value)
-		}
-	}
+	setUploadPortProperties(uploadProperties, port, actualPort)
 
 	// Run recipes for upload
 	toolEnv := pme.GetEnvVarsForSpawnedProcess()
@@ -524,6 +454,153 @@ func runProgramAction(pme *packagemanager.Explorer,
 	return updatedPort.ToRPC(), nil
 }
 
+// resolveUploadProperties resolves the board, the programmer and the upload tool
+// used to perform the given action and returns the properties needed to run it
+// (except the ones related to the build artifacts and the upload port).
+func resolveUploadProperties(pme *packagemanager.Explorer,
+	fqbnIn string, port *discovery.Port,
+	programmerID string, burnBootloader bool,
+	userFields map[string]string,
+) (*cores.FQBN, *cores.Programmer, *properties.Map, error) {
+	fqbn, err := pme.ParseFQBN(fqbnIn)
+	if err != nil {
+		return nil, nil, nil, &arduino.InvalidFQBNError{Cause: err}
+	}
+	logrus.WithField("fqbn", fqbn).Tracef("Detected FQBN")
+
+	// Find target board and board properties
+	_, boardPlatform, board, boardProperties, buildPlatform, err := pme.ResolveFQBN(fqbn)
+	if boardPlatform == nil {
+		return nil, nil, nil, &arduino.PlatformNotFoundError{
+			Platform: fmt.Sprintf("%s:%s", fqbn.Package, fqbn.PlatformArch),
+			Cause:    err,
+		}
+	} else if err != nil {
+		return nil, nil, nil, &arduino.UnknownFQBNError{Cause: err}
+	}
+	logrus.
+		WithField("boardPlatform", boardPlatform).
+		WithField("board", board).
+		WithField("buildPlatform", buildPlatform).
+		Tracef("Upload data")
+
+	// Extract programmer properties (when specified)
+	var programmer *cores.Programmer
+	if programmerID != "" {
+		programmer = boardPlatform.Programmers[programmerID]
+		if programmer == nil {
+			// Try to find the programmer in the referenced build platform
+			programmer = buildPlatform.Programmers[programmerID]
+		}
+		if programmer == nil {
+			return nil, nil, nil, &arduino.ProgrammerNotFoundError{Programmer: programmerID}
+		}
+	}
+
+	// Determine upload tool
+	// create a temporary configuration only for the selection of upload tool
+	props := properties.NewMap()
+	props.Merge(boardPlatform.Properties)
+	props.Merge(boardPlatform.RuntimeProperties())
+	props.Merge(boardProperties)
+	if programmer != nil {
+		props.Merge(programmer.Properties)
+	}
+	action := "upload"
+	if burnBootloader {
+		action = "bootloader"
+	} else if programmer != nil {
+		action = "program"
+	}
+	uploadToolID, err := getToolID(props, action, port.Protocol)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	var uploadToolPlatform *cores.PlatformRelease
+	if programmer != nil {
+		uploadToolPlatform = programmer.PlatformRelease
+	} else {
+		uploadToolPlatform = boardPlatform
+	}
+	logrus.
+		WithField("uploadToolID", uploadToolID).
+		WithField("uploadToolPlatform", uploadToolPlatform).
+		Trace("Upload tool")
+
+	if split := strings.Split(uploadToolID, ":"); len(split) > 2 {
+		return nil, nil, nil, &arduino.InvalidPlatformPropertyError{
+			Property: fmt.Sprintf("%s.tool.%s", action, port.Protocol), // TODO: Can be done better, maybe inline getToolID(...)
+			Value:    uploadToolID}
+	} else if len(split) == 2 {
+		p := pme.FindPlatform(&packagemanager.PlatformReference{
+			Package:              split[0],
+			PlatformArchitecture: boardPlatform.Platform.Architecture,
+		})
+		if p == nil {
+			return nil, nil, nil, &arduino.PlatformNotFoundError{Platform: split[0] + ":" + boardPlatform.Platform.Architecture}
+		}
+		uploadToolID = split[1]
+		uploadToolPlatform = pme.GetInstalledPlatformRelease(p)
+		if uploadToolPlatform == nil {
+			return nil, nil, nil, &arduino.PlatformNotFoundError{Platform: split[0] + ":" + boardPlatform.Platform.Architecture}
+		}
+	}
+
+	// Build configuration for upload
+	uploadProperties := properties.NewMap()
+	if uploadToolPlatform != nil {
+		uploadProperties.Merge(uploadToolPlatform.Properties)
+	}
+	uploadProperties.Set("runtime.os", properties.GetOSSuffix())
+	uploadProperties.Merge(boardPlatform.Properties)
+	uploadProperties.Merge(boardPlatform.RuntimeProperties())
+	uploadProperties.Merge(overrideProtocolProperties(action, port.Protocol, boardProperties))
+	uploadProperties.Merge(uploadProperties.SubTree("tools." + uploadToolID))
+	if programmer != nil {
+		uploadProperties.Merge(programmer.Properties)
+	}
+
+	// Certain tools require the user to provide custom fields at run time,
+	// if they've been provided set them
+	// For more info:
+	// https://arduino.github.io/arduino-cli/latest/platform-specification/#user-provided-fields
+	for name, value := range userFields {
+		uploadProperties.Set(fmt.Sprintf("%s.field.%s", action, name), value)
+	}
+
+	if !uploadProperties.ContainsKey("upload.protocol") && programmer == nil {
+		return nil, nil, nil, &arduino.ProgrammerRequiredForUploadError{}
+	}
+
+	return fqbn, programmer, uploadProperties, nil
+}
+
+// setUploadPortProperties sets the properties of the upload port, actualPort is
+// the port used for the upload after the eventual board reset.
+func setUploadPortProperties(uploadProperties *properties.Map, port, actualPort *discovery.Port) {
+	if actualPort.Address != "" {
+		// Set serial port property
+		uploadProperties.Set("serial.port", actualPort.Address)
+		if actualPort.Protocol == "serial" || actualPort.Protocol == "default" {
+			// This must be done only for serial ports
+			portFile := strings.TrimPrefix(actualPort.Address, "/dev/")
+			uploadProperties.Set("serial.port.file", portFile)
+		}
+	}
+
+	// Get Port properties gathered using pluggable discovery
+	uploadProperties.Set("upload.port.address", port.Address)
+	uploadProperties.Set("upload.port.label", port.AddressLabel)
+	uploadProperties.Set("upload.port.protocol", port.Protocol)
+	uploadProperties.Set("upload.port.protocolLabel", port.ProtocolLabel)
+	if actualPort.Properties != nil {
+		for prop, value := range actualPort.Properties.AsMap() {
+			uploadProperties.Set(fmt.Sprintf("upload.port.properties.%s", prop), value)
+		}
+	}
+}
+
 func detectUploadPort(
 	uploadCtx context.Context,
 	uploadPort *discovery.Port, watch <-chan *discovery.Event,
@@ -634,13 +711,9 @@ func runTool(recipeID string, props *properties.Map, outStream, errStream io.Wri
 	if strings.TrimSpace(recipe) == "" {
 		return nil // Nothing to run
 	}
-	if props.IsPropertyMissingInExpandPropsInString("serial.port", recipe) || props.IsPropertyMissingInExpandPropsInString("serial.port.file", recipe) {
-		return fmt.Errorf(tr("no upload port provided"))
-	}
-	cmdLine := props.ExpandPropsInString(recipe)
-	cmdArgs, err := properties.SplitQuotedString(cmdLine, `"'`, false)
+	cmdLine, cmdArgs, err := expandToolRecipe(recipe, props)
 	if err != nil {
-		return fmt.Errorf(tr("invalid recipe '%[1]s': %[2]s"), recipe, err)
+		return err
 	}
 
 	// Run Tool
@@ -670,6 +743,20 @@ func runTool(recipeID string, props *properties.Map, outStream, errStream io.Wri
 	return nil
 }
 
+// expandToolRecipe expands the given recipe with the properties and returns the
+// resulting command line together with its arguments.
+func expandToolRecipe(recipe string, props *properties.Map) (string, []string, error) {
+	if props.IsPropertyMissingInExpandPropsInString("serial.port", recipe) || props.IsPropertyMissingInExpandPropsInString("serial.port.file", recipe) {
+		return "", nil, fmt.Errorf(tr("no upload port provided"))
+	}
+	cmdLine := props.ExpandPropsInString(recipe)
+	cmdArgs, err := properties.SplitQuotedString(cmdLine, `"'`, false)
+	if err != nil {
+		return "", nil, fmt.Errorf(tr("invalid recipe '%[1]s': %[2]s"), recipe, err)
+	}
+	return cmdLine, cmdArgs, nil
+}
+
 func determineBuildPathAndSketchName(importFile, importDir string, sk *sketch.Sketch, fqbn *cores.FQBN) (*paths.Path, string, error) {
 	// In general, compiling a sketch will produce a set of files that are
 	// named as the sketch but have different extensions, for example Sketch.ino
@@ -21,14 +21,17 @@ import (
 	"strings"
 	"testing"
 
+	"github.com/arduino/arduino-cli/arduino"
 	"github.com/arduino/arduino-cli/arduino/cores"
 	"github.com/arduino/arduino-cli/arduino/cores/packagemanager"
+	"github.com/arduino/arduino-cli/arduino/discovery"
 	"github.com/arduino/arduino-cli/arduino/sketch"
 	rpc "github.com/arduino/arduino-cli/rpc/cc/arduino/cli/commands/v1"
 	paths "github.com/arduino/go-paths-helper"
 	properties "github.com/arduino/go-properties-orderedmap"
 	"github.com/sirupsen/logrus"
 	"github.com/stretchr/testify/require"
+	semver "go.bug.st/relaxed-semver"
 )
 
 func TestDetectSketchNameFromBuildPath(t *testing.T) {
@@ -225,6 +228,59 @@ func TestUploadPropertiesComposition(t *testing.T) {
 	}
 }
 
+func TestResolveUploadCommand(t *testing.T) {
+	pmb := packagemanager.NewBuilder(nil, nil, nil, nil, "test")
+	require.Empty(t, pmb.LoadHardwareFromDirectory(paths.New("testdata", "hardware")))
+	avrdudePath := paths.New("/opt", "avrdude")
+	avrdude := pmb.GetOrCreatePackage("alice").GetOrCreateTool("avrdude").GetOrCreateRelease(semver.ParseRelaxed("6.3.0"))
+	avrdude.InstallDir = avrdudePath
+	pm := pmb.Build()
+	pme, release := pm.NewExplorer()
+	defer release()
+
+	buildPath := paths.New("testdata", "build_path_2")
+	port := &discovery.Port{Address: "/dev/ttyACM0", Protocol: "serial"}
+
+	// Build artifact
+	argv, err := ResolveUploadCommand(pme, "alice:avr:uno", port, "", buildPath.Join("Blink.ino.hex"))
+	require.NoError(t, err)
+	require.Equal(t, []string{
+		avrdudePath.Join("bin", "avrdude").String(),
+		"-C" + avrdudePath.Join("etc", "avrdude.conf").String(),
+		"-q", "-q", "-V",
+		"-patmega328p", "-carduino", "-P/dev/ttyACM0", "-b115200", "-D",
+		"-Uflash:w:" + buildPath.String() + "/Blink.ino.hex:i",
+	}, argv)
+
+	// Build directory
+	argv2, err := ResolveUploadCommand(pme, "alice:avr:uno", port, "", buildPath)
+	require.NoError(t, err)
+	require.Equal(t, argv, argv2)
+
+	// Programmer
+	argv, err = ResolveUploadCommand(pme, "alice:avr:uno", nil, "usbasp", buildPath)
+	require.NoError(t, err)
+	require.Equal(t, []string{
+		avrdudePath.Join("bin", "avrdude").String(),
+		"-C" + avrdudePath.Join("etc", "avrdude.conf").String(),
+		"-q", "-q", "-V",
+		"-patmega328p", "-cusbasp", "-Pusb",
+		"-Uflash:w:" + buildPath.String() + "/Blink.ino.hex:i",
+	}, argv)
+
+	// Missing port
+	_, err = ResolveUploadCommand(pme, "alice:avr:uno", nil, "", buildPath)
+	require.Error(t, err)
+
+	// Unknown board
+	_, err = ResolveUploadCommand(pme, "alice:avr:nonexistent", port, "", buildPath)
+	require.Error(t, err)
+
+	// Missing artifact
+	_, err = ResolveUploadCommand(pme, "alice:avr:uno", port, "", nil)
+	require.IsType(t, &arduino.InvalidArgumentError{}, err)
+}
+
 func TestGetToolId(t *testing.T) {
 	props, err := properties.LoadFromBytes([]byte(`
 bootloader.tool=avrdude
@@ -79,6 +79,7 @@ func BindFlags(cmd *cobra.Command, settings *viper.Viper) {
 	settings.BindPFlag("logging.format", cmd.Flag("log-format"))
 	settings.BindPFlag("board_manager.additional_urls", cmd.Flag("additional-urls"))
 	settings.BindPFlag("output.no_color", cmd.Flag("no-color"))
+	settings.BindPFlag("security.skip_index_signature_check", cmd.Flag("skip-signature-check"))
 }
 
 // getDefaultArduinoDataDir returns the full path to the default arduino folder
@@ -72,3 +72,38 @@ func TestFindConfigFile(t *testing.T) {
 	configFile = FindConfigFileInArgs([]string{})
 	require.Equal(t, "", configFile)
 }
+
+func TestNetworkCredentials(t *testing.T) {
+	tmp := tmpDirOrDie()
+	defer os.RemoveAll(tmp)
+	configFile := filepath.Join(tmp, "arduino-cli.yaml")
+	require.NoError(t, os.WriteFile(configFile, []byte(`
+network:
+  credentials:
+    - url: https://cores.example.com/private/
+      username: ci
+      password: env:CORES_PASSWORD
+    - url: https://downloads.example.com/
+      token: "cmd:secret-tool lookup service arduino"
+`), 0644))
+	settings := Init(configFile)
+	credentials, err := NetworkCredentials(settings)
+	require.NoError(t, err)
+	require.Equal(t, []*NetworkCredential{
+		{URL: "https://cores.example.com/private/", Username: "ci", Password: "env:CORES_PASSWORD"},
+		{URL: "https://downloads.example.com/", Token: "cmd:secret-tool lookup service arduino"},
+	}, credentials)
+
+	credentials, err = NetworkCredentials(Init(filepath.Join(tmp, "missing.yaml")))
+	require.NoError(t, err)
+	require.Empty(t, credentials)
+
+	require.NoError(t, os.WriteFile(configFile, []byte(`
+network:
+  credentials:
+    - url: cores.example.com
+      token: secret
+`), 0644))
+	_, err = NetworkCredentials(Init(configFile))
+	require.Error(t, err)
+}
@@ -31,9 +31,15 @@ func SetDefaults(settings *viper.Viper) {
 
 	// Libraries
 	settings.SetDefault("library.enable_unsafe_install", false)
+	settings.SetDefault("library.index_staleness_threshold", time.Hour*24*30)
 
 	// Boards Manager
 	settings.SetDefault("board_manager.additional_urls", []string{})
+	settings.SetDefault("board_manager.keep_previous_releases", 0)
+	settings.SetDefault("board_manager.lock_timeout", time.Minute)
+	settings.SetDefault("board_manager.scripts.enabled", true)
+	settings.SetDefault("board_manager.scripts.timeout", 5*time.Minute)
+	settings.SetDefault("board_manager.scripts.skip_platforms", []string{})
 
 	// arduino directories
 	settings.SetDefault("directories.Data", getDefaultArduinoDataDir())
@@ -65,3 +65,48 @@ func NetworkProxy(settings *viper.Viper) (*url.URL, error) {
 		return proxy, nil
 	}
 }
+
+// NetworkDownloadRateLimit returns the maximum bandwidth, in bytes per second,
+// shared by all the downloads, as configured in network.download_rate_limit.
+// 0 means no limit.
+func NetworkDownloadRateLimit(settings *viper.Viper) int64 {
+	if settings == nil {
+		return 0
+	}
+	return settings.GetInt64("network.download_rate_limit")
+}
+
+// NetworkCredential are the credentials used to authenticate the requests to
+// the URLs starting with URL, with HTTP basic authentication (Username and
+// Password) or with a bearer Token. Password and Token may be given as:
+//   - the secret itself;
+//   - "env:NAME", to read the secret from the NAME environment variable;
+//   - "cmd:COMMAND ARGS...", to read the secret from the output of a command,
+//     for example a tool reading the secret from the keyring of the system.
+type NetworkCredential struct {
+	URL      string `mapstructure:"url"`
+	Username string `mapstructure:"username"`
+	Password string `mapstructure:"password"`
+	Token    string `mapstructure:"token"`
+}
+
+// NetworkCredentials returns the credentials configured in network.credentials
+// (mainly used by HTTP clients)
+func NetworkCredentials(settings *viper.Viper) ([]*NetworkCredential, error) {
+	if settings == nil || !settings.IsSet("network.credentials") {
+		return nil, nil
+	}
+	credentials := []*NetworkCredential{}
+	if err := settings.UnmarshalKey("network.credentials", &credentials); err != nil {
+		return nil, fmt.Errorf(tr("Invalid network.credentials: %s"), err)
+	}
+	for _, credential := range credentials {
+		if u, err := url.Parse(credential.URL); err != nil || u.Scheme == "" || u.Host == "" {
+			return nil, fmt.Errorf(tr("Invalid network.credentials: invalid URL '%s'"), credential.URL)
+		}
+		if credential.Token == "" && credential.Username == "" {
+			return nil, fmt.Errorf(tr("Invalid network.credentials: missing username or token for '%s'"), credential.URL)
+		}
+	}
+	return credentials, nil
+}
@@ -21,6 +21,7 @@ import (
 	"io"
 	"os"
 	"os/exec"
+	"time"
 
 	"github.com/arduino/go-paths-helper"
 	"github.com/pkg/errors"
@@ -131,6 +132,13 @@ func (p *Process) Kill() error {
 	return p.cmd.Process.Kill()
 }
 
+// SetWaitDelay sets how long Wait waits, after the Process has exited or has been killed,
+// for the completion of its I/O: after the delay the pipes are closed even if they are still
+// held open by other processes started by the Process. With 0 Wait waits indefinitely.
+func (p *Process) SetWaitDelay(delay time.Duration) {
+	p.cmd.WaitDelay = delay
+}
+
 // SetDir sets the working directory of the command. If Dir is the empty string, Run
 // runs the command in the calling process's current directory.
 func (p *Process) SetDir(dir string) {
@@ -99,10 +99,11 @@ func watchList(inst *rpc.Instance) {
 
 	for event := range eventsChan {
 		feedback.PrintResult(watchEvent{
-			Type:   event.EventType,
-			Boards: event.Port.MatchingBoards,
-			Port:   event.Port.Port,
-			Error:  event.Error,
+			Type:       event.EventType,
+			Boards:     event.Port.MatchingBoards,
+			Candidates: event.Port.BoardCandidates,
+			Port:       event.Port.Port,
+			Error:      event.Error,
 		})
 	}
 }
@@ -171,10 +172,11 @@ func (dr result) String() string {
 }
 
 type watchEvent struct {
-	Type   string               `json:"eventType"`
-	Boards []*rpc.BoardListItem `json:"matching_boards,omitempty"`
-	Port   *rpc.Port            `json:"port,omitempty"`
-	Error  string               `json:"error,omitempty"`
+	Type       string                `json:"eventType"`
+	Boards     []*rpc.BoardListItem  `json:"matching_boards,omitempty"`
+	Candidates []*rpc.BoardCandidate `json:"board_candidates,omitempty"`
+	Port       *rpc.Port             `json:"port,omitempty"`
+	Error      string                `json:"error,omitempty"`
 }
 
 func (dr watchEvent) Data() interface{} {
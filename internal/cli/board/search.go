@@ -19,7 +19,6 @@ import (
 	"context"
 	"fmt"
 	"os"
-	"sort"
 	"strings"
 
 	"github.com/arduino/arduino-cli/commands/board"
@@ -35,10 +34,11 @@ func initSearchCommand() *cobra.Command {
 	var searchCommand = &cobra.Command{
 		Use:   fmt.Sprintf("search [%s]", tr("boardname")),
 		Short: tr("Search for a board in the Boards Manager."),
-		Long:  tr(`Search for a board in the Boards Manager using the specified keywords.`),
+		Long:  tr(`Search for a board in the Boards Manager using the specified keywords. The keywords are searched in the board name, FQBN, USB VID:PID and platform, the most relevant boards are listed first.`),
 		Example: "" +
 			"  " + os.Args[0] + " board search\n" +
-			"  " + os.Args[0] + " board search zero",
+			"  " + os.Args[0] + " board search zero\n" +
+			"  " + os.Args[0] + " board search 2341:0043",
 		Args: cobra.ArbitraryArgs,
 		Run:  runSearchCommand,
 	}
@@ -74,10 +74,7 @@ func (r searchResults) Data() interface{} {
 }
 
 func (r searchResults) String() string {
-	sort.Slice(r.boards, func(i, j int) bool {
-		return r.boards[i].GetName() < r.boards[j].GetName()
-	})
-
+	// The boards are already sorted by relevance
 	t := table.New()
 	t.SetHeader(tr("Board Name"), tr("FQBN"), tr("Platform ID"), "")
 	for _, item := range r.boards {
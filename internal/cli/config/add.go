@@ -19,6 +19,7 @@ import (
 	"os"
 	"reflect"
 
+	"github.com/arduino/arduino-cli/arduino/resources"
 	"github.com/arduino/arduino-cli/configuration"
 	"github.com/arduino/arduino-cli/internal/cli/feedback"
 	"github.com/sirupsen/logrus"
@@ -94,6 +95,18 @@ func runAddCommand(cmd *cobra.Command, args []string) {
 		feedback.Fatal(msg, feedback.ErrGeneric)
 	}
 
+	if key == "board_manager.additional_urls" {
+		for _, u := range args[1:] {
+			_, warnings, err := resources.ValidateIndexURL(u, nil)
+			if err != nil {
+				feedback.Warning(tr("Invalid additional URL %[1]s: %[2]s", u, err))
+			}
+			for _, warning := range warnings {
+				feedback.Warning(tr("Additional URL %[1]s: %[2]s", u, warning))
+			}
+		}
+	}
+
 	v := configuration.Settings.GetStringSlice(key)
 	v = append(v, args[1:]...)
 	v = uniquifyStringSlice(v)
@@ -40,6 +40,8 @@ func NewCommand() *cobra.Command {
 	coreCommand.AddCommand(initUpgradeCommand())
 	coreCommand.AddCommand(initUninstallCommand())
 	coreCommand.AddCommand(initSearchCommand())
+	coreCommand.AddCommand(initPruneToolsCommand())
+	coreCommand.AddCommand(initRollbackCommand())
 
 	return coreCommand
 }
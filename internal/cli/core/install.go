@@ -19,18 +19,21 @@ import (
 	"context"
 	"fmt"
 	"os"
+	"strings"
 
 	"github.com/arduino/arduino-cli/commands/core"
 	"github.com/arduino/arduino-cli/internal/cli/arguments"
 	"github.com/arduino/arduino-cli/internal/cli/feedback"
 	"github.com/arduino/arduino-cli/internal/cli/instance"
 	rpc "github.com/arduino/arduino-cli/rpc/cc/arduino/cli/commands/v1"
+	"github.com/arduino/go-paths-helper"
 	"github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
 )
 
 func initInstallCommand() *cobra.Command {
-	var noOverwrite bool
+	var noOverwrite, dryRun bool
+	var fromPath, fromArchive, gitURL string
 	var scriptFlags arguments.PrePostScriptsFlags
 	installCommand := &cobra.Command{
 		Use:   fmt.Sprintf("install %s:%s[@%s]...", tr("PACKAGER"), tr("ARCH"), tr("VERSION")),
@@ -39,12 +42,43 @@ func initInstallCommand() *cobra.Command {
 		Example: "  # " + tr("download the latest version of Arduino SAMD core.") + "\n" +
 			"  " + os.Args[0] + " core install arduino:samd\n\n" +
 			"  # " + tr("download a specific version (in this case 1.6.9).") + "\n" +
-			"  " + os.Args[0] + " core install arduino:samd@1.6.9",
+			"  " + os.Args[0] + " core install arduino:samd@1.6.9\n\n" +
+			"  # " + tr("show what would be downloaded and installed, without doing it.") + "\n" +
+			"  " + os.Args[0] + " core install --dry-run arduino:samd\n\n" +
+			"  # " + tr("install a platform from a local directory or archive.") + "\n" +
+			"  " + os.Args[0] + " core install --from-path /path/to/platform mypackager:myarch\n" +
+			"  " + os.Args[0] + " core install --from-archive /path/to/platform.zip mypackager:myarch\n\n" +
+			"  # " + tr("install a platform from a git repository, optionally at a specific branch, tag or commit.") + "\n" +
+			"  " + os.Args[0] + " core install --git-url https://github.com/vendor/core.git#v2.1.0 vendor:arch",
 		Args: cobra.MinimumNArgs(1),
 		PreRun: func(cmd *cobra.Command, args []string) {
 			arguments.CheckFlagsConflicts(cmd, "run-post-install", "skip-post-install")
+			arguments.CheckFlagsConflicts(cmd, "from-path", "from-archive")
+			arguments.CheckFlagsConflicts(cmd, "from-path", "git-url")
+			arguments.CheckFlagsConflicts(cmd, "from-archive", "git-url")
+			arguments.CheckFlagsConflicts(cmd, "dry-run", "from-path")
+			arguments.CheckFlagsConflicts(cmd, "dry-run", "from-archive")
+			arguments.CheckFlagsConflicts(cmd, "dry-run", "git-url")
 		},
 		Run: func(cmd *cobra.Command, args []string) {
+			if dryRun {
+				runInstallDryRunCommand(args, noOverwrite)
+				return
+			}
+			if fromPath != "" {
+				checkInstallFromPath(fromPath)
+				runInstallFromSourceCommand(args, fromPath, "", scriptFlags, noOverwrite)
+				return
+			}
+			if fromArchive != "" {
+				checkInstallFromArchive(fromArchive)
+				runInstallFromSourceCommand(args, fromArchive, "", scriptFlags, noOverwrite)
+				return
+			}
+			if gitURL != "" {
+				runInstallFromSourceCommand(args, "", gitURL, scriptFlags, noOverwrite)
+				return
+			}
 			runInstallCommand(args, scriptFlags, noOverwrite)
 		},
 		ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
@@ -53,6 +87,10 @@ func initInstallCommand() *cobra.Command {
 	}
 	scriptFlags.AddToCommand(installCommand)
 	installCommand.Flags().BoolVar(&noOverwrite, "no-overwrite", false, tr("Do not overwrite already installed platforms."))
+	installCommand.Flags().BoolVar(&dryRun, "dry-run", false, tr("Show the platform and tools that would be installed, the download size and the disk space required, without downloading or installing anything."))
+	installCommand.Flags().StringVar(&fromPath, "from-path", "", tr("Install the platform from the given local directory. Can't be used together with --from-archive or --git-url."))
+	installCommand.Flags().StringVar(&fromArchive, "from-archive", "", tr("Install the platform from the given local archive (.zip, .tar.bz2, .tar.xz, .tar.zst...). Can't be used together with --from-path or --git-url."))
+	installCommand.Flags().StringVar(&gitURL, "git-url", "", tr("Install the platform from the given git repository."))
 	return installCommand
 }
 
@@ -81,3 +119,193 @@ func runInstallCommand(args []string, scriptFlags arguments.PrePostScriptsFlags,
 		}
 	}
 }
+
+// supportedPlatformArchives are the extensions of the archives accepted by --from-archive
+var supportedPlatformArchives = []string{".zip", ".tar", ".tar.gz", ".tgz", ".tar.bz2", ".tbz2", ".tar.xz", ".txz", ".tar.zst", ".tzst"}
+
+// checkInstallFromPath exits with an error if path is not an existing directory
+func checkInstallFromPath(path string) {
+	if isDir, err := paths.New(path).IsDirCheck(); err != nil {
+		feedback.Fatal(tr("Invalid argument passed: %v", err), feedback.ErrBadArgument)
+	} else if !isDir {
+		feedback.Fatal(tr("%s is not a directory, use --from-archive to install from an archive", path), feedback.ErrBadArgument)
+	}
+}
+
+// checkInstallFromArchive exits with an error if archive is not an existing
+// file with one of the supportedPlatformArchives extensions
+func checkInstallFromArchive(archive string) {
+	if isDir, err := paths.New(archive).IsDirCheck(); err != nil {
+		feedback.Fatal(tr("Invalid argument passed: %v", err), feedback.ErrBadArgument)
+	} else if isDir {
+		feedback.Fatal(tr("%s is a directory, use --from-path to install from a directory", archive), feedback.ErrBadArgument)
+	}
+	name := strings.ToLower(archive)
+	for _, ext := range supportedPlatformArchives {
+		if strings.HasSuffix(name, ext) {
+			return
+		}
+	}
+	feedback.Fatal(tr("%[1]s is not a supported archive, the supported formats are: %[2]s", archive, strings.Join(supportedPlatformArchives, ", ")), feedback.ErrBadArgument)
+}
+
+func runInstallFromSourceCommand(args []string, source, gitURL string, scriptFlags arguments.PrePostScriptsFlags, noOverwrite bool) {
+	inst := instance.CreateAndInit()
+	logrus.Info("Executing `arduino-cli core install` from a local path or git repository")
+
+	if len(args) != 1 {
+		feedback.Fatal(tr("Exactly one platform must be specified when installing from a local path or a git repository"), feedback.ErrBadArgument)
+	}
+	// The platform may be unknown, so the reference is not checked against the installed ones
+	if strings.Contains(args[0], "@") {
+		feedback.Fatal(tr("The version can't be specified when installing from a local path or a git repository"), feedback.ErrBadArgument)
+	}
+	packager, architecture, ok := strings.Cut(args[0], ":")
+	if !ok || packager == "" || architecture == "" {
+		feedback.Fatal(tr("Invalid argument passed: %v", args[0]), feedback.ErrBadArgument)
+	}
+	feedback.Print(tr("--from-path, --from-archive and --git-url flags allow installing untrusted files, use it at your own risk."))
+
+	platformInstallRequest := &rpc.PlatformInstallRequest{
+		Instance:        inst,
+		PlatformPackage: packager,
+		Architecture:    architecture,
+		SkipPostInstall: scriptFlags.DetectSkipPostInstallValue(),
+		NoOverwrite:     noOverwrite,
+	}
+	var err error
+	if gitURL != "" {
+		_, err = core.PlatformInstallFromGit(context.Background(), platformInstallRequest, gitURL, feedback.TaskProgress())
+	} else {
+		_, err = core.PlatformInstallFromPath(context.Background(), platformInstallRequest, paths.New(source), feedback.TaskProgress())
+	}
+	if err != nil {
+		feedback.Fatal(tr("Error during install: %v", err), feedback.ErrGeneric)
+	}
+}
+
+func runInstallDryRunCommand(args []string, noOverwrite bool) {
+	inst := instance.CreateAndInit()
+	logrus.Info("Executing `arduino-cli core install --dry-run`")
+
+	platformsRefs, err := arguments.ParseReferences(args)
+	if err != nil {
+		feedback.Fatal(tr("Invalid argument passed: %v", err), feedback.ErrBadArgument)
+	}
+
+	res := installPlansResult{}
+	for _, platformRef := range platformsRefs {
+		plan, err := core.PlatformInstallPlan(context.Background(), &rpc.PlatformInstallRequest{
+			Instance:        inst,
+			PlatformPackage: platformRef.PackageName,
+			Architecture:    platformRef.Architecture,
+			Version:         platformRef.Version,
+			NoOverwrite:     noOverwrite,
+		})
+		if err != nil {
+			feedback.Fatal(tr("Error during install: %v", err), feedback.ErrGeneric)
+		}
+		planResult := &installPlanResult{
+			Request:             platformRef.String(),
+			ReplacedPlatform:    plan.ReplacedPlatform,
+			Tools:               []*plannedReleaseResult{},
+			InstalledTools:      plan.InstalledTools,
+			DownloadSize:        plan.DownloadSize,
+			DiskSpace:           plan.DiskSpace,
+			DiskSpaceIsEstimate: plan.DiskSpaceIsEstimate,
+		}
+		if plan.Platform != nil {
+			planResult.Platform = &plannedReleaseResult{
+				Release:       plan.Platform.Release,
+				URL:           plan.Platform.URL,
+				Size:          plan.Platform.Size,
+				Cached:        plan.Platform.Cached,
+				InstalledSize: plan.Platform.InstalledSize,
+			}
+		}
+		for _, tool := range plan.Tools {
+			planResult.Tools = append(planResult.Tools, &plannedReleaseResult{
+				Release:       tool.Release,
+				URL:           tool.URL,
+				Size:          tool.Size,
+				Cached:        tool.Cached,
+				InstalledSize: tool.InstalledSize,
+			})
+		}
+		res = append(res, planResult)
+	}
+	feedback.PrintResult(res)
+}
+
+type plannedReleaseResult struct {
+	Release       string `json:"release"`
+	URL           string `json:"url"`
+	Size          int64  `json:"size"`
+	Cached        bool   `json:"cached"`
+	InstalledSize int64  `json:"installed_size,omitempty"`
+}
+
+type installPlanResult struct {
+	Request             string                  `json:"request"`
+	Platform            *plannedReleaseResult   `json:"platform,omitempty"`
+	ReplacedPlatform    string                  `json:"replaced_platform,omitempty"`
+	Tools               []*plannedReleaseResult `json:"tools"`
+	InstalledTools      []string                `json:"installed_tools"`
+	DownloadSize        int64                   `json:"download_size"`
+	DiskSpace           int64                   `json:"disk_space"`
+	DiskSpaceIsEstimate bool                    `json:"disk_space_is_estimate"`
+}
+
+type installPlansResult []*installPlanResult
+
+func (r installPlansResult) Data() interface{} {
+	return r
+}
+
+func (r installPlansResult) String() string {
+	res := []string{}
+	for _, plan := range r {
+		lines := []string{}
+		release := func(r *plannedReleaseResult) string {
+			if r.Cached {
+				return tr("%[1]s (%[2]s, already downloaded)", r.Release, formatSize(r.Size))
+			}
+			return tr("%[1]s (%[2]s)", r.Release, formatSize(r.Size))
+		}
+		if plan.Platform == nil {
+			lines = append(lines, tr("Platform %s already installed", plan.Request))
+		} else if plan.ReplacedPlatform != "" {
+			lines = append(lines, tr("Platform %[1]s would replace %[2]s", release(plan.Platform), plan.ReplacedPlatform))
+		} else {
+			lines = append(lines, tr("Platform %s would be installed", release(plan.Platform)))
+		}
+		for _, tool := range plan.Tools {
+			lines = append(lines, "  "+tr("Tool %s would be installed", release(tool)))
+		}
+		for _, tool := range plan.InstalledTools {
+			lines = append(lines, "  "+tr("Tool %s already installed", tool))
+		}
+		lines = append(lines, tr("Download size: %s", formatSize(plan.DownloadSize)))
+		if plan.DiskSpaceIsEstimate {
+			lines = append(lines, tr("Disk space required: at least %s", formatSize(plan.DiskSpace)))
+		} else {
+			lines = append(lines, tr("Disk space required: %s", formatSize(plan.DiskSpace)))
+		}
+		res = append(res, strings.Join(lines, "\n"))
+	}
+	return strings.Join(res, "\n\n")
+}
+
+// formatSize formats the given number of bytes with a binary unit (KiB, MiB...)
+func formatSize(size int64) string {
+	if size < 1024 {
+		return fmt.Sprintf("%d B", size)
+	}
+	value := float64(size)
+	unit := 0
+	for value >= 1024 && unit < 4 {
+		value /= 1024
+		unit++
+	}
+	return fmt.Sprintf("%.1f %s", value, []string{"B", "KiB", "MiB", "GiB", "TiB"}[unit])
+}
@@ -89,7 +89,9 @@ func (ir installedResult) String() string {
 	t.SetHeader(tr("ID"), tr("Installed"), tr("Latest"), tr("Name"))
 	for _, p := range ir.platforms {
 		name := p.Name
-		if p.Deprecated {
+		if p.EndOfLifeReached {
+			name = fmt.Sprintf("[%s] %s", tr("END OF LIFE"), name)
+		} else if p.Deprecated {
 			name = fmt.Sprintf("[%s] %s", tr("DEPRECATED"), name)
 		}
 		t.AddRow(p.Id, p.Installed, p.Latest, name)
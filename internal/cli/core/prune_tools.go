@@ -0,0 +1,78 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2023 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package core
+
+import (
+	"context"
+	"os"
+	"strings"
+
+	"github.com/arduino/arduino-cli/commands/core"
+	"github.com/arduino/arduino-cli/internal/cli/arguments"
+	"github.com/arduino/arduino-cli/internal/cli/feedback"
+	"github.com/arduino/arduino-cli/internal/cli/instance"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+func initPruneToolsCommand() *cobra.Command {
+	var dryRun bool
+	var preUninstallFlags arguments.PrePostScriptsFlags
+	pruneToolsCommand := &cobra.Command{
+		Use:   "prune-tools",
+		Short: tr("Uninstalls the tools no longer required by any installed core."),
+		Long:  tr("Uninstalls the tools no longer required by any installed core."),
+		Example: "  " + os.Args[0] + " core prune-tools\n" +
+			"  " + os.Args[0] + " core prune-tools --dry-run",
+		Args: cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			runPruneToolsCommand(dryRun, preUninstallFlags)
+		},
+	}
+	preUninstallFlags.AddToCommand(pruneToolsCommand)
+	pruneToolsCommand.Flags().BoolVar(&dryRun, "dry-run", false, tr("Only list the tools that would be uninstalled."))
+	return pruneToolsCommand
+}
+
+func runPruneToolsCommand(dryRun bool, preUninstallFlags arguments.PrePostScriptsFlags) {
+	inst := instance.CreateAndInit()
+	logrus.Info("Executing `arduino-cli core prune-tools`")
+
+	tools, err := core.PruneTools(context.Background(), inst, dryRun, preUninstallFlags.DetectSkipPreUninstallValue(), feedback.NewTaskProgressCB())
+	if err != nil {
+		feedback.Fatal(tr("Error pruning tools: %v", err), feedback.ErrGeneric)
+	}
+	feedback.PrintResult(pruneToolsResult{tools: tools, dryRun: dryRun})
+}
+
+type pruneToolsResult struct {
+	tools  []string
+	dryRun bool
+}
+
+func (r pruneToolsResult) Data() interface{} {
+	return r.tools
+}
+
+func (r pruneToolsResult) String() string {
+	if len(r.tools) == 0 {
+		return tr("No tools to uninstall.")
+	}
+	if r.dryRun {
+		return tr("The following tools would be uninstalled:") + "\n  " + strings.Join(r.tools, "\n  ")
+	}
+	return tr("The following tools have been uninstalled:") + "\n  " + strings.Join(r.tools, "\n  ")
+}
@@ -0,0 +1,65 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2023 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package core
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/arduino/arduino-cli/commands/core"
+	"github.com/arduino/arduino-cli/internal/cli/arguments"
+	"github.com/arduino/arduino-cli/internal/cli/feedback"
+	"github.com/arduino/arduino-cli/internal/cli/instance"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+func initRollbackCommand() *cobra.Command {
+	rollbackCommand := &cobra.Command{
+		Use:   fmt.Sprintf("rollback %s:%s[@%s]", tr("PACKAGER"), tr("ARCH"), tr("VERSION")),
+		Short: tr("Restores a previously installed version of a core."),
+		Long: tr("Restores a previously installed version of a core, kept on disk when the core has been upgraded or downgraded (see the board_manager.keep_previous_releases setting). " +
+			"The core is then pinned to the restored version, use `core install` without a version to follow the upgrades again."),
+		Example: "  # " + tr("restore the version of Arduino SAMD core installed before the last upgrade.") + "\n" +
+			"  " + os.Args[0] + " core rollback arduino:samd\n\n" +
+			"  # " + tr("restore a specific version (in this case 1.8.12).") + "\n" +
+			"  " + os.Args[0] + " core rollback arduino:samd@1.8.12",
+		Args: cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			runRollbackCommand(args)
+		},
+		ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+			return arguments.GetUninstallableCores(), cobra.ShellCompDirectiveDefault
+		},
+	}
+	return rollbackCommand
+}
+
+func runRollbackCommand(args []string) {
+	inst := instance.CreateAndInit()
+	logrus.Info("Executing `arduino-cli core rollback`")
+
+	platformRef, err := arguments.ParseReference(args[0])
+	if err != nil {
+		feedback.Fatal(tr("Invalid argument passed: %v", err), feedback.ErrBadArgument)
+	}
+	version, err := core.PlatformRollback(context.Background(), inst, platformRef.PackageName, platformRef.Architecture, platformRef.Version, feedback.ProgressBar(), feedback.TaskProgress())
+	if err != nil {
+		feedback.Fatal(tr("Error during rollback: %v", err), feedback.ErrGeneric)
+	}
+	feedback.Print(tr("Platform %[1]s rolled back to version %[2]s", platformRef.PackageName+":"+platformRef.Architecture, version))
+}
@@ -100,7 +100,9 @@ func (sr searchResults) String() string {
 		t.SetHeader(tr("ID"), tr("Version"), tr("Name"))
 		for _, item := range sr.platforms {
 			name := item.GetName()
-			if item.Deprecated {
+			if item.EndOfLifeReached {
+				name = fmt.Sprintf("[%s] %s", tr("END OF LIFE"), name)
+			} else if item.Deprecated {
 				name = fmt.Sprintf("[%s] %s", tr("DEPRECATED"), name)
 			}
 			t.AddRow(item.GetId(), item.GetLatest(), name)
@@ -19,6 +19,7 @@ import (
 	"context"
 	"fmt"
 	"os"
+	"strings"
 
 	"github.com/arduino/arduino-cli/commands/core"
 	"github.com/arduino/arduino-cli/internal/cli/arguments"
@@ -73,4 +74,10 @@ func runUninstallCommand(args []string, preUninstallFlags arguments.PrePostScrip
 			feedback.Fatal(tr("Error during uninstall: %v", err), feedback.ErrGeneric)
 		}
 	}
+
+	// Offer to remove the tools left behind by previously uninstalled platforms
+	if orphans, err := core.PruneTools(context.Background(), inst, true, false, nil); err == nil && len(orphans) > 0 {
+		feedback.Print(tr("The following tools are no longer required by any installed platform: %s", strings.Join(orphans, ", ")))
+		feedback.Print(tr("Run `%s` to uninstall them.", os.Args[0]+" core prune-tools"))
+	}
 }
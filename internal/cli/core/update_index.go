@@ -18,6 +18,7 @@ package core
 import (
 	"context"
 	"os"
+	"strings"
 
 	"github.com/arduino/arduino-cli/commands"
 	"github.com/arduino/arduino-cli/internal/cli/feedback"
@@ -28,21 +29,59 @@ import (
 )
 
 func initUpdateIndexCommand() *cobra.Command {
+	var report bool
 	updateIndexCommand := &cobra.Command{
-		Use:     "update-index",
-		Short:   tr("Updates the index of cores."),
-		Long:    tr("Updates the index of cores to the latest version."),
-		Example: "  " + os.Args[0] + " core update-index",
-		Args:    cobra.NoArgs,
-		Run:     runUpdateIndexCommand,
+		Use:   "update-index",
+		Short: tr("Updates the index of cores."),
+		Long:  tr("Updates the index of cores to the latest version."),
+		Example: "  " + os.Args[0] + " core update-index\n" +
+			"  " + os.Args[0] + " core update-index --report --format json",
+		Args: cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			runUpdateIndexCommand(report)
+		},
 	}
+	updateIndexCommand.Flags().BoolVar(&report, "report", false, tr("Report the changes of the indexes: new and removed platforms and releases, changed checksums."))
 	return updateIndexCommand
 }
 
-func runUpdateIndexCommand(cmd *cobra.Command, args []string) {
+func runUpdateIndexCommand(report bool) {
 	inst := instance.CreateAndInit()
 	logrus.Info("Executing `arduino-cli core update-index`")
-	UpdateIndex(inst)
+	if !report {
+		UpdateIndex(inst)
+		return
+	}
+	diff, err := commands.UpdateIndexWithReport(context.Background(), &rpc.UpdateIndexRequest{Instance: inst}, feedback.ProgressBar())
+	if err != nil {
+		feedback.FatalError(err, feedback.ErrGeneric)
+	}
+
+	orEmpty := func(list []string) []string {
+		if list == nil {
+			return []string{}
+		}
+		return list
+	}
+	res := &indexDiffResult{
+		NewPlatforms:            orEmpty(diff.NewPlatforms),
+		RemovedPlatforms:        orEmpty(diff.RemovedPlatforms),
+		NewPlatformReleases:     orEmpty(diff.NewPlatformReleases),
+		RemovedPlatformReleases: orEmpty(diff.RemovedPlatformReleases),
+		NewToolReleases:         orEmpty(diff.NewToolReleases),
+		RemovedToolReleases:     orEmpty(diff.RemovedToolReleases),
+		ChangedChecksums:        []*indexChecksumChangeResult{},
+		empty:                   diff.IsEmpty(),
+	}
+	for _, change := range diff.ChangedChecksums {
+		res.ChangedChecksums = append(res.ChangedChecksums, &indexChecksumChangeResult{
+			Release:     change.Release,
+			Host:        change.Host,
+			OldChecksum: change.OldChecksum,
+			NewChecksum: change.NewChecksum,
+		})
+	}
+	feedback.PrintResult(res)
 }
 
 // UpdateIndex updates the index of platforms.
@@ -52,3 +91,53 @@ func UpdateIndex(inst *rpc.Instance) {
 		feedback.FatalError(err, feedback.ErrGeneric)
 	}
 }
+
+type indexChecksumChangeResult struct {
+	Release     string `json:"release"`
+	Host        string `json:"host,omitempty"`
+	OldChecksum string `json:"old_checksum"`
+	NewChecksum string `json:"new_checksum"`
+}
+
+type indexDiffResult struct {
+	NewPlatforms            []string                     `json:"new_platforms"`
+	RemovedPlatforms        []string                     `json:"removed_platforms"`
+	NewPlatformReleases     []string                     `json:"new_platform_releases"`
+	RemovedPlatformReleases []string                     `json:"removed_platform_releases"`
+	NewToolReleases         []string                     `json:"new_tool_releases"`
+	RemovedToolReleases     []string                     `json:"removed_tool_releases"`
+	ChangedChecksums        []*indexChecksumChangeResult `json:"changed_checksums"`
+	empty                   bool
+}
+
+func (r *indexDiffResult) Data() interface{} {
+	return r
+}
+
+func (r *indexDiffResult) String() string {
+	if r.empty {
+		return tr("The indexes have not changed.")
+	}
+	res := ""
+	section := func(title string, list []string) {
+		if len(list) > 0 {
+			res += title + "\n  " + strings.Join(list, "\n  ") + "\n"
+		}
+	}
+	section(tr("New platforms:"), r.NewPlatforms)
+	section(tr("Removed platforms:"), r.RemovedPlatforms)
+	section(tr("New platform releases:"), r.NewPlatformReleases)
+	section(tr("Removed platform releases:"), r.RemovedPlatformReleases)
+	section(tr("New tool releases:"), r.NewToolReleases)
+	section(tr("Removed tool releases:"), r.RemovedToolReleases)
+	changes := []string{}
+	for _, change := range r.ChangedChecksums {
+		release := change.Release
+		if change.Host != "" {
+			release += " (" + change.Host + ")"
+		}
+		changes = append(changes, tr("%[1]s: %[2]s -> %[3]s", release, change.OldChecksum, change.NewChecksum))
+	}
+	section(tr("Changed checksums:"), changes)
+	return strings.TrimSuffix(res, "\n")
+}
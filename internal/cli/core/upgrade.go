@@ -116,6 +116,11 @@ func Upgrade(inst *rpc.Instance, args []string, skipPostInstall bool, skipPreUni
 				feedback.Warning(err.Error())
 				continue
 			}
+			var pinnedErr *arduino.PlatformPinnedError
+			if errors.As(err, &pinnedErr) {
+				feedback.Warning(err.Error())
+				continue
+			}
 
 			feedback.Fatal(tr("Error during upgrade: %v", err), feedback.ErrGeneric)
 		}
@@ -94,7 +94,9 @@ func (ir outdatedResult) String() string {
 	// Based on internal/cli/core/list.go
 	for _, p := range ir.Platforms {
 		name := p.Name
-		if p.Deprecated {
+		if p.EndOfLifeReached {
+			name = fmt.Sprintf("[%s] %s", tr("END OF LIFE"), name)
+		} else if p.Deprecated {
 			name = fmt.Sprintf("[%s] %s", tr("DEPRECATED"), name)
 		}
 		t.AddRow(p.Id, name, p.Installed, p.Latest, "", "")